@@ -1,17 +1,41 @@
 package routes
 
 import (
-	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/authz"
 	"hospital-management-system/internal/handlers"
 	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(
 	authHandler *handlers.AuthHandler,
+	mfaHandler *handlers.MFAHandler,
 	patientHandler *handlers.PatientHandler,
-	jwtService *auth.JWTService,
+	patientHistoryHandler *handlers.PatientHistoryHandler,
+	accessLogHandler *handlers.AccessLogHandler,
+	userHandler *handlers.UserHandler,
+	appointmentHandler *handlers.AppointmentHandler,
+	availabilityHandler *handlers.AvailabilityHandler,
+	encounterHandler *handlers.EncounterHandler,
+	allergyHandler *handlers.AllergyHandler,
+	medicationHandler *handlers.MedicationHandler,
+	prescriptionHandler *handlers.PrescriptionHandler,
+	problemHandler *handlers.ProblemHandler,
+	icd10Handler *handlers.ICD10Handler,
+	vitalSignsHandler *handlers.VitalSignsHandler,
+	labHandler *handlers.LabHandler,
+	wardHandler *handlers.WardHandler,
+	admissionHandler *handlers.AdmissionHandler,
+	edHandler *handlers.EDHandler,
+	priceListHandler *handlers.PriceListHandler,
+	billingHandler *handlers.BillingHandler,
+	insuranceHandler *handlers.InsuranceHandler,
+	claimHandler *handlers.ClaimHandler,
+	accessLogService *services.AccessLogService,
+	authService *services.AuthService,
+	policy *authz.Policy,
 ) *gin.Engine {
 	router := gin.Default()
 
@@ -35,32 +59,248 @@ func SetupRoutes(
 		})
 	})
 
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
+
 	v1 := router.Group("/api/v1")
 	{
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/register", authHandler.Register)
+			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/mfa/verify", mfaHandler.VerifyLogin)
+		}
+
+		mfaEnrollment := v1.Group("/auth/mfa")
+		mfaEnrollment.Use(middleware.MFAEnrollmentAuth(authService))
+		{
+			mfaEnrollment.POST("/enroll", mfaHandler.BeginEnrollment)
+			mfaEnrollment.POST("/enroll/confirm", mfaHandler.ConfirmEnrollment)
 		}
 
 		authProtected := v1.Group("/auth")
-		authProtected.Use(middleware.AuthMiddleware(jwtService))
+		authProtected.Use(middleware.AuthMiddleware(authService))
 		{
-			authProtected.POST("/refresh", authHandler.RefreshToken)
+			authProtected.POST("/logout", authHandler.Logout)
+			authProtected.POST("/logout-all", authHandler.LogoutAll)
 			authProtected.GET("/profile", authHandler.GetProfile)
+			authProtected.POST("/mfa/disable", mfaHandler.Disable)
+			authProtected.POST("/register", middleware.RequirePermission(policy, authz.UserManage), userHandler.CreateUser)
 		}
 
 		patients := v1.Group("/patients")
-		patients.Use(middleware.AuthMiddleware(jwtService))
+		patients.Use(middleware.AuthMiddleware(authService), middleware.PatientAccessLogger(accessLogService))
+		{
+			patients.GET("", middleware.RequirePermission(policy, authz.PatientRead), patientHandler.ListPatients)
+			patients.GET("/search", middleware.RequirePermission(policy, authz.PatientRead), patientHandler.SearchPatients)
+			patients.GET("/:id", middleware.RequirePermission(policy, authz.PatientRead), patientHandler.GetPatient)
+			patients.GET("/by-diagnosis", middleware.RequirePermission(policy, authz.ProblemRead), problemHandler.ListPatientsWithDiagnosis)
+			patients.GET("/by-patient-id/:patient_id", middleware.RequirePermission(policy, authz.PatientRead), patientHandler.GetPatientByPatientID)
+			patients.PUT("/:id", middleware.RequirePermission(policy, authz.PatientUpdate), patientHandler.UpdatePatient)
+			patients.GET("/:id/duplicates", middleware.RequirePermission(policy, authz.PatientMerge), patientHandler.FindDuplicates)
+			patients.POST("/:id/merge", middleware.RequirePermission(policy, authz.PatientMerge), patientHandler.MergePatients)
+
+			patients.GET("/:id/history", middleware.RequirePermission(policy, authz.PatientHistoryRead), patientHistoryHandler.GetHistory)
+			patients.GET("/:id/history/diff", middleware.RequirePermission(policy, authz.PatientHistoryDiff), patientHistoryHandler.DiffVersions)
+			patients.GET("/:id/history/:version", middleware.RequirePermission(policy, authz.PatientHistoryRead), patientHistoryHandler.GetVersion)
+
+			patients.GET("/:id/encounters", middleware.RequirePermission(policy, authz.EncounterRead), encounterHandler.ListEncounters)
+			patients.GET("/:id/encounters/:encounter_id", middleware.RequirePermission(policy, authz.EncounterRead), encounterHandler.GetEncounter)
+			patients.POST("/:id/encounters", middleware.RequirePermission(policy, authz.EncounterWrite), encounterHandler.CreateEncounter)
+			patients.PUT("/:id/encounters/:encounter_id", middleware.RequirePermission(policy, authz.EncounterWrite), encounterHandler.UpdateEncounter)
+			patients.POST("/:id/encounters/:encounter_id/sign", middleware.RequirePermission(policy, authz.EncounterWrite), encounterHandler.SignEncounter)
+			patients.POST("/:id/encounters/:encounter_id/addenda", middleware.RequirePermission(policy, authz.EncounterWrite), encounterHandler.AddAddendum)
+
+			patients.GET("/:id/allergies", middleware.RequirePermission(policy, authz.AllergyRead), allergyHandler.ListAllergies)
+			patients.GET("/:id/allergies/:allergy_id", middleware.RequirePermission(policy, authz.AllergyRead), allergyHandler.GetAllergy)
+			patients.POST("/:id/allergies", middleware.RequirePermission(policy, authz.AllergyWrite), allergyHandler.CreateAllergy)
+			patients.PUT("/:id/allergies/:allergy_id", middleware.RequirePermission(policy, authz.AllergyWrite), allergyHandler.UpdateAllergy)
+
+			patients.GET("/:id/medications/active", middleware.RequirePermission(policy, authz.PrescriptionRead), prescriptionHandler.ActiveMedications)
+			patients.GET("/:id/prescriptions", middleware.RequirePermission(policy, authz.PrescriptionRead), prescriptionHandler.ListPrescriptions)
+			patients.GET("/:id/prescriptions/:prescription_id", middleware.RequirePermission(policy, authz.PrescriptionRead), prescriptionHandler.GetPrescription)
+			patients.POST("/:id/prescriptions", middleware.RequirePermission(policy, authz.PrescriptionWrite), prescriptionHandler.CreatePrescription)
+			patients.POST("/:id/prescriptions/:prescription_id/stop", middleware.RequirePermission(policy, authz.PrescriptionWrite), prescriptionHandler.StopPrescription)
+			patients.POST("/:id/prescriptions/:prescription_id/discontinue", middleware.RequirePermission(policy, authz.PrescriptionWrite), prescriptionHandler.DiscontinuePrescription)
+
+			patients.GET("/:id/problems", middleware.RequirePermission(policy, authz.ProblemRead), problemHandler.ListProblems)
+			patients.GET("/:id/problems/:problem_id", middleware.RequirePermission(policy, authz.ProblemRead), problemHandler.GetProblem)
+			patients.POST("/:id/problems", middleware.RequirePermission(policy, authz.ProblemWrite), problemHandler.CreateProblem)
+			patients.PUT("/:id/problems/:problem_id", middleware.RequirePermission(policy, authz.ProblemWrite), problemHandler.UpdateProblem)
+
+			patients.GET("/:id/vitals", middleware.RequirePermission(policy, authz.VitalSignsRead), vitalSignsHandler.ListVitalSigns)
+			patients.GET("/:id/vitals/trend", middleware.RequirePermission(policy, authz.VitalSignsRead), vitalSignsHandler.GetTrend)
+			patients.GET("/:id/vitals/:vitals_id", middleware.RequirePermission(policy, authz.VitalSignsRead), vitalSignsHandler.GetVitalSigns)
+			patients.POST("/:id/vitals", middleware.RequirePermission(policy, authz.VitalSignsWrite), vitalSignsHandler.RecordVitalSigns)
+
+			patients.GET("/:id/lab-orders", middleware.RequirePermission(policy, authz.LabOrderRead), labHandler.ListLabOrders)
+			patients.GET("/:id/lab-orders/:order_id", middleware.RequirePermission(policy, authz.LabOrderRead), labHandler.GetLabOrder)
+			patients.POST("/:id/lab-orders", middleware.RequirePermission(policy, authz.LabOrderWrite), labHandler.CreateLabOrder)
+			patients.POST("/:id/lab-orders/:order_id/cancel", middleware.RequirePermission(policy, authz.LabOrderWrite), labHandler.CancelLabOrder)
+			patients.POST("/:id/lab-orders/:order_id/collect", middleware.RequirePermission(policy, authz.LabResultWrite), labHandler.CollectSpecimen)
+			patients.POST("/:id/lab-orders/:order_id/start", middleware.RequirePermission(policy, authz.LabResultWrite), labHandler.StartProcessing)
+			patients.POST("/:id/lab-orders/:order_id/results", middleware.RequirePermission(policy, authz.LabResultWrite), labHandler.RecordResults)
+			patients.POST("/:id/lab-orders/:order_id/review", middleware.RequirePermission(policy, authz.LabOrderRead), labHandler.ReviewResults)
+
+			patients.GET("/:id/admissions", middleware.RequirePermission(policy, authz.AdmissionRead), admissionHandler.ListAdmissions)
+			patients.GET("/:id/admissions/current", middleware.RequirePermission(policy, authz.AdmissionRead), admissionHandler.CurrentAdmission)
+			patients.GET("/:id/admissions/:admission_id", middleware.RequirePermission(policy, authz.AdmissionRead), admissionHandler.GetAdmission)
+			patients.POST("/:id/admissions", middleware.RequirePermission(policy, authz.AdmissionWrite), admissionHandler.AdmitPatient)
+			patients.POST("/:id/admissions/:admission_id/transfer", middleware.RequirePermission(policy, authz.AdmissionWrite), admissionHandler.TransferPatient)
+			patients.POST("/:id/admissions/:admission_id/discharge", middleware.RequirePermission(policy, authz.AdmissionWrite), admissionHandler.DischargePatient)
+
+			patients.GET("/:id/balance", middleware.RequirePermission(policy, authz.InvoiceRead), billingHandler.PatientBalance)
+			patients.GET("/:id/invoices", middleware.RequirePermission(policy, authz.InvoiceRead), billingHandler.ListInvoices)
+			patients.GET("/:id/invoices/:invoice_id", middleware.RequirePermission(policy, authz.InvoiceRead), billingHandler.GetInvoice)
+			patients.GET("/:id/invoices/:invoice_id/print", middleware.RequirePermission(policy, authz.InvoiceRead), billingHandler.PrintInvoice)
+			patients.POST("/:id/invoices", middleware.RequirePermission(policy, authz.InvoiceWrite), billingHandler.CreateInvoice)
+			patients.PUT("/:id/invoices/:invoice_id", middleware.RequirePermission(policy, authz.InvoiceWrite), billingHandler.UpdateDraftInvoice)
+			patients.POST("/:id/invoices/:invoice_id/issue", middleware.RequirePermission(policy, authz.InvoiceWrite), billingHandler.IssueInvoice)
+			patients.POST("/:id/invoices/:invoice_id/void", middleware.RequirePermission(policy, authz.InvoiceWrite), billingHandler.VoidInvoice)
+			patients.POST("/:id/invoices/:invoice_id/payments", middleware.RequirePermission(policy, authz.PaymentWrite), billingHandler.RecordPayment)
+			patients.POST("/:id/invoices/:invoice_id/refunds", middleware.RequirePermission(policy, authz.PaymentWrite), billingHandler.RefundPayment)
+
+			patients.GET("/:id/insurance-policies", middleware.RequirePermission(policy, authz.InsurancePolicyRead), insuranceHandler.ListPolicies)
+			patients.GET("/:id/insurance-policies/:policy_id", middleware.RequirePermission(policy, authz.InsurancePolicyRead), insuranceHandler.GetPolicy)
+			patients.POST("/:id/insurance-policies", middleware.RequirePermission(policy, authz.InsurancePolicyWrite), insuranceHandler.CreatePolicy)
+			patients.PUT("/:id/insurance-policies/:policy_id", middleware.RequirePermission(policy, authz.InsurancePolicyWrite), insuranceHandler.UpdatePolicy)
+			patients.GET("/:id/eligibility", middleware.RequirePermission(policy, authz.InsurancePolicyRead), insuranceHandler.CheckEligibility)
+
+			patients.GET("/:id/claims", middleware.RequirePermission(policy, authz.ClaimRead), claimHandler.ListClaims)
+			patients.GET("/:id/claims/:claim_id", middleware.RequirePermission(policy, authz.ClaimRead), claimHandler.GetClaim)
+			patients.POST("/:id/claims", middleware.RequirePermission(policy, authz.ClaimWrite), claimHandler.CreateClaim)
+			patients.POST("/:id/claims/:claim_id/accept", middleware.RequirePermission(policy, authz.ClaimWrite), claimHandler.AcceptClaim)
+			patients.POST("/:id/claims/:claim_id/deny", middleware.RequirePermission(policy, authz.ClaimWrite), claimHandler.DenyClaim)
+			patients.POST("/:id/claims/:claim_id/pay", middleware.RequirePermission(policy, authz.ClaimWrite), claimHandler.PayClaim)
+			patients.POST("/:id/claims/:claim_id/reopen", middleware.RequirePermission(policy, authz.ClaimWrite), claimHandler.ReopenClaim)
+
+			patients.POST("", middleware.RequirePermission(policy, authz.PatientCreate), patientHandler.CreatePatient)
+			patients.DELETE("/:id", middleware.RequirePermission(policy, authz.PatientDelete), patientHandler.DeletePatient)
+		}
+
+		accessLogs := v1.Group("/access-logs")
+		accessLogs.Use(middleware.AuthMiddleware(authService))
+		{
+			accessLogs.GET("", middleware.RequirePermission(policy, authz.AccessLogRead), accessLogHandler.ListAccessLogs)
+		}
+
+		users := v1.Group("/users")
+		users.Use(middleware.AuthMiddleware(authService), middleware.RequirePermission(policy, authz.UserManage))
+		{
+			users.GET("", userHandler.ListUsers)
+			users.POST("", userHandler.CreateUser)
+			users.GET("/:id", userHandler.GetUser)
+			users.PUT("/:id", userHandler.UpdateUser)
+			users.POST("/:id/deactivate", userHandler.DeactivateUser)
+			users.POST("/:id/reactivate", userHandler.ReactivateUser)
+			users.POST("/:id/reset-password", userHandler.ResetPassword)
+		}
+
+		appointments := v1.Group("/appointments")
+		appointments.Use(middleware.AuthMiddleware(authService), middleware.PatientAccessLogger(accessLogService))
+		{
+			appointments.GET("", middleware.RequirePermission(policy, authz.AppointmentRead), appointmentHandler.ListAppointments)
+			appointments.GET("/:id", middleware.RequirePermission(policy, authz.AppointmentRead), appointmentHandler.GetAppointment)
+
+			appointments.POST("", middleware.RequirePermission(policy, authz.AppointmentManage), appointmentHandler.CreateAppointment)
+			appointments.POST("/:id/reschedule", middleware.RequirePermission(policy, authz.AppointmentManage), appointmentHandler.RescheduleAppointment)
+			appointments.POST("/:id/cancel", middleware.RequirePermission(policy, authz.AppointmentManage), appointmentHandler.CancelAppointment)
+			appointments.POST("/:id/check-in", middleware.RequirePermission(policy, authz.AppointmentManage), appointmentHandler.CheckInAppointment)
+			appointments.POST("/:id/complete", middleware.RequirePermission(policy, authz.AppointmentComplete), appointmentHandler.CompleteAppointment)
+		}
+
+		doctors := v1.Group("/doctors")
+		doctors.Use(middleware.AuthMiddleware(authService))
 		{
-			patients.GET("", middleware.RequireReceptionistOrDoctor(), patientHandler.ListPatients)
-			patients.GET("/search", middleware.RequireReceptionistOrDoctor(), patientHandler.SearchPatients)
-			patients.GET("/:id", middleware.RequireReceptionistOrDoctor(), patientHandler.GetPatient)
-			patients.GET("/by-patient-id/:patient_id", middleware.RequireReceptionistOrDoctor(), patientHandler.GetPatientByPatientID)
-			patients.PUT("/:id", middleware.RequireReceptionistOrDoctor(), patientHandler.UpdatePatient)
+			doctors.GET("/:id/schedule", middleware.RequirePermission(policy, authz.AvailabilityRead), availabilityHandler.GetSchedule)
+			doctors.PUT("/:id/schedule", middleware.RequirePermission(policy, authz.AvailabilityManage), availabilityHandler.UpdateSchedule)
+			doctors.POST("/:id/schedule/exceptions", middleware.RequirePermission(policy, authz.AvailabilityManage), availabilityHandler.AddException)
+			doctors.DELETE("/:id/schedule/exceptions/:exception_id", middleware.RequirePermission(policy, authz.AvailabilityManage), availabilityHandler.DeleteException)
+		}
+
+		availability := v1.Group("/availability")
+		availability.Use(middleware.AuthMiddleware(authService))
+		{
+			availability.GET("/slots", middleware.RequirePermission(policy, authz.AvailabilityRead), availabilityHandler.FindOpenSlots)
+		}
 
-			patients.POST("", middleware.RequireReceptionist(), patientHandler.CreatePatient)
-			patients.DELETE("/:id", middleware.RequireReceptionist(), patientHandler.DeletePatient)
+		medications := v1.Group("/medications")
+		medications.Use(middleware.AuthMiddleware(authService))
+		{
+			medications.GET("", middleware.RequirePermission(policy, authz.MedicationRead), medicationHandler.ListMedications)
+			medications.GET("/:id", middleware.RequirePermission(policy, authz.MedicationRead), medicationHandler.GetMedication)
+			medications.POST("", middleware.RequirePermission(policy, authz.MedicationCatalogManage), medicationHandler.CreateMedication)
+			medications.PUT("/:id", middleware.RequirePermission(policy, authz.MedicationCatalogManage), medicationHandler.UpdateMedication)
+		}
+
+		priceList := v1.Group("/price-list")
+		priceList.Use(middleware.AuthMiddleware(authService))
+		{
+			priceList.GET("", middleware.RequirePermission(policy, authz.PriceListRead), priceListHandler.ListItems)
+			priceList.GET("/:id", middleware.RequirePermission(policy, authz.PriceListRead), priceListHandler.GetItem)
+			priceList.POST("", middleware.RequirePermission(policy, authz.PriceListManage), priceListHandler.CreateItem)
+			priceList.PUT("/:id", middleware.RequirePermission(policy, authz.PriceListManage), priceListHandler.UpdateItem)
+		}
+
+		payers := v1.Group("/insurance-payers")
+		payers.Use(middleware.AuthMiddleware(authService))
+		{
+			payers.GET("", middleware.RequirePermission(policy, authz.InsurancePayerRead), insuranceHandler.ListPayers)
+			payers.POST("", middleware.RequirePermission(policy, authz.InsurancePayerManage), insuranceHandler.CreatePayer)
+			payers.PUT("/:id", middleware.RequirePermission(policy, authz.InsurancePayerManage), insuranceHandler.UpdatePayer)
+		}
+
+		claims := v1.Group("/claims")
+		claims.Use(middleware.AuthMiddleware(authService), middleware.PatientAccessLogger(accessLogService))
+		{
+			claims.GET("", middleware.RequirePermission(policy, authz.ClaimRead), claimHandler.SearchClaims)
+			claims.POST("/batches", middleware.RequirePermission(policy, authz.ClaimWrite), claimHandler.CreateBatch)
+			claims.GET("/batches/:batch_id", middleware.RequirePermission(policy, authz.ClaimRead), claimHandler.GetBatch)
+			claims.GET("/batches/:batch_id/file", middleware.RequirePermission(policy, authz.ClaimRead), claimHandler.DownloadBatch)
+		}
+
+		lab := v1.Group("/lab")
+		lab.Use(middleware.AuthMiddleware(authService), middleware.PatientAccessLogger(accessLogService))
+		{
+			lab.GET("/inbox", middleware.RequirePermission(policy, authz.LabOrderRead), labHandler.Inbox)
+		}
+
+		wards := v1.Group("/wards")
+		wards.Use(middleware.AuthMiddleware(authService), middleware.PatientAccessLogger(accessLogService))
+		{
+			wards.GET("", middleware.RequirePermission(policy, authz.WardRead), wardHandler.ListWards)
+			wards.GET("/board", middleware.RequirePermission(policy, authz.WardRead), wardHandler.Board)
+			wards.POST("", middleware.RequirePermission(policy, authz.WardManage), wardHandler.CreateWard)
+			wards.POST("/:id/rooms", middleware.RequirePermission(policy, authz.WardManage), wardHandler.CreateRoom)
+			wards.POST("/:id/rooms/:room_id/beds", middleware.RequirePermission(policy, authz.WardManage), wardHandler.CreateBed)
+			wards.PUT("/beds/:bed_id", middleware.RequirePermission(policy, authz.WardManage), wardHandler.UpdateBed)
+		}
+
+		ed := v1.Group("/ed")
+		ed.Use(middleware.AuthMiddleware(authService), middleware.PatientAccessLogger(accessLogService))
+		{
+			ed.GET("/queue", middleware.RequirePermission(policy, authz.EDVisitRead), edHandler.Queue)
+			ed.GET("/metrics", middleware.RequirePermission(policy, authz.EDVisitRead), edHandler.Metrics)
+			ed.POST("/visits", middleware.RequirePermission(policy, authz.EDVisitTriage), edHandler.CheckIn)
+			ed.GET("/visits/:id", middleware.RequirePermission(policy, authz.EDVisitRead), edHandler.GetVisit)
+			ed.POST("/visits/:id/triage", middleware.RequirePermission(policy, authz.EDVisitTriage), edHandler.Retriage)
+			ed.POST("/visits/:id/assign", middleware.RequirePermission(policy, authz.EDVisitTriage), edHandler.AssignDoctor)
+			ed.POST("/visits/:id/leave", middleware.RequirePermission(policy, authz.EDVisitTriage), edHandler.LeftWithoutBeingSeen)
+			ed.POST("/visits/:id/start", middleware.RequirePermission(policy, authz.EDVisitTreat), edHandler.StartTreatment)
+			ed.POST("/visits/:id/complete", middleware.RequirePermission(policy, authz.EDVisitTreat), edHandler.Complete)
+		}
+
+		icd10Codes := v1.Group("/icd10-codes")
+		icd10Codes.Use(middleware.AuthMiddleware(authService))
+		{
+			icd10Codes.GET("", middleware.RequirePermission(policy, authz.ICD10CodeRead), icd10Handler.SearchCodes)
+			icd10Codes.POST("/import", middleware.RequirePermission(policy, authz.ICD10CodeImport), icd10Handler.ImportCodes)
+		}
+
+		mfaPolicies := v1.Group("/mfa-policies")
+		mfaPolicies.Use(middleware.AuthMiddleware(authService), middleware.RequirePermission(policy, authz.MFAPolicyManage))
+		{
+			mfaPolicies.GET("", mfaHandler.ListPolicies)
+			mfaPolicies.PUT("", mfaHandler.SetPolicy)
 		}
 	}
 
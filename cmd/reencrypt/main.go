@@ -0,0 +1,52 @@
+// Command reencrypt encrypts patient PHI, including allergy and prescription
+// text, that is still stored in plaintext and moves records off retired
+// master keys. Run it after enabling PHI_KEY_FILE for the first time, and
+// after adding a new master key version and making it active; once it
+// reports no remaining records the old key can be removed from the keyfile.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"hospital-management-system/internal/config"
+	"hospital-management-system/internal/phi"
+	"hospital-management-system/pkg/database"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", 500, "number of records rewritten per transaction")
+	all := flag.Bool("all", false, "rewrite every record, also rebuilding the blind indexes")
+	flag.Parse()
+
+	if *batchSize <= 0 {
+		log.Fatal("batch-size must be positive")
+	}
+
+	cfg := config.Load()
+	if cfg.PHI.KeyFile == "" {
+		log.Fatal("PHI_KEY_FILE is not set")
+	}
+
+	keyring, err := phi.LoadKeyring(cfg.PHI.KeyFile)
+	if err != nil {
+		log.Fatalf("Failed to load PHI keyfile: %v", err)
+	}
+	phi.SetKeyring(keyring)
+
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if err := database.Migrate(); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	stats, err := database.ReencryptPHI(*batchSize, *all)
+	if err != nil {
+		log.Fatalf("Re-encryption stopped: %v", err)
+	}
+
+	log.Printf("Re-encrypted %d patients, %d patient revisions, %d allergies and %d prescriptions under key version %d",
+		stats.Patients, stats.Revisions, stats.Allergies, stats.Prescriptions, keyring.ActiveVersion())
+}
@@ -7,9 +7,14 @@ import (
 
 	"hospital-management-system/api/routes"
 	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/billing"
+	"hospital-management-system/internal/clinical"
 	"hospital-management-system/internal/config"
 	"hospital-management-system/internal/handlers"
 	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/patientid"
+	"hospital-management-system/internal/phi"
 	"hospital-management-system/internal/repository"
 	"hospital-management-system/internal/services"
 	"hospital-management-system/pkg/database"
@@ -26,24 +31,129 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	phiKeyring, err := loadPHIKeyring(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load PHI keyfile: %v", err)
+	}
+	phi.SetKeyring(phiKeyring)
+
 	if err := database.Migrate(); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	jwtService := auth.NewJWTService(cfg.JWT.Secret)
+	patientIDFormat, err := loadPatientIDFormat(cfg)
+	if err != nil {
+		log.Fatalf("Invalid patient ID format: %v", err)
+	}
+
+	jwtKeys, err := loadJWTKeys(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	jwtService := auth.NewJWTServiceWithKeys(jwtKeys, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL, cfg.JWT.SessionMaxLifetime)
+
+	policy, err := loadPolicy(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load authorization policy: %v", err)
+	}
+
+	safetyRules, err := loadSafetyRules(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load clinical safety rules: %v", err)
+	}
 
 	userRepo := repository.NewUserRepository(database.GetDB())
-	patientRepo := repository.NewPatientRepository(database.GetDB())
+	sessionRepo := repository.NewSessionRepository(database.GetDB())
+	mfaRepo := repository.NewMFARepository(database.GetDB())
+	patientRepo := repository.NewPatientRepository(database.GetDB(), patientIDFormat)
+	patientRevisionRepo := repository.NewPatientRevisionRepository(database.GetDB())
+	accessLogRepo := repository.NewAccessLogRepository(database.GetDB())
+	appointmentRepo := repository.NewAppointmentRepository(database.GetDB())
+	availabilityRepo := repository.NewAvailabilityRepository(database.GetDB())
+	encounterRepo := repository.NewEncounterRepository(database.GetDB())
+	allergyRepo := repository.NewAllergyRepository(database.GetDB())
+	medicationRepo := repository.NewMedicationRepository(database.GetDB())
+	prescriptionRepo := repository.NewPrescriptionRepository(database.GetDB())
+	problemRepo := repository.NewProblemRepository(database.GetDB())
+	icd10Repo := repository.NewICD10Repository(database.GetDB())
+	vitalSignsRepo := repository.NewVitalSignsRepository(database.GetDB())
+	labOrderRepo := repository.NewLabOrderRepository(database.GetDB())
+	wardRepo := repository.NewWardRepository(database.GetDB())
+	admissionRepo := repository.NewAdmissionRepository(database.GetDB())
+	edVisitRepo := repository.NewEDVisitRepository(database.GetDB())
+	priceListRepo := repository.NewPriceListRepository(database.GetDB())
+	invoiceRepo := repository.NewInvoiceRepository(database.GetDB())
+	insuranceRepo := repository.NewInsuranceRepository(database.GetDB())
+	claimRepo := repository.NewClaimRepository(database.GetDB())
 
-	authService := services.NewAuthService(userRepo, jwtService)
-	patientService := services.NewPatientService(patientRepo, userRepo)
+	authService := services.NewAuthService(userRepo, sessionRepo, mfaRepo, jwtService)
+	mfaService := services.NewMFAService(authService, policy, cfg.App.Name)
+	patientService := services.NewPatientService(patientRepo, userRepo, policy)
+	patientHistoryService := services.NewPatientHistoryService(patientRevisionRepo, patientRepo)
+	accessLogService := services.NewAccessLogService(accessLogRepo)
+	userService := services.NewUserService(userRepo, sessionRepo, policy)
+	appointmentService := services.NewAppointmentService(appointmentRepo, patientRepo, userRepo, policy)
+	availabilityService := services.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo, policy)
+	encounterService := services.NewEncounterService(encounterRepo, patientRepo, appointmentRepo, policy)
+	allergyService := services.NewAllergyService(allergyRepo, patientRepo, policy)
+	medicationService := services.NewMedicationService(medicationRepo)
+	prescriptionService := services.NewPrescriptionService(prescriptionRepo, medicationRepo, patientRepo, encounterRepo, allergyRepo, safetyRules, policy)
+	problemService := services.NewProblemService(problemRepo, icd10Repo, patientRepo, policy)
+	icd10Service := services.NewICD10Service(icd10Repo)
+	vitalSignsService := services.NewVitalSignsService(vitalSignsRepo, patientRepo, policy)
+	labService := services.NewLabService(labOrderRepo, patientRepo, encounterRepo, policy)
+	wardService := services.NewWardService(wardRepo, admissionRepo, policy)
+	admissionService := services.NewAdmissionService(admissionRepo, wardRepo, patientRepo, userRepo, policy)
+	edService := services.NewEDService(edVisitRepo, patientRepo, userRepo, policy)
+	priceListService := services.NewPriceListService(priceListRepo)
+	billingService := services.NewBillingService(invoiceRepo, priceListRepo, patientRepo, encounterRepo, admissionRepo, billing.PrintOptions{
+		HospitalName: cfg.App.Name,
+		Currency:     cfg.Billing.Currency,
+	}, policy)
+	insuranceService := services.NewInsuranceService(insuranceRepo, patientRepo, policy)
+	claimService := services.NewClaimService(claimRepo, invoiceRepo, insuranceRepo, encounterRepo, admissionRepo, icd10Repo, billing.ClaimSubmitter{
+		Name:         cfg.App.Name,
+		SubmitterID:  cfg.Billing.Claims.SubmitterID,
+		ContactPhone: cfg.Billing.Claims.ProviderPhone,
+		ReceiverName: cfg.Billing.Claims.ReceiverName,
+		ReceiverID:   cfg.Billing.Claims.ReceiverID,
+		NPI:          cfg.Billing.Claims.ProviderNPI,
+		TaxID:        cfg.Billing.Claims.ProviderTaxID,
+		Address:      cfg.Billing.Claims.ProviderAddress,
+		City:         cfg.Billing.Claims.ProviderCity,
+		State:        cfg.Billing.Claims.ProviderState,
+		PostalCode:   cfg.Billing.Claims.ProviderPostalCode,
+	}, policy)
 
 	authHandler := handlers.NewAuthHandler(authService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
 	patientHandler := handlers.NewPatientHandler(patientService)
+	patientHistoryHandler := handlers.NewPatientHistoryHandler(patientHistoryService)
+	accessLogHandler := handlers.NewAccessLogHandler(accessLogService)
+	userHandler := handlers.NewUserHandler(userService)
+	appointmentHandler := handlers.NewAppointmentHandler(appointmentService)
+	availabilityHandler := handlers.NewAvailabilityHandler(availabilityService)
+	encounterHandler := handlers.NewEncounterHandler(encounterService)
+	allergyHandler := handlers.NewAllergyHandler(allergyService)
+	medicationHandler := handlers.NewMedicationHandler(medicationService)
+	prescriptionHandler := handlers.NewPrescriptionHandler(prescriptionService)
+	problemHandler := handlers.NewProblemHandler(problemService)
+	icd10Handler := handlers.NewICD10Handler(icd10Service)
+	vitalSignsHandler := handlers.NewVitalSignsHandler(vitalSignsService)
+	labHandler := handlers.NewLabHandler(labService)
+	wardHandler := handlers.NewWardHandler(wardService)
+	admissionHandler := handlers.NewAdmissionHandler(admissionService)
+	edHandler := handlers.NewEDHandler(edService)
+	priceListHandler := handlers.NewPriceListHandler(priceListService)
+	billingHandler := handlers.NewBillingHandler(billingService)
+	insuranceHandler := handlers.NewInsuranceHandler(insuranceService)
+	claimHandler := handlers.NewClaimHandler(claimService)
 
-	createDefaultUsers(authService)
+	createDefaultUsers(userService)
+	createBootstrapAdmin(userService, cfg.Bootstrap.AdminPassword)
 
-	router := routes.SetupRoutes(authHandler, patientHandler, jwtService)
+	router := routes.SetupRoutes(authHandler, mfaHandler, patientHandler, patientHistoryHandler, accessLogHandler, userHandler, appointmentHandler, availabilityHandler, encounterHandler, allergyHandler, medicationHandler, prescriptionHandler, problemHandler, icd10Handler, vitalSignsHandler, labHandler, wardHandler, admissionHandler, edHandler, priceListHandler, billingHandler, insuranceHandler, claimHandler, accessLogService, authService, policy)
 
 	server := &http.Server{
 		Addr:           ":" + cfg.Server.Port,
@@ -62,7 +172,88 @@ func main() {
 	}
 }
 
-func createDefaultUsers(authService *services.AuthService) {
+func loadJWTKeys(cfg *config.Config) (*auth.KeySet, error) {
+	if cfg.JWT.KeysDir != "" {
+		keySet, err := auth.LoadKeySet(cfg.JWT.KeysDir)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.JWT.KeysReload > 0 {
+			go keySet.AutoReload(cfg.JWT.KeysReload, nil)
+		} else {
+			log.Println("JWT_KEYS_RELOAD_INTERVAL is not positive, JWT signing keys are not reloaded")
+		}
+		log.Printf("Loaded JWT signing keys from %s", cfg.JWT.KeysDir)
+		return keySet, nil
+	}
+
+	if cfg.JWT.Secret != "" {
+		log.Println("WARNING: JWT_KEYS_DIR not set, signing tokens with the shared JWT_SECRET (HS256)")
+		return auth.NewHMACKeySet(cfg.JWT.Secret), nil
+	}
+
+	log.Println("WARNING: neither JWT_KEYS_DIR nor JWT_SECRET is set, using an ephemeral signing key; tokens will not survive a restart")
+	return auth.NewEphemeralKeySet()
+}
+
+func loadPHIKeyring(cfg *config.Config) (*phi.Keyring, error) {
+	if cfg.PHI.KeyFile == "" {
+		log.Println("WARNING: PHI_KEY_FILE not set, patient PHI is stored unencrypted")
+		return nil, nil
+	}
+
+	keyring, err := phi.LoadKeyring(cfg.PHI.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded PHI keyfile from %s (active key version %d)", cfg.PHI.KeyFile, keyring.ActiveVersion())
+	return keyring, nil
+}
+
+func loadPatientIDFormat(cfg *config.Config) (patientid.Format, error) {
+	format := patientid.Format{
+		Prefix:     cfg.PatientID.Prefix,
+		DateFormat: cfg.PatientID.DateFormat,
+		Width:      cfg.PatientID.Width,
+		CheckDigit: cfg.PatientID.CheckDigit,
+	}
+	if format.DateFormat == "none" {
+		format.DateFormat = ""
+	}
+	return format, format.Validate()
+}
+
+func loadPolicy(cfg *config.Config) (*authz.Policy, error) {
+	if cfg.Authz.PolicyFile == "" {
+		return authz.DefaultPolicy(), nil
+	}
+
+	policy, err := authz.LoadPolicy(cfg.Authz.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded authorization policy from %s", cfg.Authz.PolicyFile)
+	return policy, nil
+}
+
+func loadSafetyRules(cfg *config.Config) (*clinical.Rules, error) {
+	if cfg.Clinical.SafetyRulesFile == "" {
+		return clinical.DefaultRules(), nil
+	}
+
+	rules, err := clinical.LoadRules(cfg.Clinical.SafetyRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded clinical safety rules from %s", cfg.Clinical.SafetyRulesFile)
+	return rules, nil
+}
+
+func createDefaultUsers(userService *services.UserService) {
+	if _, err := userService.GetUser(1); err == nil {
+		return
+	}
+
 	defaultUsers := []services.RegisterRequest{
 		{
 			Username:  "admin_receptionist",
@@ -83,14 +274,11 @@ func createDefaultUsers(authService *services.AuthService) {
 	}
 
 	for _, user := range defaultUsers {
-		existingUser, err := authService.GetUserByID(1)
-		if err != nil || existingUser == nil {
-			_, err := authService.Register(user)
-			if err != nil && err.Error() != "username already exists" && err.Error() != "email already exists" {
-				log.Printf("Failed to create default user %s: %v", user.Username, err)
-			} else if err == nil {
-				log.Printf("Created default user: %s", user.Username)
-			}
+		_, err := userService.CreateUser(user)
+		if err != nil && err.Error() != "username already exists" && err.Error() != "email already exists" {
+			log.Printf("Failed to create default user %s: %v", user.Username, err)
+		} else if err == nil {
+			log.Printf("Created default user: %s", user.Username)
 		}
 	}
 
@@ -103,3 +291,37 @@ func createDefaultUsers(authService *services.AuthService) {
 	log.Println("  Password: password123")
 	log.Println("=================================")
 }
+
+// createBootstrapAdmin creates an administrator when there is no active one,
+// using the password from BOOTSTRAP_ADMIN_PASSWORD so that no install ships
+// with a known admin password. The password is never logged.
+func createBootstrapAdmin(userService *services.UserService, password string) {
+	admins, err := userService.ListUsers(services.UserListQuery{Role: models.RoleAdmin}, 1, 1)
+	if err != nil {
+		log.Printf("Failed to check for an administrator account: %v", err)
+		return
+	}
+	if admins.Pagination.Total > 0 {
+		return
+	}
+
+	if len(password) < services.MinPasswordLength {
+		log.Printf("No administrator account exists; set BOOTSTRAP_ADMIN_PASSWORD (at least %d characters) and restart to create one", services.MinPasswordLength)
+		return
+	}
+
+	_, err = userService.CreateUser(services.RegisterRequest{
+		Username:  "admin",
+		Email:     "admin@hospital.com",
+		Password:  password,
+		FirstName: "System",
+		LastName:  "Administrator",
+		Role:      models.RoleAdmin,
+	})
+	if err != nil {
+		log.Printf("Failed to create administrator account: %v", err)
+		return
+	}
+
+	log.Println("Created administrator account: admin")
+}
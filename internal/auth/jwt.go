@@ -5,44 +5,117 @@ import (
 	"time"
 
 	"hospital-management-system/internal/models"
+	"hospital-management-system/pkg/utils"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// DefaultSessionMaxLifetime bounds how long refresh token rotation can
+	// keep a session alive after login.
+	DefaultSessionMaxLifetime = 30 * 24 * time.Hour
+	MFATokenTTL               = 5 * time.Minute
+)
+
+// Token purposes for short-lived tokens issued during a two-step login. They
+// are never accepted as access tokens.
+const (
+	PurposeMFAChallenge  = "mfa_challenge"
+	PurposeMFAEnrollment = "mfa_enrollment"
+)
+
 type Claims struct {
-	UserID   uint            `json:"user_id"`
-	Username string          `json:"username"`
-	Role     models.UserRole `json:"role"`
+	UserID    uint            `json:"user_id"`
+	Username  string          `json:"username"`
+	Role      models.UserRole `json:"role"`
+	SessionID uint            `json:"sid"`
+	Purpose   string          `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTService struct {
-	secretKey []byte
+	keys               *KeySet
+	accessTokenTTL     time.Duration
+	refreshTokenTTL    time.Duration
+	sessionMaxLifetime time.Duration
 }
 
 func NewJWTService(secretKey string) *JWTService {
+	return NewJWTServiceWithKeys(NewHMACKeySet(secretKey), DefaultAccessTokenTTL, DefaultRefreshTokenTTL, DefaultSessionMaxLifetime)
+}
+
+func NewJWTServiceWithKeys(keys *KeySet, accessTokenTTL, refreshTokenTTL, sessionMaxLifetime time.Duration) *JWTService {
 	return &JWTService{
-		secretKey: []byte(secretKey),
+		keys:               keys,
+		accessTokenTTL:     accessTokenTTL,
+		refreshTokenTTL:    refreshTokenTTL,
+		sessionMaxLifetime: sessionMaxLifetime,
 	}
 }
 
-func (j *JWTService) GenerateToken(user *models.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+func (j *JWTService) AccessTokenTTL() time.Duration {
+	return j.accessTokenTTL
+}
+
+func (j *JWTService) RefreshTokenTTL() time.Duration {
+	return j.refreshTokenTTL
+}
+
+func (j *JWTService) SessionMaxLifetime() time.Duration {
+	return j.sessionMaxLifetime
+}
+
+// GenerateToken issues a short-lived access token bound to the given session.
+func (j *JWTService) GenerateToken(user *models.User, sessionID uint) (string, error) {
+	return j.sign(&Claims{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "hospital-management-system",
+			Subject:   user.Username,
+		},
+	})
+}
+
+// GenerateMFAToken issues a token that only proves the password step of a
+// login succeeded, for use with the given MFA purpose. Its ID identifies the
+// challenge so failed attempts can be counted against it.
+func (j *JWTService) GenerateMFAToken(user *models.User, purpose string) (string, error) {
+	challengeID, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return "", err
+	}
 
-	claims := &Claims{
+	return j.sign(&Claims{
 		UserID:   user.ID,
 		Username: user.Username,
 		Role:     user.Role,
+		Purpose:  purpose,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			ID:        challengeID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(MFATokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "hospital-management-system",
 			Subject:   user.Username,
 		},
+	})
+}
+
+func (j *JWTService) sign(claims *Claims) (string, error) {
+	key, err := j.keys.SigningKey(time.Now())
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(j.secretKey)
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", err
 	}
@@ -51,13 +124,44 @@ func (j *JWTService) GenerateToken(user *models.User) (string, error) {
 }
 
 func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
+func (j *JWTService) ValidateMFAToken(tokenString string, purpose string) (*Claims, error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != purpose {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
+func (j *JWTService) parse(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, err := j.keys.VerificationKey(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("invalid signing method")
 		}
-		return j.secretKey, nil
+		return key.PublicKey, nil
 	})
 
 	if err != nil {
@@ -71,20 +175,7 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
-func (j *JWTService) RefreshToken(tokenString string) (string, error) {
-	claims, err := j.ValidateToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-
-	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(24 * time.Hour))
-	claims.IssuedAt = jwt.NewNumericDate(time.Now())
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	newTokenString, err := token.SignedString(j.secretKey)
-	if err != nil {
-		return "", err
-	}
-
-	return newTokenString, nil
+// JWKS returns the public keys other services can use to verify tokens.
+func (j *JWTService) JWKS() JWKSet {
+	return j.keys.JWKS(time.Now())
 }
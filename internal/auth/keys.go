@@ -0,0 +1,355 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyManifestFile is the file inside the keys directory that lists the
+// signing keys and their rotation schedule.
+const KeyManifestFile = "keys.json"
+
+// SigningKey is a single key in a KeySet. Keys without a private key are
+// only used to verify tokens issued before the key was rotated out.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey interface{}
+	PublicKey  interface{}
+	NotBefore  time.Time
+	RetireAt   time.Time
+}
+
+func (k *SigningKey) IsRetired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+func (k *SigningKey) CanSign(now time.Time) bool {
+	return k.PrivateKey != nil && !now.Before(k.NotBefore) && !k.IsRetired(now)
+}
+
+func (k *SigningKey) isSymmetric() bool {
+	_, ok := k.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+type keyManifest struct {
+	Keys []keyManifestEntry `json:"keys"`
+}
+
+type keyManifestEntry struct {
+	ID             string `json:"kid"`
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	PublicKeyFile  string `json:"public_key_file,omitempty"`
+	NotBefore      string `json:"not_before,omitempty"`
+	RetireAt       string `json:"retire_at,omitempty"`
+}
+
+// KeySet holds the keys used to sign and verify tokens. The newest key whose
+// NotBefore has passed signs new tokens; every key that is not yet retired
+// is accepted when validating and published in the JWKS document.
+type KeySet struct {
+	mu   sync.RWMutex
+	keys []*SigningKey
+	dir  string
+}
+
+func NewKeySet(keys ...*SigningKey) *KeySet {
+	keySet := &KeySet{}
+	keySet.setKeys(keys)
+	return keySet
+}
+
+// NewHMACKeySet wraps a shared secret for deployments that have not yet
+// moved to asymmetric keys. HMAC keys are never published in the JWKS.
+func NewHMACKeySet(secret string) *KeySet {
+	return NewKeySet(&SigningKey{
+		ID:         "hmac",
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: []byte(secret),
+		PublicKey:  []byte(secret),
+	})
+}
+
+// NewEphemeralKeySet generates an in-memory Ed25519 key. Tokens signed with
+// it become invalid when the process restarts.
+func NewEphemeralKeySet() (*KeySet, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySet(&SigningKey{
+		ID:         "ephemeral-" + time.Now().UTC().Format("20060102150405"),
+		Method:     jwt.SigningMethodEdDSA,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}), nil
+}
+
+// LoadKeySet reads the key manifest and PEM files from dir.
+func LoadKeySet(dir string) (*KeySet, error) {
+	keySet := &KeySet{dir: dir}
+	if err := keySet.Reload(); err != nil {
+		return nil, err
+	}
+	return keySet, nil
+}
+
+// Reload re-reads the keys directory so that newly added or retired keys take
+// effect without a restart. The current keys are kept if loading fails.
+func (s *KeySet) Reload() error {
+	if s.dir == "" {
+		return errors.New("key set was not loaded from a directory")
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, KeyManifestFile))
+	if err != nil {
+		return fmt.Errorf("failed to read key manifest: %w", err)
+	}
+
+	var manifest keyManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse key manifest: %w", err)
+	}
+
+	keys := make([]*SigningKey, 0, len(manifest.Keys))
+	seen := make(map[string]bool, len(manifest.Keys))
+	for _, entry := range manifest.Keys {
+		if entry.ID == "" {
+			return errors.New("key manifest entry is missing kid")
+		}
+		if seen[entry.ID] {
+			return fmt.Errorf("duplicate kid %q in key manifest", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		key, err := loadManifestKey(s.dir, entry)
+		if err != nil {
+			return fmt.Errorf("failed to load key %q: %w", entry.ID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return errors.New("key manifest does not contain any keys")
+	}
+
+	s.setKeys(keys)
+	return nil
+}
+
+// AutoReload reloads the key set every interval until stop is closed, so a
+// scheduled key added to the manifest is picked up before its NotBefore.
+// An interval of zero or less disables reloading.
+func (s *KeySet) AutoReload(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Reload(); err != nil {
+				log.Printf("Failed to reload JWT signing keys: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// SigningKey returns the most recently activated key that can sign at now.
+func (s *KeySet) SigningKey(now time.Time) (*SigningKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.CanSign(now) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no active signing key")
+}
+
+// VerificationKey returns the key identified by kid. Tokens without a kid
+// are checked against the current signing key.
+func (s *KeySet) VerificationKey(kid string, now time.Time) (*SigningKey, error) {
+	if kid == "" {
+		return s.SigningKey(now)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.ID == kid {
+			if key.IsRetired(now) {
+				return nil, errors.New("signing key has been retired")
+			}
+			return key, nil
+		}
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// JWKS returns the public keys that verifiers should currently accept.
+func (s *KeySet) JWKS(now time.Time) JWKSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jwks := JWKSet{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if key.isSymmetric() || key.IsRetired(now) {
+			continue
+		}
+		if jwk, ok := toJWK(key); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+func (s *KeySet) setKeys(keys []*SigningKey) {
+	sorted := make([]*SigningKey, len(keys))
+	copy(sorted, keys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].NotBefore.After(sorted[j].NotBefore)
+	})
+
+	s.mu.Lock()
+	s.keys = sorted
+	s.mu.Unlock()
+}
+
+func loadManifestKey(dir string, entry keyManifestEntry) (*SigningKey, error) {
+	key := &SigningKey{ID: entry.ID}
+
+	var err error
+	if entry.NotBefore != "" {
+		if key.NotBefore, err = time.Parse(time.RFC3339, entry.NotBefore); err != nil {
+			return nil, errors.New("invalid not_before, use RFC 3339")
+		}
+	}
+	if entry.RetireAt != "" {
+		if key.RetireAt, err = time.Parse(time.RFC3339, entry.RetireAt); err != nil {
+			return nil, errors.New("invalid retire_at, use RFC 3339")
+		}
+	}
+
+	switch {
+	case entry.PrivateKeyFile != "":
+		block, err := readPEM(filepath.Join(dir, entry.PrivateKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		if key.PrivateKey, err = parsePrivateKey(block); err != nil {
+			return nil, err
+		}
+		switch privateKey := key.PrivateKey.(type) {
+		case *rsa.PrivateKey:
+			key.PublicKey = &privateKey.PublicKey
+		case ed25519.PrivateKey:
+			key.PublicKey = privateKey.Public()
+		}
+	case entry.PublicKeyFile != "":
+		block, err := readPEM(filepath.Join(dir, entry.PublicKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		if key.PublicKey, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+
+	switch key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported key type, use RSA or Ed25519")
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in " + filepath.Base(path))
+	}
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (interface{}, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+// JWK is the public representation of a key as defined in RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func toJWK(key *SigningKey) (JWK, bool) {
+	jwk := JWK{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Method.Alg(),
+	}
+
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
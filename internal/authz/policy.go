@@ -0,0 +1,289 @@
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"hospital-management-system/internal/models"
+)
+
+// Permission names an action that a role may be granted. Permissions are
+// dot-separated so that a policy can grant a whole area with a wildcard
+// such as "patient.*".
+type Permission string
+
+const (
+	PatientRead         Permission = "patient.read"
+	PatientCreate       Permission = "patient.create"
+	PatientUpdate       Permission = "patient.update"
+	PatientMedicalWrite Permission = "patient.medical.write"
+	PatientDelete       Permission = "patient.delete"
+	PatientHistoryRead  Permission = "patient.history.read"
+	PatientHistoryDiff  Permission = "patient.history.diff"
+	PatientMerge        Permission = "patient.merge"
+	AccessLogRead       Permission = "access_log.read"
+	UserManage          Permission = "user.manage"
+	MFAPolicyManage     Permission = "mfa_policy.manage"
+
+	// AppointmentRead lets a user see their own schedule; AppointmentReadAll
+	// extends that to every doctor's schedule. AppointmentComplete also marks
+	// a role as one that patients can be booked with.
+	AppointmentRead     Permission = "appointment.read"
+	AppointmentReadAll  Permission = "appointment.read_all"
+	AppointmentManage   Permission = "appointment.manage"
+	AppointmentComplete Permission = "appointment.complete"
+
+	// AvailabilityManage lets a doctor edit their own working hours;
+	// AvailabilityManageAll extends that to every doctor.
+	AvailabilityRead      Permission = "availability.read"
+	AvailabilityManage    Permission = "availability.manage"
+	AvailabilityManageAll Permission = "availability.manage_all"
+
+	EncounterRead  Permission = "encounter.read"
+	EncounterWrite Permission = "encounter.write"
+
+	AllergyRead  Permission = "allergy.read"
+	AllergyWrite Permission = "allergy.write"
+
+	MedicationRead          Permission = "medication.read"
+	MedicationCatalogManage Permission = "medication.catalog.manage"
+	PrescriptionRead        Permission = "prescription.read"
+	PrescriptionWrite       Permission = "prescription.write"
+
+	ProblemRead     Permission = "problem.read"
+	ProblemWrite    Permission = "problem.write"
+	ICD10CodeRead   Permission = "icd10.read"
+	ICD10CodeImport Permission = "icd10.import"
+
+	VitalSignsRead  Permission = "vital_signs.read"
+	VitalSignsWrite Permission = "vital_signs.write"
+
+	// LabOrderWrite covers ordering and cancelling tests; LabResultWrite
+	// covers specimen collection and result entry, so it can be granted to
+	// laboratory staff on its own.
+	LabOrderRead   Permission = "lab_order.read"
+	LabOrderWrite  Permission = "lab_order.write"
+	LabResultWrite Permission = "lab_result.write"
+
+	// WardRead shows the bed board; patients on it are only shown to roles
+	// that also hold AdmissionRead.
+	WardRead       Permission = "ward.read"
+	WardManage     Permission = "ward.manage"
+	AdmissionRead  Permission = "admission.read"
+	AdmissionWrite Permission = "admission.write"
+
+	// EDVisitTriage covers check-in, re-triage and doctor assignment in the
+	// emergency department; EDVisitTreat covers seeing and discharging
+	// patients from it.
+	EDVisitRead   Permission = "ed_visit.read"
+	EDVisitTriage Permission = "ed_visit.triage"
+	EDVisitTreat  Permission = "ed_visit.treat"
+
+	// Billing permissions are kept under "billing." and are not granted to
+	// doctors, who should not see financial data.
+	PriceListRead   Permission = "billing.price_list.read"
+	PriceListManage Permission = "billing.price_list.manage"
+	InvoiceRead     Permission = "billing.invoice.read"
+	InvoiceWrite    Permission = "billing.invoice.write"
+	PaymentWrite    Permission = "billing.payment.write"
+
+	InsurancePayerRead   Permission = "billing.payer.read"
+	InsurancePayerManage Permission = "billing.payer.manage"
+	InsurancePolicyRead  Permission = "billing.insurance_policy.read"
+	InsurancePolicyWrite Permission = "billing.insurance_policy.write"
+	ClaimRead            Permission = "billing.claim.read"
+	ClaimWrite           Permission = "billing.claim.write"
+)
+
+// ErrForbidden is returned by services when the caller's role lacks the
+// permission for an action.
+var ErrForbidden = errors.New("insufficient permissions")
+
+// Policy maps roles to the permissions they hold. A role that is not in the
+// policy has no permissions and cannot be assigned to users.
+type Policy struct {
+	roles map[models.UserRole][]Permission
+}
+
+type policyFile struct {
+	Roles map[models.UserRole][]Permission `json:"roles"`
+}
+
+// DefaultPolicy is used when no policy file is configured. It covers the
+// built-in receptionist, doctor and admin roles.
+func DefaultPolicy() *Policy {
+	return NewPolicy(map[models.UserRole][]Permission{
+		models.RoleReceptionist: {
+			PatientRead,
+			PatientCreate,
+			PatientUpdate,
+			PatientDelete,
+			PatientHistoryRead,
+			AppointmentRead,
+			AppointmentReadAll,
+			AppointmentManage,
+			AvailabilityRead,
+			AvailabilityManage,
+			AvailabilityManageAll,
+			AllergyRead,
+			MedicationRead,
+			PrescriptionRead,
+			VitalSignsRead,
+			VitalSignsWrite,
+			WardRead,
+			AdmissionRead,
+			AdmissionWrite,
+			EDVisitRead,
+			EDVisitTriage,
+			PriceListRead,
+			InvoiceRead,
+			InvoiceWrite,
+			PaymentWrite,
+			InsurancePayerRead,
+			InsurancePolicyRead,
+			InsurancePolicyWrite,
+			ClaimRead,
+			ClaimWrite,
+		},
+		models.RoleDoctor: {
+			PatientRead,
+			PatientUpdate,
+			PatientMedicalWrite,
+			PatientHistoryRead,
+			PatientHistoryDiff,
+			PatientMerge,
+			AppointmentRead,
+			AppointmentComplete,
+			AvailabilityRead,
+			AvailabilityManage,
+			EncounterRead,
+			EncounterWrite,
+			AllergyRead,
+			AllergyWrite,
+			MedicationRead,
+			PrescriptionRead,
+			PrescriptionWrite,
+			ProblemRead,
+			ProblemWrite,
+			ICD10CodeRead,
+			VitalSignsRead,
+			VitalSignsWrite,
+			LabOrderRead,
+			LabOrderWrite,
+			LabResultWrite,
+			WardRead,
+			AdmissionRead,
+			AdmissionWrite,
+			EDVisitRead,
+			EDVisitTriage,
+			EDVisitTreat,
+		},
+		models.RoleAdmin: {
+			PatientMerge,
+			AccessLogRead,
+			UserManage,
+			MFAPolicyManage,
+			MedicationRead,
+			MedicationCatalogManage,
+			ICD10CodeRead,
+			ICD10CodeImport,
+			WardRead,
+			WardManage,
+			PriceListRead,
+			PriceListManage,
+			InsurancePayerRead,
+			InsurancePayerManage,
+		},
+	})
+}
+
+func NewPolicy(roles map[models.UserRole][]Permission) *Policy {
+	policy := &Policy{roles: make(map[models.UserRole][]Permission, len(roles))}
+	for role, permissions := range roles {
+		policy.roles[role] = append([]Permission(nil), permissions...)
+	}
+	return policy
+}
+
+// LoadPolicy reads a policy from a JSON file of the form
+//
+//	{"roles": {"nurse": ["patient.read", "patient.history.read"]}}
+//
+// The file replaces the default policy entirely, so it must list every role.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	if len(file.Roles) == 0 {
+		return nil, errors.New("policy file does not define any roles")
+	}
+
+	for role, permissions := range file.Roles {
+		if strings.TrimSpace(string(role)) == "" {
+			return nil, errors.New("policy file contains an empty role name")
+		}
+		for _, permission := range permissions {
+			if strings.TrimSpace(string(permission)) == "" {
+				return nil, fmt.Errorf("role %q has an empty permission", role)
+			}
+		}
+	}
+
+	return NewPolicy(file.Roles), nil
+}
+
+// Can reports whether role holds permission. A grant of "*" matches every
+// permission and "area.*" matches every permission under "area.".
+func (p *Policy) Can(role models.UserRole, permission Permission) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, granted := range p.roles[role] {
+		if granted == permission || granted == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(string(granted), ".*"); ok && strings.HasPrefix(string(permission), prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize is the service-level counterpart of the RequirePermission
+// middleware.
+func (p *Policy) Authorize(role models.UserRole, permission Permission) error {
+	if !p.Can(role, permission) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (p *Policy) HasRole(role models.UserRole) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.roles[role]
+	return ok
+}
+
+// Roles returns the roles defined by the policy in alphabetical order.
+func (p *Policy) Roles() []models.UserRole {
+	roles := make([]models.UserRole, 0, len(p.roles))
+	for role := range p.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	return roles
+}
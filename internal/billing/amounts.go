@@ -0,0 +1,59 @@
+package billing
+
+import (
+	"fmt"
+	"math"
+)
+
+// Amounts are kept in minor currency units (cents) so that totals add up
+// exactly; percentages are applied per line and rounded half away from
+// zero.
+
+// Line is the priced part of an invoice line.
+type Line struct {
+	Quantity        int
+	UnitPriceCents  int64
+	DiscountPercent float64
+	TaxRatePercent  float64
+}
+
+// LineAmounts breaks a line down into its gross amount, discount, tax and
+// total. Tax is charged on the discounted amount.
+type LineAmounts struct {
+	GrossCents    int64
+	DiscountCents int64
+	TaxCents      int64
+	TotalCents    int64
+}
+
+func (l Line) Amounts() LineAmounts {
+	gross := int64(l.Quantity) * l.UnitPriceCents
+	discount := percentOf(gross, l.DiscountPercent)
+	tax := percentOf(gross-discount, l.TaxRatePercent)
+
+	return LineAmounts{
+		GrossCents:    gross,
+		DiscountCents: discount,
+		TaxCents:      tax,
+		TotalCents:    gross - discount + tax,
+	}
+}
+
+func percentOf(cents int64, percent float64) int64 {
+	return int64(math.Round(float64(cents) * percent / 100))
+}
+
+// FormatCents renders an amount such as 123456 as "1,234.56".
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	whole := fmt.Sprintf("%d", cents/100)
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, whole, cents%100)
+}
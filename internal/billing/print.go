@@ -0,0 +1,78 @@
+package billing
+
+import (
+	"bytes"
+	"html/template"
+	"time"
+
+	"hospital-management-system/internal/models"
+)
+
+// PrintOptions are the deployment details shown on printed invoices.
+type PrintOptions struct {
+	HospitalName string
+	Currency     string
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": FormatCents,
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Invoice.InvoiceNumber}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+td.amount, th.amount { text-align: right; }
+.totals td { border: none; }
+</style>
+</head>
+<body>
+<h1>{{.HospitalName}}</h1>
+<h2>Invoice {{.Invoice.InvoiceNumber}}{{if eq .Invoice.Status "draft"}} (draft){{end}}{{if eq .Invoice.Status "void"}} (void){{end}}</h2>
+<p>
+{{with .Invoice.Patient}}Patient: {{.FirstName}} {{.LastName}} ({{.PatientID}})<br>{{end}}
+{{with .Invoice.IssuedAt}}Issued: {{date .}}<br>{{end}}
+{{with .Invoice.DueDate}}Due: {{date .}}<br>{{end}}
+</p>
+<table>
+<thead>
+<tr><th>Code</th><th>Description</th><th class="amount">Qty</th><th class="amount">Unit price</th><th class="amount">Discount</th><th class="amount">Tax</th><th class="amount">Total</th></tr>
+</thead>
+<tbody>
+{{range .Invoice.Items}}<tr><td>{{.Code}}</td><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{money .UnitPriceCents}}</td><td class="amount">{{money .DiscountCents}}</td><td class="amount">{{money .TaxCents}}</td><td class="amount">{{money .TotalCents}}</td></tr>
+{{end}}</tbody>
+</table>
+<table class="totals">
+<tr><td class="amount">Subtotal</td><td class="amount">{{.Currency}} {{money .Invoice.SubtotalCents}}</td></tr>
+<tr><td class="amount">Discounts</td><td class="amount">-{{.Currency}} {{money .Invoice.DiscountCents}}</td></tr>
+<tr><td class="amount">Tax</td><td class="amount">{{.Currency}} {{money .Invoice.TaxCents}}</td></tr>
+<tr><td class="amount"><strong>Total</strong></td><td class="amount"><strong>{{.Currency}} {{money .Invoice.TotalCents}}</strong></td></tr>
+<tr><td class="amount">Paid</td><td class="amount">{{.Currency}} {{money .Invoice.NetPaidCents}}</td></tr>
+<tr><td class="amount"><strong>Balance due</strong></td><td class="amount"><strong>{{.Currency}} {{money .Invoice.BalanceCents}}</strong></td></tr>
+</table>
+{{with .Invoice.Notes}}<p>{{.}}</p>{{end}}
+</body>
+</html>
+`))
+
+// RenderInvoice renders an invoice as a printable HTML page.
+func RenderInvoice(invoice *models.Invoice, options PrintOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	err := invoiceTemplate.Execute(&buf, struct {
+		PrintOptions
+		Invoice *models.Invoice
+	}{options, invoice})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,254 @@
+package billing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/models"
+)
+
+// ClaimFormat837P is the format of claim batch files: ANSI X12 837
+// professional claims, version 005010X222A1.
+const ClaimFormat837P = "x12-837p"
+
+// ClaimSubmitter identifies the hospital as submitter and billing provider
+// in claim files, and the clearinghouse the files are sent to.
+type ClaimSubmitter struct {
+	Name         string
+	SubmitterID  string
+	ContactPhone string
+	ReceiverName string
+	ReceiverID   string
+	NPI          string
+	TaxID        string
+	Address      string
+	City         string
+	State        string
+	PostalCode   string
+}
+
+// maxDiagnosisCodes is the number of diagnoses an 837P claim can carry.
+const maxDiagnosisCodes = 12
+
+var x12Escaper = strings.NewReplacer("*", " ", "~", " ", ":", " ", "^", " ", "\n", " ", "\r", " ")
+
+// RenderClaims837 writes the claims as one X12 837P interchange. The claims
+// need their patient, invoice items and policy with payer loaded. The
+// batch ID is used as the interchange control number.
+func RenderClaims837(batch *models.ClaimBatch, claims []*models.Claim, submitter ClaimSubmitter, now time.Time) (string, error) {
+	if len(claims) == 0 {
+		return "", errors.New("claim batch is empty")
+	}
+
+	w := &x12Writer{}
+	control := batch.ID % 1000000000
+
+	w.raw(fmt.Sprintf("ISA*00*%-10s*00*%-10s*ZZ*%-15s*ZZ*%-15s*%s*%s*^*00501*%09d*0*P*:",
+		"", "", element(submitter.SubmitterID), element(submitter.ReceiverID), now.Format("060102"), now.Format("1504"), control))
+	w.raw(fmt.Sprintf("GS*HC*%s*%s*%s*%s*%d*X*005010X222A1",
+		element(submitter.SubmitterID), element(submitter.ReceiverID), now.Format("20060102"), now.Format("1504"), control))
+
+	w.begin()
+	w.segment("ST", "837", "0001", "005010X222A1")
+	w.segment("BHT", "0019", "00", batch.BatchNumber, now.Format("20060102"), now.Format("1504"), "CH")
+	w.segment("NM1", "41", "2", submitter.Name, "", "", "", "", "46", submitter.SubmitterID)
+	w.segment("PER", "IC", submitter.Name, "TE", digits(submitter.ContactPhone))
+	w.segment("NM1", "40", "2", submitter.ReceiverName, "", "", "", "", "46", submitter.ReceiverID)
+
+	w.segment("HL", "1", "", "20", "1")
+	w.segment("NM1", "85", "2", submitter.Name, "", "", "", "", "XX", submitter.NPI)
+	w.segment("N3", submitter.Address)
+	w.segment("N4", submitter.City, submitter.State, digits(submitter.PostalCode))
+	w.segment("REF", "EI", digits(submitter.TaxID))
+
+	hl := 1
+	for _, claim := range claims {
+		if err := writeClaim(w, claim, &hl); err != nil {
+			return "", err
+		}
+	}
+
+	w.segment("SE", fmt.Sprintf("%d", w.count+1), "0001")
+	w.raw(fmt.Sprintf("GE*1*%d", control))
+	w.raw(fmt.Sprintf("IEA*1*%09d", control))
+
+	return w.String(), nil
+}
+
+func writeClaim(w *x12Writer, claim *models.Claim, hl *int) error {
+	if claim.Patient == nil || claim.Invoice == nil || claim.Policy == nil || claim.Policy.Payer == nil {
+		return fmt.Errorf("claim %s is missing its patient, invoice or policy", claim.ClaimNumber)
+	}
+	if len(claim.DiagnosisCodes) == 0 {
+		return fmt.Errorf("claim %s has no diagnosis codes", claim.ClaimNumber)
+	}
+
+	patient := claim.Patient
+	policy := claim.Policy
+	self := policy.SubscriberRelationship == models.SubscriberRelationshipSelf
+
+	*hl++
+	subscriberHL := *hl
+	child := "0"
+	if !self {
+		child = "1"
+	}
+	w.segment("HL", fmt.Sprintf("%d", subscriberHL), "1", "22", child)
+
+	priority := "P"
+	if policy.Priority == models.CoveragePrioritySecondary {
+		priority = "S"
+	}
+	relationship := ""
+	if self {
+		relationship = "18"
+	}
+	w.segment("SBR", priority, relationship, policy.GroupNumber, "", "", "", "", "", "CI")
+
+	if self {
+		w.segment("NM1", "IL", "1", patient.LastName, patient.FirstName, "", "", "", "MI", policy.PolicyNumber)
+		w.segment("DMG", "D8", patient.DateOfBirth.Format("20060102"), genderCode(patient.Gender))
+	} else {
+		last, first := splitName(policy.SubscriberName)
+		w.segment("NM1", "IL", "1", last, first, "", "", "", "MI", policy.PolicyNumber)
+	}
+	w.segment("NM1", "PR", "2", policy.Payer.Name, "", "", "", "", "PI", policy.Payer.PayerCode)
+
+	if !self {
+		*hl++
+		w.segment("HL", fmt.Sprintf("%d", *hl), fmt.Sprintf("%d", subscriberHL), "23", "0")
+		w.segment("PAT", patientRelationshipCode(policy.SubscriberRelationship))
+		w.segment("NM1", "QC", "1", patient.LastName, patient.FirstName)
+		w.segment("DMG", "D8", patient.DateOfBirth.Format("20060102"), genderCode(patient.Gender))
+	}
+
+	placeOfService := "11"
+	if claim.Invoice.AdmissionID != nil {
+		placeOfService = "21"
+	}
+	frequency := "1"
+	if claim.PayerClaimRef != "" {
+		frequency = "7"
+	}
+	w.rawSegment("CLM", element(claim.ClaimNumber), dollars(claim.BilledCents), "", "", placeOfService+":B:"+frequency, "Y", "A", "Y", "Y")
+	if claim.PayerClaimRef != "" {
+		w.segment("REF", "F8", claim.PayerClaimRef)
+	}
+
+	codes := claim.DiagnosisCodes
+	if len(codes) > maxDiagnosisCodes {
+		codes = codes[:maxDiagnosisCodes]
+	}
+	diagnoses := make([]string, len(codes))
+	for i, code := range codes {
+		qualifier := "ABF"
+		if i == 0 {
+			qualifier = "ABK"
+		}
+		diagnoses[i] = qualifier + ":" + element(strings.ReplaceAll(code, ".", ""))
+	}
+	w.rawSegment("HI", diagnoses...)
+
+	pointers := make([]string, 0, 4)
+	for i := 0; i < len(codes) && i < 4; i++ {
+		pointers = append(pointers, fmt.Sprintf("%d", i+1))
+	}
+
+	serviceDate := claim.ServiceDate.Format("20060102")
+	for i, item := range claim.Invoice.Items {
+		w.segment("LX", fmt.Sprintf("%d", i+1))
+		w.rawSegment("SV1", "HC:"+element(item.Code), dollars(item.TotalCents), "UN", fmt.Sprintf("%d", item.Quantity), "", "", strings.Join(pointers, ":"))
+		w.segment("DTP", "472", "D8", serviceDate)
+	}
+	return nil
+}
+
+// x12Writer collects segments and counts those between ST and SE.
+type x12Writer struct {
+	b        strings.Builder
+	counting bool
+	count    int
+}
+
+func (w *x12Writer) begin() {
+	w.counting = true
+}
+
+func (w *x12Writer) raw(segment string) {
+	w.b.WriteString(segment)
+	w.b.WriteString("~\n")
+}
+
+// segment escapes every element and drops trailing empty ones.
+func (w *x12Writer) segment(id string, elements ...string) {
+	escaped := make([]string, len(elements))
+	for i, value := range elements {
+		escaped[i] = element(value)
+	}
+	w.rawSegment(id, escaped...)
+}
+
+// rawSegment writes elements that are already escaped, such as composites.
+func (w *x12Writer) rawSegment(id string, elements ...string) {
+	for len(elements) > 0 && elements[len(elements)-1] == "" {
+		elements = elements[:len(elements)-1]
+	}
+	w.raw(strings.Join(append([]string{id}, elements...), "*"))
+	if w.counting {
+		w.count++
+	}
+}
+
+func (w *x12Writer) String() string {
+	return w.b.String()
+}
+
+func element(value string) string {
+	return strings.ToUpper(strings.TrimSpace(x12Escaper.Replace(value)))
+}
+
+func digits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+func dollars(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+func genderCode(gender models.Gender) string {
+	switch gender {
+	case models.GenderMale:
+		return "M"
+	case models.GenderFemale:
+		return "F"
+	default:
+		return "U"
+	}
+}
+
+func patientRelationshipCode(relationship models.SubscriberRelationship) string {
+	switch relationship {
+	case models.SubscriberRelationshipSpouse:
+		return "01"
+	case models.SubscriberRelationshipChild:
+		return "19"
+	default:
+		return "G8"
+	}
+}
+
+// splitName takes the last word of a free-text name as the last name.
+func splitName(name string) (last, first string) {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	return fields[len(fields)-1], strings.Join(fields[:len(fields)-1], " ")
+}
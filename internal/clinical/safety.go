@@ -0,0 +1,373 @@
+package clinical
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"hospital-management-system/internal/models"
+)
+
+// AlertLevel says whether a safety alert stops prescribing outright or can
+// be overridden by the prescriber with a documented reason.
+type AlertLevel string
+
+const (
+	AlertLevelBlocking AlertLevel = "blocking"
+	AlertLevelWarning  AlertLevel = "warning"
+)
+
+type AlertKind string
+
+const (
+	AlertKindDrugAllergy     AlertKind = "drug_allergy"
+	AlertKindDrugInteraction AlertKind = "drug_interaction"
+)
+
+// Alert is a single finding of the safety checker. AllergyID or
+// PrescriptionID points at the record the new medication conflicts with.
+type Alert struct {
+	Kind           AlertKind  `json:"kind"`
+	Level          AlertLevel `json:"level"`
+	Description    string     `json:"description"`
+	AllergyID      *uint      `json:"allergy_id,omitempty"`
+	PrescriptionID *uint      `json:"prescription_id,omitempty"`
+}
+
+// AllergyClass groups drugs that a patient allergic to one of them is
+// likely to react to. Members are matched against medication names,
+// generic names and drug classes, and against the allergy substance.
+type AllergyClass struct {
+	Name          string          `json:"name"`
+	Members       []string        `json:"members"`
+	Level         AlertLevel      `json:"level"`
+	CrossReactive []CrossReaction `json:"cross_reactive,omitempty"`
+}
+
+// CrossReaction flags drugs of another class that are known to cross-react
+// with an allergy class, usually at a lower level than the class itself.
+type CrossReaction struct {
+	Class string     `json:"class"`
+	Level AlertLevel `json:"level"`
+}
+
+// Interaction is a drug-drug interaction between two drugs or drug classes.
+type Interaction struct {
+	Drugs       [2]string  `json:"drugs"`
+	Level       AlertLevel `json:"level"`
+	Description string     `json:"description"`
+}
+
+// Rules is the allergy-class and interaction table used by the checker.
+type Rules struct {
+	allergyClasses []AllergyClass
+	classIndex     map[string]int
+	interactions   []Interaction
+}
+
+type rulesFile struct {
+	AllergyClasses []AllergyClass `json:"allergy_classes"`
+	Interactions   []Interaction  `json:"interactions"`
+}
+
+// DefaultRules is used when no rules file is configured. It only covers a
+// handful of well-known checks; deployments are expected to load their own
+// table.
+func DefaultRules() *Rules {
+	rules, err := NewRules([]AllergyClass{
+		{
+			Name:    "penicillin",
+			Members: []string{"penicillin", "amoxicillin", "ampicillin", "flucloxacillin", "piperacillin"},
+			Level:   AlertLevelBlocking,
+			CrossReactive: []CrossReaction{
+				{Class: "cephalosporin", Level: AlertLevelWarning},
+			},
+		},
+		{
+			Name:    "cephalosporin",
+			Members: []string{"cephalosporin", "cefalexin", "cephalexin", "ceftriaxone", "cefuroxime"},
+			Level:   AlertLevelBlocking,
+		},
+		{
+			Name:    "sulfonamide",
+			Members: []string{"sulfonamide", "sulfamethoxazole", "sulfasalazine"},
+			Level:   AlertLevelBlocking,
+		},
+		{
+			Name:    "nsaid",
+			Members: []string{"nsaid", "ibuprofen", "naproxen", "diclofenac", "aspirin"},
+			Level:   AlertLevelWarning,
+		},
+	}, []Interaction{
+		{Drugs: [2]string{"warfarin", "aspirin"}, Level: AlertLevelWarning, Description: "increased risk of bleeding"},
+		{Drugs: [2]string{"warfarin", "nsaid"}, Level: AlertLevelWarning, Description: "increased risk of bleeding"},
+		{Drugs: [2]string{"ssri", "maoi"}, Level: AlertLevelBlocking, Description: "risk of serotonin syndrome"},
+		{Drugs: [2]string{"sildenafil", "nitrate"}, Level: AlertLevelBlocking, Description: "risk of severe hypotension"},
+		{Drugs: [2]string{"simvastatin", "clarithromycin"}, Level: AlertLevelBlocking, Description: "risk of rhabdomyolysis"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// NewRules validates and normalizes an allergy-class and interaction table.
+// Names are compared case-insensitively.
+func NewRules(allergyClasses []AllergyClass, interactions []Interaction) (*Rules, error) {
+	rules := &Rules{classIndex: make(map[string]int, len(allergyClasses))}
+
+	for _, class := range allergyClasses {
+		class.Name = normalize(class.Name)
+		if class.Name == "" {
+			return nil, errors.New("allergy class has an empty name")
+		}
+		if _, exists := rules.classIndex[class.Name]; exists {
+			return nil, fmt.Errorf("allergy class %q is defined twice", class.Name)
+		}
+		if !validLevel(class.Level) {
+			return nil, fmt.Errorf("allergy class %q has an invalid level %q", class.Name, class.Level)
+		}
+		class.Members = normalizeAll(append([]string{class.Name}, class.Members...))
+		class.CrossReactive = append([]CrossReaction(nil), class.CrossReactive...)
+		rules.classIndex[class.Name] = len(rules.allergyClasses)
+		rules.allergyClasses = append(rules.allergyClasses, class)
+	}
+
+	for _, class := range rules.allergyClasses {
+		for i := range class.CrossReactive {
+			cross := &class.CrossReactive[i]
+			cross.Class = normalize(cross.Class)
+			if _, ok := rules.classIndex[cross.Class]; !ok {
+				return nil, fmt.Errorf("allergy class %q cross-reacts with unknown class %q", class.Name, cross.Class)
+			}
+			if !validLevel(cross.Level) {
+				return nil, fmt.Errorf("allergy class %q has an invalid cross-reaction level %q", class.Name, cross.Level)
+			}
+		}
+	}
+
+	for _, interaction := range interactions {
+		interaction.Drugs[0] = normalize(interaction.Drugs[0])
+		interaction.Drugs[1] = normalize(interaction.Drugs[1])
+		if interaction.Drugs[0] == "" || interaction.Drugs[1] == "" {
+			return nil, errors.New("interaction must name two drugs")
+		}
+		if !validLevel(interaction.Level) {
+			return nil, fmt.Errorf("interaction between %q and %q has an invalid level %q", interaction.Drugs[0], interaction.Drugs[1], interaction.Level)
+		}
+		rules.interactions = append(rules.interactions, interaction)
+	}
+
+	return rules, nil
+}
+
+// LoadRules reads a rules table from a JSON file of the form
+//
+//	{
+//	  "allergy_classes": [{"name": "penicillin", "members": ["amoxicillin"], "level": "blocking",
+//	                       "cross_reactive": [{"class": "cephalosporin", "level": "warning"}]}],
+//	  "interactions": [{"drugs": ["warfarin", "aspirin"], "level": "warning", "description": "bleeding"}]
+//	}
+//
+// The file replaces the default rules entirely.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read safety rules file: %w", err)
+	}
+
+	var file rulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse safety rules file: %w", err)
+	}
+
+	return NewRules(file.AllergyClasses, file.Interactions)
+}
+
+// Check compares a medication about to be prescribed with the patient's
+// active allergies and current prescriptions.
+func (r *Rules) Check(medication *models.Medication, allergies []*models.Allergy, current []*models.Prescription) []Alert {
+	drug := medicationTerms(medication)
+	var alerts []Alert
+
+	for _, allergy := range allergies {
+		if allergy.Status != models.AllergyStatusActive {
+			continue
+		}
+		if alert, ok := r.checkAllergy(medication.Name, drug, allergy); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	for _, prescription := range current {
+		if alert, ok := r.checkInteraction(medication.Name, drug, prescription); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts
+}
+
+// checkInteraction returns the most severe interaction between the drug and
+// a current prescription. Several rules can match one pair, for example one
+// on the drug itself and one on its class.
+func (r *Rules) checkInteraction(name string, drug []string, prescription *models.Prescription) (Alert, bool) {
+	other := prescriptionTerms(prescription)
+
+	var best *Alert
+	for _, interaction := range r.interactions {
+		if (matches(drug, interaction.Drugs[0]) && matches(other, interaction.Drugs[1])) ||
+			(matches(drug, interaction.Drugs[1]) && matches(other, interaction.Drugs[0])) {
+			best = stronger(best, &Alert{
+				Kind:           AlertKindDrugInteraction,
+				Level:          interaction.Level,
+				Description:    fmt.Sprintf("%s interacts with current medication %s: %s", name, prescription.MedicationName, interaction.Description),
+				PrescriptionID: &prescription.ID,
+			})
+		}
+	}
+
+	if best == nil {
+		return Alert{}, false
+	}
+	return *best, true
+}
+
+func (r *Rules) checkAllergy(name string, drug []string, allergy *models.Allergy) (Alert, bool) {
+	allergen := allergyTerms(allergy)
+	alert := Alert{Kind: AlertKindDrugAllergy, AllergyID: &allergy.ID}
+	label := allergy.Substance
+	if allergy.IsLegacy {
+		label = allergy.Notes
+	}
+
+	// A named allergy to the drug itself always blocks.
+	if !allergy.IsLegacy && matches(drug, normalize(allergy.Substance)) {
+		alert.Level = AlertLevelBlocking
+		alert.Description = fmt.Sprintf("Patient is allergic to %s", label)
+		return alert, true
+	}
+
+	var best *Alert
+	for _, class := range r.allergyClasses {
+		if !matchesAny(allergen, class.Members) {
+			continue
+		}
+		if matchesAny(drug, class.Members) {
+			candidate := alert
+			candidate.Level = class.Level
+			candidate.Description = fmt.Sprintf("%s belongs to the %s class; patient has a recorded allergy to %s", name, class.Name, label)
+			best = stronger(best, &candidate)
+			continue
+		}
+		for _, cross := range class.CrossReactive {
+			if matchesAny(drug, r.allergyClasses[r.classIndex[cross.Class]].Members) {
+				candidate := alert
+				candidate.Level = cross.Level
+				candidate.Description = fmt.Sprintf("%s (%s) may cross-react with the recorded %s allergy to %s", name, cross.Class, class.Name, label)
+				best = stronger(best, &candidate)
+			}
+		}
+	}
+
+	if best == nil {
+		return Alert{}, false
+	}
+	return *best, true
+}
+
+// HasBlocking reports whether any alert cannot be overridden.
+func HasBlocking(alerts []Alert) bool {
+	for _, alert := range alerts {
+		if alert.Level == AlertLevelBlocking {
+			return true
+		}
+	}
+	return false
+}
+
+func stronger(current, candidate *Alert) *Alert {
+	if current == nil || (current.Level == AlertLevelWarning && candidate.Level == AlertLevelBlocking) {
+		return candidate
+	}
+	return current
+}
+
+// medicationTerms lists the lower-cased texts a table entry is matched
+// against for a catalog medication.
+func medicationTerms(medication *models.Medication) []string {
+	return normalizeAll([]string{medication.Name, medication.GenericName, medication.DrugClass})
+}
+
+// prescriptionTerms falls back to the free-text instructions for legacy
+// prescriptions, which carry no catalog reference.
+func prescriptionTerms(prescription *models.Prescription) []string {
+	if prescription.Medication != nil {
+		return medicationTerms(prescription.Medication)
+	}
+	terms := []string{prescription.MedicationName}
+	if prescription.IsLegacy {
+		terms = append(terms, prescription.Instructions)
+	}
+	return normalizeAll(terms)
+}
+
+// allergyTerms uses the free-text note for legacy allergies, whose
+// substance is only a placeholder.
+func allergyTerms(allergy *models.Allergy) []string {
+	if allergy.IsLegacy {
+		return normalizeAll([]string{allergy.Notes})
+	}
+	return normalizeAll([]string{allergy.Substance})
+}
+
+// matches reports whether term appears as a whole word in any of texts.
+func matches(texts []string, term string) bool {
+	if term == "" {
+		return false
+	}
+	for _, text := range texts {
+		for _, word := range strings.FieldsFunc(text, isSeparator) {
+			if word == term || strings.TrimSuffix(word, "s") == term {
+				return true
+			}
+		}
+		if strings.Contains(term, " ") && strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(texts []string, terms []string) bool {
+	for _, term := range terms {
+		if matches(texts, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSeparator(r rune) bool {
+	return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-'
+}
+
+func validLevel(level AlertLevel) bool {
+	return level == AlertLevelBlocking || level == AlertLevelWarning
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func normalizeAll(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = normalize(value); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
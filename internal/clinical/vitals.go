@@ -0,0 +1,107 @@
+package clinical
+
+import "hospital-management-system/internal/models"
+
+// ReferenceRange is the inclusive normal range of a vital sign.
+type ReferenceRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// vitalRangeBand holds the reference ranges for patients younger than
+// MaxAge years. Bands are ordered by age.
+type vitalRangeBand struct {
+	MaxAge int
+	Ranges map[string]ReferenceRange
+}
+
+// Paediatric heart rate, respiratory rate and blood pressure ranges follow
+// the usual resuscitation guideline tables. Temperature and SpO2 do not
+// depend on age. BMI is only flagged for adults, as children need
+// percentile charts.
+var vitalRangeBands = []vitalRangeBand{
+	{MaxAge: 1, Ranges: map[string]ReferenceRange{
+		"heart_rate": {100, 160}, "respiratory_rate": {30, 60}, "systolic_bp": {70, 100}, "diastolic_bp": {50, 70},
+	}},
+	{MaxAge: 3, Ranges: map[string]ReferenceRange{
+		"heart_rate": {90, 150}, "respiratory_rate": {24, 40}, "systolic_bp": {86, 106}, "diastolic_bp": {42, 63},
+	}},
+	{MaxAge: 6, Ranges: map[string]ReferenceRange{
+		"heart_rate": {80, 140}, "respiratory_rate": {22, 34}, "systolic_bp": {89, 112}, "diastolic_bp": {46, 72},
+	}},
+	{MaxAge: 13, Ranges: map[string]ReferenceRange{
+		"heart_rate": {70, 120}, "respiratory_rate": {18, 30}, "systolic_bp": {97, 120}, "diastolic_bp": {57, 80},
+	}},
+	{MaxAge: 18, Ranges: map[string]ReferenceRange{
+		"heart_rate": {60, 100}, "respiratory_rate": {12, 20}, "systolic_bp": {110, 131}, "diastolic_bp": {64, 83},
+	}},
+}
+
+var adultVitalRanges = map[string]ReferenceRange{
+	"heart_rate":       {60, 100},
+	"respiratory_rate": {12, 20},
+	"systolic_bp":      {90, 139},
+	"diastolic_bp":     {60, 89},
+	"bmi":              {18.5, 24.9},
+}
+
+var ageIndependentVitalRanges = map[string]ReferenceRange{
+	"temperature": {36.0, 37.9},
+	"spo2":        {95, 100},
+}
+
+// VitalMeasures lists the measurements that can be charted, in display
+// order.
+var VitalMeasures = []string{
+	"systolic_bp", "diastolic_bp", "heart_rate", "temperature", "respiratory_rate", "spo2", "weight_kg", "height_cm", "bmi",
+}
+
+// VitalReferenceRanges returns the reference ranges for a patient of the
+// given age in years. Weight and height have no range.
+func VitalReferenceRanges(age int) map[string]ReferenceRange {
+	ranges := make(map[string]ReferenceRange)
+	for measure, r := range ageIndependentVitalRanges {
+		ranges[measure] = r
+	}
+
+	band := adultVitalRanges
+	for _, b := range vitalRangeBands {
+		if age < b.MaxAge {
+			band = b.Ranges
+			break
+		}
+	}
+	for measure, r := range band {
+		ranges[measure] = r
+	}
+	return ranges
+}
+
+// EvaluateVitals flags the measurements of a reading that fall outside the
+// reference ranges for the patient's age.
+func EvaluateVitals(vitals *models.VitalSigns, age int) []models.VitalSignFlag {
+	ranges := VitalReferenceRanges(age)
+
+	var flags []models.VitalSignFlag
+	for _, measure := range VitalMeasures {
+		r, ok := ranges[measure]
+		if !ok {
+			continue
+		}
+		value, ok := vitals.Value(measure)
+		if !ok {
+			continue
+		}
+
+		status := ""
+		if value < r.Min {
+			status = "low"
+		} else if value > r.Max {
+			status = "high"
+		}
+		if status != "" {
+			flags = append(flags, models.VitalSignFlag{Measure: measure, Value: value, Status: status, Min: r.Min, Max: r.Max})
+		}
+	}
+	return flags
+}
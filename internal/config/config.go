@@ -3,15 +3,23 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	JWT      JWTConfig
-	App      AppConfig
+	Database  DatabaseConfig
+	Server    ServerConfig
+	JWT       JWTConfig
+	Authz     AuthzConfig
+	Clinical  ClinicalConfig
+	Billing   BillingConfig
+	PHI       PHIConfig
+	PatientID PatientIDConfig
+	Bootstrap BootstrapConfig
+	App       AppConfig
 }
 
 type DatabaseConfig struct {
@@ -24,7 +32,59 @@ type ServerConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string
+	Secret             string
+	KeysDir            string
+	KeysReload         time.Duration
+	AccessTokenTTL     time.Duration
+	RefreshTokenTTL    time.Duration
+	SessionMaxLifetime time.Duration
+}
+
+type AuthzConfig struct {
+	PolicyFile string
+}
+
+type ClinicalConfig struct {
+	SafetyRulesFile string
+}
+
+// PHIConfig points at the keyfile used to encrypt patient PHI at rest.
+type PHIConfig struct {
+	KeyFile string
+}
+
+// PatientIDConfig describes the format of newly issued patient IDs. A date
+// format of "none" leaves the date part out.
+type PatientIDConfig struct {
+	Prefix     string
+	DateFormat string
+	Width      int
+	CheckDigit string
+}
+
+// BootstrapConfig holds the password for the administrator account created
+// when no active administrator exists. Without it none is created.
+type BootstrapConfig struct {
+	AdminPassword string
+}
+
+type BillingConfig struct {
+	Currency string
+	Claims   ClaimsConfig
+}
+
+// ClaimsConfig holds the identifiers written into insurance claim files.
+type ClaimsConfig struct {
+	SubmitterID        string
+	ReceiverID         string
+	ReceiverName       string
+	ProviderNPI        string
+	ProviderTaxID      string
+	ProviderAddress    string
+	ProviderCity       string
+	ProviderState      string
+	ProviderPostalCode string
+	ProviderPhone      string
 }
 
 type AppConfig struct {
@@ -46,7 +106,45 @@ func Load() *Config {
 			GinMode: getEnv("GIN_MODE", "debug"),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "default_secret"),
+			Secret:             getEnv("JWT_SECRET", ""),
+			KeysDir:            getEnv("JWT_KEYS_DIR", ""),
+			KeysReload:         getDurationEnv("JWT_KEYS_RELOAD_INTERVAL", 5*time.Minute),
+			AccessTokenTTL:     getDurationEnv("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL:    getDurationEnv("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour),
+			SessionMaxLifetime: getDurationEnv("JWT_SESSION_MAX_LIFETIME", 30*24*time.Hour),
+		},
+		Authz: AuthzConfig{
+			PolicyFile: getEnv("AUTHZ_POLICY_FILE", ""),
+		},
+		Clinical: ClinicalConfig{
+			SafetyRulesFile: getEnv("CLINICAL_SAFETY_RULES_FILE", ""),
+		},
+		Billing: BillingConfig{
+			Currency: getEnv("BILLING_CURRENCY", "USD"),
+			Claims: ClaimsConfig{
+				SubmitterID:        getEnv("CLAIMS_SUBMITTER_ID", ""),
+				ReceiverID:         getEnv("CLAIMS_RECEIVER_ID", ""),
+				ReceiverName:       getEnv("CLAIMS_RECEIVER_NAME", ""),
+				ProviderNPI:        getEnv("CLAIMS_PROVIDER_NPI", ""),
+				ProviderTaxID:      getEnv("CLAIMS_PROVIDER_TAX_ID", ""),
+				ProviderAddress:    getEnv("CLAIMS_PROVIDER_ADDRESS", ""),
+				ProviderCity:       getEnv("CLAIMS_PROVIDER_CITY", ""),
+				ProviderState:      getEnv("CLAIMS_PROVIDER_STATE", ""),
+				ProviderPostalCode: getEnv("CLAIMS_PROVIDER_POSTAL_CODE", ""),
+				ProviderPhone:      getEnv("CLAIMS_PROVIDER_PHONE", ""),
+			},
+		},
+		PHI: PHIConfig{
+			KeyFile: getEnv("PHI_KEY_FILE", ""),
+		},
+		PatientID: PatientIDConfig{
+			Prefix:     getEnv("PATIENT_ID_PREFIX", "PAT"),
+			DateFormat: getEnv("PATIENT_ID_DATE_FORMAT", "YYYYMMDD"),
+			Width:      getIntEnv("PATIENT_ID_WIDTH", 4),
+			CheckDigit: getEnv("PATIENT_ID_CHECK_DIGIT", "luhn"),
+		},
+		Bootstrap: BootstrapConfig{
+			AdminPassword: getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
 		},
 		App: AppConfig{
 			Name:    getEnv("APP_NAME", "Hospital Management System"),
@@ -61,3 +159,31 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s, using default %s", key, fallback)
+		return fallback
+	}
+	return duration
+}
+
+func getIntEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid number for %s, using default %d", key, fallback)
+		return fallback
+	}
+	return number
+}
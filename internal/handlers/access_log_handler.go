@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessLogHandler struct {
+	accessLogService *services.AccessLogService
+}
+
+func NewAccessLogHandler(accessLogService *services.AccessLogService) *AccessLogHandler {
+	return &AccessLogHandler{
+		accessLogService: accessLogService,
+	}
+}
+
+func (h *AccessLogHandler) ListAccessLogs(c *gin.Context) {
+	req := services.AccessLogQuery{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	if patientIDParam := c.Query("patient_id"); patientIDParam != "" {
+		patientID, err := strconv.ParseUint(patientIDParam, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+			return
+		}
+		id := uint(patientID)
+		req.PatientID = &id
+	}
+
+	if userIDParam := c.Query("user_id"); userIDParam != "" {
+		userID, err := strconv.ParseUint(userIDParam, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid user ID", err)
+			return
+		}
+		id := uint(userID)
+		req.UserID = &id
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	logs, err := h.accessLogService.ListAccessLogs(req, page, pageSize)
+	if err != nil {
+		if err.Error() == "failed to retrieve access logs" || err.Error() == "failed to count access logs" {
+			utils.InternalErrorResponse(c, "Failed to retrieve access logs", err)
+			return
+		}
+		utils.ValidationErrorResponse(c, err.Error(), err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Access logs retrieved successfully", logs)
+}
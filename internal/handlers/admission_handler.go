@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdmissionHandler struct {
+	admissionService *services.AdmissionService
+}
+
+func NewAdmissionHandler(admissionService *services.AdmissionService) *AdmissionHandler {
+	return &AdmissionHandler{
+		admissionService: admissionService,
+	}
+}
+
+func (h *AdmissionHandler) ListAdmissions(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	admissions, err := h.admissionService.ListAdmissions(uint(patientID), userRole)
+	if err != nil {
+		respondAdmissionError(c, "Failed to retrieve admissions", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Admissions retrieved successfully", admissions)
+}
+
+func (h *AdmissionHandler) CurrentAdmission(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	admission, err := h.admissionService.CurrentAdmission(uint(patientID), userRole)
+	if err != nil {
+		respondAdmissionError(c, "Failed to retrieve current admission", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Current admission retrieved successfully", admission)
+}
+
+func (h *AdmissionHandler) GetAdmission(c *gin.Context) {
+	patientID, admissionID, ok := parseAdmissionParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	admission, err := h.admissionService.GetAdmission(patientID, admissionID, userRole)
+	if err != nil {
+		respondAdmissionError(c, "Failed to retrieve admission", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Admission retrieved successfully", admission)
+}
+
+func (h *AdmissionHandler) AdmitPatient(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.AdmitPatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	admission, err := h.admissionService.AdmitPatient(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondAdmissionError(c, "Failed to admit patient", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Patient admitted successfully", admission)
+}
+
+func (h *AdmissionHandler) TransferPatient(c *gin.Context) {
+	patientID, admissionID, ok := parseAdmissionParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.TransferPatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	admission, err := h.admissionService.TransferPatient(patientID, admissionID, req, userID, userRole)
+	if err != nil {
+		respondAdmissionError(c, "Failed to transfer patient", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Patient transferred successfully", admission)
+}
+
+func (h *AdmissionHandler) DischargePatient(c *gin.Context) {
+	patientID, admissionID, ok := parseAdmissionParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.DischargePatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	admission, err := h.admissionService.DischargePatient(patientID, admissionID, req, userID, userRole)
+	if err != nil {
+		respondAdmissionError(c, "Failed to discharge patient", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Patient discharged successfully", admission)
+}
+
+func parseAdmissionParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	admissionID, err := strconv.ParseUint(c.Param("admission_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid admission ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(admissionID), true
+}
+
+func respondAdmissionError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage admissions")
+		return
+	}
+
+	switch err.Error() {
+	case "admission not found", "patient not found", "doctor not found", "bed not found", "patient is not admitted":
+		utils.NotFoundResponse(c, err.Error())
+	case "bed is already occupied", "bed is not available", "patient is already admitted", "admission is no longer active",
+		"only current admissions can be transferred", "patient is already in this bed", "patient has already been discharged":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
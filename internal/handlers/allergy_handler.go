@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AllergyHandler struct {
+	allergyService *services.AllergyService
+}
+
+func NewAllergyHandler(allergyService *services.AllergyService) *AllergyHandler {
+	return &AllergyHandler{
+		allergyService: allergyService,
+	}
+}
+
+func (h *AllergyHandler) ListAllergies(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	allergies, err := h.allergyService.ListAllergies(uint(patientID), models.AllergyStatus(c.Query("status")), userRole)
+	if err != nil {
+		respondAllergyError(c, "Failed to retrieve allergies", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Allergies retrieved successfully", allergies)
+}
+
+func (h *AllergyHandler) GetAllergy(c *gin.Context) {
+	patientID, allergyID, ok := parseAllergyParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	allergy, err := h.allergyService.GetAllergy(patientID, allergyID, userRole)
+	if err != nil {
+		respondAllergyError(c, "Failed to retrieve allergy", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Allergy retrieved successfully", allergy)
+}
+
+func (h *AllergyHandler) CreateAllergy(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateAllergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	allergy, err := h.allergyService.CreateAllergy(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondAllergyError(c, "Failed to create allergy", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Allergy created successfully", allergy)
+}
+
+func (h *AllergyHandler) UpdateAllergy(c *gin.Context) {
+	patientID, allergyID, ok := parseAllergyParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateAllergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	allergy, err := h.allergyService.UpdateAllergy(patientID, allergyID, req, userID, userRole)
+	if err != nil {
+		respondAllergyError(c, "Failed to update allergy", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Allergy updated successfully", allergy)
+}
+
+func parseAllergyParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	allergyID, err := strconv.ParseUint(c.Param("allergy_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid allergy ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(allergyID), true
+}
+
+func respondAllergyError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage allergies")
+		return
+	}
+
+	switch err.Error() {
+	case "allergy not found", "patient not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "an active allergy to this substance is already recorded":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "substance is required", "invalid onset date format, use YYYY-MM-DD", "onset date cannot be in the future":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppointmentHandler struct {
+	appointmentService *services.AppointmentService
+}
+
+func NewAppointmentHandler(appointmentService *services.AppointmentService) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+	}
+}
+
+func (h *AppointmentHandler) ListAppointments(c *gin.Context) {
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	req := services.AppointmentListQuery{
+		Date:   c.Query("date"),
+		Status: models.AppointmentStatus(c.Query("status")),
+	}
+
+	if value := c.Query("doctor_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid doctor ID", err)
+			return
+		}
+		doctorID := uint(id)
+		req.DoctorID = &doctorID
+	}
+
+	if value := c.Query("patient_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+			return
+		}
+		patientID := uint(id)
+		req.PatientID = &patientID
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	appointments, err := h.appointmentService.ListAppointments(req, page, pageSize, userID, userRole)
+	if err != nil {
+		if err.Error() == "failed to retrieve appointments" || err.Error() == "failed to count appointments" {
+			utils.InternalErrorResponse(c, "Failed to retrieve appointments", err)
+			return
+		}
+		utils.ValidationErrorResponse(c, err.Error(), err)
+		return
+	}
+
+	patientIDs := make([]uint, len(appointments.Appointments))
+	for i, appointment := range appointments.Appointments {
+		patientIDs[i] = appointment.Patient.ID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+
+	utils.SuccessResponse(c, http.StatusOK, "Appointments retrieved successfully", appointments)
+}
+
+func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
+	id, ok := parseAppointmentID(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	appointment, err := h.appointmentService.GetAppointment(id, userID, userRole)
+	if err != nil {
+		respondAppointmentError(c, "Failed to retrieve appointment", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, appointment.Patient.ID)
+	utils.SuccessResponse(c, http.StatusOK, "Appointment retrieved successfully", appointment)
+}
+
+func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
+	var req services.CreateAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	appointment, err := h.appointmentService.CreateAppointment(req, userID, userRole)
+	if err != nil {
+		respondAppointmentError(c, "Failed to create appointment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Appointment created successfully", appointment)
+}
+
+func (h *AppointmentHandler) RescheduleAppointment(c *gin.Context) {
+	id, ok := parseAppointmentID(c)
+	if !ok {
+		return
+	}
+
+	var req services.RescheduleAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	appointment, err := h.appointmentService.RescheduleAppointment(id, req, userID, userRole)
+	if err != nil {
+		respondAppointmentError(c, "Failed to reschedule appointment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Appointment rescheduled successfully", appointment)
+}
+
+func (h *AppointmentHandler) CancelAppointment(c *gin.Context) {
+	id, ok := parseAppointmentID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CancelAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	appointment, err := h.appointmentService.CancelAppointment(id, req, userID, userRole)
+	if err != nil {
+		respondAppointmentError(c, "Failed to cancel appointment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Appointment cancelled successfully", appointment)
+}
+
+func (h *AppointmentHandler) CheckInAppointment(c *gin.Context) {
+	id, ok := parseAppointmentID(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	appointment, err := h.appointmentService.CheckInAppointment(id, userID, userRole)
+	if err != nil {
+		respondAppointmentError(c, "Failed to check in appointment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Patient checked in successfully", appointment)
+}
+
+func (h *AppointmentHandler) CompleteAppointment(c *gin.Context) {
+	id, ok := parseAppointmentID(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	appointment, err := h.appointmentService.CompleteAppointment(id, userID, userRole)
+	if err != nil {
+		respondAppointmentError(c, "Failed to complete appointment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Appointment completed successfully", appointment)
+}
+
+func parseAppointmentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid appointment ID", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondAppointmentError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions for this appointment")
+		return
+	}
+	if errors.Is(err, repository.ErrAppointmentConflict) || errors.Is(err, repository.ErrAppointmentStatusChanged) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "appointment not found":
+		utils.NotFoundResponse(c, "Appointment not found")
+	case "patient not found", "doctor not found", "start time must be in the future":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	case "only scheduled appointments can be rescheduled", "appointment can no longer be cancelled",
+		"only scheduled appointments can be checked in", "only checked-in appointments can be completed":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"hospital-management-system/internal/services"
 	"hospital-management-system/pkg/utils"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,48 +34,62 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
 }
 
-func (h *AuthHandler) Register(c *gin.Context) {
-	var req services.RegisterRequest
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req services.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, "Invalid request data", err)
 		return
 	}
 
-	response, err := h.authService.Register(req)
+	tokens, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
-		if err.Error() == "username already exists" || err.Error() == "email already exists" {
-			utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
-			return
-		}
-		utils.InternalErrorResponse(c, "Failed to register user", err)
+		utils.UnauthorizedResponse(c, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusCreated, "User registered successfully", response)
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", tokens)
 }
 
-func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		utils.UnauthorizedResponse(c, "Authorization header required")
+func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		utils.InternalErrorResponse(c, "Session ID not found in context", nil)
 		return
 	}
 
-	tokenParts := strings.Split(authHeader, " ")
-	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-		utils.UnauthorizedResponse(c, "Invalid authorization header format")
+	id, ok := sessionID.(uint)
+	if !ok {
+		utils.InternalErrorResponse(c, "Invalid session ID format", nil)
 		return
 	}
 
-	newToken, err := h.authService.RefreshToken(tokenParts[1])
-	if err != nil {
-		utils.UnauthorizedResponse(c, "Invalid or expired token")
+	if err := h.authService.Logout(id); err != nil {
+		utils.InternalErrorResponse(c, "Failed to log out", err)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", gin.H{
-		"token": newToken,
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.InternalErrorResponse(c, "User ID not found in context", nil)
+		return
+	}
+
+	id, ok := userID.(uint)
+	if !ok {
+		utils.InternalErrorResponse(c, "Invalid user ID format", nil)
+		return
+	}
+
+	if err := h.authService.LogoutAll(id); err != nil {
+		utils.InternalErrorResponse(c, "Failed to log out all sessions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "All sessions logged out successfully", nil)
 }
 
 func (h *AuthHandler) GetProfile(c *gin.Context) {
@@ -100,3 +113,10 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", user)
 }
+
+// JWKS serves the public signing keys in the standard JWK Set format rather
+// than the usual API envelope so that JWT libraries can consume it directly.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
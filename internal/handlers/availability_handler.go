@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+	availabilityService *services.AvailabilityService
+}
+
+func NewAvailabilityHandler(availabilityService *services.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+	}
+}
+
+func (h *AvailabilityHandler) GetSchedule(c *gin.Context) {
+	doctorID, ok := parseDoctorID(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	schedule, err := h.availabilityService.GetSchedule(doctorID, userRole)
+	if err != nil {
+		respondAvailabilityError(c, "Failed to retrieve schedule", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedule retrieved successfully", schedule)
+}
+
+func (h *AvailabilityHandler) UpdateSchedule(c *gin.Context) {
+	doctorID, ok := parseDoctorID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	schedule, err := h.availabilityService.UpdateSchedule(doctorID, req, userID, userRole)
+	if err != nil {
+		respondAvailabilityError(c, "Failed to update schedule", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedule updated successfully", schedule)
+}
+
+func (h *AvailabilityHandler) AddException(c *gin.Context) {
+	doctorID, ok := parseDoctorID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateScheduleExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	exception, err := h.availabilityService.AddException(doctorID, req, userID, userRole)
+	if err != nil {
+		respondAvailabilityError(c, "Failed to create schedule exception", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Schedule exception created successfully", exception)
+}
+
+func (h *AvailabilityHandler) DeleteException(c *gin.Context) {
+	doctorID, ok := parseDoctorID(c)
+	if !ok {
+		return
+	}
+
+	exceptionID, err := strconv.ParseUint(c.Param("exception_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid exception ID", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	if err := h.availabilityService.DeleteException(doctorID, uint(exceptionID), userID, userRole); err != nil {
+		respondAvailabilityError(c, "Failed to delete schedule exception", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedule exception deleted successfully", nil)
+}
+
+func (h *AvailabilityHandler) FindOpenSlots(c *gin.Context) {
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	req := services.OpenSlotQuery{
+		Specialty: c.Query("specialty"),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+	}
+
+	if value := c.Query("doctor_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid doctor ID", err)
+			return
+		}
+		doctorID := uint(id)
+		req.DoctorID = &doctorID
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			utils.ValidationErrorResponse(c, "Invalid limit", err)
+			return
+		}
+		req.Limit = limit
+	}
+
+	slots, err := h.availabilityService.FindOpenSlots(req, userRole)
+	if err != nil {
+		respondAvailabilityError(c, "Failed to find open slots", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Open slots retrieved successfully", slots)
+}
+
+func parseDoctorID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid doctor ID", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondAvailabilityError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage this schedule")
+		return
+	}
+
+	switch err.Error() {
+	case "schedule not found", "schedule exception not found", "doctor not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "failed to save schedule", "failed to create schedule exception", "failed to retrieve schedules",
+		"failed to retrieve schedule exceptions", "failed to retrieve appointments":
+		utils.InternalErrorResponse(c, message, err)
+	default:
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	}
+}
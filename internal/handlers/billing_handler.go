@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BillingHandler struct {
+	billingService *services.BillingService
+}
+
+func NewBillingHandler(billingService *services.BillingService) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+	}
+}
+
+func (h *BillingHandler) ListInvoices(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoices, err := h.billingService.ListInvoices(uint(patientID), models.InvoiceStatus(c.Query("status")), userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to retrieve invoices", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Invoices retrieved successfully", invoices)
+}
+
+func (h *BillingHandler) PatientBalance(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	balance, err := h.billingService.PatientBalance(uint(patientID), userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to retrieve balance", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Balance retrieved successfully", balance)
+}
+
+func (h *BillingHandler) GetInvoice(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.GetInvoice(patientID, invoiceID, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to retrieve invoice", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Invoice retrieved successfully", invoice)
+}
+
+func (h *BillingHandler) PrintInvoice(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	page, err := h.billingService.PrintInvoice(patientID, invoiceID, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to print invoice", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+func (h *BillingHandler) CreateInvoice(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.CreateInvoice(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to create invoice", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Invoice created successfully", invoice)
+}
+
+func (h *BillingHandler) UpdateDraftInvoice(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.UpdateDraftInvoice(patientID, invoiceID, req, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to update invoice", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Invoice updated successfully", invoice)
+}
+
+func (h *BillingHandler) IssueInvoice(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.IssueInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.IssueInvoice(patientID, invoiceID, req, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to issue invoice", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Invoice issued successfully", invoice)
+}
+
+func (h *BillingHandler) VoidInvoice(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.VoidInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.VoidInvoice(patientID, invoiceID, req, userID, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to void invoice", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Invoice voided successfully", invoice)
+}
+
+func (h *BillingHandler) RecordPayment(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.RecordPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.RecordPayment(patientID, invoiceID, req, userID, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to record payment", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusCreated, "Payment recorded successfully", invoice)
+}
+
+func (h *BillingHandler) RefundPayment(c *gin.Context) {
+	patientID, invoiceID, ok := parseInvoiceParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.RefundPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	invoice, err := h.billingService.RefundPayment(patientID, invoiceID, req, userID, userRole)
+	if err != nil {
+		respondBillingError(c, "Failed to record refund", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusCreated, "Refund recorded successfully", invoice)
+}
+
+func parseInvoiceParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("invoice_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid invoice ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(invoiceID), true
+}
+
+func respondBillingError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage billing")
+		return
+	}
+	if errors.Is(err, repository.ErrInvoiceStatusChanged) ||
+		errors.Is(err, repository.ErrInvoiceHasClaims) ||
+		errors.Is(err, repository.ErrPaymentExceedsBalance) ||
+		errors.Is(err, repository.ErrRefundExceedsPaid) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "invoice not found", "patient not found", "encounter not found for this patient",
+		"admission not found for this patient", "price list item not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "only draft invoices can be edited", "only draft invoices can be issued", "invoice is already void",
+		"payments must be refunded before the invoice is voided", "payments can only be taken on issued invoices",
+		"refunds can only be made on issued invoices":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "invoice must reference either an encounter or an admission", "invalid due date format, use YYYY-MM-DD",
+		"due date cannot be in the past":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
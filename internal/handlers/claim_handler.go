@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClaimHandler struct {
+	claimService *services.ClaimService
+}
+
+func NewClaimHandler(claimService *services.ClaimService) *ClaimHandler {
+	return &ClaimHandler{
+		claimService: claimService,
+	}
+}
+
+func (h *ClaimHandler) ListClaims(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claims, err := h.claimService.ListClaims(uint(patientID), models.ClaimStatus(c.Query("status")), userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to retrieve claims", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Claims retrieved successfully", claims)
+}
+
+func (h *ClaimHandler) SearchClaims(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	req := services.ClaimQuery{Status: models.ClaimStatus(c.Query("status"))}
+	if payerID := c.Query("payer_id"); payerID != "" {
+		id, err := strconv.ParseUint(payerID, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid insurance payer ID", err)
+			return
+		}
+		req.PayerID = uint(id)
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claims, err := h.claimService.SearchClaims(req, page, pageSize, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to retrieve claims", err)
+		return
+	}
+
+	patientIDs := make([]uint, len(claims.Claims))
+	for i, claim := range claims.Claims {
+		patientIDs[i] = claim.PatientID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+	utils.SuccessResponse(c, http.StatusOK, "Claims retrieved successfully", claims)
+}
+
+func (h *ClaimHandler) GetClaim(c *gin.Context) {
+	patientID, claimID, ok := parseClaimParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claim, err := h.claimService.GetClaim(patientID, claimID, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to retrieve claim", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Claim retrieved successfully", claim)
+}
+
+func (h *ClaimHandler) CreateClaim(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claim, err := h.claimService.CreateClaim(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to create claim", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Claim created successfully", claim)
+}
+
+func (h *ClaimHandler) AcceptClaim(c *gin.Context) {
+	patientID, claimID, ok := parseClaimParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.AcceptClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claim, err := h.claimService.AcceptClaim(patientID, claimID, req, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to accept claim", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Claim accepted successfully", claim)
+}
+
+func (h *ClaimHandler) DenyClaim(c *gin.Context) {
+	patientID, claimID, ok := parseClaimParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.DenyClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claim, err := h.claimService.DenyClaim(patientID, claimID, req, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to deny claim", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Claim denied successfully", claim)
+}
+
+func (h *ClaimHandler) PayClaim(c *gin.Context) {
+	patientID, claimID, ok := parseClaimParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.PayClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claim, err := h.claimService.PayClaim(patientID, claimID, req, userID, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to record claim payment", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Claim payment recorded successfully", claim)
+}
+
+func (h *ClaimHandler) ReopenClaim(c *gin.Context) {
+	patientID, claimID, ok := parseClaimParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReopenClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	claim, err := h.claimService.ReopenClaim(patientID, claimID, req, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to reopen claim", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Claim reopened successfully", claim)
+}
+
+// CreateBatch accepts an empty body, which submits the drafts for every
+// payer.
+func (h *ClaimHandler) CreateBatch(c *gin.Context) {
+	var req services.CreateClaimBatchRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	batch, err := h.claimService.CreateBatch(req, userID, userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to create claim batch", err)
+		return
+	}
+
+	patientIDs := make([]uint, len(batch.Claims))
+	for i, claim := range batch.Claims {
+		patientIDs[i] = claim.PatientID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+	utils.SuccessResponse(c, http.StatusCreated, "Claim batch created successfully", batch)
+}
+
+func (h *ClaimHandler) GetBatch(c *gin.Context) {
+	batchID, err := strconv.ParseUint(c.Param("batch_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid claim batch ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	batch, err := h.claimService.GetBatch(uint(batchID), userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to retrieve claim batch", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Claim batch retrieved successfully", batch)
+}
+
+// DownloadBatch returns the batch's claim file as it was submitted.
+func (h *ClaimHandler) DownloadBatch(c *gin.Context) {
+	batchID, err := strconv.ParseUint(c.Param("batch_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid claim batch ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	batch, err := h.claimService.GetBatch(uint(batchID), userRole)
+	if err != nil {
+		respondClaimError(c, "Failed to retrieve claim batch", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", batch.BatchNumber+".837"))
+	c.Data(http.StatusOK, "application/edi-x12", []byte(batch.Content))
+}
+
+func parseClaimParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	claimID, err := strconv.ParseUint(c.Param("claim_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid claim ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(claimID), true
+}
+
+func respondClaimError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage claims")
+		return
+	}
+	if errors.Is(err, repository.ErrClaimStatusChanged) ||
+		errors.Is(err, repository.ErrClaimExists) ||
+		errors.Is(err, repository.ErrInvoiceStatusChanged) ||
+		errors.Is(err, repository.ErrPaymentExceedsBalance) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "claim not found", "invoice not found", "insurance policy not found", "insurance payer not found",
+		"claim batch not found", "ICD-10 code not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "claims can only be made for issued invoices", "only submitted claims can be accepted",
+		"only submitted or accepted claims can be denied", "only submitted or accepted claims can be paid",
+		"only denied claims can be reopened", "claims on void invoices cannot be reopened", "no draft claims to submit":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "insurance policy does not cover the service date", "paid amount cannot exceed the billed amount",
+		"invalid ICD-10 code":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	case "claim submission is not configured":
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
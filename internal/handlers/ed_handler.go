@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EDHandler struct {
+	edService *services.EDService
+}
+
+func NewEDHandler(edService *services.EDService) *EDHandler {
+	return &EDHandler{
+		edService: edService,
+	}
+}
+
+func (h *EDHandler) Queue(c *gin.Context) {
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	queue, err := h.edService.Queue(c.Query("include_in_treatment") == "true", userRole)
+	if err != nil {
+		respondEDError(c, "Failed to retrieve emergency queue", err)
+		return
+	}
+
+	patientIDs := make([]uint, len(queue.Visits))
+	for i, visit := range queue.Visits {
+		patientIDs[i] = visit.PatientID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+	utils.SuccessResponse(c, http.StatusOK, "Emergency queue retrieved successfully", queue)
+}
+
+func (h *EDHandler) Metrics(c *gin.Context) {
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	metrics, err := h.edService.Metrics(services.EDMetricsQuery{From: c.Query("from"), To: c.Query("to")}, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to retrieve emergency metrics", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Emergency metrics retrieved successfully", metrics)
+}
+
+func (h *EDHandler) CheckIn(c *gin.Context) {
+	var req services.EDCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.CheckIn(req, userID, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to check in patient", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, visit.PatientID)
+	utils.SuccessResponse(c, http.StatusCreated, "Patient checked in successfully", visit)
+}
+
+func (h *EDHandler) GetVisit(c *gin.Context) {
+	id, ok := parseEDVisitID(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.GetVisit(id, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to retrieve emergency visit", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, visit.PatientID)
+	utils.SuccessResponse(c, http.StatusOK, "Emergency visit retrieved successfully", visit)
+}
+
+func (h *EDHandler) Retriage(c *gin.Context) {
+	id, ok := parseEDVisitID(c)
+	if !ok {
+		return
+	}
+
+	var req services.EDRetriageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.Retriage(id, req, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to re-triage patient", err)
+		return
+	}
+
+	h.respondVisit(c, "Patient re-triaged successfully", visit)
+}
+
+func (h *EDHandler) AssignDoctor(c *gin.Context) {
+	id, ok := parseEDVisitID(c)
+	if !ok {
+		return
+	}
+
+	var req services.EDAssignDoctorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.AssignDoctor(id, req, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to assign doctor", err)
+		return
+	}
+
+	h.respondVisit(c, "Doctor assigned successfully", visit)
+}
+
+func (h *EDHandler) StartTreatment(c *gin.Context) {
+	id, ok := parseEDVisitID(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.StartTreatment(id, userID, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to start treatment", err)
+		return
+	}
+
+	h.respondVisit(c, "Treatment started successfully", visit)
+}
+
+func (h *EDHandler) Complete(c *gin.Context) {
+	id, ok := parseEDVisitID(c)
+	if !ok {
+		return
+	}
+
+	var req services.EDCompleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.Complete(id, req, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to complete emergency visit", err)
+		return
+	}
+
+	h.respondVisit(c, "Emergency visit completed successfully", visit)
+}
+
+func (h *EDHandler) LeftWithoutBeingSeen(c *gin.Context) {
+	id, ok := parseEDVisitID(c)
+	if !ok {
+		return
+	}
+
+	var req services.EDLeftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	visit, err := h.edService.LeftWithoutBeingSeen(id, req, userRole)
+	if err != nil {
+		respondEDError(c, "Failed to update emergency visit", err)
+		return
+	}
+
+	h.respondVisit(c, "Emergency visit closed successfully", visit)
+}
+
+func (h *EDHandler) respondVisit(c *gin.Context, message string, visit *models.EDVisitResponse) {
+	middleware.SetAccessedPatients(c, visit.PatientID)
+	utils.SuccessResponse(c, http.StatusOK, message, visit)
+}
+
+func parseEDVisitID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid emergency visit ID", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondEDError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage emergency visits")
+		return
+	}
+	if errors.Is(err, repository.ErrEDVisitStatusChanged) || errors.Is(err, repository.ErrPatientAlreadyInED) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "emergency visit not found", "patient not found", "doctor not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "only patients waiting to be seen can be re-triaged", "only patients waiting to be seen can be assigned",
+		"only patients waiting to be seen can be started", "patient is assigned to another doctor",
+		"only patients in treatment can be completed", "only patients waiting to be seen can leave without being seen":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "invalid arrived_at format, use RFC3339", "arrived_at cannot be in the future",
+		"invalid from format, use RFC3339", "invalid to format, use RFC3339",
+		"from must be before to", "metrics range cannot exceed one year":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
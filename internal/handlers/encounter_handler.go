@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EncounterHandler struct {
+	encounterService *services.EncounterService
+}
+
+func NewEncounterHandler(encounterService *services.EncounterService) *EncounterHandler {
+	return &EncounterHandler{
+		encounterService: encounterService,
+	}
+}
+
+func (h *EncounterHandler) ListEncounters(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	encounters, err := h.encounterService.ListEncounters(uint(patientID), page, pageSize, userRole)
+	if err != nil {
+		respondEncounterError(c, "Failed to retrieve encounters", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Encounters retrieved successfully", encounters)
+}
+
+func (h *EncounterHandler) GetEncounter(c *gin.Context) {
+	patientID, encounterID, ok := parseEncounterParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	encounter, err := h.encounterService.GetEncounter(patientID, encounterID, userRole)
+	if err != nil {
+		respondEncounterError(c, "Failed to retrieve encounter", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Encounter retrieved successfully", encounter)
+}
+
+func (h *EncounterHandler) CreateEncounter(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateEncounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	encounter, err := h.encounterService.CreateEncounter(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondEncounterError(c, "Failed to create encounter", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Encounter created successfully", encounter)
+}
+
+func (h *EncounterHandler) UpdateEncounter(c *gin.Context) {
+	patientID, encounterID, ok := parseEncounterParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateEncounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	encounter, err := h.encounterService.UpdateEncounter(patientID, encounterID, req, userID, userRole)
+	if err != nil {
+		respondEncounterError(c, "Failed to update encounter", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Encounter updated successfully", encounter)
+}
+
+func (h *EncounterHandler) SignEncounter(c *gin.Context) {
+	patientID, encounterID, ok := parseEncounterParams(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	encounter, err := h.encounterService.SignEncounter(patientID, encounterID, userID, userRole)
+	if err != nil {
+		respondEncounterError(c, "Failed to sign encounter", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Encounter signed successfully", encounter)
+}
+
+func (h *EncounterHandler) AddAddendum(c *gin.Context) {
+	patientID, encounterID, ok := parseEncounterParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.AddAddendumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	encounter, err := h.encounterService.AddAddendum(patientID, encounterID, req, userID, userRole)
+	if err != nil {
+		respondEncounterError(c, "Failed to add addendum", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusCreated, "Addendum added successfully", encounter)
+}
+
+func parseEncounterParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	encounterID, err := strconv.ParseUint(c.Param("encounter_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid encounter ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(encounterID), true
+}
+
+func respondEncounterError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions for this encounter")
+		return
+	}
+	if errors.Is(err, repository.ErrEncounterLocked) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "encounter not found", "patient not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "appointment not found for this patient":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	case "addenda can only be added to signed encounters":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
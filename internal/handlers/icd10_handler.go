@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ICD10Handler struct {
+	icd10Service *services.ICD10Service
+}
+
+func NewICD10Handler(icd10Service *services.ICD10Service) *ICD10Handler {
+	return &ICD10Handler{
+		icd10Service: icd10Service,
+	}
+}
+
+func (h *ICD10Handler) SearchCodes(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	codes, err := h.icd10Service.SearchCodes(c.Query("q"), limit)
+	if err != nil {
+		if err.Error() == "search query is required" {
+			utils.ValidationErrorResponse(c, err.Error(), err)
+			return
+		}
+		utils.InternalErrorResponse(c, "Failed to search ICD-10 codes", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "ICD-10 codes retrieved successfully", codes)
+}
+
+// ImportCodes takes the code table as a CSV file in the "file" form field.
+func (h *ICD10Handler) ImportCodes(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ValidationErrorResponse(c, "CSV file is required", err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Failed to read uploaded file", err)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.icd10Service.ImportCodes(file)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to") {
+			utils.InternalErrorResponse(c, "Failed to import ICD-10 codes", err)
+			return
+		}
+		utils.ValidationErrorResponse(c, err.Error(), err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "ICD-10 codes imported successfully", result)
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InsuranceHandler struct {
+	insuranceService *services.InsuranceService
+}
+
+func NewInsuranceHandler(insuranceService *services.InsuranceService) *InsuranceHandler {
+	return &InsuranceHandler{
+		insuranceService: insuranceService,
+	}
+}
+
+func (h *InsuranceHandler) ListPayers(c *gin.Context) {
+	payers, err := h.insuranceService.ListPayers(c.Query("include_inactive") == "true")
+	if err != nil {
+		utils.InternalErrorResponse(c, "Failed to retrieve insurance payers", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Insurance payers retrieved successfully", payers)
+}
+
+func (h *InsuranceHandler) CreatePayer(c *gin.Context) {
+	var req services.CreateInsurancePayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	payer, err := h.insuranceService.CreatePayer(req)
+	if err != nil {
+		respondInsuranceError(c, "Failed to create insurance payer", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Insurance payer created successfully", payer)
+}
+
+func (h *InsuranceHandler) UpdatePayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid insurance payer ID", err)
+		return
+	}
+
+	var req services.UpdateInsurancePayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	payer, err := h.insuranceService.UpdatePayer(uint(id), req)
+	if err != nil {
+		respondInsuranceError(c, "Failed to update insurance payer", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Insurance payer updated successfully", payer)
+}
+
+func (h *InsuranceHandler) ListPolicies(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	policies, err := h.insuranceService.ListPolicies(uint(patientID), c.Query("include_inactive") == "true", userRole)
+	if err != nil {
+		respondInsuranceError(c, "Failed to retrieve insurance policies", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Insurance policies retrieved successfully", policies)
+}
+
+func (h *InsuranceHandler) GetPolicy(c *gin.Context) {
+	patientID, policyID, ok := parsePolicyParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	policy, err := h.insuranceService.GetPolicy(patientID, policyID, userRole)
+	if err != nil {
+		respondInsuranceError(c, "Failed to retrieve insurance policy", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Insurance policy retrieved successfully", policy)
+}
+
+func (h *InsuranceHandler) CreatePolicy(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateInsurancePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	policy, err := h.insuranceService.CreatePolicy(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondInsuranceError(c, "Failed to create insurance policy", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Insurance policy created successfully", policy)
+}
+
+func (h *InsuranceHandler) UpdatePolicy(c *gin.Context) {
+	patientID, policyID, ok := parsePolicyParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateInsurancePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	policy, err := h.insuranceService.UpdatePolicy(patientID, policyID, req, userRole)
+	if err != nil {
+		respondInsuranceError(c, "Failed to update insurance policy", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Insurance policy updated successfully", policy)
+}
+
+func (h *InsuranceHandler) CheckEligibility(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	eligibility, err := h.insuranceService.CheckEligibility(uint(patientID), c.Query("date"), userRole)
+	if err != nil {
+		respondInsuranceError(c, "Failed to check eligibility", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Eligibility checked successfully", eligibility)
+}
+
+func parsePolicyParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	policyID, err := strconv.ParseUint(c.Param("policy_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid insurance policy ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(policyID), true
+}
+
+func respondInsuranceError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage insurance")
+		return
+	}
+	if errors.Is(err, repository.ErrActivePolicyExists) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "patient not found", "insurance payer not found", "insurance policy not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "insurance payer already exists":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "payer name and code are required", "subscriber name is required when the patient is not the subscriber",
+		"invalid coverage date format, use YYYY-MM-DD", "coverage end cannot be before coverage start",
+		"invalid date format, use YYYY-MM-DD":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabHandler struct {
+	labService *services.LabService
+}
+
+func NewLabHandler(labService *services.LabService) *LabHandler {
+	return &LabHandler{
+		labService: labService,
+	}
+}
+
+func (h *LabHandler) ListLabOrders(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	orders, err := h.labService.ListLabOrders(uint(patientID), models.LabOrderStatus(c.Query("status")), userRole)
+	if err != nil {
+		respondLabError(c, "Failed to retrieve lab orders", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Lab orders retrieved successfully", orders)
+}
+
+func (h *LabHandler) GetLabOrder(c *gin.Context) {
+	patientID, orderID, ok := parseLabOrderParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.GetLabOrder(patientID, orderID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to retrieve lab order", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Lab order retrieved successfully", order)
+}
+
+func (h *LabHandler) CreateLabOrder(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateLabOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.CreateLabOrder(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to create lab order", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Lab order created successfully", order)
+}
+
+func (h *LabHandler) CollectSpecimen(c *gin.Context) {
+	patientID, orderID, ok := parseLabOrderParams(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.CollectSpecimen(patientID, orderID, userID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to collect specimen", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Specimen collected successfully", order)
+}
+
+func (h *LabHandler) StartProcessing(c *gin.Context) {
+	patientID, orderID, ok := parseLabOrderParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.StartProcessing(patientID, orderID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to start lab order", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Lab order started successfully", order)
+}
+
+func (h *LabHandler) RecordResults(c *gin.Context) {
+	patientID, orderID, ok := parseLabOrderParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.RecordLabResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.RecordResults(patientID, orderID, req, userID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to record lab results", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Lab results recorded successfully", order)
+}
+
+func (h *LabHandler) CancelLabOrder(c *gin.Context) {
+	patientID, orderID, ok := parseLabOrderParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.CancelLabOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.CancelLabOrder(patientID, orderID, req, userID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to cancel lab order", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Lab order cancelled successfully", order)
+}
+
+func (h *LabHandler) ReviewResults(c *gin.Context) {
+	patientID, orderID, ok := parseLabOrderParams(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	order, err := h.labService.ReviewResults(patientID, orderID, userID, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to review lab results", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Lab results reviewed successfully", order)
+}
+
+func (h *LabHandler) Inbox(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	inbox, err := h.labService.Inbox(userID, c.Query("include_reviewed") == "true", page, pageSize, userRole)
+	if err != nil {
+		respondLabError(c, "Failed to retrieve lab inbox", err)
+		return
+	}
+
+	patientIDs := make([]uint, len(inbox.Orders))
+	for i, order := range inbox.Orders {
+		patientIDs[i] = order.PatientID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+	utils.SuccessResponse(c, http.StatusOK, "Lab inbox retrieved successfully", inbox)
+}
+
+func parseLabOrderParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid lab order ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(orderID), true
+}
+
+func respondLabError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage lab orders")
+		return
+	}
+	if errors.Is(err, repository.ErrLabOrderStatusChanged) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "lab order not found", "patient not found", "encounter not found for this patient":
+		utils.NotFoundResponse(c, err.Error())
+	case "only ordered lab orders can be collected", "only collected lab orders can be started",
+		"results can only be recorded for collected or in-progress lab orders", "lab order can no longer be cancelled",
+		"only resulted lab orders can be reviewed", "lab results have already been reviewed":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "reference low cannot be above reference high":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MedicationHandler struct {
+	medicationService *services.MedicationService
+}
+
+func NewMedicationHandler(medicationService *services.MedicationService) *MedicationHandler {
+	return &MedicationHandler{
+		medicationService: medicationService,
+	}
+}
+
+func (h *MedicationHandler) ListMedications(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	req := services.MedicationListQuery{
+		Search:          c.Query("q"),
+		IncludeInactive: c.Query("include_inactive") == "true",
+	}
+
+	medications, err := h.medicationService.ListMedications(req, page, pageSize)
+	if err != nil {
+		utils.InternalErrorResponse(c, "Failed to retrieve medications", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Medications retrieved successfully", medications)
+}
+
+func (h *MedicationHandler) GetMedication(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid medication ID", err)
+		return
+	}
+
+	medication, err := h.medicationService.GetMedication(uint(id))
+	if err != nil {
+		respondMedicationError(c, "Failed to retrieve medication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Medication retrieved successfully", medication)
+}
+
+func (h *MedicationHandler) CreateMedication(c *gin.Context) {
+	var req services.CreateMedicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	medication, err := h.medicationService.CreateMedication(req)
+	if err != nil {
+		respondMedicationError(c, "Failed to create medication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Medication created successfully", medication)
+}
+
+func (h *MedicationHandler) UpdateMedication(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid medication ID", err)
+		return
+	}
+
+	var req services.UpdateMedicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	medication, err := h.medicationService.UpdateMedication(uint(id), req)
+	if err != nil {
+		respondMedicationError(c, "Failed to update medication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Medication updated successfully", medication)
+}
+
+func respondMedicationError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "medication not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "medication already exists":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "medication name is required":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaService *services.MFAService
+}
+
+func NewMFAHandler(mfaService *services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *MFAHandler) BeginEnrollment(c *gin.Context) {
+	userID, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(userID)
+	if err != nil {
+		if err.Error() == "multi-factor authentication is already enabled" {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		utils.InternalErrorResponse(c, "Failed to start MFA enrollment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the provisioning URI with an authenticator app and confirm with a code", enrollment)
+}
+
+func (h *MFAHandler) ConfirmEnrollment(c *gin.Context) {
+	var req services.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	response, err := h.mfaService.ConfirmEnrollment(userID, req.Code, c.GetBool("mfa_enrollment"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid MFA code":
+			utils.UnauthorizedResponse(c, err.Error())
+		case "multi-factor authentication is locked after too many failed attempts":
+			utils.ForbiddenResponse(c, err.Error())
+		case "multi-factor authentication is already enabled", "multi-factor enrollment has changed, please start again":
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		case "multi-factor enrollment has not been started":
+			utils.ValidationErrorResponse(c, err.Error(), err)
+		default:
+			utils.InternalErrorResponse(c, "Failed to confirm MFA enrollment", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Multi-factor authentication enabled; store the recovery codes securely", response)
+}
+
+func (h *MFAHandler) VerifyLogin(c *gin.Context) {
+	var req services.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := h.mfaService.VerifyLogin(req)
+	if err != nil {
+		if err.Error() == "code or recovery_code is required" {
+			utils.ValidationErrorResponse(c, err.Error(), err)
+			return
+		}
+		utils.UnauthorizedResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req services.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	if err := h.mfaService.Disable(userID, req.Code); err != nil {
+		switch err.Error() {
+		case "invalid MFA code", "MFA code has already been used":
+			utils.UnauthorizedResponse(c, err.Error())
+		case "multi-factor authentication is required for your role",
+			"multi-factor authentication is locked after too many failed attempts":
+			utils.ForbiddenResponse(c, err.Error())
+		case "multi-factor authentication is not enabled":
+			utils.ValidationErrorResponse(c, err.Error(), err)
+		default:
+			utils.InternalErrorResponse(c, "Failed to disable multi-factor authentication", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Multi-factor authentication disabled", nil)
+}
+
+func (h *MFAHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.mfaService.ListPolicies()
+	if err != nil {
+		utils.InternalErrorResponse(c, "Failed to retrieve MFA policies", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "MFA policies retrieved successfully", policies)
+}
+
+func (h *MFAHandler) SetPolicy(c *gin.Context) {
+	var req services.MFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	policy, err := h.mfaService.SetPolicy(req, userID)
+	if err != nil {
+		if err.Error() == "unknown role" {
+			utils.ValidationErrorResponse(c, err.Error(), err)
+			return
+		}
+		utils.InternalErrorResponse(c, "Failed to update MFA policy", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "MFA policy updated successfully", policy)
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"hospital-management-system/internal/authz"
 	"hospital-management-system/internal/middleware"
 	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/patientid"
+	"hospital-management-system/internal/repository"
 	"hospital-management-system/internal/services"
 	"hospital-management-system/pkg/utils"
 
@@ -35,17 +39,27 @@ func (h *PatientHandler) CreatePatient(c *gin.Context) {
 		return
 	}
 
-	if userRole != models.RoleReceptionist {
-		utils.ForbiddenResponse(c, "Only receptionists can create patients")
-		return
-	}
-
-	patient, err := h.patientService.CreatePatient(req, userID)
+	patient, err := h.patientService.CreatePatient(req, userID, userRole)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			utils.ForbiddenResponse(c, "Insufficient permissions to create patients")
+			return
+		}
+		var duplicateErr *services.DuplicatePatientError
+		if errors.As(err, &duplicateErr) {
+			for _, candidate := range duplicateErr.Candidates {
+				middleware.SetAccessedPatients(c, candidate.Patient.ID)
+			}
+			utils.ErrorResponseWithData(c, http.StatusConflict, err.Error(), err, gin.H{
+				"candidates": duplicateErr.Candidates,
+			})
+			return
+		}
 		utils.InternalErrorResponse(c, "Failed to create patient", err)
 		return
 	}
 
+	middleware.SetAccessedPatients(c, patient.ID)
 	utils.SuccessResponse(c, http.StatusCreated, "Patient created successfully", patient)
 }
 
@@ -63,6 +77,7 @@ func (h *PatientHandler) GetPatient(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAccessedPatients(c, patient.ID)
 	utils.SuccessResponse(c, http.StatusOK, "Patient retrieved successfully", patient)
 }
 
@@ -75,10 +90,15 @@ func (h *PatientHandler) GetPatientByPatientID(c *gin.Context) {
 
 	patient, err := h.patientService.GetPatientByPatientID(patientID)
 	if err != nil {
+		if errors.Is(err, patientid.ErrInvalidCheckDigit) {
+			utils.ValidationErrorResponse(c, "Invalid patient ID, check for a mistyped digit", err)
+			return
+		}
 		utils.NotFoundResponse(c, "Patient not found")
 		return
 	}
 
+	middleware.SetAccessedPatients(c, patient.ID)
 	utils.SuccessResponse(c, http.StatusOK, "Patient retrieved successfully", patient)
 }
 
@@ -104,10 +124,15 @@ func (h *PatientHandler) UpdatePatient(c *gin.Context) {
 
 	patient, err := h.patientService.UpdatePatient(uint(id), req, userID, userRole)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			utils.ForbiddenResponse(c, "Insufficient permissions to update these patient fields")
+			return
+		}
 		utils.InternalErrorResponse(c, "Failed to update patient", err)
 		return
 	}
 
+	middleware.SetAccessedPatients(c, patient.ID)
 	utils.SuccessResponse(c, http.StatusOK, "Patient updated successfully", patient)
 }
 
@@ -119,27 +144,151 @@ func (h *PatientHandler) DeletePatient(c *gin.Context) {
 		return
 	}
 
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	err = h.patientService.DeletePatient(uint(id), userID, userRole)
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			utils.ForbiddenResponse(c, "Insufficient permissions to delete patients")
+			return
+		}
+		utils.InternalErrorResponse(c, "Failed to delete patient", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Patient deleted successfully", nil)
+}
+
+func (h *PatientHandler) FindDuplicates(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
 	_, userRole, err := middleware.GetUserFromContext(c)
 	if err != nil {
 		utils.UnauthorizedResponse(c, "User context not found")
 		return
 	}
 
-	if userRole != models.RoleReceptionist {
-		utils.ForbiddenResponse(c, "Only receptionists can delete patients")
+	candidates, err := h.patientService.FindDuplicates(uint(id), userRole)
+	if err != nil {
+		respondPatientMergeError(c, "Failed to find duplicate patients", err)
 		return
 	}
 
-	err = h.patientService.DeletePatient(uint(id), userRole)
+	setAccessedCandidates(c, candidates)
+	middleware.SetAccessedPatients(c, uint(id))
+	utils.SuccessResponse(c, http.StatusOK, "Duplicate candidates retrieved successfully", candidates)
+}
+
+func (h *PatientHandler) MergePatients(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		utils.InternalErrorResponse(c, "Failed to delete patient", err)
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Patient deleted successfully", nil)
+	var req services.MergePatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	merged, err := h.patientService.MergePatients(uint(id), req, userID, userRole)
+	if err != nil {
+		respondPatientMergeError(c, "Failed to merge patients", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(id), req.DuplicateID)
+	utils.SuccessResponse(c, http.StatusOK, "Patients merged successfully", merged)
+}
+
+func setAccessedCandidates(c *gin.Context, candidates []services.DuplicateCandidate) {
+	ids := make([]uint, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.Patient.ID
+	}
+	middleware.SetAccessedPatients(c, ids...)
+}
+
+func respondPatientMergeError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to merge patients")
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrPatientMergeChanged),
+		errors.Is(err, repository.ErrMergeBothAdmitted),
+		errors.Is(err, repository.ErrMergeBothInED),
+		errors.Is(err, repository.ErrMergePolicyConflict):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "patient not found", "duplicate patient not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "cannot merge a patient into itself":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
 }
 
 func (h *PatientHandler) ListPatients(c *gin.Context) {
+	req := services.PatientListQuery{
+		Gender:      models.Gender(c.Query("gender")),
+		BloodType:   models.BloodType(c.Query("blood_type")),
+		CreatedFrom: c.Query("created_from"),
+		CreatedTo:   c.Query("created_to"),
+		UpdatedFrom: c.Query("updated_from"),
+		UpdatedTo:   c.Query("updated_to"),
+		Status:      c.Query("status"),
+		Sort:        c.Query("sort"),
+	}
+
+	if minAgeParam := c.Query("min_age"); minAgeParam != "" {
+		minAge, err := strconv.Atoi(minAgeParam)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid min_age", err)
+			return
+		}
+		req.MinAge = &minAge
+	}
+
+	if maxAgeParam := c.Query("max_age"); maxAgeParam != "" {
+		maxAge, err := strconv.Atoi(maxAgeParam)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid max_age", err)
+			return
+		}
+		req.MaxAge = &maxAge
+	}
+
+	if createdByParam := c.Query("created_by"); createdByParam != "" {
+		createdByID, err := strconv.ParseUint(createdByParam, 10, 32)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid created_by user ID", err)
+			return
+		}
+		id := uint(createdByID)
+		req.CreatedByID = &id
+	}
+
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
 		page = 1
@@ -150,12 +299,17 @@ func (h *PatientHandler) ListPatients(c *gin.Context) {
 		pageSize = 10
 	}
 
-	patients, err := h.patientService.ListPatients(page, pageSize)
+	patients, err := h.patientService.ListPatients(req, page, pageSize)
 	if err != nil {
-		utils.InternalErrorResponse(c, "Failed to retrieve patients", err)
+		if err.Error() == "failed to retrieve patients" || err.Error() == "failed to count patients" {
+			utils.InternalErrorResponse(c, "Failed to retrieve patients", err)
+			return
+		}
+		utils.ValidationErrorResponse(c, err.Error(), err)
 		return
 	}
 
+	setAccessedPatientList(c, patients)
 	utils.SuccessResponse(c, http.StatusOK, "Patients retrieved successfully", patients)
 }
 
@@ -182,5 +336,18 @@ func (h *PatientHandler) SearchPatients(c *gin.Context) {
 		return
 	}
 
+	patientIDs := make([]uint, len(patients.Patients))
+	for i, patient := range patients.Patients {
+		patientIDs[i] = patient.ID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
 	utils.SuccessResponse(c, http.StatusOK, "Patient search completed successfully", patients)
 }
+
+func setAccessedPatientList(c *gin.Context, list *services.PatientListResponse) {
+	patientIDs := make([]uint, len(list.Patients))
+	for i, patient := range list.Patients {
+		patientIDs[i] = patient.ID
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+}
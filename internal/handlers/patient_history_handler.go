@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PatientHistoryHandler struct {
+	historyService *services.PatientHistoryService
+}
+
+func NewPatientHistoryHandler(historyService *services.PatientHistoryService) *PatientHistoryHandler {
+	return &PatientHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+func (h *PatientHistoryHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	history, err := h.historyService.GetPatientHistory(uint(id))
+	if err != nil {
+		if err.Error() == "patient not found" {
+			utils.NotFoundResponse(c, "Patient not found")
+			return
+		}
+		utils.InternalErrorResponse(c, "Failed to retrieve patient history", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(id))
+	utils.SuccessResponse(c, http.StatusOK, "Patient history retrieved successfully", history)
+}
+
+func (h *PatientHistoryHandler) GetVersion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.ValidationErrorResponse(c, "Invalid version", err)
+		return
+	}
+
+	revision, err := h.historyService.GetPatientVersion(uint(id), version)
+	if err != nil {
+		utils.NotFoundResponse(c, "Patient revision not found")
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(id))
+	utils.SuccessResponse(c, http.StatusOK, "Patient revision retrieved successfully", revision)
+}
+
+func (h *PatientHistoryHandler) DiffVersions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	fromVersion, err := strconv.Atoi(c.Query("from"))
+	if err != nil || fromVersion < 1 {
+		utils.ValidationErrorResponse(c, "Invalid from version", err)
+		return
+	}
+
+	toVersion, err := strconv.Atoi(c.Query("to"))
+	if err != nil || toVersion < 1 {
+		utils.ValidationErrorResponse(c, "Invalid to version", err)
+		return
+	}
+
+	diff, err := h.historyService.DiffPatientVersions(uint(id), fromVersion, toVersion)
+	if err != nil {
+		if err.Error() == "patient revision not found" {
+			utils.NotFoundResponse(c, "Patient revision not found")
+			return
+		}
+		utils.InternalErrorResponse(c, "Failed to compare patient revisions", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(id))
+	utils.SuccessResponse(c, http.StatusOK, "Patient revisions compared successfully", diff)
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrescriptionHandler struct {
+	prescriptionService *services.PrescriptionService
+}
+
+func NewPrescriptionHandler(prescriptionService *services.PrescriptionService) *PrescriptionHandler {
+	return &PrescriptionHandler{
+		prescriptionService: prescriptionService,
+	}
+}
+
+func (h *PrescriptionHandler) ListPrescriptions(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	prescriptions, err := h.prescriptionService.ListPrescriptions(uint(patientID), models.PrescriptionStatus(c.Query("status")), userRole)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to retrieve prescriptions", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Prescriptions retrieved successfully", prescriptions)
+}
+
+func (h *PrescriptionHandler) ActiveMedications(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	prescriptions, err := h.prescriptionService.ActiveMedications(uint(patientID), userRole)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to retrieve active medications", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Active medications retrieved successfully", prescriptions)
+}
+
+func (h *PrescriptionHandler) GetPrescription(c *gin.Context) {
+	patientID, prescriptionID, ok := parsePrescriptionParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	prescription, err := h.prescriptionService.GetPrescription(patientID, prescriptionID, userRole)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to retrieve prescription", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Prescription retrieved successfully", prescription)
+}
+
+func (h *PrescriptionHandler) CreatePrescription(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreatePrescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	prescription, err := h.prescriptionService.CreatePrescription(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to create prescription", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Prescription created successfully", prescription)
+}
+
+func (h *PrescriptionHandler) StopPrescription(c *gin.Context) {
+	patientID, prescriptionID, ok := parsePrescriptionParams(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	prescription, err := h.prescriptionService.StopPrescription(patientID, prescriptionID, userID, userRole)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to stop prescription", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Prescription stopped successfully", prescription)
+}
+
+func (h *PrescriptionHandler) DiscontinuePrescription(c *gin.Context) {
+	patientID, prescriptionID, ok := parsePrescriptionParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.DiscontinuePrescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	prescription, err := h.prescriptionService.DiscontinuePrescription(patientID, prescriptionID, req, userID, userRole)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to discontinue prescription", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Prescription discontinued successfully", prescription)
+}
+
+func parsePrescriptionParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	prescriptionID, err := strconv.ParseUint(c.Param("prescription_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid prescription ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(prescriptionID), true
+}
+
+func respondPrescriptionError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage prescriptions")
+		return
+	}
+
+	var safetyErr *services.SafetyCheckError
+	if errors.As(err, &safetyErr) {
+		status := http.StatusUnprocessableEntity
+		if safetyErr.Overridable() {
+			status = http.StatusConflict
+		}
+		utils.ErrorResponseWithData(c, status, err.Error(), err, gin.H{
+			"alerts":      safetyErr.Alerts,
+			"overridable": safetyErr.Overridable(),
+		})
+		return
+	}
+
+	switch err.Error() {
+	case "prescription not found", "patient not found", "medication not found", "encounter not found for this patient":
+		utils.NotFoundResponse(c, err.Error())
+	case "only active prescriptions can be stopped or discontinued":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "invalid start date format, use YYYY-MM-DD", "start date cannot be in the past":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceListHandler struct {
+	priceListService *services.PriceListService
+}
+
+func NewPriceListHandler(priceListService *services.PriceListService) *PriceListHandler {
+	return &PriceListHandler{
+		priceListService: priceListService,
+	}
+}
+
+func (h *PriceListHandler) ListItems(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	req := services.PriceListQuery{
+		Search:          c.Query("q"),
+		Category:        c.Query("category"),
+		IncludeInactive: c.Query("include_inactive") == "true",
+	}
+
+	items, err := h.priceListService.ListItems(req, page, pageSize)
+	if err != nil {
+		utils.InternalErrorResponse(c, "Failed to retrieve price list", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Price list retrieved successfully", items)
+}
+
+func (h *PriceListHandler) GetItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid price list item ID", err)
+		return
+	}
+
+	item, err := h.priceListService.GetItem(uint(id))
+	if err != nil {
+		respondPriceListError(c, "Failed to retrieve price list item", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Price list item retrieved successfully", item)
+}
+
+func (h *PriceListHandler) CreateItem(c *gin.Context) {
+	var req services.CreatePriceListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	item, err := h.priceListService.CreateItem(req)
+	if err != nil {
+		respondPriceListError(c, "Failed to create price list item", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Price list item created successfully", item)
+}
+
+func (h *PriceListHandler) UpdateItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid price list item ID", err)
+		return
+	}
+
+	var req services.UpdatePriceListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	item, err := h.priceListService.UpdateItem(uint(id), req)
+	if err != nil {
+		respondPriceListError(c, "Failed to update price list item", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Price list item updated successfully", item)
+}
+
+func respondPriceListError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "price list item not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "price list item already exists":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "price list item code is required":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
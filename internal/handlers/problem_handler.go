@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProblemHandler struct {
+	problemService *services.ProblemService
+}
+
+func NewProblemHandler(problemService *services.ProblemService) *ProblemHandler {
+	return &ProblemHandler{
+		problemService: problemService,
+	}
+}
+
+func (h *ProblemHandler) ListProblems(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	problems, err := h.problemService.ListProblems(uint(patientID), models.ProblemStatus(c.Query("status")), userRole)
+	if err != nil {
+		respondProblemError(c, "Failed to retrieve problems", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Problems retrieved successfully", problems)
+}
+
+func (h *ProblemHandler) GetProblem(c *gin.Context) {
+	patientID, problemID, ok := parseProblemParams(c)
+	if !ok {
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	problem, err := h.problemService.GetProblem(patientID, problemID, userRole)
+	if err != nil {
+		respondProblemError(c, "Failed to retrieve problem", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Problem retrieved successfully", problem)
+}
+
+func (h *ProblemHandler) CreateProblem(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.CreateProblemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	problem, err := h.problemService.CreateProblem(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondProblemError(c, "Failed to create problem", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Problem created successfully", problem)
+}
+
+func (h *ProblemHandler) UpdateProblem(c *gin.Context) {
+	patientID, problemID, ok := parseProblemParams(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateProblemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	problem, err := h.problemService.UpdateProblem(patientID, problemID, req, userID, userRole)
+	if err != nil {
+		respondProblemError(c, "Failed to update problem", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, patientID)
+	utils.SuccessResponse(c, http.StatusOK, "Problem updated successfully", problem)
+}
+
+func (h *ProblemHandler) ListPatientsWithDiagnosis(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	req := services.DiagnosisQuery{
+		Code:   c.Query("code"),
+		Status: models.ProblemStatus(c.Query("status")),
+	}
+
+	patients, err := h.problemService.ListPatientsWithDiagnosis(req, page, pageSize, userRole)
+	if err != nil {
+		respondProblemError(c, "Failed to retrieve patients", err)
+		return
+	}
+
+	setAccessedPatientList(c, patients)
+	utils.SuccessResponse(c, http.StatusOK, "Patients retrieved successfully", patients)
+}
+
+func parseProblemParams(c *gin.Context) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return 0, 0, false
+	}
+
+	problemID, err := strconv.ParseUint(c.Param("problem_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid problem ID", err)
+		return 0, 0, false
+	}
+
+	return uint(patientID), uint(problemID), true
+}
+
+func respondProblemError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage problems")
+		return
+	}
+
+	switch err.Error() {
+	case "problem not found", "patient not found", "ICD-10 code not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "an active problem with this code is already recorded":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "invalid ICD-10 code", "invalid onset date format, use YYYY-MM-DD", "onset date cannot be in the future",
+		"invalid resolved date format, use YYYY-MM-DD", "resolved date cannot be in the future",
+		"resolved date cannot be before onset date", "only resolved problems have a resolved date":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	userService *services.UserService
+}
+
+func NewUserHandler(userService *services.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	req := services.UserListQuery{
+		Role:   models.UserRole(c.Query("role")),
+		Status: c.Query("status"),
+		Search: c.Query("q"),
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	users, err := h.userService.ListUsers(req, page, pageSize)
+	if err != nil {
+		if err.Error() == "failed to retrieve users" || err.Error() == "failed to count users" {
+			utils.InternalErrorResponse(c, "Failed to retrieve users", err)
+			return
+		}
+		utils.ValidationErrorResponse(c, err.Error(), err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", users)
+}
+
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetUser(id)
+	if err != nil {
+		utils.NotFoundResponse(c, "User not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User retrieved successfully", user)
+}
+
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req services.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	user, err := h.userService.CreateUser(req)
+	if err != nil {
+		respondUserError(c, "Failed to create user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "User created successfully", user)
+}
+
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	actorID, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	user, err := h.userService.UpdateUser(id, req, actorID)
+	if err != nil {
+		respondUserError(c, "Failed to update user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User updated successfully", user)
+}
+
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	actorID, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	if err := h.userService.DeactivateUser(id, actorID); err != nil {
+		respondUserError(c, "Failed to deactivate user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User deactivated successfully", nil)
+}
+
+func (h *UserHandler) ReactivateUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.ReactivateUser(id)
+	if err != nil {
+		respondUserError(c, "Failed to reactivate user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User reactivated successfully", user)
+}
+
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := h.userService.ResetPassword(id, req); err != nil {
+		respondUserError(c, "Failed to reset password", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid user ID", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondUserError(c *gin.Context, message string, err error) {
+	if strings.HasPrefix(err.Error(), "role must be one of") {
+		utils.ValidationErrorResponse(c, err.Error(), err)
+		return
+	}
+
+	switch err.Error() {
+	case "user not found":
+		utils.NotFoundResponse(c, "User not found")
+	case "username already exists", "email already exists", "user is already deactivated", "user is already active":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "you cannot change your own role", "you cannot deactivate your own account", "cannot remove the last active administrator":
+		utils.ForbiddenResponse(c, err.Error())
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
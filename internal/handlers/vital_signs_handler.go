@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VitalSignsHandler struct {
+	vitalSignsService *services.VitalSignsService
+}
+
+func NewVitalSignsHandler(vitalSignsService *services.VitalSignsService) *VitalSignsHandler {
+	return &VitalSignsHandler{
+		vitalSignsService: vitalSignsService,
+	}
+}
+
+func (h *VitalSignsHandler) ListVitalSigns(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	req := services.VitalSignsQuery{From: c.Query("from"), To: c.Query("to")}
+
+	vitals, err := h.vitalSignsService.ListVitalSigns(uint(patientID), req, userRole)
+	if err != nil {
+		respondVitalSignsError(c, "Failed to retrieve vital signs", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Vital signs retrieved successfully", vitals)
+}
+
+func (h *VitalSignsHandler) GetTrend(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	req := services.VitalSignsQuery{From: c.Query("from"), To: c.Query("to")}
+
+	trend, err := h.vitalSignsService.GetTrend(uint(patientID), c.Query("measure"), req, userRole)
+	if err != nil {
+		respondVitalSignsError(c, "Failed to retrieve vital signs trend", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Vital signs trend retrieved successfully", trend)
+}
+
+func (h *VitalSignsHandler) GetVitalSigns(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	vitalsID, err := strconv.ParseUint(c.Param("vitals_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid vital signs ID", err)
+		return
+	}
+
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	vitals, err := h.vitalSignsService.GetVitalSigns(uint(patientID), uint(vitalsID), userRole)
+	if err != nil {
+		respondVitalSignsError(c, "Failed to retrieve vital signs", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusOK, "Vital signs retrieved successfully", vitals)
+}
+
+func (h *VitalSignsHandler) RecordVitalSigns(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid patient ID", err)
+		return
+	}
+
+	var req services.RecordVitalSignsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	userID, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	vitals, err := h.vitalSignsService.RecordVitalSigns(uint(patientID), req, userID, userRole)
+	if err != nil {
+		respondVitalSignsError(c, "Failed to record vital signs", err)
+		return
+	}
+
+	middleware.SetAccessedPatients(c, uint(patientID))
+	utils.SuccessResponse(c, http.StatusCreated, "Vital signs recorded successfully", vitals)
+}
+
+func respondVitalSignsError(c *gin.Context, message string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ForbiddenResponse(c, "Insufficient permissions to manage vital signs")
+		return
+	}
+
+	switch err.Error() {
+	case "vital signs not found", "patient not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "invalid recorded_at format, use RFC 3339", "recorded_at cannot be in the future",
+		"at least one measurement is required", "systolic and diastolic blood pressure must be recorded together",
+		"systolic blood pressure must be higher than diastolic", "unknown vital sign measure",
+		"invalid from format, use RFC 3339", "invalid to format, use RFC 3339",
+		"from must be before to", "period cannot be longer than two years":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WardHandler struct {
+	wardService *services.WardService
+}
+
+func NewWardHandler(wardService *services.WardService) *WardHandler {
+	return &WardHandler{
+		wardService: wardService,
+	}
+}
+
+func (h *WardHandler) ListWards(c *gin.Context) {
+	wards, err := h.wardService.ListWards(c.Query("include_inactive") == "true")
+	if err != nil {
+		utils.InternalErrorResponse(c, "Failed to retrieve wards", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Wards retrieved successfully", wards)
+}
+
+func (h *WardHandler) Board(c *gin.Context) {
+	_, userRole, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User context not found")
+		return
+	}
+
+	board, err := h.wardService.Board(userRole)
+	if err != nil {
+		utils.InternalErrorResponse(c, "Failed to retrieve bed board", err)
+		return
+	}
+
+	var patientIDs []uint
+	for _, ward := range board.Wards {
+		for _, room := range ward.Rooms {
+			for _, bed := range room.Beds {
+				if bed.Patient != nil {
+					patientIDs = append(patientIDs, bed.Patient.ID)
+				}
+			}
+		}
+	}
+	middleware.SetAccessedPatients(c, patientIDs...)
+	utils.SuccessResponse(c, http.StatusOK, "Bed board retrieved successfully", board)
+}
+
+func (h *WardHandler) CreateWard(c *gin.Context) {
+	var req services.CreateWardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	ward, err := h.wardService.CreateWard(req)
+	if err != nil {
+		respondWardError(c, "Failed to create ward", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Ward created successfully", ward)
+}
+
+func (h *WardHandler) CreateRoom(c *gin.Context) {
+	wardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid ward ID", err)
+		return
+	}
+
+	var req services.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	room, err := h.wardService.CreateRoom(uint(wardID), req)
+	if err != nil {
+		respondWardError(c, "Failed to create room", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Room created successfully", room)
+}
+
+func (h *WardHandler) CreateBed(c *gin.Context) {
+	wardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid ward ID", err)
+		return
+	}
+
+	roomID, err := strconv.ParseUint(c.Param("room_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid room ID", err)
+		return
+	}
+
+	var req services.CreateBedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	bed, err := h.wardService.CreateBed(uint(wardID), uint(roomID), req)
+	if err != nil {
+		respondWardError(c, "Failed to create bed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Bed created successfully", bed)
+}
+
+func (h *WardHandler) UpdateBed(c *gin.Context) {
+	bedID, err := strconv.ParseUint(c.Param("bed_id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid bed ID", err)
+		return
+	}
+
+	var req services.UpdateBedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, "Invalid request data", err)
+		return
+	}
+
+	bed, err := h.wardService.UpdateBed(uint(bedID), req)
+	if err != nil {
+		respondWardError(c, "Failed to update bed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bed updated successfully", bed)
+}
+
+func respondWardError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "ward not found", "room not found", "bed not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "ward already exists", "occupied beds cannot be taken out of service":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+	case "ward name is required", "room number is required", "bed label is required":
+		utils.ValidationErrorResponse(c, err.Error(), err)
+	default:
+		utils.InternalErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,195 @@
+package matching
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"hospital-management-system/internal/models"
+)
+
+// MatchLevel says how likely two patient records are the same person.
+type MatchLevel string
+
+const (
+	MatchLevelLikely   MatchLevel = "likely"
+	MatchLevelPossible MatchLevel = "possible"
+)
+
+// Scores are out of 100. A matching name and date of birth alone reach
+// LikelyScore; a phone number or email has to back up anything weaker.
+const (
+	LikelyScore   = 70
+	PossibleScore = 50
+
+	nameWeight  = 40
+	dobWeight   = 30
+	phoneWeight = 15
+	emailWeight = 15
+
+	// minNameSimilarity is the Jaro-Winkler similarity below which names
+	// are considered different and score nothing.
+	minNameSimilarity = 0.75
+)
+
+// Match is the result of comparing a patient with a candidate record.
+type Match struct {
+	Score   int        `json:"score"`
+	Level   MatchLevel `json:"level,omitempty"`
+	Reasons []string   `json:"reasons"`
+}
+
+// IsDuplicate reports whether the match is at least a possible duplicate.
+func (m Match) IsDuplicate() bool {
+	return m.Level != ""
+}
+
+// Score compares two patients on name similarity, date of birth, phone
+// number and email. Names are compared with Jaro-Winkler similarity, also
+// with first and last name swapped; a date of birth with day and month
+// transposed counts for half.
+func Score(a, b *models.Patient) Match {
+	match := Match{Reasons: []string{}}
+
+	similarity := math.Max(
+		(JaroWinkler(normalizeName(a.FirstName), normalizeName(b.FirstName))+JaroWinkler(normalizeName(a.LastName), normalizeName(b.LastName)))/2,
+		(JaroWinkler(normalizeName(a.FirstName), normalizeName(b.LastName))+JaroWinkler(normalizeName(a.LastName), normalizeName(b.FirstName)))/2,
+	)
+	if similarity >= minNameSimilarity {
+		match.Score += int(math.Round(nameWeight * similarity))
+		if similarity >= 0.99 {
+			match.Reasons = append(match.Reasons, "same name")
+		} else {
+			match.Reasons = append(match.Reasons, "similar name")
+		}
+	}
+
+	if !a.DateOfBirth.IsZero() && !b.DateOfBirth.IsZero() {
+		ay, am, ad := a.DateOfBirth.UTC().Date()
+		by, bm, bd := b.DateOfBirth.UTC().Date()
+		switch {
+		case ay == by && am == bm && ad == bd:
+			match.Score += dobWeight
+			match.Reasons = append(match.Reasons, "same date of birth")
+		case ay == by && int(am) == bd && ad == int(bm):
+			match.Score += dobWeight / 2
+			match.Reasons = append(match.Reasons, "date of birth with day and month swapped")
+		}
+	}
+
+	if phone := NormalizePhone(a.Phone); phone != "" && phone == NormalizePhone(b.Phone) {
+		match.Score += phoneWeight
+		match.Reasons = append(match.Reasons, "same phone number")
+	}
+
+	if email := NormalizeEmail(a.Email); email != "" && email == NormalizeEmail(b.Email) {
+		match.Score += emailWeight
+		match.Reasons = append(match.Reasons, "same email")
+	}
+
+	if match.Score > 100 {
+		match.Score = 100
+	}
+	switch {
+	case match.Score >= LikelyScore:
+		match.Level = MatchLevelLikely
+	case match.Score >= PossibleScore:
+		match.Level = MatchLevelPossible
+	}
+	return match
+}
+
+// SwappedDate returns the date with day and month transposed, and false if
+// that is not a different valid date.
+func SwappedDate(date time.Time) (time.Time, bool) {
+	year, month, day := date.UTC().Date()
+	if day > 12 || day == int(month) {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(day), int(month), 0, 0, 0, 0, time.UTC), true
+}
+
+// NormalizePhone keeps only the digits of a phone number.
+func NormalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeName lowercases a name and drops everything but letters, so that
+// "O'Neil" and "Oneil" compare equal.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings, from 0 for
+// nothing in common to 1 for equal strings. It favours strings that share a
+// prefix, which suits names with typos near the end.
+func JaroWinkler(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 || len(t) == 0 {
+		if len(s) == len(t) {
+			return 1
+		}
+		return 0
+	}
+
+	window := max(len(s), len(t))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i] = true
+				tMatched[j] = true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
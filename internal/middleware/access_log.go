@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"hospital-management-system/internal/phi"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const accessedPatientsKey = "accessed_patient_ids"
+
+// SetAccessedPatients marks patient records as returned by the current request
+// so that PatientAccessLogger can record them once the handler completes.
+func SetAccessedPatients(c *gin.Context, patientIDs ...uint) {
+	var ids []uint
+	if value, exists := c.Get(accessedPatientsKey); exists {
+		if current, ok := value.([]uint); ok {
+			ids = current
+		}
+	}
+
+	c.Set(accessedPatientsKey, append(ids, patientIDs...))
+}
+
+// PatientAccessLogger writes a PHI access log entry for every patient record
+// a handler reported through SetAccessedPatients. The response is held back
+// until the entry is written, so PHI never leaves without an audit record;
+// if the write fails the client gets a 500 instead. Handlers only report
+// patients whose data they return, which includes client errors such as the
+// duplicate-candidate conflict, so only server errors are skipped.
+func PatientAccessLogger(accessLogService *services.AccessLogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &bufferedResponseWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		// Restored on the way out even if a handler panics, so that
+		// Recovery can still respond.
+		defer func() { c.Writer = writer.ResponseWriter }()
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if err := recordPatientAccess(c, accessLogService, writer.status); err != nil {
+			log.Printf("Failed to record patient access: %v", err)
+			c.Writer.Header().Del("Content-Length")
+			utils.InternalErrorResponse(c, "Failed to record patient access", nil)
+			return
+		}
+
+		writer.flush()
+	}
+}
+
+func recordPatientAccess(c *gin.Context, accessLogService *services.AccessLogService, status int) error {
+	if status >= 500 {
+		return nil
+	}
+
+	value, exists := c.Get(accessedPatientsKey)
+	if !exists {
+		return nil
+	}
+
+	patientIDs, ok := value.([]uint)
+	if !ok || len(patientIDs) == 0 {
+		return nil
+	}
+
+	userID, userRole, err := GetUserFromContext(c)
+	if err != nil {
+		return errors.New("user context not found")
+	}
+
+	ctx := services.AccessContext{
+		UserID:   userID,
+		UserRole: userRole,
+		Endpoint: c.Request.Method + " " + c.FullPath(),
+		Query:    redactQuery(c.Request.URL.RawQuery),
+		ClientIP: c.ClientIP(),
+	}
+
+	if err := accessLogService.RecordPatientAccess(ctx, patientIDs); err != nil {
+		return fmt.Errorf("user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// bufferedResponseWriter keeps the status and body a handler writes until
+// flush is called. Headers go straight to the underlying writer.
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedResponseWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedResponseWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedResponseWriter) Written() bool {
+	return w.written
+}
+
+func (w *bufferedResponseWriter) Flush() {}
+
+func (w *bufferedResponseWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// redactQuery replaces a phone number or email address searched for with
+// its blind index, so the access log does not keep them in plaintext but a
+// search for a given number or address can still be traced. Without a PHI
+// keyring they are dropped.
+func redactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ""
+	}
+
+	terms, ok := values["q"]
+	if !ok {
+		return rawQuery
+	}
+
+	keyring := phi.CurrentKeyring()
+	for i, term := range terms {
+		switch {
+		case strings.Contains(term, "@"):
+			terms[i] = "email_index:" + keyring.EmailIndex(term)
+		case strings.IndexFunc(term, unicode.IsDigit) >= 0 && strings.IndexFunc(term, unicode.IsLetter) < 0:
+			terms[i] = "phone_index:" + keyring.PhoneIndex(term)
+		}
+	}
+	return values.Encode()
+}
@@ -4,37 +4,63 @@ import (
 	"net/http"
 	"strings"
 
-	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/authz"
 	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
 	"hospital-management-system/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
+func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			utils.UnauthorizedResponse(c, "Authorization header is required")
+		token, ok := bearerToken(c)
+		if !ok {
 			c.Abort()
 			return
 		}
 
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			utils.UnauthorizedResponse(c, "Invalid authorization header format")
+		claims, err := authService.ValidateToken(token)
+		if err != nil {
+			utils.UnauthorizedResponse(c, "Invalid or expired token")
 			c.Abort()
 			return
 		}
 
-		token := tokenParts[1]
-		claims, err := jwtService.ValidateToken(token)
-		if err != nil {
-			utils.UnauthorizedResponse(c, "Invalid or expired token")
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("claims", claims)
+
+		c.Next()
+	}
+}
+
+// MFAEnrollmentAuth authenticates either a normal access token or the MFA
+// enrollment token handed out at login when the role policy requires MFA but
+// the user has not enrolled yet. The latter sets "mfa_enrollment" to true.
+func MFAEnrollmentAuth(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
 			c.Abort()
 			return
 		}
 
+		claims, err := authService.ValidateToken(token)
+		if err == nil {
+			c.Set("session_id", claims.SessionID)
+		} else {
+			claims, err = authService.ValidateMFAEnrollmentToken(token)
+			if err != nil {
+				utils.UnauthorizedResponse(c, "Invalid or expired token")
+				c.Abort()
+				return
+			}
+			c.Set("mfa_enrollment", true)
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("user_role", claims.Role)
@@ -44,7 +70,25 @@ func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
 	}
 }
 
-func RequireRole(allowedRoles ...models.UserRole) gin.HandlerFunc {
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		utils.UnauthorizedResponse(c, "Authorization header is required")
+		return "", false
+	}
+
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		utils.UnauthorizedResponse(c, "Invalid authorization header format")
+		return "", false
+	}
+
+	return tokenParts[1], true
+}
+
+// RequirePermission rejects the request unless the caller's role holds
+// permission under policy.
+func RequirePermission(policy *authz.Policy, permission authz.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("user_role")
 		if !exists {
@@ -60,30 +104,16 @@ func RequireRole(allowedRoles ...models.UserRole) gin.HandlerFunc {
 			return
 		}
 
-		for _, allowedRole := range allowedRoles {
-			if role == allowedRole {
-				c.Next()
-				return
-			}
+		if !policy.Can(role, permission) {
+			utils.ForbiddenResponse(c, "Insufficient permissions")
+			c.Abort()
+			return
 		}
 
-		utils.ForbiddenResponse(c, "Insufficient permissions")
-		c.Abort()
+		c.Next()
 	}
 }
 
-func RequireReceptionist() gin.HandlerFunc {
-	return RequireRole(models.RoleReceptionist)
-}
-
-func RequireDoctor() gin.HandlerFunc {
-	return RequireRole(models.RoleDoctor)
-}
-
-func RequireReceptionistOrDoctor() gin.HandlerFunc {
-	return RequireRole(models.RoleReceptionist, models.RoleDoctor)
-}
-
 func GetUserFromContext(c *gin.Context) (uint, models.UserRole, error) {
 	userID, exists := c.Get("user_id")
 	if !exists {
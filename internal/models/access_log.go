@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PatientAccessLog records a single patient record being returned to a user.
+type PatientAccessLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;index"`
+	UserRole   UserRole  `json:"user_role" gorm:"not null"`
+	PatientID  uint      `json:"patient_id" gorm:"not null;index"`
+	Endpoint   string    `json:"endpoint" gorm:"not null"`
+	Query      string    `json:"query" gorm:"type:text"`
+	ClientIP   string    `json:"client_ip"`
+	AccessedAt time.Time `json:"accessed_at" gorm:"not null;index"`
+}
+
+func (PatientAccessLog) TableName() string {
+	return "patient_access_logs"
+}
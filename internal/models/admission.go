@@ -0,0 +1,170 @@
+package models
+
+import "time"
+
+// Ward is an inpatient unit. Wards, rooms and beds are deactivated rather
+// than deleted so that past admissions keep their location.
+type Ward struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	IsActive    bool      `json:"is_active" gorm:"not null;default:true"`
+	Rooms       []Room    `json:"rooms,omitempty" gorm:"foreignKey:WardID"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Ward) TableName() string {
+	return "wards"
+}
+
+type Room struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	WardID    uint      `json:"ward_id" gorm:"not null;uniqueIndex:idx_rooms_ward_number"`
+	Ward      *Ward     `json:"ward,omitempty" gorm:"foreignKey:WardID"`
+	Number    string    `json:"number" gorm:"not null;uniqueIndex:idx_rooms_ward_number"`
+	IsActive  bool      `json:"is_active" gorm:"not null;default:true"`
+	Beds      []Bed     `json:"beds,omitempty" gorm:"foreignKey:RoomID"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Room) TableName() string {
+	return "rooms"
+}
+
+type Bed struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	RoomID    uint      `json:"room_id" gorm:"not null;uniqueIndex:idx_beds_room_label"`
+	Room      *Room     `json:"room,omitempty" gorm:"foreignKey:RoomID"`
+	Label     string    `json:"label" gorm:"not null;uniqueIndex:idx_beds_room_label"`
+	IsActive  bool      `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Bed) TableName() string {
+	return "beds"
+}
+
+// BedLocation describes where a bed is for display on admissions.
+type BedLocation struct {
+	BedID      uint   `json:"bed_id"`
+	BedLabel   string `json:"bed_label"`
+	RoomID     uint   `json:"room_id"`
+	RoomNumber string `json:"room_number"`
+	WardID     uint   `json:"ward_id"`
+	WardName   string `json:"ward_name"`
+}
+
+// Location requires the bed's room and ward to be loaded; missing parts are
+// left empty.
+func (b *Bed) Location() BedLocation {
+	location := BedLocation{BedID: b.ID, BedLabel: b.Label, RoomID: b.RoomID}
+	if b.Room != nil {
+		location.RoomNumber = b.Room.Number
+		location.WardID = b.Room.WardID
+		if b.Room.Ward != nil {
+			location.WardName = b.Room.Ward.Name
+		}
+	}
+	return location
+}
+
+type AdmissionStatus string
+
+const (
+	AdmissionStatusAdmitted   AdmissionStatus = "admitted"
+	AdmissionStatusDischarged AdmissionStatus = "discharged"
+)
+
+// Admission is an inpatient stay. BedID is the bed the patient currently
+// occupies, or occupied at discharge; earlier beds are kept as transfers.
+// A bed is occupied while an admitted admission points at it; partial
+// unique indexes back up the repository's locking so that neither a bed nor
+// a patient can have two current admissions.
+type Admission struct {
+	ID                uint            `json:"id" gorm:"primaryKey"`
+	PatientID         uint            `json:"patient_id" gorm:"not null;index;uniqueIndex:idx_admissions_current_patient,where:status = 'admitted'"`
+	Patient           *Patient        `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	BedID             uint            `json:"bed_id" gorm:"not null;index;uniqueIndex:idx_admissions_occupied_bed,where:status = 'admitted'"`
+	Bed               *Bed            `json:"bed,omitempty" gorm:"foreignKey:BedID"`
+	AdmittingDoctorID uint            `json:"admitting_doctor_id" gorm:"not null"`
+	AdmittingDoctor   User            `json:"admitting_doctor" gorm:"foreignKey:AdmittingDoctorID"`
+	Reason            string          `json:"reason" gorm:"type:text;not null"`
+	Status            AdmissionStatus `json:"status" gorm:"not null;default:admitted;index"`
+	AdmittedAt        time.Time       `json:"admitted_at" gorm:"not null"`
+	AdmittedByID      uint            `json:"admitted_by_id" gorm:"not null"`
+	DischargedAt      *time.Time      `json:"discharged_at"`
+	DischargedByID    *uint           `json:"discharged_by_id"`
+	DischargeNotes    string          `json:"discharge_notes" gorm:"type:text"`
+	Transfers         []BedTransfer   `json:"transfers,omitempty" gorm:"foreignKey:AdmissionID"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+func (Admission) TableName() string {
+	return "admissions"
+}
+
+// BedTransfer records a move between beds during an admission.
+type BedTransfer struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	AdmissionID     uint      `json:"admission_id" gorm:"not null;index"`
+	FromBedID       uint      `json:"from_bed_id" gorm:"not null"`
+	ToBedID         uint      `json:"to_bed_id" gorm:"not null"`
+	Reason          string    `json:"reason" gorm:"type:text"`
+	TransferredByID uint      `json:"transferred_by_id" gorm:"not null"`
+	TransferredAt   time.Time `json:"transferred_at" gorm:"not null"`
+}
+
+func (BedTransfer) TableName() string {
+	return "bed_transfers"
+}
+
+type AdmissionResponse struct {
+	ID              uint            `json:"id"`
+	PatientID       uint            `json:"patient_id"`
+	Patient         *PatientSummary `json:"patient,omitempty"`
+	Location        BedLocation     `json:"location"`
+	AdmittingDoctor UserResponse    `json:"admitting_doctor"`
+	Reason          string          `json:"reason"`
+	Status          AdmissionStatus `json:"status"`
+	AdmittedAt      time.Time       `json:"admitted_at"`
+	DischargedAt    *time.Time      `json:"discharged_at,omitempty"`
+	DischargeNotes  string          `json:"discharge_notes,omitempty"`
+	Transfers       []BedTransfer   `json:"transfers"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+func (a *Admission) ToResponse() AdmissionResponse {
+	transfers := a.Transfers
+	if transfers == nil {
+		transfers = []BedTransfer{}
+	}
+
+	response := AdmissionResponse{
+		ID:              a.ID,
+		PatientID:       a.PatientID,
+		Location:        BedLocation{BedID: a.BedID},
+		AdmittingDoctor: a.AdmittingDoctor.ToResponse(),
+		Reason:          a.Reason,
+		Status:          a.Status,
+		AdmittedAt:      a.AdmittedAt,
+		DischargedAt:    a.DischargedAt,
+		DischargeNotes:  a.DischargeNotes,
+		Transfers:       transfers,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+
+	if a.Bed != nil {
+		response.Location = a.Bed.Location()
+	}
+	if a.Patient != nil {
+		patient := a.Patient.ToSummary()
+		response.Patient = &patient
+	}
+	return response
+}
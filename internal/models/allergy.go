@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+type AllergyCategory string
+
+const (
+	AllergyCategoryMedication  AllergyCategory = "medication"
+	AllergyCategoryFood        AllergyCategory = "food"
+	AllergyCategoryEnvironment AllergyCategory = "environment"
+	AllergyCategoryOther       AllergyCategory = "other"
+)
+
+type AllergySeverity string
+
+const (
+	AllergySeverityMild     AllergySeverity = "mild"
+	AllergySeverityModerate AllergySeverity = "moderate"
+	AllergySeveritySevere   AllergySeverity = "severe"
+)
+
+type AllergyStatus string
+
+const (
+	AllergyStatusActive         AllergyStatus = "active"
+	AllergyStatusInactive       AllergyStatus = "inactive"
+	AllergyStatusResolved       AllergyStatus = "resolved"
+	AllergyStatusEnteredInError AllergyStatus = "entered_in_error"
+)
+
+// LegacyAllergySubstance is the substance recorded on allergies migrated
+// from the old free-text Patient.Allergies column. The original text is kept
+// in Notes until a clinician replaces it with structured entries.
+const LegacyAllergySubstance = "Unstructured allergy note"
+
+type Allergy struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	PatientID uint            `json:"patient_id" gorm:"not null;index"`
+	Substance string          `json:"substance" gorm:"not null"`
+	Category  AllergyCategory `json:"category" gorm:"not null"`
+	Reaction  string          `json:"reaction" gorm:"type:text"`
+	Severity  AllergySeverity `json:"severity"`
+	OnsetDate *time.Time      `json:"onset_date"`
+	Status    AllergyStatus   `json:"status" gorm:"not null;default:active;index"`
+	Notes     string          `json:"notes" gorm:"type:text"`
+	IsLegacy  bool            `json:"is_legacy" gorm:"not null;default:false"`
+
+	// Encryption fields, see clinical_phi.go
+	EncryptedDataKey string `json:"-"`
+	KeyVersion       int    `json:"-" gorm:"not null;default:0"`
+
+	// System fields
+	RecordedByID    uint      `json:"recorded_by_id" gorm:"not null"`
+	RecordedBy      User      `json:"recorded_by" gorm:"foreignKey:RecordedByID"`
+	LastUpdatedByID *uint     `json:"last_updated_by_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (Allergy) TableName() string {
+	return "allergies"
+}
+
+type AllergyResponse struct {
+	ID         uint            `json:"id"`
+	PatientID  uint            `json:"patient_id"`
+	Substance  string          `json:"substance"`
+	Category   AllergyCategory `json:"category"`
+	Reaction   string          `json:"reaction"`
+	Severity   AllergySeverity `json:"severity,omitempty"`
+	OnsetDate  *time.Time      `json:"onset_date,omitempty"`
+	Status     AllergyStatus   `json:"status"`
+	Notes      string          `json:"notes,omitempty"`
+	IsLegacy   bool            `json:"is_legacy"`
+	RecordedBy UserResponse    `json:"recorded_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func (a *Allergy) ToResponse() AllergyResponse {
+	return AllergyResponse{
+		ID:         a.ID,
+		PatientID:  a.PatientID,
+		Substance:  a.Substance,
+		Category:   a.Category,
+		Reaction:   a.Reaction,
+		Severity:   a.Severity,
+		OnsetDate:  a.OnsetDate,
+		Status:     a.Status,
+		Notes:      a.Notes,
+		IsLegacy:   a.IsLegacy,
+		RecordedBy: a.RecordedBy.ToResponse(),
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+	}
+}
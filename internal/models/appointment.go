@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AppointmentStatus string
+
+const (
+	AppointmentStatusScheduled AppointmentStatus = "scheduled"
+	AppointmentStatusCheckedIn AppointmentStatus = "checked_in"
+	AppointmentStatusCompleted AppointmentStatus = "completed"
+	AppointmentStatusCancelled AppointmentStatus = "cancelled"
+)
+
+// BlocksSchedule reports whether an appointment in this status still occupies
+// the doctor's and patient's time.
+func (s AppointmentStatus) BlocksSchedule() bool {
+	return s == AppointmentStatusScheduled || s == AppointmentStatusCheckedIn
+}
+
+type Appointment struct {
+	ID                 uint              `json:"id" gorm:"primaryKey"`
+	PatientID          uint              `json:"patient_id" gorm:"not null;index:idx_appointments_patient_start"`
+	Patient            Patient           `json:"patient" gorm:"foreignKey:PatientID"`
+	DoctorID           uint              `json:"doctor_id" gorm:"not null;index:idx_appointments_doctor_start"`
+	Doctor             User              `json:"doctor" gorm:"foreignKey:DoctorID"`
+	StartTime          time.Time         `json:"start_time" gorm:"not null;index:idx_appointments_doctor_start;index:idx_appointments_patient_start"`
+	EndTime            time.Time         `json:"end_time" gorm:"not null"`
+	Reason             string            `json:"reason" gorm:"type:text"`
+	Status             AppointmentStatus `json:"status" gorm:"not null;default:scheduled;index"`
+	CancellationReason string            `json:"cancellation_reason" gorm:"type:text"`
+	CheckedInAt        *time.Time        `json:"checked_in_at"`
+	CompletedAt        *time.Time        `json:"completed_at"`
+	CancelledAt        *time.Time        `json:"cancelled_at"`
+
+	// System fields
+	CreatedByID     uint           `json:"created_by_id" gorm:"not null"`
+	CreatedBy       User           `json:"created_by" gorm:"foreignKey:CreatedByID"`
+	LastUpdatedByID *uint          `json:"last_updated_by_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (Appointment) TableName() string {
+	return "appointments"
+}
+
+type AppointmentResponse struct {
+	ID                 uint              `json:"id"`
+	Patient            PatientSummary    `json:"patient"`
+	Doctor             UserResponse      `json:"doctor"`
+	StartTime          time.Time         `json:"start_time"`
+	EndTime            time.Time         `json:"end_time"`
+	DurationMinutes    int               `json:"duration_minutes"`
+	Reason             string            `json:"reason"`
+	Status             AppointmentStatus `json:"status"`
+	CancellationReason string            `json:"cancellation_reason,omitempty"`
+	CheckedInAt        *time.Time        `json:"checked_in_at,omitempty"`
+	CompletedAt        *time.Time        `json:"completed_at,omitempty"`
+	CancelledAt        *time.Time        `json:"cancelled_at,omitempty"`
+	CreatedBy          UserResponse      `json:"created_by"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+func (a *Appointment) ToResponse() AppointmentResponse {
+	return AppointmentResponse{
+		ID:                 a.ID,
+		Patient:            a.Patient.ToSummary(),
+		Doctor:             a.Doctor.ToResponse(),
+		StartTime:          a.StartTime,
+		EndTime:            a.EndTime,
+		DurationMinutes:    int(a.EndTime.Sub(a.StartTime).Minutes()),
+		Reason:             a.Reason,
+		Status:             a.Status,
+		CancellationReason: a.CancellationReason,
+		CheckedInAt:        a.CheckedInAt,
+		CompletedAt:        a.CompletedAt,
+		CancelledAt:        a.CancelledAt,
+		CreatedBy:          a.CreatedBy.ToResponse(),
+		CreatedAt:          a.CreatedAt,
+		UpdatedAt:          a.UpdatedAt,
+	}
+}
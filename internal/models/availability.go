@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// DoctorSchedule holds the booking settings of a doctor. Working hours and
+// breaks repeat every week and are expressed as "HH:MM" in Timezone.
+type DoctorSchedule struct {
+	DoctorID     uint                 `json:"doctor_id" gorm:"primaryKey"`
+	Doctor       User                 `json:"doctor" gorm:"foreignKey:DoctorID"`
+	Specialty    string               `json:"specialty" gorm:"index"`
+	SlotMinutes  int                  `json:"slot_minutes" gorm:"not null"`
+	Timezone     string               `json:"timezone" gorm:"not null;default:UTC"`
+	WorkingHours []DoctorWorkingHours `json:"working_hours" gorm:"foreignKey:DoctorID;references:DoctorID"`
+	Breaks       []DoctorBreak        `json:"breaks" gorm:"foreignKey:DoctorID;references:DoctorID"`
+	UpdatedByID  *uint                `json:"updated_by_id"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+func (DoctorSchedule) TableName() string {
+	return "doctor_schedules"
+}
+
+type DoctorWorkingHours struct {
+	ID        uint         `json:"-" gorm:"primaryKey"`
+	DoctorID  uint         `json:"-" gorm:"not null;index"`
+	Weekday   time.Weekday `json:"weekday" gorm:"not null"`
+	StartTime string       `json:"start_time" gorm:"size:5;not null"`
+	EndTime   string       `json:"end_time" gorm:"size:5;not null"`
+}
+
+func (DoctorWorkingHours) TableName() string {
+	return "doctor_working_hours"
+}
+
+type DoctorBreak struct {
+	ID        uint         `json:"-" gorm:"primaryKey"`
+	DoctorID  uint         `json:"-" gorm:"not null;index"`
+	Weekday   time.Weekday `json:"weekday" gorm:"not null"`
+	StartTime string       `json:"start_time" gorm:"size:5;not null"`
+	EndTime   string       `json:"end_time" gorm:"size:5;not null"`
+}
+
+func (DoctorBreak) TableName() string {
+	return "doctor_breaks"
+}
+
+type ScheduleExceptionKind string
+
+const (
+	ScheduleExceptionLeave   ScheduleExceptionKind = "leave"
+	ScheduleExceptionHoliday ScheduleExceptionKind = "holiday"
+	ScheduleExceptionOther   ScheduleExceptionKind = "other"
+)
+
+// DoctorScheduleException is a one-off period in which the doctor cannot be
+// booked, regardless of their weekly working hours.
+type DoctorScheduleException struct {
+	ID          uint                  `json:"id" gorm:"primaryKey"`
+	DoctorID    uint                  `json:"doctor_id" gorm:"not null;index:idx_schedule_exceptions_doctor_start"`
+	StartsAt    time.Time             `json:"starts_at" gorm:"not null;index:idx_schedule_exceptions_doctor_start"`
+	EndsAt      time.Time             `json:"ends_at" gorm:"not null"`
+	Kind        ScheduleExceptionKind `json:"kind" gorm:"not null"`
+	Reason      string                `json:"reason"`
+	CreatedByID uint                  `json:"created_by_id" gorm:"not null"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+func (DoctorScheduleException) TableName() string {
+	return "doctor_schedule_exceptions"
+}
+
+type DoctorScheduleResponse struct {
+	Doctor       UserResponse              `json:"doctor"`
+	Specialty    string                    `json:"specialty"`
+	SlotMinutes  int                       `json:"slot_minutes"`
+	Timezone     string                    `json:"timezone"`
+	WorkingHours []DoctorWorkingHours      `json:"working_hours"`
+	Breaks       []DoctorBreak             `json:"breaks"`
+	Exceptions   []DoctorScheduleException `json:"exceptions"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+func (s *DoctorSchedule) ToResponse(exceptions []DoctorScheduleException) DoctorScheduleResponse {
+	return DoctorScheduleResponse{
+		Doctor:       s.Doctor.ToResponse(),
+		Specialty:    s.Specialty,
+		SlotMinutes:  s.SlotMinutes,
+		Timezone:     s.Timezone,
+		WorkingHours: s.WorkingHours,
+		Breaks:       s.Breaks,
+		Exceptions:   exceptions,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+// OpenSlot is a bookable period with a doctor.
+type OpenSlot struct {
+	DoctorID   uint      `json:"doctor_id"`
+	DoctorName string    `json:"doctor_name"`
+	Specialty  string    `json:"specialty"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
@@ -0,0 +1,235 @@
+package models
+
+import "time"
+
+// PriceListItem is a billable service. Prices and tax rates are copied onto
+// invoice lines when they are added, so later price changes do not alter
+// existing invoices.
+type PriceListItem struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Code           string    `json:"code" gorm:"uniqueIndex;not null"`
+	Name           string    `json:"name" gorm:"not null"`
+	Category       string    `json:"category" gorm:"index"`
+	UnitPriceCents int64     `json:"unit_price_cents" gorm:"not null"`
+	TaxRatePercent float64   `json:"tax_rate_percent" gorm:"type:decimal(5,2);not null;default:0"`
+	IsActive       bool      `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (PriceListItem) TableName() string {
+	return "price_list_items"
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft         InvoiceStatus = "draft"
+	InvoiceStatusIssued        InvoiceStatus = "issued"
+	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+	InvoiceStatusVoid          InvoiceStatus = "void"
+)
+
+// IsOpen reports whether an invoice in this status has been issued and not
+// voided, so that it counts towards the patient's balance.
+func (s InvoiceStatus) IsOpen() bool {
+	return s == InvoiceStatusIssued || s == InvoiceStatusPartiallyPaid || s == InvoiceStatusPaid
+}
+
+// Invoice bills a patient for an encounter or an admission. It is edited as
+// a draft, then issued, after which only payments and refunds change it.
+type Invoice struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	InvoiceNumber string        `json:"invoice_number" gorm:"uniqueIndex"`
+	PatientID     uint          `json:"patient_id" gorm:"not null;index"`
+	Patient       *Patient      `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	EncounterID   *uint         `json:"encounter_id" gorm:"index"`
+	AdmissionID   *uint         `json:"admission_id" gorm:"index"`
+	Status        InvoiceStatus `json:"status" gorm:"not null;default:draft;index"`
+	Notes         string        `json:"notes" gorm:"type:text"`
+	Items         []InvoiceItem `json:"items" gorm:"foreignKey:InvoiceID"`
+	Payments      []Payment     `json:"payments" gorm:"foreignKey:InvoiceID"`
+
+	SubtotalCents       int64 `json:"subtotal_cents" gorm:"not null;default:0"`
+	DiscountCents       int64 `json:"discount_cents" gorm:"not null;default:0"`
+	TaxCents            int64 `json:"tax_cents" gorm:"not null;default:0"`
+	TotalCents          int64 `json:"total_cents" gorm:"not null;default:0"`
+	AmountPaidCents     int64 `json:"amount_paid_cents" gorm:"not null;default:0"`
+	AmountRefundedCents int64 `json:"amount_refunded_cents" gorm:"not null;default:0"`
+
+	IssuedAt    *time.Time `json:"issued_at"`
+	DueDate     *time.Time `json:"due_date" gorm:"type:date"`
+	VoidedAt    *time.Time `json:"voided_at"`
+	VoidedByID  *uint      `json:"voided_by_id"`
+	VoidReason  string     `json:"void_reason" gorm:"type:text"`
+	CreatedByID uint       `json:"created_by_id" gorm:"not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// BalanceCents is what the patient still owes on the invoice.
+func (i *Invoice) BalanceCents() int64 {
+	if !i.Status.IsOpen() {
+		return 0
+	}
+	return i.TotalCents - i.NetPaidCents()
+}
+
+func (i *Invoice) NetPaidCents() int64 {
+	return i.AmountPaidCents - i.AmountRefundedCents
+}
+
+// SetTotals recomputes the invoice totals from its items.
+func (i *Invoice) SetTotals() {
+	i.SubtotalCents, i.DiscountCents, i.TaxCents, i.TotalCents = 0, 0, 0, 0
+	for _, item := range i.Items {
+		i.SubtotalCents += int64(item.Quantity) * item.UnitPriceCents
+		i.DiscountCents += item.DiscountCents
+		i.TaxCents += item.TaxCents
+		i.TotalCents += item.TotalCents
+	}
+}
+
+// SettleStatus derives the status of an issued invoice from what has been
+// paid on it.
+func (i *Invoice) SettleStatus() {
+	if !i.Status.IsOpen() {
+		return
+	}
+	switch netPaid := i.NetPaidCents(); {
+	case netPaid >= i.TotalCents:
+		i.Status = InvoiceStatusPaid
+	case netPaid > 0:
+		i.Status = InvoiceStatusPartiallyPaid
+	default:
+		i.Status = InvoiceStatusIssued
+	}
+}
+
+type InvoiceItem struct {
+	ID              uint    `json:"id" gorm:"primaryKey"`
+	InvoiceID       uint    `json:"invoice_id" gorm:"not null;index"`
+	PriceListItemID *uint   `json:"price_list_item_id"`
+	Code            string  `json:"code" gorm:"not null"`
+	Description     string  `json:"description" gorm:"not null"`
+	Quantity        int     `json:"quantity" gorm:"not null"`
+	UnitPriceCents  int64   `json:"unit_price_cents" gorm:"not null"`
+	DiscountPercent float64 `json:"discount_percent" gorm:"type:decimal(5,2);not null;default:0"`
+	DiscountCents   int64   `json:"discount_cents" gorm:"not null;default:0"`
+	TaxRatePercent  float64 `json:"tax_rate_percent" gorm:"type:decimal(5,2);not null;default:0"`
+	TaxCents        int64   `json:"tax_cents" gorm:"not null;default:0"`
+	TotalCents      int64   `json:"total_cents" gorm:"not null"`
+}
+
+func (InvoiceItem) TableName() string {
+	return "invoice_items"
+}
+
+type PaymentKind string
+
+const (
+	PaymentKindPayment PaymentKind = "payment"
+	PaymentKindRefund  PaymentKind = "refund"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCash         PaymentMethod = "cash"
+	PaymentMethodCard         PaymentMethod = "card"
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+	PaymentMethodInsurance    PaymentMethod = "insurance"
+	PaymentMethodOther        PaymentMethod = "other"
+)
+
+// Payment is money received for, or refunded against, an invoice. Amounts
+// are always positive; Kind says which way the money went.
+type Payment struct {
+	ID           uint          `json:"id" gorm:"primaryKey"`
+	InvoiceID    uint          `json:"invoice_id" gorm:"not null;index"`
+	PatientID    uint          `json:"patient_id" gorm:"not null;index"`
+	Kind         PaymentKind   `json:"kind" gorm:"not null"`
+	AmountCents  int64         `json:"amount_cents" gorm:"not null"`
+	Method       PaymentMethod `json:"method" gorm:"not null"`
+	Reference    string        `json:"reference"`
+	Notes        string        `json:"notes" gorm:"type:text"`
+	ReceivedByID uint          `json:"received_by_id" gorm:"not null"`
+	ReceivedAt   time.Time     `json:"received_at" gorm:"not null"`
+}
+
+func (Payment) TableName() string {
+	return "payments"
+}
+
+type InvoiceResponse struct {
+	ID                  uint            `json:"id"`
+	InvoiceNumber       string          `json:"invoice_number"`
+	PatientID           uint            `json:"patient_id"`
+	Patient             *PatientSummary `json:"patient,omitempty"`
+	EncounterID         *uint           `json:"encounter_id,omitempty"`
+	AdmissionID         *uint           `json:"admission_id,omitempty"`
+	Status              InvoiceStatus   `json:"status"`
+	Notes               string          `json:"notes,omitempty"`
+	Items               []InvoiceItem   `json:"items"`
+	Payments            []Payment       `json:"payments"`
+	SubtotalCents       int64           `json:"subtotal_cents"`
+	DiscountCents       int64           `json:"discount_cents"`
+	TaxCents            int64           `json:"tax_cents"`
+	TotalCents          int64           `json:"total_cents"`
+	AmountPaidCents     int64           `json:"amount_paid_cents"`
+	AmountRefundedCents int64           `json:"amount_refunded_cents"`
+	BalanceCents        int64           `json:"balance_cents"`
+	IssuedAt            *time.Time      `json:"issued_at,omitempty"`
+	DueDate             *time.Time      `json:"due_date,omitempty"`
+	VoidedAt            *time.Time      `json:"voided_at,omitempty"`
+	VoidReason          string          `json:"void_reason,omitempty"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+func (i *Invoice) ToResponse() InvoiceResponse {
+	items := i.Items
+	if items == nil {
+		items = []InvoiceItem{}
+	}
+	payments := i.Payments
+	if payments == nil {
+		payments = []Payment{}
+	}
+
+	response := InvoiceResponse{
+		ID:                  i.ID,
+		InvoiceNumber:       i.InvoiceNumber,
+		PatientID:           i.PatientID,
+		EncounterID:         i.EncounterID,
+		AdmissionID:         i.AdmissionID,
+		Status:              i.Status,
+		Notes:               i.Notes,
+		Items:               items,
+		Payments:            payments,
+		SubtotalCents:       i.SubtotalCents,
+		DiscountCents:       i.DiscountCents,
+		TaxCents:            i.TaxCents,
+		TotalCents:          i.TotalCents,
+		AmountPaidCents:     i.AmountPaidCents,
+		AmountRefundedCents: i.AmountRefundedCents,
+		BalanceCents:        i.BalanceCents(),
+		IssuedAt:            i.IssuedAt,
+		DueDate:             i.DueDate,
+		VoidedAt:            i.VoidedAt,
+		VoidReason:          i.VoidReason,
+		CreatedAt:           i.CreatedAt,
+		UpdatedAt:           i.UpdatedAt,
+	}
+
+	if i.Patient != nil {
+		patient := i.Patient.ToSummary()
+		response.Patient = &patient
+	}
+	return response
+}
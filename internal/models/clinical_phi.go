@@ -0,0 +1,66 @@
+package models
+
+import (
+	"hospital-management-system/internal/phi"
+
+	"gorm.io/gorm"
+)
+
+// Allergies and prescriptions carry the free text that used to live in the
+// patients' allergies and current_medications columns, so their text
+// columns are encrypted in the same way as patients, see patient_phi.go.
+
+func (a *Allergy) phiFields() []phi.Field {
+	return []phi.Field{
+		{Name: "substance", Value: &a.Substance},
+		{Name: "reaction", Value: &a.Reaction},
+		{Name: "notes", Value: &a.Notes},
+	}
+}
+
+func (a *Allergy) SealPHI(keyring *phi.Keyring) error {
+	return keyring.Seal(&a.EncryptedDataKey, &a.KeyVersion, a.phiFields()...)
+}
+
+func (a *Allergy) OpenPHI(keyring *phi.Keyring) error {
+	return keyring.Open(a.EncryptedDataKey, a.KeyVersion, a.phiFields()...)
+}
+
+func (a *Allergy) BeforeSave(tx *gorm.DB) error {
+	return a.SealPHI(phi.CurrentKeyring())
+}
+
+func (a *Allergy) AfterSave(tx *gorm.DB) error {
+	return a.OpenPHI(phi.CurrentKeyring())
+}
+
+func (a *Allergy) AfterFind(tx *gorm.DB) error {
+	return a.OpenPHI(phi.CurrentKeyring())
+}
+
+func (p *Prescription) phiFields() []phi.Field {
+	return []phi.Field{
+		{Name: "instructions", Value: &p.Instructions},
+		{Name: "discontinued_reason", Value: &p.DiscontinuedReason},
+	}
+}
+
+func (p *Prescription) SealPHI(keyring *phi.Keyring) error {
+	return keyring.Seal(&p.EncryptedDataKey, &p.KeyVersion, p.phiFields()...)
+}
+
+func (p *Prescription) OpenPHI(keyring *phi.Keyring) error {
+	return keyring.Open(p.EncryptedDataKey, p.KeyVersion, p.phiFields()...)
+}
+
+func (p *Prescription) BeforeSave(tx *gorm.DB) error {
+	return p.SealPHI(phi.CurrentKeyring())
+}
+
+func (p *Prescription) AfterSave(tx *gorm.DB) error {
+	return p.OpenPHI(phi.CurrentKeyring())
+}
+
+func (p *Prescription) AfterFind(tx *gorm.DB) error {
+	return p.OpenPHI(phi.CurrentKeyring())
+}
@@ -0,0 +1,136 @@
+package models
+
+import "time"
+
+type EDVisitStatus string
+
+const (
+	EDVisitStatusWaiting     EDVisitStatus = "waiting"
+	EDVisitStatusAssigned    EDVisitStatus = "assigned"
+	EDVisitStatusInTreatment EDVisitStatus = "in_treatment"
+	EDVisitStatusCompleted   EDVisitStatus = "completed"
+	EDVisitStatusLeft        EDVisitStatus = "left_without_being_seen"
+)
+
+// IsActive reports whether a visit in this status is still in the
+// department.
+func (s EDVisitStatus) IsActive() bool {
+	return s == EDVisitStatusWaiting || s == EDVisitStatusAssigned || s == EDVisitStatusInTreatment
+}
+
+type EDDisposition string
+
+const (
+	EDDispositionDischarged  EDDisposition = "discharged"
+	EDDispositionAdmitted    EDDisposition = "admitted"
+	EDDispositionTransferred EDDisposition = "transferred"
+	EDDispositionDeceased    EDDisposition = "deceased"
+)
+
+// Emergency Severity Index levels, 1 being the most acute.
+const (
+	TriageLevelResuscitation = 1
+	TriageLevelNonUrgent     = 5
+)
+
+// EDVisit is a patient's stay in the emergency department, from arrival to
+// disposition. Each stage is timestamped so that door-to-doctor time
+// (ArrivedAt to SeenAt) can be measured.
+type EDVisit struct {
+	ID               uint          `json:"id" gorm:"primaryKey"`
+	PatientID        uint          `json:"patient_id" gorm:"not null;index"`
+	Patient          *Patient      `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	TriageLevel      int           `json:"triage_level" gorm:"not null;index:idx_ed_visits_queue"`
+	ChiefComplaint   string        `json:"chief_complaint" gorm:"type:text;not null"`
+	Status           EDVisitStatus `json:"status" gorm:"not null;default:waiting;index:idx_ed_visits_queue"`
+	ArrivedAt        time.Time     `json:"arrived_at" gorm:"not null;index:idx_ed_visits_queue"`
+	TriagedAt        time.Time     `json:"triaged_at" gorm:"not null"`
+	TriagedByID      uint          `json:"triaged_by_id" gorm:"not null"`
+	AssignedDoctorID *uint         `json:"assigned_doctor_id" gorm:"index"`
+	AssignedDoctor   *User         `json:"assigned_doctor,omitempty" gorm:"foreignKey:AssignedDoctorID"`
+	AssignedAt       *time.Time    `json:"assigned_at"`
+	SeenAt           *time.Time    `json:"seen_at"`
+	CompletedAt      *time.Time    `json:"completed_at"`
+	Disposition      EDDisposition `json:"disposition"`
+	Notes            string        `json:"notes" gorm:"type:text"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+func (EDVisit) TableName() string {
+	return "ed_visits"
+}
+
+// DoorToDoctor is the time from arrival until a doctor first saw the
+// patient, or nil if that has not happened.
+func (v *EDVisit) DoorToDoctor() *time.Duration {
+	if v.SeenAt == nil {
+		return nil
+	}
+	d := v.SeenAt.Sub(v.ArrivedAt)
+	return &d
+}
+
+type EDVisitResponse struct {
+	ID                  uint            `json:"id"`
+	PatientID           uint            `json:"patient_id"`
+	Patient             *PatientSummary `json:"patient,omitempty"`
+	TriageLevel         int             `json:"triage_level"`
+	ChiefComplaint      string          `json:"chief_complaint"`
+	Status              EDVisitStatus   `json:"status"`
+	ArrivedAt           time.Time       `json:"arrived_at"`
+	TriagedAt           time.Time       `json:"triaged_at"`
+	AssignedDoctor      *UserResponse   `json:"assigned_doctor,omitempty"`
+	AssignedAt          *time.Time      `json:"assigned_at,omitempty"`
+	SeenAt              *time.Time      `json:"seen_at,omitempty"`
+	CompletedAt         *time.Time      `json:"completed_at,omitempty"`
+	Disposition         EDDisposition   `json:"disposition,omitempty"`
+	Notes               string          `json:"notes,omitempty"`
+	WaitMinutes         int             `json:"wait_minutes"`
+	DoorToDoctorMinutes *int            `json:"door_to_doctor_minutes,omitempty"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+// ToResponse computes the wait time at now: time since arrival for patients
+// not yet seen, otherwise the door-to-doctor time.
+func (v *EDVisit) ToResponse(now time.Time) EDVisitResponse {
+	response := EDVisitResponse{
+		ID:             v.ID,
+		PatientID:      v.PatientID,
+		TriageLevel:    v.TriageLevel,
+		ChiefComplaint: v.ChiefComplaint,
+		Status:         v.Status,
+		ArrivedAt:      v.ArrivedAt,
+		TriagedAt:      v.TriagedAt,
+		AssignedAt:     v.AssignedAt,
+		SeenAt:         v.SeenAt,
+		CompletedAt:    v.CompletedAt,
+		Disposition:    v.Disposition,
+		Notes:          v.Notes,
+		CreatedAt:      v.CreatedAt,
+		UpdatedAt:      v.UpdatedAt,
+	}
+
+	if doorToDoctor := v.DoorToDoctor(); doorToDoctor != nil {
+		minutes := int(doorToDoctor.Minutes())
+		response.DoorToDoctorMinutes = &minutes
+		response.WaitMinutes = minutes
+	} else {
+		end := now
+		if v.CompletedAt != nil {
+			end = *v.CompletedAt
+		}
+		response.WaitMinutes = int(end.Sub(v.ArrivedAt).Minutes())
+	}
+
+	if v.Patient != nil {
+		patient := v.Patient.ToSummary()
+		response.Patient = &patient
+	}
+	if v.AssignedDoctor != nil {
+		doctor := v.AssignedDoctor.ToResponse()
+		response.AssignedDoctor = &doctor
+	}
+	return response
+}
@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+type EncounterStatus string
+
+const (
+	EncounterStatusDraft  EncounterStatus = "draft"
+	EncounterStatusSigned EncounterStatus = "signed"
+)
+
+// Encounter is a single clinical visit documented as a SOAP note. Once signed
+// the note is locked and can only be extended with addenda.
+type Encounter struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	PatientID     uint            `json:"patient_id" gorm:"not null;index:idx_encounters_patient_date"`
+	AppointmentID *uint           `json:"appointment_id" gorm:"index"`
+	AuthorID      uint            `json:"author_id" gorm:"not null;index"`
+	Author        User            `json:"author" gorm:"foreignKey:AuthorID"`
+	EncounterDate time.Time       `json:"encounter_date" gorm:"not null;index:idx_encounters_patient_date"`
+	Status        EncounterStatus `json:"status" gorm:"not null;default:draft"`
+
+	// SOAP note
+	Subjective string `json:"subjective" gorm:"type:text"`
+	Objective  string `json:"objective" gorm:"type:text"`
+	Assessment string `json:"assessment" gorm:"type:text"`
+	Plan       string `json:"plan" gorm:"type:text"`
+
+	SignedAt  *time.Time          `json:"signed_at"`
+	Addenda   []EncounterAddendum `json:"addenda" gorm:"foreignKey:EncounterID"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+func (Encounter) TableName() string {
+	return "encounters"
+}
+
+// EncounterAddendum is an append-only note attached to a signed encounter.
+type EncounterAddendum struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	EncounterID uint      `json:"encounter_id" gorm:"not null;index"`
+	AuthorID    uint      `json:"author_id" gorm:"not null"`
+	Author      User      `json:"author" gorm:"foreignKey:AuthorID"`
+	Content     string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (EncounterAddendum) TableName() string {
+	return "encounter_addenda"
+}
+
+type EncounterAddendumResponse struct {
+	ID        uint         `json:"id"`
+	Author    UserResponse `json:"author"`
+	Content   string       `json:"content"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type EncounterResponse struct {
+	ID            uint                        `json:"id"`
+	PatientID     uint                        `json:"patient_id"`
+	AppointmentID *uint                       `json:"appointment_id,omitempty"`
+	Author        UserResponse                `json:"author"`
+	EncounterDate time.Time                   `json:"encounter_date"`
+	Status        EncounterStatus             `json:"status"`
+	Subjective    string                      `json:"subjective"`
+	Objective     string                      `json:"objective"`
+	Assessment    string                      `json:"assessment"`
+	Plan          string                      `json:"plan"`
+	SignedAt      *time.Time                  `json:"signed_at,omitempty"`
+	Addenda       []EncounterAddendumResponse `json:"addenda"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	UpdatedAt     time.Time                   `json:"updated_at"`
+}
+
+func (e *Encounter) ToResponse() EncounterResponse {
+	addenda := make([]EncounterAddendumResponse, len(e.Addenda))
+	for i, addendum := range e.Addenda {
+		addenda[i] = EncounterAddendumResponse{
+			ID:        addendum.ID,
+			Author:    addendum.Author.ToResponse(),
+			Content:   addendum.Content,
+			CreatedAt: addendum.CreatedAt,
+		}
+	}
+
+	return EncounterResponse{
+		ID:            e.ID,
+		PatientID:     e.PatientID,
+		AppointmentID: e.AppointmentID,
+		Author:        e.Author.ToResponse(),
+		EncounterDate: e.EncounterDate,
+		Status:        e.Status,
+		Subjective:    e.Subjective,
+		Objective:     e.Objective,
+		Assessment:    e.Assessment,
+		Plan:          e.Plan,
+		SignedAt:      e.SignedAt,
+		Addenda:       addenda,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+}
@@ -0,0 +1,205 @@
+package models
+
+import "time"
+
+// InsurancePayer is an insurance company that claims are sent to. PayerCode
+// is the payer identifier used in electronic claim files.
+type InsurancePayer struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null"`
+	PayerCode string    `json:"payer_code" gorm:"uniqueIndex;not null"`
+	Phone     string    `json:"phone"`
+	Address   string    `json:"address" gorm:"type:text"`
+	IsActive  bool      `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (InsurancePayer) TableName() string {
+	return "insurance_payers"
+}
+
+type CoveragePriority string
+
+const (
+	CoveragePriorityPrimary   CoveragePriority = "primary"
+	CoveragePrioritySecondary CoveragePriority = "secondary"
+)
+
+// SubscriberRelationship is the patient's relationship to the person who
+// holds the policy.
+type SubscriberRelationship string
+
+const (
+	SubscriberRelationshipSelf   SubscriberRelationship = "self"
+	SubscriberRelationshipSpouse SubscriberRelationship = "spouse"
+	SubscriberRelationshipChild  SubscriberRelationship = "child"
+	SubscriberRelationshipOther  SubscriberRelationship = "other"
+)
+
+// InsurancePolicy is a patient's coverage with a payer. A patient has at
+// most one active primary and one active secondary policy; replaced
+// policies are deactivated rather than deleted so that old claims keep
+// pointing at them.
+type InsurancePolicy struct {
+	ID                     uint                   `json:"id" gorm:"primaryKey"`
+	PatientID              uint                   `json:"patient_id" gorm:"not null;index;uniqueIndex:idx_insurance_policies_active_priority,where:is_active = true"`
+	PayerID                uint                   `json:"payer_id" gorm:"not null;index"`
+	Payer                  *InsurancePayer        `json:"payer,omitempty" gorm:"foreignKey:PayerID"`
+	PolicyNumber           string                 `json:"policy_number" gorm:"not null"`
+	GroupNumber            string                 `json:"group_number"`
+	SubscriberName         string                 `json:"subscriber_name"`
+	SubscriberRelationship SubscriberRelationship `json:"subscriber_relationship" gorm:"not null;default:self"`
+	Priority               CoveragePriority       `json:"priority" gorm:"not null;uniqueIndex:idx_insurance_policies_active_priority,where:is_active = true"`
+	CoverageStart          time.Time              `json:"coverage_start" gorm:"type:date;not null"`
+	CoverageEnd            *time.Time             `json:"coverage_end" gorm:"type:date"`
+	IsActive               bool                   `json:"is_active" gorm:"not null;default:true"`
+	CreatedByID            uint                   `json:"created_by_id" gorm:"not null"`
+	CreatedAt              time.Time              `json:"created_at"`
+	UpdatedAt              time.Time              `json:"updated_at"`
+}
+
+func (InsurancePolicy) TableName() string {
+	return "insurance_policies"
+}
+
+// CoversOn reports whether the coverage dates include the given day. The
+// end date is inclusive.
+func (p *InsurancePolicy) CoversOn(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(p.CoverageStart.Year(), p.CoverageStart.Month(), p.CoverageStart.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(start) {
+		return false
+	}
+	if p.CoverageEnd != nil {
+		end := time.Date(p.CoverageEnd.Year(), p.CoverageEnd.Month(), p.CoverageEnd.Day(), 0, 0, 0, 0, time.UTC)
+		if day.After(end) {
+			return false
+		}
+	}
+	return true
+}
+
+type ClaimStatus string
+
+const (
+	ClaimStatusDraft     ClaimStatus = "draft"
+	ClaimStatusSubmitted ClaimStatus = "submitted"
+	ClaimStatusAccepted  ClaimStatus = "accepted"
+	ClaimStatusDenied    ClaimStatus = "denied"
+	ClaimStatusPaid      ClaimStatus = "paid"
+	ClaimStatusCancelled ClaimStatus = "cancelled"
+)
+
+// Claim asks a payer to pay an issued invoice under one of the patient's
+// policies. Drafts are submitted in batches; the payer's answer is then
+// recorded as accepted, denied or paid. Denied claims can be reopened,
+// corrected and submitted again. Drafts are cancelled when their invoice is
+// voided.
+type Claim struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	ClaimNumber    string           `json:"claim_number" gorm:"uniqueIndex"`
+	PatientID      uint             `json:"patient_id" gorm:"not null;index"`
+	Patient        *Patient         `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	InvoiceID      uint             `json:"invoice_id" gorm:"not null;uniqueIndex:idx_claims_invoice_policy"`
+	Invoice        *Invoice         `json:"invoice,omitempty" gorm:"foreignKey:InvoiceID"`
+	PolicyID       uint             `json:"policy_id" gorm:"not null;uniqueIndex:idx_claims_invoice_policy"`
+	Policy         *InsurancePolicy `json:"policy,omitempty" gorm:"foreignKey:PolicyID"`
+	Status         ClaimStatus      `json:"status" gorm:"not null;default:draft;index"`
+	ServiceDate    time.Time        `json:"service_date" gorm:"type:date;not null"`
+	DiagnosisCodes []string         `json:"diagnosis_codes" gorm:"type:text;serializer:json"`
+	BilledCents    int64            `json:"billed_cents" gorm:"not null"`
+	PaidCents      int64            `json:"paid_cents" gorm:"not null;default:0"`
+	PayerClaimRef  string           `json:"payer_claim_ref"`
+	DenialReason   string           `json:"denial_reason" gorm:"type:text"`
+	BatchID        *uint            `json:"batch_id" gorm:"index"`
+	SubmittedAt    *time.Time       `json:"submitted_at"`
+	SubmittedByID  *uint            `json:"submitted_by_id"`
+	AdjudicatedAt  *time.Time       `json:"adjudicated_at"`
+	PaidAt         *time.Time       `json:"paid_at"`
+	CreatedByID    uint             `json:"created_by_id" gorm:"not null"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+func (Claim) TableName() string {
+	return "claims"
+}
+
+// ClaimBatch is one submission file. The file is stored as it was sent so
+// that it can be downloaded again.
+type ClaimBatch struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	BatchNumber      string    `json:"batch_number" gorm:"uniqueIndex"`
+	PayerID          *uint     `json:"payer_id" gorm:"index"`
+	Format           string    `json:"format" gorm:"not null"`
+	ClaimCount       int       `json:"claim_count" gorm:"not null"`
+	TotalBilledCents int64     `json:"total_billed_cents" gorm:"not null"`
+	Content          string    `json:"-" gorm:"type:text"`
+	CreatedByID      uint      `json:"created_by_id" gorm:"not null"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (ClaimBatch) TableName() string {
+	return "claim_batches"
+}
+
+type ClaimResponse struct {
+	ID             uint             `json:"id"`
+	ClaimNumber    string           `json:"claim_number"`
+	PatientID      uint             `json:"patient_id"`
+	Patient        *PatientSummary  `json:"patient,omitempty"`
+	InvoiceID      uint             `json:"invoice_id"`
+	InvoiceNumber  string           `json:"invoice_number,omitempty"`
+	Policy         *InsurancePolicy `json:"policy,omitempty"`
+	Status         ClaimStatus      `json:"status"`
+	ServiceDate    time.Time        `json:"service_date"`
+	DiagnosisCodes []string         `json:"diagnosis_codes"`
+	BilledCents    int64            `json:"billed_cents"`
+	PaidCents      int64            `json:"paid_cents"`
+	PayerClaimRef  string           `json:"payer_claim_ref,omitempty"`
+	DenialReason   string           `json:"denial_reason,omitempty"`
+	BatchID        *uint            `json:"batch_id,omitempty"`
+	SubmittedAt    *time.Time       `json:"submitted_at,omitempty"`
+	AdjudicatedAt  *time.Time       `json:"adjudicated_at,omitempty"`
+	PaidAt         *time.Time       `json:"paid_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+func (c *Claim) ToResponse() ClaimResponse {
+	diagnosisCodes := c.DiagnosisCodes
+	if diagnosisCodes == nil {
+		diagnosisCodes = []string{}
+	}
+
+	response := ClaimResponse{
+		ID:             c.ID,
+		ClaimNumber:    c.ClaimNumber,
+		PatientID:      c.PatientID,
+		InvoiceID:      c.InvoiceID,
+		Policy:         c.Policy,
+		Status:         c.Status,
+		ServiceDate:    c.ServiceDate,
+		DiagnosisCodes: diagnosisCodes,
+		BilledCents:    c.BilledCents,
+		PaidCents:      c.PaidCents,
+		PayerClaimRef:  c.PayerClaimRef,
+		DenialReason:   c.DenialReason,
+		BatchID:        c.BatchID,
+		SubmittedAt:    c.SubmittedAt,
+		AdjudicatedAt:  c.AdjudicatedAt,
+		PaidAt:         c.PaidAt,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+
+	if c.Patient != nil {
+		patient := c.Patient.ToSummary()
+		response.Patient = &patient
+	}
+	if c.Invoice != nil {
+		response.InvoiceNumber = c.Invoice.InvoiceNumber
+	}
+	return response
+}
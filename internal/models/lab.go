@@ -0,0 +1,159 @@
+package models
+
+import "time"
+
+type LabOrderStatus string
+
+const (
+	LabOrderStatusOrdered    LabOrderStatus = "ordered"
+	LabOrderStatusCollected  LabOrderStatus = "collected"
+	LabOrderStatusInProgress LabOrderStatus = "in_progress"
+	LabOrderStatusResulted   LabOrderStatus = "resulted"
+	LabOrderStatusCancelled  LabOrderStatus = "cancelled"
+)
+
+type LabOrderPriority string
+
+const (
+	LabOrderPriorityRoutine LabOrderPriority = "routine"
+	LabOrderPriorityUrgent  LabOrderPriority = "urgent"
+	LabOrderPriorityStat    LabOrderPriority = "stat"
+)
+
+type LabResultFlag string
+
+const (
+	LabResultFlagNormal   LabResultFlag = "normal"
+	LabResultFlagLow      LabResultFlag = "low"
+	LabResultFlagHigh     LabResultFlag = "high"
+	LabResultFlagAbnormal LabResultFlag = "abnormal"
+	LabResultFlagCritical LabResultFlag = "critical"
+)
+
+// LabOrder is a laboratory test ordered for a patient. It moves from
+// ordered through collected and in_progress to resulted, and can be
+// cancelled until it is resulted. Resulted orders appear in the ordering
+// doctor's inbox until they are reviewed.
+type LabOrder struct {
+	ID               uint             `json:"id" gorm:"primaryKey"`
+	PatientID        uint             `json:"patient_id" gorm:"not null;index"`
+	Patient          *Patient         `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	OrderingDoctorID uint             `json:"ordering_doctor_id" gorm:"not null;index:idx_lab_orders_inbox"`
+	OrderingDoctor   User             `json:"ordering_doctor" gorm:"foreignKey:OrderingDoctorID"`
+	EncounterID      *uint            `json:"encounter_id" gorm:"index"`
+	TestCode         string           `json:"test_code" gorm:"not null"`
+	TestName         string           `json:"test_name" gorm:"not null"`
+	Priority         LabOrderPriority `json:"priority" gorm:"not null;default:routine"`
+	ClinicalNotes    string           `json:"clinical_notes" gorm:"type:text"`
+	Status           LabOrderStatus   `json:"status" gorm:"not null;default:ordered;index:idx_lab_orders_inbox"`
+	OrderedAt        time.Time        `json:"ordered_at" gorm:"not null"`
+
+	CollectedAt   *time.Time `json:"collected_at"`
+	CollectedByID *uint      `json:"collected_by_id"`
+	StartedAt     *time.Time `json:"started_at"`
+	ResultedAt    *time.Time `json:"resulted_at"`
+	ResultedByID  *uint      `json:"resulted_by_id"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
+	CancelledByID *uint      `json:"cancelled_by_id"`
+	CancelReason  string     `json:"cancel_reason" gorm:"type:text"`
+
+	// HasAbnormalResults is set when any result is flagged, so that the
+	// inbox can highlight the order without loading its results.
+	HasAbnormalResults bool        `json:"has_abnormal_results" gorm:"not null;default:false"`
+	ReviewedAt         *time.Time  `json:"reviewed_at" gorm:"index:idx_lab_orders_inbox"`
+	Results            []LabResult `json:"results,omitempty" gorm:"foreignKey:LabOrderID"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (LabOrder) TableName() string {
+	return "lab_orders"
+}
+
+// LabResult is one analyte of a resulted order. Value is kept as text so
+// that qualitative results such as "positive" can be recorded; NumericValue
+// is set when the value is a number.
+type LabResult struct {
+	ID             uint          `json:"id" gorm:"primaryKey"`
+	LabOrderID     uint          `json:"lab_order_id" gorm:"not null;index"`
+	Analyte        string        `json:"analyte" gorm:"not null"`
+	Value          string        `json:"value" gorm:"not null"`
+	NumericValue   *float64      `json:"numeric_value"`
+	Unit           string        `json:"unit"`
+	ReferenceLow   *float64      `json:"reference_low"`
+	ReferenceHigh  *float64      `json:"reference_high"`
+	ReferenceRange string        `json:"reference_range"`
+	Flag           LabResultFlag `json:"flag"`
+	Comment        string        `json:"comment" gorm:"type:text"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+func (LabResult) TableName() string {
+	return "lab_results"
+}
+
+// IsAbnormal reports whether the result is flagged as anything but normal.
+func (r *LabResult) IsAbnormal() bool {
+	return r.Flag != "" && r.Flag != LabResultFlagNormal
+}
+
+type LabOrderResponse struct {
+	ID                 uint             `json:"id"`
+	PatientID          uint             `json:"patient_id"`
+	Patient            *PatientResponse `json:"patient,omitempty"`
+	OrderingDoctor     UserResponse     `json:"ordering_doctor"`
+	EncounterID        *uint            `json:"encounter_id,omitempty"`
+	TestCode           string           `json:"test_code"`
+	TestName           string           `json:"test_name"`
+	Priority           LabOrderPriority `json:"priority"`
+	ClinicalNotes      string           `json:"clinical_notes,omitempty"`
+	Status             LabOrderStatus   `json:"status"`
+	OrderedAt          time.Time        `json:"ordered_at"`
+	CollectedAt        *time.Time       `json:"collected_at,omitempty"`
+	StartedAt          *time.Time       `json:"started_at,omitempty"`
+	ResultedAt         *time.Time       `json:"resulted_at,omitempty"`
+	CancelledAt        *time.Time       `json:"cancelled_at,omitempty"`
+	CancelReason       string           `json:"cancel_reason,omitempty"`
+	HasAbnormalResults bool             `json:"has_abnormal_results"`
+	ReviewedAt         *time.Time       `json:"reviewed_at,omitempty"`
+	Results            []LabResult      `json:"results"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+func (o *LabOrder) ToResponse() LabOrderResponse {
+	results := o.Results
+	if results == nil {
+		results = []LabResult{}
+	}
+
+	response := LabOrderResponse{
+		ID:                 o.ID,
+		PatientID:          o.PatientID,
+		OrderingDoctor:     o.OrderingDoctor.ToResponse(),
+		EncounterID:        o.EncounterID,
+		TestCode:           o.TestCode,
+		TestName:           o.TestName,
+		Priority:           o.Priority,
+		ClinicalNotes:      o.ClinicalNotes,
+		Status:             o.Status,
+		OrderedAt:          o.OrderedAt,
+		CollectedAt:        o.CollectedAt,
+		StartedAt:          o.StartedAt,
+		ResultedAt:         o.ResultedAt,
+		CancelledAt:        o.CancelledAt,
+		CancelReason:       o.CancelReason,
+		HasAbnormalResults: o.HasAbnormalResults,
+		ReviewedAt:         o.ReviewedAt,
+		Results:            results,
+		CreatedAt:          o.CreatedAt,
+		UpdatedAt:          o.UpdatedAt,
+	}
+
+	if o.Patient != nil {
+		patient := o.Patient.ToResponse()
+		response.Patient = &patient
+	}
+	return response
+}
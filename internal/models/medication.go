@@ -0,0 +1,174 @@
+package models
+
+import "time"
+
+// Medication is an entry in the local medication catalog. DrugClass groups
+// related drugs, for example "penicillin", so that clinical checks can match
+// on the class rather than on every product name.
+type Medication struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	GenericName string    `json:"generic_name" gorm:"index"`
+	Form        string    `json:"form"`
+	Strength    string    `json:"strength"`
+	DrugClass   string    `json:"drug_class" gorm:"index"`
+	IsActive    bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Medication) TableName() string {
+	return "medications"
+}
+
+type MedicationRoute string
+
+const (
+	MedicationRouteOral          MedicationRoute = "oral"
+	MedicationRouteIntravenous   MedicationRoute = "intravenous"
+	MedicationRouteIntramuscular MedicationRoute = "intramuscular"
+	MedicationRouteSubcutaneous  MedicationRoute = "subcutaneous"
+	MedicationRouteTopical       MedicationRoute = "topical"
+	MedicationRouteInhaled       MedicationRoute = "inhaled"
+	MedicationRouteOther         MedicationRoute = "other"
+)
+
+type PrescriptionStatus string
+
+const (
+	PrescriptionStatusActive       PrescriptionStatus = "active"
+	PrescriptionStatusStopped      PrescriptionStatus = "stopped"
+	PrescriptionStatusDiscontinued PrescriptionStatus = "discontinued"
+)
+
+// LegacyMedicationName is the medication name recorded on prescriptions
+// migrated from the old free-text Patient.CurrentMedications column. The
+// original text is kept in Instructions.
+const LegacyMedicationName = "Unstructured medication note"
+
+// Prescription is a medication order for a patient. It is current from
+// StartDate until EndDate, or until it is stopped or discontinued.
+// MedicationName copies the catalog name at the time of prescribing.
+type Prescription struct {
+	ID             uint               `json:"id" gorm:"primaryKey"`
+	PatientID      uint               `json:"patient_id" gorm:"not null;index:idx_prescriptions_patient_status"`
+	MedicationID   *uint              `json:"medication_id" gorm:"index"`
+	Medication     *Medication        `json:"medication,omitempty" gorm:"foreignKey:MedicationID"`
+	MedicationName string             `json:"medication_name" gorm:"not null"`
+	EncounterID    *uint              `json:"encounter_id" gorm:"index"`
+	Dose           string             `json:"dose"`
+	Route          MedicationRoute    `json:"route"`
+	Frequency      string             `json:"frequency"`
+	DurationDays   *int               `json:"duration_days"`
+	Refills        int                `json:"refills" gorm:"not null;default:0"`
+	Instructions   string             `json:"instructions" gorm:"type:text"`
+	StartDate      time.Time          `json:"start_date" gorm:"not null"`
+	EndDate        *time.Time         `json:"end_date"`
+	Status         PrescriptionStatus `json:"status" gorm:"not null;default:active;index:idx_prescriptions_patient_status"`
+	IsLegacy       bool               `json:"is_legacy" gorm:"not null;default:false"`
+
+	StoppedAt          *time.Time `json:"stopped_at"`
+	StoppedByID        *uint      `json:"stopped_by_id"`
+	DiscontinuedReason string     `json:"discontinued_reason" gorm:"type:text"`
+
+	// Encryption fields, see clinical_phi.go
+	EncryptedDataKey string `json:"-"`
+	KeyVersion       int    `json:"-" gorm:"not null;default:0"`
+
+	// SafetyOverrides records the clinical safety warnings the prescriber
+	// accepted when writing the prescription.
+	SafetyOverrides []PrescriptionSafetyOverride `json:"safety_overrides,omitempty" gorm:"foreignKey:PrescriptionID"`
+
+	// System fields. Legacy prescriptions have no known prescriber.
+	PrescriberID *uint     `json:"prescriber_id"`
+	Prescriber   *User     `json:"prescriber,omitempty" gorm:"foreignKey:PrescriberID"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (Prescription) TableName() string {
+	return "prescriptions"
+}
+
+// IsCurrent reports whether the prescription is in effect at the given time.
+func (p *Prescription) IsCurrent(at time.Time) bool {
+	if p.Status != PrescriptionStatusActive || p.StartDate.After(at) {
+		return false
+	}
+	return p.EndDate == nil || p.EndDate.After(at)
+}
+
+// PrescriptionSafetyOverride is the audit record of a drug-allergy or
+// drug-drug interaction warning that was overridden on prescribing.
+type PrescriptionSafetyOverride struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	PrescriptionID uint      `json:"prescription_id" gorm:"not null;index"`
+	PatientID      uint      `json:"patient_id" gorm:"not null;index"`
+	AlertKind      string    `json:"alert_kind" gorm:"not null"`
+	AlertLevel     string    `json:"alert_level" gorm:"not null"`
+	Description    string    `json:"description" gorm:"type:text;not null"`
+	Reason         string    `json:"reason" gorm:"type:text;not null"`
+	OverriddenByID uint      `json:"overridden_by_id" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (PrescriptionSafetyOverride) TableName() string {
+	return "prescription_safety_overrides"
+}
+
+type PrescriptionResponse struct {
+	ID                 uint                         `json:"id"`
+	PatientID          uint                         `json:"patient_id"`
+	Medication         *Medication                  `json:"medication,omitempty"`
+	MedicationName     string                       `json:"medication_name"`
+	EncounterID        *uint                        `json:"encounter_id,omitempty"`
+	Dose               string                       `json:"dose"`
+	Route              MedicationRoute              `json:"route"`
+	Frequency          string                       `json:"frequency"`
+	DurationDays       *int                         `json:"duration_days,omitempty"`
+	Refills            int                          `json:"refills"`
+	Instructions       string                       `json:"instructions"`
+	StartDate          time.Time                    `json:"start_date"`
+	EndDate            *time.Time                   `json:"end_date,omitempty"`
+	Status             PrescriptionStatus           `json:"status"`
+	IsCurrent          bool                         `json:"is_current"`
+	IsLegacy           bool                         `json:"is_legacy"`
+	StoppedAt          *time.Time                   `json:"stopped_at,omitempty"`
+	DiscontinuedReason string                       `json:"discontinued_reason,omitempty"`
+	SafetyOverrides    []PrescriptionSafetyOverride `json:"safety_overrides,omitempty"`
+	Prescriber         *UserResponse                `json:"prescriber,omitempty"`
+	CreatedAt          time.Time                    `json:"created_at"`
+	UpdatedAt          time.Time                    `json:"updated_at"`
+}
+
+func (p *Prescription) ToResponse() PrescriptionResponse {
+	response := PrescriptionResponse{
+		ID:                 p.ID,
+		PatientID:          p.PatientID,
+		Medication:         p.Medication,
+		MedicationName:     p.MedicationName,
+		EncounterID:        p.EncounterID,
+		Dose:               p.Dose,
+		Route:              p.Route,
+		Frequency:          p.Frequency,
+		DurationDays:       p.DurationDays,
+		Refills:            p.Refills,
+		Instructions:       p.Instructions,
+		StartDate:          p.StartDate,
+		EndDate:            p.EndDate,
+		Status:             p.Status,
+		IsCurrent:          p.IsCurrent(time.Now()),
+		IsLegacy:           p.IsLegacy,
+		StoppedAt:          p.StoppedAt,
+		DiscontinuedReason: p.DiscontinuedReason,
+		SafetyOverrides:    p.SafetyOverrides,
+		CreatedAt:          p.CreatedAt,
+		UpdatedAt:          p.UpdatedAt,
+	}
+
+	if p.Prescriber != nil {
+		prescriber := p.Prescriber.ToResponse()
+		response.Prescriber = &prescriber
+	}
+	return response
+}
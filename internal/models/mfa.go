@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// MFARecoveryCode is a single-use code that replaces a TOTP code when the
+// user has lost access to their authenticator. Only the hash is stored.
+type MFARecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAPolicy records whether multi-factor authentication is mandatory for
+// every account with the given role.
+type MFAPolicy struct {
+	Role        UserRole  `json:"role" gorm:"primaryKey"`
+	Required    bool      `json:"required" gorm:"not null;default:false"`
+	UpdatedByID *uint     `json:"updated_by_id"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (MFAPolicy) TableName() string {
+	return "mfa_policies"
+}
+
+// MFAChallenge counts failed codes against one MFA challenge token, which is
+// abandoned after a few failures so each password login allows only a
+// handful of guesses. Rows are removed once the token has expired.
+type MFAChallenge struct {
+	ID             string    `json:"id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"not null;index"`
+	FailedAttempts int       `json:"failed_attempts" gorm:"not null;default:0"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+func (MFAChallenge) TableName() string {
+	return "mfa_challenges"
+}
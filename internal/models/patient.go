@@ -31,7 +31,7 @@ type Patient struct {
 	PatientID       string         `json:"patient_id" gorm:"uniqueIndex;not null"`
 	FirstName       string         `json:"first_name" gorm:"not null" binding:"required,min=2,max=50"`
 	LastName        string         `json:"last_name" gorm:"not null" binding:"required,min=2,max=50"`
-	Email           string         `json:"email" gorm:"uniqueIndex" binding:"omitempty,email"`
+	Email           string         `json:"email" binding:"omitempty,email"`
 	Phone           string         `json:"phone" gorm:"not null" binding:"required,min=10,max=15"`
 	DateOfBirth     time.Time      `json:"date_of_birth" gorm:"not null" binding:"required"`
 	Gender          Gender         `json:"gender" gorm:"not null" binding:"required,oneof=male female other"`
@@ -39,8 +39,16 @@ type Patient struct {
 	Address         string         `json:"address" gorm:"type:text"`
 	EmergencyContact string        `json:"emergency_contact" gorm:"not null" binding:"required,min=10,max=15"`
 	MedicalHistory  string         `json:"medical_history" gorm:"type:text"`
-	Allergies       string         `json:"allergies" gorm:"type:text"`
-	CurrentMedications string      `json:"current_medications" gorm:"type:text"`
+
+	// Encryption fields, see patient_phi.go
+	EncryptedDataKey string        `json:"-"`
+	KeyVersion      int            `json:"-" gorm:"not null;default:0"`
+	PhoneIndex      string         `json:"-" gorm:"index"`
+	EmailIndex      string         `json:"-" gorm:"uniqueIndex:idx_patients_email_index,where:email_index <> ''"`
+	// SearchRank is only set on results of a patient search. It is computed
+	// from the search_vector column, which the database generates and which
+	// is not mapped here.
+	SearchRank      float64        `json:"-" gorm:"->;-:migration"`
 	
 	// System fields
 	CreatedByID     uint           `json:"created_by_id" gorm:"not null"`
@@ -48,6 +56,10 @@ type Patient struct {
 	LastUpdatedByID *uint          `json:"last_updated_by_id"`
 	LastUpdatedBy   *User          `json:"last_updated_by,omitempty" gorm:"foreignKey:LastUpdatedByID"`
 	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	// MergedIntoID is set on a duplicate record once it has been merged
+	// into the surviving patient, see PatientAlias.
+	MergedIntoID    *uint          `json:"merged_into_id,omitempty" gorm:"index"`
+	MergedAt        *time.Time     `json:"merged_at,omitempty"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
@@ -71,8 +83,6 @@ type PatientResponse struct {
 	Address            string        `json:"address"`
 	EmergencyContact   string        `json:"emergency_contact"`
 	MedicalHistory     string        `json:"medical_history"`
-	Allergies          string        `json:"allergies"`
-	CurrentMedications string        `json:"current_medications"`
 	CreatedBy          UserResponse  `json:"created_by"`
 	LastUpdatedBy      *UserResponse `json:"last_updated_by,omitempty"`
 	IsActive           bool          `json:"is_active"`
@@ -95,8 +105,6 @@ func (p *Patient) ToResponse() PatientResponse {
 		Address:            p.Address,
 		EmergencyContact:   p.EmergencyContact,
 		MedicalHistory:     p.MedicalHistory,
-		Allergies:          p.Allergies,
-		CurrentMedications: p.CurrentMedications,
 		CreatedBy:          p.CreatedBy.ToResponse(),
 		IsActive:           p.IsActive,
 		CreatedAt:          p.CreatedAt,
@@ -121,4 +129,21 @@ func (p *Patient) CalculateAge() int {
 	}
 	
 	return age
-} 
\ No newline at end of file
+} 
+// PatientSummary identifies a patient on records that belong to them, such as
+// appointments, without repeating the full demographic and medical details.
+type PatientSummary struct {
+	ID        uint   `json:"id"`
+	PatientID string `json:"patient_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+func (p *Patient) ToSummary() PatientSummary {
+	return PatientSummary{
+		ID:        p.ID,
+		PatientID: p.PatientID,
+		FirstName: p.FirstName,
+		LastName:  p.LastName,
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PatientAlias keeps the patient ID of a duplicate record that was merged
+// into another patient, so that lookups by the old ID still find the
+// surviving record. Aliases follow the survivor if it is merged again.
+type PatientAlias struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	Alias           string    `json:"alias" gorm:"uniqueIndex;not null"`
+	PatientID       uint      `json:"patient_id" gorm:"not null;index"`
+	MergedPatientID uint      `json:"merged_patient_id" gorm:"not null"`
+	CreatedByID     uint      `json:"created_by_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (PatientAlias) TableName() string {
+	return "patient_aliases"
+}
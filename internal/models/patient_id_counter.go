@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PatientIDCounter holds the last sequence number issued for a patient ID
+// scope, the prefix and date part that come before the number. Incrementing
+// the row locks it until the registering transaction ends, so concurrent
+// registrations are handed consecutive numbers.
+type PatientIDCounter struct {
+	Scope     string    `json:"scope" gorm:"primaryKey;size:50"`
+	Value     int64     `json:"value" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (PatientIDCounter) TableName() string {
+	return "patient_id_counters"
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"hospital-management-system/internal/phi"
+
+	"gorm.io/gorm"
+)
+
+// Patients and their revisions are encrypted transparently: the hooks below
+// seal the PHI columns with the record's data key before every write and
+// open them after every read, using the keyring installed with
+// phi.SetKeyring. Without a keyring records are written in plaintext.
+//
+// Encrypted columns cannot be searched, so the phone number and email also
+// get a blind index, a keyed hash that supports exact-match lookups.
+
+func (p *Patient) phiFields() []phi.Field {
+	return []phi.Field{
+		{Name: "email", Value: &p.Email},
+		{Name: "phone", Value: &p.Phone},
+		{Name: "address", Value: &p.Address},
+		{Name: "medical_history", Value: &p.MedicalHistory},
+	}
+}
+
+// SealPHI refreshes the blind indexes and encrypts the PHI columns in place,
+// moving the record to the active master key if it is on an older one.
+func (p *Patient) SealPHI(keyring *phi.Keyring) error {
+	if err := p.OpenPHI(keyring); err != nil {
+		return err
+	}
+	p.PhoneIndex = keyring.PhoneIndex(p.Phone)
+	p.EmailIndex = keyring.EmailIndex(p.Email)
+	return keyring.Seal(&p.EncryptedDataKey, &p.KeyVersion, p.phiFields()...)
+}
+
+// OpenPHI decrypts the PHI columns in place.
+func (p *Patient) OpenPHI(keyring *phi.Keyring) error {
+	return keyring.Open(p.EncryptedDataKey, p.KeyVersion, p.phiFields()...)
+}
+
+func (p *Patient) BeforeSave(tx *gorm.DB) error {
+	return p.SealPHI(phi.CurrentKeyring())
+}
+
+func (p *Patient) AfterSave(tx *gorm.DB) error {
+	return p.OpenPHI(phi.CurrentKeyring())
+}
+
+func (p *Patient) AfterFind(tx *gorm.DB) error {
+	return p.OpenPHI(phi.CurrentKeyring())
+}
+
+func (r *PatientRevision) phiFields() []phi.Field {
+	return []phi.Field{
+		{Name: "changes", Value: &r.Changes, JSON: true},
+		{Name: "snapshot", Value: &r.Snapshot, JSON: true},
+	}
+}
+
+func (r *PatientRevision) SealPHI(keyring *phi.Keyring) error {
+	return keyring.Seal(&r.EncryptedDataKey, &r.KeyVersion, r.phiFields()...)
+}
+
+func (r *PatientRevision) OpenPHI(keyring *phi.Keyring) error {
+	return keyring.Open(r.EncryptedDataKey, r.KeyVersion, r.phiFields()...)
+}
+
+func (r *PatientRevision) BeforeSave(tx *gorm.DB) error {
+	return r.SealPHI(phi.CurrentKeyring())
+}
+
+func (r *PatientRevision) AfterSave(tx *gorm.DB) error {
+	return r.OpenPHI(phi.CurrentKeyring())
+}
+
+func (r *PatientRevision) AfterFind(tx *gorm.DB) error {
+	return r.OpenPHI(phi.CurrentKeyring())
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type RevisionAction string
+
+const (
+	RevisionActionCreate RevisionAction = "create"
+	RevisionActionUpdate RevisionAction = "update"
+	RevisionActionDelete RevisionAction = "delete"
+	RevisionActionMerge  RevisionAction = "merge"
+	// RevisionActionSafetyOverride records the clinical safety warnings a
+	// prescriber overrode. The patient itself is unchanged.
+	RevisionActionSafetyOverride RevisionAction = "safety_override"
+)
+
+// PatientRevision is an append-only record of a single change to a patient.
+// Changes holds the field-level diff and Snapshot the full tracked state of
+// the patient after the change, both stored as JSON.
+type PatientRevision struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	PatientID   uint           `json:"patient_id" gorm:"not null;uniqueIndex:idx_patient_revisions_patient_version"`
+	Version     int            `json:"version" gorm:"not null;uniqueIndex:idx_patient_revisions_patient_version"`
+	Action      RevisionAction `json:"action" gorm:"not null"`
+	Changes     string         `json:"-" gorm:"type:jsonb;not null"`
+	Snapshot    string         `json:"-" gorm:"type:jsonb;not null"`
+	ChangedByID uint           `json:"changed_by_id" gorm:"not null"`
+	ChangedBy   User           `json:"changed_by" gorm:"foreignKey:ChangedByID"`
+	CreatedAt   time.Time      `json:"created_at"`
+
+	// EncryptedDataKey and KeyVersion protect Changes and Snapshot, which
+	// carry the same PHI as the patient, see patient_phi.go.
+	EncryptedDataKey string `json:"-"`
+	KeyVersion       int    `json:"-" gorm:"not null;default:0"`
+}
+
+func (PatientRevision) TableName() string {
+	return "patient_revisions"
+}
+
+// PatientSnapshot captures the patient fields tracked by the revision history.
+type PatientSnapshot struct {
+	PatientID        string    `json:"patient_id"`
+	FirstName        string    `json:"first_name"`
+	LastName         string    `json:"last_name"`
+	Email            string    `json:"email"`
+	Phone            string    `json:"phone"`
+	DateOfBirth      time.Time `json:"date_of_birth"`
+	Gender           Gender    `json:"gender"`
+	BloodType        BloodType `json:"blood_type"`
+	Address          string    `json:"address"`
+	EmergencyContact string    `json:"emergency_contact"`
+	MedicalHistory   string    `json:"medical_history"`
+	IsActive         bool      `json:"is_active"`
+}
+
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type PatientRevisionResponse struct {
+	ID        uint            `json:"id"`
+	PatientID uint            `json:"patient_id"`
+	Version   int             `json:"version"`
+	Action    RevisionAction  `json:"action"`
+	Changes   []FieldChange   `json:"changes"`
+	Snapshot  PatientSnapshot `json:"snapshot"`
+	ChangedBy UserResponse    `json:"changed_by"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type PatientRevisionDiffResponse struct {
+	PatientID   uint          `json:"patient_id"`
+	FromVersion int           `json:"from_version"`
+	ToVersion   int           `json:"to_version"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+func (p *Patient) Snapshot() PatientSnapshot {
+	return PatientSnapshot{
+		PatientID:        p.PatientID,
+		FirstName:        p.FirstName,
+		LastName:         p.LastName,
+		Email:            p.Email,
+		Phone:            p.Phone,
+		DateOfBirth:      p.DateOfBirth,
+		Gender:           p.Gender,
+		BloodType:        p.BloodType,
+		Address:          p.Address,
+		EmergencyContact: p.EmergencyContact,
+		MedicalHistory:   p.MedicalHistory,
+		IsActive:         p.IsActive,
+	}
+}
+
+// NewPatientRevision builds the revision recording a change of a patient from
+// before to after. It returns nil for an update that leaves every tracked
+// field unchanged, which is not recorded.
+func NewPatientRevision(patientID uint, action RevisionAction, before, after PatientSnapshot, changedByID uint) (*PatientRevision, error) {
+	changes := DiffPatientSnapshots(before, after)
+	if action == RevisionActionUpdate && len(changes) == 0 {
+		return nil, nil
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	snapshotJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PatientRevision{
+		PatientID:   patientID,
+		Action:      action,
+		Changes:     string(changesJSON),
+		Snapshot:    string(snapshotJSON),
+		ChangedByID: changedByID,
+	}, nil
+}
+
+// NewSafetyOverrideRevision builds the revision recording the safety
+// warnings overridden when prescribing. Each override is listed as a change
+// of the safety_override field, the snapshot is the patient as it stands.
+func NewSafetyOverrideRevision(snapshot PatientSnapshot, prescription *Prescription, changedByID uint) (*PatientRevision, error) {
+	changes := make([]FieldChange, len(prescription.SafetyOverrides))
+	for i, override := range prescription.SafetyOverrides {
+		changes[i] = FieldChange{
+			Field: "safety_override",
+			After: map[string]interface{}{
+				"prescription_id": prescription.ID,
+				"medication_name": prescription.MedicationName,
+				"alert_kind":      override.AlertKind,
+				"alert_level":     override.AlertLevel,
+				"description":     override.Description,
+				"reason":          override.Reason,
+			},
+		}
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PatientRevision{
+		PatientID:   prescription.PatientID,
+		Action:      RevisionActionSafetyOverride,
+		Changes:     string(changesJSON),
+		Snapshot:    string(snapshotJSON),
+		ChangedByID: changedByID,
+	}, nil
+}
+
+// DiffPatientSnapshots returns the fields that differ between two snapshots,
+// in the order they are declared on PatientSnapshot.
+func DiffPatientSnapshots(before, after PatientSnapshot) []FieldChange {
+	beforeFields := snapshotFields(before)
+	afterFields := snapshotFields(after)
+
+	changes := []FieldChange{}
+	snapshotType := reflect.TypeOf(PatientSnapshot{})
+	for i := 0; i < snapshotType.NumField(); i++ {
+		field := strings.Split(snapshotType.Field(i).Tag.Get("json"), ",")[0]
+		if !reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			changes = append(changes, FieldChange{
+				Field:  field,
+				Before: beforeFields[field],
+				After:  afterFields[field],
+			})
+		}
+	}
+
+	return changes
+}
+
+func snapshotFields(snapshot PatientSnapshot) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func (r *PatientRevision) GetChanges() ([]FieldChange, error) {
+	changes := []FieldChange{}
+	if r.Changes == "" {
+		return changes, nil
+	}
+	if err := json.Unmarshal([]byte(r.Changes), &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *PatientRevision) GetSnapshot() (PatientSnapshot, error) {
+	var snapshot PatientSnapshot
+	if r.Snapshot == "" {
+		return snapshot, nil
+	}
+	if err := json.Unmarshal([]byte(r.Snapshot), &snapshot); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+func (r *PatientRevision) ToResponse() PatientRevisionResponse {
+	changes, _ := r.GetChanges()
+	snapshot, _ := r.GetSnapshot()
+
+	return PatientRevisionResponse{
+		ID:        r.ID,
+		PatientID: r.PatientID,
+		Version:   r.Version,
+		Action:    r.Action,
+		Changes:   changes,
+		Snapshot:  snapshot,
+		ChangedBy: r.ChangedBy.ToResponse(),
+		CreatedAt: r.CreatedAt,
+	}
+}
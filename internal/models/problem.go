@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// ICD10Code is an entry of the ICD-10 code table used to code problems.
+// Codes are stored in their dotted form, for example "E11.9".
+type ICD10Code struct {
+	Code        string    `json:"code" gorm:"primaryKey;size:8"`
+	Description string    `json:"description" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (ICD10Code) TableName() string {
+	return "icd10_codes"
+}
+
+type ProblemStatus string
+
+const (
+	ProblemStatusActive   ProblemStatus = "active"
+	ProblemStatusResolved ProblemStatus = "resolved"
+)
+
+// Problem is a coded diagnosis on the patient's problem list. Description
+// copies the code table text at the time of recording and may be refined
+// by the clinician.
+type Problem struct {
+	ID           uint          `json:"id" gorm:"primaryKey"`
+	PatientID    uint          `json:"patient_id" gorm:"not null;index"`
+	ICD10Code    string        `json:"icd10_code" gorm:"column:icd10_code;size:8;not null;index:idx_problems_code_status"`
+	Description  string        `json:"description" gorm:"not null"`
+	OnsetDate    *time.Time    `json:"onset_date"`
+	ResolvedDate *time.Time    `json:"resolved_date"`
+	Status       ProblemStatus `json:"status" gorm:"not null;default:active;index:idx_problems_code_status"`
+	Notes        string        `json:"notes" gorm:"type:text"`
+
+	// System fields
+	RecordedByID    uint      `json:"recorded_by_id" gorm:"not null"`
+	RecordedBy      User      `json:"recorded_by" gorm:"foreignKey:RecordedByID"`
+	LastUpdatedByID *uint     `json:"last_updated_by_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (Problem) TableName() string {
+	return "patient_problems"
+}
+
+type ProblemResponse struct {
+	ID           uint          `json:"id"`
+	PatientID    uint          `json:"patient_id"`
+	ICD10Code    string        `json:"icd10_code"`
+	Description  string        `json:"description"`
+	OnsetDate    *time.Time    `json:"onset_date,omitempty"`
+	ResolvedDate *time.Time    `json:"resolved_date,omitempty"`
+	Status       ProblemStatus `json:"status"`
+	Notes        string        `json:"notes,omitempty"`
+	RecordedBy   UserResponse  `json:"recorded_by"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (p *Problem) ToResponse() ProblemResponse {
+	return ProblemResponse{
+		ID:           p.ID,
+		PatientID:    p.PatientID,
+		ICD10Code:    p.ICD10Code,
+		Description:  p.Description,
+		OnsetDate:    p.OnsetDate,
+		ResolvedDate: p.ResolvedDate,
+		Status:       p.Status,
+		Notes:        p.Notes,
+		RecordedBy:   p.RecordedBy.ToResponse(),
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// UserSession is created at login and shared by every access and refresh
+// token issued from it. Revoking the session invalidates all of them.
+type UserSession struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	User          User       `json:"-" gorm:"foreignKey:UserID"`
+	ExpiresAt     time.Time  `json:"expires_at" gorm:"not null"`
+	LastUsedAt    time.Time  `json:"last_used_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+func (s *UserSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// RefreshToken stores the SHA-256 hash of an opaque refresh token. Each token
+// may be used once; using it again marks the session as compromised.
+type RefreshToken struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	SessionID uint        `json:"session_id" gorm:"not null;index"`
+	Session   UserSession `json:"-" gorm:"foreignKey:SessionID"`
+	TokenHash string      `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time   `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time  `json:"used_at,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -10,17 +10,30 @@ type UserRole string
 const (
 	RoleReceptionist UserRole = "receptionist"
 	RoleDoctor      UserRole = "doctor"
+	RoleAdmin        UserRole = "admin"
 )
 
 type User struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
 	Username  string         `json:"username" gorm:"uniqueIndex;not null" binding:"required,min=3,max=50"`
 	Email     string         `json:"email" gorm:"uniqueIndex;not null" binding:"required,email"`
-	Password  string         `json:"-" gorm:"not null" binding:"required,min=6"`
+	Password  string         `json:"-" gorm:"not null" binding:"required,min=12"`
 	FirstName string         `json:"first_name" gorm:"not null" binding:"required,min=2,max=50"`
 	LastName  string         `json:"last_name" gorm:"not null" binding:"required,min=2,max=50"`
-	Role      UserRole       `json:"role" gorm:"not null" binding:"required,oneof=receptionist doctor"`
+	Role      UserRole       `json:"role" gorm:"not null" binding:"required"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
+
+	// Multi-factor authentication. MFASecret is set when enrollment starts and
+	// only becomes effective once MFAEnabled is set by confirming a code.
+	// Failed codes are counted until one succeeds; too many lock MFA, and
+	// with it login, until MFALockedUntil.
+	MFAEnabled        bool       `json:"mfa_enabled" gorm:"default:false"`
+	MFASecret         string     `json:"-"`
+	MFALastUsedStep   int64      `json:"-"`
+	MFAEnrolledAt     *time.Time `json:"mfa_enrolled_at,omitempty"`
+	MFAFailedAttempts int        `json:"-" gorm:"not null;default:0"`
+	MFALockedUntil    *time.Time `json:"-"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
@@ -38,6 +51,7 @@ type UserResponse struct {
 	LastName  string   `json:"last_name"`
 	Role      UserRole `json:"role"`
 	IsActive  bool     `json:"is_active"`
+	MFAEnabled bool    `json:"mfa_enabled"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -51,6 +65,7 @@ func (u *User) ToResponse() UserResponse {
 		LastName:  u.LastName,
 		Role:      u.Role,
 		IsActive:  u.IsActive,
+		MFAEnabled: u.MFAEnabled,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -0,0 +1,141 @@
+package models
+
+import "time"
+
+// VitalSignFlag marks one measurement of a reading that fell outside the
+// reference range for the patient's age at the time it was recorded.
+type VitalSignFlag struct {
+	Measure string  `json:"measure"`
+	Value   float64 `json:"value"`
+	Status  string  `json:"status"` // "low" or "high"
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// VitalSigns is one set of observations taken at RecordedAt. Every
+// measurement is optional; BMI is computed from weight and height.
+// Temperature is in degrees Celsius, weight in kilograms and height in
+// centimetres.
+type VitalSigns struct {
+	ID              uint            `json:"id" gorm:"primaryKey"`
+	PatientID       uint            `json:"patient_id" gorm:"not null;index:idx_vital_signs_patient_time"`
+	RecordedAt      time.Time       `json:"recorded_at" gorm:"not null;index:idx_vital_signs_patient_time"`
+	SystolicBP      *int            `json:"systolic_bp"`
+	DiastolicBP     *int            `json:"diastolic_bp"`
+	HeartRate       *int            `json:"heart_rate"`
+	Temperature     *float64        `json:"temperature"`
+	RespiratoryRate *int            `json:"respiratory_rate"`
+	SpO2            *int            `json:"spo2" gorm:"column:spo2"`
+	WeightKg        *float64        `json:"weight_kg"`
+	HeightCm        *float64        `json:"height_cm"`
+	BMI             *float64        `json:"bmi" gorm:"column:bmi"`
+	Notes           string          `json:"notes" gorm:"type:text"`
+	IsAbnormal      bool            `json:"is_abnormal" gorm:"not null;default:false"`
+	AbnormalFlags   []VitalSignFlag `json:"abnormal_flags" gorm:"type:text;serializer:json"`
+
+	// System fields
+	RecordedByID uint      `json:"recorded_by_id" gorm:"not null"`
+	RecordedBy   User      `json:"recorded_by" gorm:"foreignKey:RecordedByID"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (VitalSigns) TableName() string {
+	return "vital_signs"
+}
+
+type VitalSignsResponse struct {
+	ID              uint            `json:"id"`
+	PatientID       uint            `json:"patient_id"`
+	RecordedAt      time.Time       `json:"recorded_at"`
+	SystolicBP      *int            `json:"systolic_bp,omitempty"`
+	DiastolicBP     *int            `json:"diastolic_bp,omitempty"`
+	HeartRate       *int            `json:"heart_rate,omitempty"`
+	Temperature     *float64        `json:"temperature,omitempty"`
+	RespiratoryRate *int            `json:"respiratory_rate,omitempty"`
+	SpO2            *int            `json:"spo2,omitempty"`
+	WeightKg        *float64        `json:"weight_kg,omitempty"`
+	HeightCm        *float64        `json:"height_cm,omitempty"`
+	BMI             *float64        `json:"bmi,omitempty"`
+	Notes           string          `json:"notes,omitempty"`
+	IsAbnormal      bool            `json:"is_abnormal"`
+	AbnormalFlags   []VitalSignFlag `json:"abnormal_flags"`
+	RecordedBy      UserResponse    `json:"recorded_by"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+func (v *VitalSigns) ToResponse() VitalSignsResponse {
+	flags := v.AbnormalFlags
+	if flags == nil {
+		flags = []VitalSignFlag{}
+	}
+
+	return VitalSignsResponse{
+		ID:              v.ID,
+		PatientID:       v.PatientID,
+		RecordedAt:      v.RecordedAt,
+		SystolicBP:      v.SystolicBP,
+		DiastolicBP:     v.DiastolicBP,
+		HeartRate:       v.HeartRate,
+		Temperature:     v.Temperature,
+		RespiratoryRate: v.RespiratoryRate,
+		SpO2:            v.SpO2,
+		WeightKg:        v.WeightKg,
+		HeightCm:        v.HeightCm,
+		BMI:             v.BMI,
+		Notes:           v.Notes,
+		IsAbnormal:      v.IsAbnormal,
+		AbnormalFlags:   flags,
+		RecordedBy:      v.RecordedBy.ToResponse(),
+		CreatedAt:       v.CreatedAt,
+	}
+}
+
+// Value returns the named measurement, or false when it was not taken.
+// Measure names match the JSON field names.
+func (v *VitalSigns) Value(measure string) (float64, bool) {
+	switch measure {
+	case "systolic_bp":
+		return intValue(v.SystolicBP)
+	case "diastolic_bp":
+		return intValue(v.DiastolicBP)
+	case "heart_rate":
+		return intValue(v.HeartRate)
+	case "temperature":
+		return floatValue(v.Temperature)
+	case "respiratory_rate":
+		return intValue(v.RespiratoryRate)
+	case "spo2":
+		return intValue(v.SpO2)
+	case "weight_kg":
+		return floatValue(v.WeightKg)
+	case "height_cm":
+		return floatValue(v.HeightCm)
+	case "bmi":
+		return floatValue(v.BMI)
+	}
+	return 0, false
+}
+
+// Flag returns the abnormal flag recorded for the named measurement.
+func (v *VitalSigns) Flag(measure string) *VitalSignFlag {
+	for i := range v.AbnormalFlags {
+		if v.AbnormalFlags[i].Measure == measure {
+			return &v.AbnormalFlags[i]
+		}
+	}
+	return nil
+}
+
+func intValue(value *int) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return float64(*value), true
+}
+
+func floatValue(value *float64) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
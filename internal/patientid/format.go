@@ -0,0 +1,121 @@
+package patientid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CheckDigitLuhn appends a Luhn mod-10 check digit computed over the digits
+// of the ID, which catches any single mistyped digit and most swaps of
+// adjacent digits. CheckDigitNone leaves IDs without one.
+const (
+	CheckDigitNone = "none"
+	CheckDigitLuhn = "luhn"
+)
+
+// ErrInvalidCheckDigit is returned for a patient ID whose check digit does
+// not match, which usually means it was misread or mistyped.
+var ErrInvalidCheckDigit = errors.New("invalid patient ID check digit")
+
+// Format describes how patient IDs are built: a prefix, an optional date
+// part, a sequence number padded to Width digits and an optional check
+// digit, for example PAT + 20240101 + 0042 + 7. The sequence restarts for
+// every prefix and date part.
+type Format struct {
+	Prefix     string
+	DateFormat string // date part in YYYY, YY, MM and DD, empty for none
+	Width      int
+	CheckDigit string
+}
+
+var dateTokens = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02")
+
+// Validate reports configuration mistakes before any ID is issued.
+func (f Format) Validate() error {
+	if f.Width < 1 || f.Width > 12 {
+		return errors.New("patient ID width must be between 1 and 12")
+	}
+	if strings.ContainsAny(f.Prefix, "0123456789%_") {
+		return errors.New("patient ID prefix cannot contain digits, % or _")
+	}
+	if f.DateFormat != "" && strings.Trim(dateTokens.Replace(f.DateFormat), "0123456789") != "" {
+		return fmt.Errorf("patient ID date format %q may only use YYYY, YY, MM and DD", f.DateFormat)
+	}
+	switch f.CheckDigit {
+	case CheckDigitNone, CheckDigitLuhn:
+	default:
+		return fmt.Errorf("unknown patient ID check digit %q, use %s or %s", f.CheckDigit, CheckDigitLuhn, CheckDigitNone)
+	}
+	return nil
+}
+
+// Scope is the part of the ID before the sequence number. Each scope has its
+// own counter, so that a date part restarts numbering every period.
+func (f Format) Scope(now time.Time) string {
+	if f.DateFormat == "" {
+		return f.Prefix
+	}
+	return f.Prefix + now.Format(dateTokens.Replace(f.DateFormat))
+}
+
+// Build formats the sequence number allocated in scope as a patient ID.
+func (f Format) Build(scope string, sequence int64) string {
+	id := fmt.Sprintf("%s%0*d", scope, f.Width, sequence)
+	if f.CheckDigit == CheckDigitLuhn {
+		id += fmt.Sprintf("%d", LuhnCheckDigit(digits(id)))
+	}
+	return id
+}
+
+// Check verifies the check digit of an ID issued in this format. IDs that
+// do not have the shape of one, such as those issued before check digits
+// were introduced, are not checked and are simply looked up.
+func (f Format) Check(id string) error {
+	if f.CheckDigit != CheckDigitLuhn || !strings.HasPrefix(id, f.Prefix) {
+		return nil
+	}
+
+	body := strings.TrimPrefix(id, f.Prefix)
+	if body == "" || digits(body) != body {
+		return nil
+	}
+	if len(body) <= len(dateTokens.Replace(f.DateFormat))+f.Width {
+		return nil
+	}
+
+	payload, check := body[:len(body)-1], int(body[len(body)-1]-'0')
+	if LuhnCheckDigit(payload) != check {
+		return ErrInvalidCheckDigit
+	}
+	return nil
+}
+
+// LuhnCheckDigit computes the Luhn mod-10 check digit for a string of
+// decimal digits.
+func LuhnCheckDigit(number string) int {
+	sum := 0
+	double := true
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+func digits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
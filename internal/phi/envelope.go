@@ -0,0 +1,260 @@
+package phi
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ciphertextPrefix marks an encrypted value. Values without it are legacy
+// plaintext and are read as they are.
+const ciphertextPrefix = "enc:"
+
+// blindIndexSize is the number of HMAC bytes kept in a blind index. Sixteen
+// bytes make collisions negligible while not storing the full MAC.
+const blindIndexSize = 16
+
+// ErrNoKeyring is returned when an encrypted record is read without a
+// keyring installed.
+var ErrNoKeyring = errors.New("record is encrypted but no PHI keyfile is configured")
+
+// Field is one encrypted column of a record. Name is bound to the
+// ciphertext, so a value copied into another column does not decrypt.
+// JSON fields live in jsonb columns and store their ciphertext as a JSON
+// string so that the column stays valid JSON.
+type Field struct {
+	Name  string
+	Value *string
+	JSON  bool
+}
+
+// Seal encrypts the fields in place with the record's data key. A record
+// without a data key, or whose data key is wrapped with an older master key,
+// gets a fresh data key under the active master key, which is how records
+// are rotated. Fields that are already encrypted are decrypted first, and
+// empty fields are left empty. On a nil keyring Seal does nothing.
+func (k *Keyring) Seal(dataKey *string, keyVersion *int, fields ...Field) error {
+	if k == nil {
+		return nil
+	}
+	if err := k.Open(*dataKey, *keyVersion, fields...); err != nil {
+		return err
+	}
+
+	empty := true
+	for _, field := range fields {
+		if *field.Value != "" {
+			empty = false
+		}
+	}
+	if empty && *dataKey == "" {
+		return nil
+	}
+
+	var key []byte
+	var err error
+	if *dataKey != "" && *keyVersion == k.active {
+		key, err = k.unwrap(*dataKey, *keyVersion)
+	} else {
+		key, err = randomBytes(keySize)
+		if err == nil {
+			*dataKey, err = k.wrap(key, k.active)
+			*keyVersion = k.active
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if *field.Value == "" {
+			continue
+		}
+		sealed, err := seal(key, field.Name, *field.Value)
+		if err != nil {
+			return err
+		}
+		if field.JSON {
+			encoded, _ := json.Marshal(sealed)
+			sealed = string(encoded)
+		}
+		*field.Value = sealed
+	}
+	return nil
+}
+
+// Open decrypts the encrypted fields in place. Plaintext fields are left as
+// they are, so records written before encryption was enabled still read.
+func (k *Keyring) Open(dataKey string, keyVersion int, fields ...Field) error {
+	if !IsSealed(fields...) {
+		return nil
+	}
+	if k == nil {
+		return ErrNoKeyring
+	}
+	if dataKey == "" {
+		return errors.New("encrypted record has no data key")
+	}
+
+	key, err := k.unwrap(dataKey, keyVersion)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		value, ok := sealedValue(field)
+		if !ok {
+			continue
+		}
+		plaintext, err := open(key, field.Name, value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+		}
+		*field.Value = plaintext
+	}
+	return nil
+}
+
+// IsSealed reports whether any of the fields holds ciphertext.
+func IsSealed(fields ...Field) bool {
+	for _, field := range fields {
+		if _, ok := sealedValue(field); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PhoneIndex is the blind index of a phone number, computed over its digits
+// only so that formatting does not matter. It is empty for an empty number
+// or a nil keyring.
+func (k *Keyring) PhoneIndex(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	return k.blindIndex("phone", digits)
+}
+
+// EmailIndex is the blind index of a case-insensitive email address.
+func (k *Keyring) EmailIndex(email string) string {
+	return k.blindIndex("email", strings.ToLower(strings.TrimSpace(email)))
+}
+
+func (k *Keyring) blindIndex(kind, value string) string {
+	if k == nil || value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:blindIndexSize])
+}
+
+func (k *Keyring) wrap(key []byte, version int) (string, error) {
+	master, err := k.master(version)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := encrypt(master, wrapContext(version), key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+func (k *Keyring) unwrap(dataKey string, version int) ([]byte, error) {
+	master, err := k.master(version)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(dataKey)
+	if err != nil {
+		return nil, errors.New("data key is not valid base64")
+	}
+	key, err := decrypt(master, wrapContext(version), wrapped)
+	if err != nil {
+		return nil, errors.New("failed to unwrap data key")
+	}
+	return key, nil
+}
+
+func wrapContext(version int) string {
+	return fmt.Sprintf("data-key:v%d", version)
+}
+
+func sealedValue(field Field) (string, bool) {
+	value := *field.Value
+	if field.JSON && strings.HasPrefix(value, `"`+ciphertextPrefix) {
+		var decoded string
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return "", false
+		}
+		value = decoded
+	}
+	return value, strings.HasPrefix(value, ciphertextPrefix)
+}
+
+func seal(key []byte, name, plaintext string) (string, error) {
+	ciphertext, err := encrypt(key, name, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return ciphertextPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func open(key []byte, name, value string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, ciphertextPrefix))
+	if err != nil {
+		return "", errors.New("ciphertext is not valid base64")
+	}
+	plaintext, err := decrypt(key, name, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// encrypt seals plaintext with AES-GCM and returns the nonce followed by the
+// ciphertext. additionalData is authenticated but not stored.
+func encrypt(key []byte, additionalData string, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, []byte(additionalData)), nil
+}
+
+func decrypt(key []byte, additionalData string, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, []byte(additionalData))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,150 @@
+package phi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// keySize is the length of master, data and blind-index keys: AES-256.
+const keySize = 32
+
+// Keyring holds the master keys that wrap per-record data keys, and the
+// key used for blind indexes. New data keys are always wrapped with the
+// active master key; older versions are kept so that records written before
+// a rotation can still be read until they are re-encrypted.
+type Keyring struct {
+	active   int
+	masters  map[int][]byte
+	indexKey []byte
+}
+
+type keyfile struct {
+	ActiveVersion int              `json:"active_version"`
+	MasterKeys    []keyfileVersion `json:"master_keys"`
+	BlindIndexKey string           `json:"blind_index_key"`
+}
+
+type keyfileVersion struct {
+	Version int    `json:"version"`
+	Key     string `json:"key"`
+}
+
+// NewKeyring builds a keyring from raw keys. masters maps key versions,
+// which must be positive, to 32-byte keys.
+func NewKeyring(active int, masters map[int][]byte, indexKey []byte) (*Keyring, error) {
+	if len(masters) == 0 {
+		return nil, errors.New("keyring does not contain any master keys")
+	}
+	for version, key := range masters {
+		if version <= 0 {
+			return nil, fmt.Errorf("invalid master key version %d", version)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("master key version %d must be %d bytes", version, keySize)
+		}
+	}
+	if _, ok := masters[active]; !ok {
+		return nil, fmt.Errorf("active master key version %d is not in the keyring", active)
+	}
+	if len(indexKey) != keySize {
+		return nil, fmt.Errorf("blind index key must be %d bytes", keySize)
+	}
+
+	keyring := &Keyring{
+		active:   active,
+		masters:  make(map[int][]byte, len(masters)),
+		indexKey: append([]byte(nil), indexKey...),
+	}
+	for version, key := range masters {
+		keyring.masters[version] = append([]byte(nil), key...)
+	}
+	return keyring, nil
+}
+
+// LoadKeyring reads a JSON keyfile of the form
+//
+//	{
+//	  "active_version": 2,
+//	  "master_keys": [{"version": 1, "key": "<base64>"}, {"version": 2, "key": "<base64>"}],
+//	  "blind_index_key": "<base64>"
+//	}
+//
+// where every key is 32 random bytes, for example from openssl rand -base64 32.
+// The blind index key cannot be rotated without rebuilding every index.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PHI keyfile: %w", err)
+	}
+
+	var file keyfile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse PHI keyfile: %w", err)
+	}
+
+	masters := make(map[int][]byte, len(file.MasterKeys))
+	for _, entry := range file.MasterKeys {
+		if _, ok := masters[entry.Version]; ok {
+			return nil, fmt.Errorf("duplicate master key version %d in PHI keyfile", entry.Version)
+		}
+		key, err := base64.StdEncoding.DecodeString(entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("master key version %d is not valid base64", entry.Version)
+		}
+		masters[entry.Version] = key
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(file.BlindIndexKey)
+	if err != nil {
+		return nil, errors.New("blind index key is not valid base64")
+	}
+
+	return NewKeyring(file.ActiveVersion, masters, indexKey)
+}
+
+// ActiveVersion is the master key version new data keys are wrapped with.
+func (k *Keyring) ActiveVersion() int {
+	return k.active
+}
+
+func (k *Keyring) master(version int) ([]byte, error) {
+	key, ok := k.masters[version]
+	if !ok {
+		return nil, fmt.Errorf("master key version %d is not in the keyring", version)
+	}
+	return key, nil
+}
+
+var (
+	currentMu sync.RWMutex
+	current   *Keyring
+)
+
+// SetKeyring installs the keyring used by the model hooks. A nil keyring
+// turns encryption off: records are written in plaintext, and reading an
+// encrypted record fails.
+func SetKeyring(keyring *Keyring) {
+	currentMu.Lock()
+	current = keyring
+	currentMu.Unlock()
+}
+
+// CurrentKeyring returns the installed keyring, or nil if there is none.
+func CurrentKeyring() *Keyring {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
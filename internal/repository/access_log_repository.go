@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AccessLogFilter struct {
+	PatientID *uint
+	UserID    *uint
+	From      time.Time
+	To        time.Time
+}
+
+type AccessLogRepository interface {
+	CreateBatch(logs []*models.PatientAccessLog) error
+	Search(filter AccessLogFilter, limit, offset int) ([]*models.PatientAccessLog, error)
+	Count(filter AccessLogFilter) (int64, error)
+}
+
+type accessLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessLogRepository(db *gorm.DB) AccessLogRepository {
+	return &accessLogRepository{db: db}
+}
+
+func (r *accessLogRepository) CreateBatch(logs []*models.PatientAccessLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.Create(&logs).Error
+}
+
+func (r *accessLogRepository) Search(filter AccessLogFilter, limit, offset int) ([]*models.PatientAccessLog, error) {
+	var logs []*models.PatientAccessLog
+	query := r.applyFilter(r.db.Model(&models.PatientAccessLog{}), filter).Order("accessed_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (r *accessLogRepository) Count(filter AccessLogFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.Model(&models.PatientAccessLog{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *accessLogRepository) applyFilter(query *gorm.DB, filter AccessLogFilter) *gorm.DB {
+	if filter.PatientID != nil {
+		query = query.Where("patient_id = ?", *filter.PatientID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("accessed_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("accessed_at < ?", filter.To)
+	}
+	return query
+}
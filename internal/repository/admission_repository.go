@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrBedOccupied is returned when another admission already holds the
+	// requested bed, including one that got there first concurrently.
+	ErrBedOccupied = errors.New("bed is already occupied")
+	// ErrBedUnavailable is returned when the bed has been deactivated.
+	ErrBedUnavailable         = errors.New("bed is not available")
+	ErrPatientAlreadyAdmitted = errors.New("patient is already admitted")
+	// ErrAdmissionNotActive is returned when an admission was discharged
+	// before a transfer or discharge could be applied.
+	ErrAdmissionNotActive = errors.New("admission is no longer active")
+)
+
+type AdmissionRepository interface {
+	Admit(admission *models.Admission) error
+	GetByID(patientID, id uint) (*models.Admission, error)
+	GetCurrent(patientID uint) (*models.Admission, error)
+	ListByPatient(patientID uint) ([]*models.Admission, error)
+	ListActive() ([]*models.Admission, error)
+	IsBedOccupied(bedID uint) (bool, error)
+	Transfer(admission *models.Admission, transfer *models.BedTransfer) error
+	Discharge(admission *models.Admission) error
+}
+
+type admissionRepository struct {
+	db *gorm.DB
+}
+
+func NewAdmissionRepository(db *gorm.DB) AdmissionRepository {
+	return &admissionRepository{db: db}
+}
+
+// Admit locks the bed and the patient before checking that neither is
+// already taken, so concurrent requests for the same bed or patient are
+// serialized and only the first one succeeds.
+func (r *admissionRepository) Admit(admission *models.Admission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockAvailableBed(tx, admission.BedID, 0); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Patient{}, admission.PatientID).Error; err != nil {
+			return err
+		}
+
+		var admitted int64
+		if err := tx.Model(&models.Admission{}).
+			Where("patient_id = ? AND status = ?", admission.PatientID, models.AdmissionStatusAdmitted).
+			Count(&admitted).Error; err != nil {
+			return err
+		}
+		if admitted > 0 {
+			return ErrPatientAlreadyAdmitted
+		}
+
+		return tx.Omit(clause.Associations).Create(admission).Error
+	})
+}
+
+func (r *admissionRepository) GetByID(patientID, id uint) (*models.Admission, error) {
+	var admission models.Admission
+	if err := r.preload(r.db).Where("id = ? AND patient_id = ?", id, patientID).First(&admission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("admission not found")
+		}
+		return nil, err
+	}
+	return &admission, nil
+}
+
+func (r *admissionRepository) GetCurrent(patientID uint) (*models.Admission, error) {
+	var admission models.Admission
+	if err := r.preload(r.db).
+		Where("patient_id = ? AND status = ?", patientID, models.AdmissionStatusAdmitted).
+		First(&admission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("patient is not admitted")
+		}
+		return nil, err
+	}
+	return &admission, nil
+}
+
+func (r *admissionRepository) ListByPatient(patientID uint) ([]*models.Admission, error) {
+	var admissions []*models.Admission
+	if err := r.preload(r.db).
+		Where("patient_id = ?", patientID).
+		Order("admitted_at DESC, id DESC").
+		Find(&admissions).Error; err != nil {
+		return nil, err
+	}
+	return admissions, nil
+}
+
+// ListActive returns every current admission with its patient, for the
+// occupancy board.
+func (r *admissionRepository) ListActive() ([]*models.Admission, error) {
+	var admissions []*models.Admission
+	if err := r.db.Preload("Patient").Preload("AdmittingDoctor").
+		Where("status = ?", models.AdmissionStatusAdmitted).
+		Find(&admissions).Error; err != nil {
+		return nil, err
+	}
+	return admissions, nil
+}
+
+func (r *admissionRepository) IsBedOccupied(bedID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Admission{}).
+		Where("bed_id = ? AND status = ?", bedID, models.AdmissionStatusAdmitted).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Transfer moves an admitted patient to transfer.ToBedID and records the
+// move. The target bed is locked and checked in the same way as on
+// admission.
+func (r *admissionRepository) Transfer(admission *models.Admission, transfer *models.BedTransfer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Admission
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", admission.ID, models.AdmissionStatusAdmitted).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAdmissionNotActive
+			}
+			return err
+		}
+
+		if err := lockAvailableBed(tx, transfer.ToBedID, admission.ID); err != nil {
+			return err
+		}
+
+		transfer.AdmissionID = admission.ID
+		transfer.FromBedID = current.BedID
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		admission.BedID = transfer.ToBedID
+		return tx.Model(&models.Admission{}).Where("id = ?", admission.ID).
+			Updates(map[string]interface{}{"bed_id": transfer.ToBedID, "updated_at": time.Now()}).Error
+	})
+}
+
+// Discharge only applies to admissions that are still admitted.
+func (r *admissionRepository) Discharge(admission *models.Admission) error {
+	result := r.db.Model(&models.Admission{}).
+		Where("id = ? AND status = ?", admission.ID, models.AdmissionStatusAdmitted).
+		Updates(map[string]interface{}{
+			"status":           admission.Status,
+			"discharged_at":    admission.DischargedAt,
+			"discharged_by_id": admission.DischargedByID,
+			"discharge_notes":  admission.DischargeNotes,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAdmissionNotActive
+	}
+	return nil
+}
+
+// lockAvailableBed locks an active bed and checks that no admission other
+// than exceptAdmissionID holds it.
+func lockAvailableBed(tx *gorm.DB, bedID, exceptAdmissionID uint) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ? AND is_active = ?", bedID, true).
+		First(&models.Bed{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBedUnavailable
+		}
+		return err
+	}
+
+	var occupied int64
+	if err := tx.Model(&models.Admission{}).
+		Where("bed_id = ? AND status = ? AND id <> ?", bedID, models.AdmissionStatusAdmitted, exceptAdmissionID).
+		Count(&occupied).Error; err != nil {
+		return err
+	}
+	if occupied > 0 {
+		return ErrBedOccupied
+	}
+	return nil
+}
+
+func (r *admissionRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Bed.Room.Ward").Preload("AdmittingDoctor").
+		Preload("Transfers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("transferred_at ASC")
+		})
+}
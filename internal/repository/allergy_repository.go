@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AllergyRepository interface {
+	Create(allergy *models.Allergy) error
+	GetByID(patientID, id uint) (*models.Allergy, error)
+	Update(allergy *models.Allergy) error
+	ListByPatient(patientID uint, status models.AllergyStatus) ([]*models.Allergy, error)
+	FindActiveBySubstance(patientID uint, substance string) (*models.Allergy, error)
+}
+
+type allergyRepository struct {
+	db *gorm.DB
+}
+
+func NewAllergyRepository(db *gorm.DB) AllergyRepository {
+	return &allergyRepository{db: db}
+}
+
+func (r *allergyRepository) Create(allergy *models.Allergy) error {
+	return r.db.Omit(clause.Associations).Create(allergy).Error
+}
+
+func (r *allergyRepository) GetByID(patientID, id uint) (*models.Allergy, error) {
+	var allergy models.Allergy
+	if err := r.db.Preload("RecordedBy").Where("id = ? AND patient_id = ?", id, patientID).First(&allergy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("allergy not found")
+		}
+		return nil, err
+	}
+	return &allergy, nil
+}
+
+func (r *allergyRepository) Update(allergy *models.Allergy) error {
+	return r.db.Omit(clause.Associations).Save(allergy).Error
+}
+
+// ListByPatient returns the patient's allergies with the given status. An
+// empty status returns everything except entries marked as entered in error.
+func (r *allergyRepository) ListByPatient(patientID uint, status models.AllergyStatus) ([]*models.Allergy, error) {
+	var allergies []*models.Allergy
+	query := r.db.Preload("RecordedBy").Where("patient_id = ?", patientID)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	} else {
+		query = query.Where("status <> ?", models.AllergyStatusEnteredInError)
+	}
+
+	if err := query.Order("created_at DESC").Find(&allergies).Error; err != nil {
+		return nil, err
+	}
+	return allergies, nil
+}
+
+// FindActiveBySubstance compares substances after loading the patient's
+// active allergies, because the substance column may be encrypted.
+func (r *allergyRepository) FindActiveBySubstance(patientID uint, substance string) (*models.Allergy, error) {
+	var allergies []*models.Allergy
+	if err := r.db.Where("patient_id = ? AND status = ?", patientID, models.AllergyStatusActive).
+		Order("id ASC").Find(&allergies).Error; err != nil {
+		return nil, err
+	}
+	for _, allergy := range allergies {
+		if strings.EqualFold(allergy.Substance, substance) {
+			return allergy, nil
+		}
+	}
+	return nil, errors.New("allergy not found")
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrAppointmentConflict is returned when a booking overlaps another
+	// active appointment of the same doctor or patient.
+	ErrAppointmentConflict = errors.New("appointment conflicts with an existing booking")
+	// ErrAppointmentStatusChanged is returned when an appointment has moved
+	// on since it was read, for example when it was cancelled while being
+	// rescheduled.
+	ErrAppointmentStatusChanged = errors.New("appointment status has changed")
+)
+
+var blockingAppointmentStatuses = []models.AppointmentStatus{models.AppointmentStatusScheduled, models.AppointmentStatusCheckedIn}
+
+type AppointmentFilter struct {
+	DoctorID  *uint
+	PatientID *uint
+	Status    models.AppointmentStatus
+	From      time.Time
+	To        time.Time
+}
+
+type AppointmentRepository interface {
+	Create(appointment *models.Appointment) error
+	GetByID(id uint) (*models.Appointment, error)
+	Transition(appointment *models.Appointment, from ...models.AppointmentStatus) error
+	Reschedule(appointment *models.Appointment) error
+	List(filter AppointmentFilter, limit, offset int) ([]*models.Appointment, error)
+	Count(filter AppointmentFilter) (int64, error)
+	ListBusy(doctorID uint, from, to time.Time) ([]*models.Appointment, error)
+}
+
+type appointmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAppointmentRepository(db *gorm.DB) AppointmentRepository {
+	return &appointmentRepository{db: db}
+}
+
+func (r *appointmentRepository) Create(appointment *models.Appointment) error {
+	return r.saveWithoutConflicts(appointment, func(tx *gorm.DB) error {
+		return tx.Omit(clause.Associations).Create(appointment).Error
+	})
+}
+
+func (r *appointmentRepository) GetByID(id uint) (*models.Appointment, error) {
+	var appointment models.Appointment
+	if err := r.preload(r.db).Where("id = ?", id).First(&appointment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("appointment not found")
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// Transition saves a status change, provided the appointment is still in
+// one of the from statuses. Changes to the time slot must go through
+// Reschedule so that they are checked for conflicts.
+func (r *appointmentRepository) Transition(appointment *models.Appointment, from ...models.AppointmentStatus) error {
+	return updateAppointment(r.db, appointment, from, map[string]interface{}{
+		"status":              appointment.Status,
+		"cancellation_reason": appointment.CancellationReason,
+		"checked_in_at":       appointment.CheckedInAt,
+		"completed_at":        appointment.CompletedAt,
+		"cancelled_at":        appointment.CancelledAt,
+	})
+}
+
+// Reschedule moves a scheduled appointment to its new time slot. The status
+// is checked under the same locks as the conflict check, so a booking
+// cancelled or checked in meanwhile is not put back on the schedule.
+func (r *appointmentRepository) Reschedule(appointment *models.Appointment) error {
+	return r.saveWithoutConflicts(appointment, func(tx *gorm.DB) error {
+		return updateAppointment(tx, appointment, []models.AppointmentStatus{models.AppointmentStatusScheduled}, map[string]interface{}{
+			"start_time": appointment.StartTime,
+			"end_time":   appointment.EndTime,
+		})
+	})
+}
+
+func updateAppointment(db *gorm.DB, appointment *models.Appointment, from []models.AppointmentStatus, updates map[string]interface{}) error {
+	updates["last_updated_by_id"] = appointment.LastUpdatedByID
+	updates["updated_at"] = time.Now()
+
+	result := db.Model(&models.Appointment{}).
+		Where("id = ? AND status IN ?", appointment.ID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAppointmentStatusChanged
+	}
+	return nil
+}
+
+func (r *appointmentRepository) List(filter AppointmentFilter, limit, offset int) ([]*models.Appointment, error) {
+	var appointments []*models.Appointment
+	query := r.applyFilter(r.preload(r.db), filter).Order("start_time ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+func (r *appointmentRepository) Count(filter AppointmentFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.Model(&models.Appointment{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListBusy returns the doctor's appointments that still occupy time within
+// [from, to).
+func (r *appointmentRepository) ListBusy(doctorID uint, from, to time.Time) ([]*models.Appointment, error) {
+	var appointments []*models.Appointment
+	if err := r.db.
+		Where("doctor_id = ?", doctorID).
+		Where("status IN ?", blockingAppointmentStatuses).
+		Where("start_time < ? AND end_time > ?", to, from).
+		Order("start_time ASC").
+		Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// saveWithoutConflicts locks the doctor and patient rows before checking for
+// overlapping bookings, so two concurrent requests for the same slot are
+// serialized and only the first one succeeds. save writes the appointment
+// while the locks are held.
+func (r *appointmentRepository) saveWithoutConflicts(appointment *models.Appointment, save func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		locking := clause.Locking{Strength: "UPDATE"}
+		if err := tx.Clauses(locking).Select("id").First(&models.User{}, appointment.DoctorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(locking).Select("id").First(&models.Patient{}, appointment.PatientID).Error; err != nil {
+			return err
+		}
+
+		var overlapping int64
+		if err := tx.Model(&models.Appointment{}).
+			Where("(doctor_id = ? OR patient_id = ?)", appointment.DoctorID, appointment.PatientID).
+			Where("status IN ?", blockingAppointmentStatuses).
+			Where("start_time < ? AND end_time > ?", appointment.EndTime, appointment.StartTime).
+			Where("id <> ?", appointment.ID).
+			Count(&overlapping).Error; err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return ErrAppointmentConflict
+		}
+
+		return save(tx)
+	})
+}
+
+func (r *appointmentRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Patient").Preload("Doctor").Preload("CreatedBy")
+}
+
+func (r *appointmentRepository) applyFilter(query *gorm.DB, filter AppointmentFilter) *gorm.DB {
+	if filter.DoctorID != nil {
+		query = query.Where("doctor_id = ?", *filter.DoctorID)
+	}
+	if filter.PatientID != nil {
+		query = query.Where("patient_id = ?", *filter.PatientID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("start_time >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("start_time < ?", filter.To)
+	}
+	return query
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AvailabilityRepository interface {
+	GetSchedule(doctorID uint) (*models.DoctorSchedule, error)
+	SaveSchedule(schedule *models.DoctorSchedule) error
+	ListSchedules(specialty string) ([]*models.DoctorSchedule, error)
+	CreateException(exception *models.DoctorScheduleException) error
+	DeleteException(doctorID, exceptionID uint) error
+	ListExceptions(doctorID uint, from, to time.Time) ([]models.DoctorScheduleException, error)
+}
+
+type availabilityRepository struct {
+	db *gorm.DB
+}
+
+func NewAvailabilityRepository(db *gorm.DB) AvailabilityRepository {
+	return &availabilityRepository{db: db}
+}
+
+func (r *availabilityRepository) GetSchedule(doctorID uint) (*models.DoctorSchedule, error) {
+	var schedule models.DoctorSchedule
+	if err := r.preload(r.db).Where("doctor_id = ?", doctorID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("schedule not found")
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// SaveSchedule replaces the doctor's settings, working hours and breaks in a
+// single transaction.
+func (r *availabilityRepository) SaveSchedule(schedule *models.DoctorSchedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(schedule).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("doctor_id = ?", schedule.DoctorID).Delete(&models.DoctorWorkingHours{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("doctor_id = ?", schedule.DoctorID).Delete(&models.DoctorBreak{}).Error; err != nil {
+			return err
+		}
+
+		for i := range schedule.WorkingHours {
+			schedule.WorkingHours[i].ID = 0
+			schedule.WorkingHours[i].DoctorID = schedule.DoctorID
+		}
+		for i := range schedule.Breaks {
+			schedule.Breaks[i].ID = 0
+			schedule.Breaks[i].DoctorID = schedule.DoctorID
+		}
+
+		if len(schedule.WorkingHours) > 0 {
+			if err := tx.Create(&schedule.WorkingHours).Error; err != nil {
+				return err
+			}
+		}
+		if len(schedule.Breaks) > 0 {
+			if err := tx.Create(&schedule.Breaks).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListSchedules returns the schedules of active doctors, optionally limited
+// to one specialty (case-insensitive).
+func (r *availabilityRepository) ListSchedules(specialty string) ([]*models.DoctorSchedule, error) {
+	var schedules []*models.DoctorSchedule
+	query := r.preload(r.db).
+		Joins("JOIN users ON users.id = doctor_schedules.doctor_id AND users.is_active = ? AND users.deleted_at IS NULL", true)
+
+	if specialty != "" {
+		query = query.Where("LOWER(doctor_schedules.specialty) = LOWER(?)", specialty)
+	}
+
+	if err := query.Order("doctor_schedules.doctor_id ASC").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *availabilityRepository) CreateException(exception *models.DoctorScheduleException) error {
+	return r.db.Create(exception).Error
+}
+
+func (r *availabilityRepository) DeleteException(doctorID, exceptionID uint) error {
+	result := r.db.Where("id = ? AND doctor_id = ?", exceptionID, doctorID).Delete(&models.DoctorScheduleException{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("schedule exception not found")
+	}
+	return nil
+}
+
+// ListExceptions returns the exceptions that overlap [from, to).
+func (r *availabilityRepository) ListExceptions(doctorID uint, from, to time.Time) ([]models.DoctorScheduleException, error) {
+	var exceptions []models.DoctorScheduleException
+	if err := r.db.Where("doctor_id = ? AND starts_at < ? AND ends_at > ?", doctorID, to, from).
+		Order("starts_at ASC").
+		Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+func (r *availabilityRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Doctor").
+		Preload("WorkingHours", func(db *gorm.DB) *gorm.DB { return db.Order("weekday ASC, start_time ASC") }).
+		Preload("Breaks", func(db *gorm.DB) *gorm.DB { return db.Order("weekday ASC, start_time ASC") })
+}
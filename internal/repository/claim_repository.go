@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrClaimStatusChanged is returned when a claim is no longer in the
+	// status an operation expects, for example when it was picked up by a
+	// concurrent batch export.
+	ErrClaimStatusChanged = errors.New("claim status has changed")
+	ErrClaimExists        = errors.New("a claim already exists for this invoice and policy")
+)
+
+type ClaimFilter struct {
+	Status  models.ClaimStatus
+	PayerID uint
+}
+
+type ClaimRepository interface {
+	Create(claim *models.Claim) error
+	GetByID(patientID, id uint) (*models.Claim, error)
+	ListByPatient(patientID uint, status models.ClaimStatus) ([]*models.Claim, error)
+	Search(filter ClaimFilter, limit, offset int) ([]*models.Claim, error)
+	Count(filter ClaimFilter) (int64, error)
+	ListDrafts(payerID uint) ([]*models.Claim, error)
+	Transition(claim *models.Claim, from ...models.ClaimStatus) error
+	MarkPaid(claim *models.Claim, payment *models.Payment, from ...models.ClaimStatus) error
+	CreateBatch(batch *models.ClaimBatch, claims []*models.Claim, render func(batch *models.ClaimBatch) (string, error)) error
+	GetBatch(id uint) (*models.ClaimBatch, error)
+}
+
+type claimRepository struct {
+	db *gorm.DB
+}
+
+func NewClaimRepository(db *gorm.DB) ClaimRepository {
+	return &claimRepository{db: db}
+}
+
+// Create locks the invoice so that the same invoice cannot be claimed twice
+// against one policy, or voided meanwhile, then numbers the claim from its
+// ID.
+func (r *claimRepository) Create(claim *models.Claim) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&invoice, claim.InvoiceID).Error; err != nil {
+			return err
+		}
+		if !invoice.Status.IsOpen() {
+			return ErrInvoiceStatusChanged
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Claim{}).
+			Where("invoice_id = ? AND policy_id = ?", claim.InvoiceID, claim.PolicyID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrClaimExists
+		}
+
+		if err := tx.Omit(clause.Associations).Create(claim).Error; err != nil {
+			return err
+		}
+		claim.ClaimNumber = fmt.Sprintf("CLM-%06d", claim.ID)
+		return tx.Model(&models.Claim{}).Where("id = ?", claim.ID).Update("claim_number", claim.ClaimNumber).Error
+	})
+}
+
+func (r *claimRepository) GetByID(patientID, id uint) (*models.Claim, error) {
+	var claim models.Claim
+	if err := r.preload(r.db).Where("claims.id = ? AND claims.patient_id = ?", id, patientID).First(&claim).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("claim not found")
+		}
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *claimRepository) ListByPatient(patientID uint, status models.ClaimStatus) ([]*models.Claim, error) {
+	var claims []*models.Claim
+	query := r.preload(r.db).Where("claims.patient_id = ?", patientID)
+
+	if status != "" {
+		query = query.Where("claims.status = ?", status)
+	}
+
+	if err := query.Order("claims.created_at DESC, claims.id DESC").Find(&claims).Error; err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (r *claimRepository) Search(filter ClaimFilter, limit, offset int) ([]*models.Claim, error) {
+	var claims []*models.Claim
+	query := r.applyFilter(r.preload(r.db).Preload("Patient"), filter).Order("claims.created_at DESC, claims.id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&claims).Error; err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (r *claimRepository) Count(filter ClaimFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.Model(&models.Claim{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListDrafts loads the draft claims waiting to be submitted, with everything
+// the claim file needs, oldest first. Claims on void invoices are left out.
+func (r *claimRepository) ListDrafts(payerID uint) ([]*models.Claim, error) {
+	var claims []*models.Claim
+	query := r.applyFilter(r.preload(r.db).Preload("Patient"), ClaimFilter{Status: models.ClaimStatusDraft, PayerID: payerID}).
+		Where("claims.invoice_id IN (?)", r.db.Model(&models.Invoice{}).Select("id").Where("status <> ?", models.InvoiceStatusVoid))
+
+	if err := query.Order("claims.created_at ASC, claims.id ASC").Find(&claims).Error; err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Transition saves the claim's workflow fields only if it is still in one
+// of the given statuses.
+func (r *claimRepository) Transition(claim *models.Claim, from ...models.ClaimStatus) error {
+	return transitionClaim(r.db, claim, from)
+}
+
+// MarkPaid records the payer's payment on the claim and posts it to the
+// invoice in one transaction.
+func (r *claimRepository) MarkPaid(claim *models.Claim, payment *models.Payment, from ...models.ClaimStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionClaim(tx, claim, from); err != nil {
+			return err
+		}
+		invoice := &models.Invoice{ID: claim.InvoiceID}
+		return recordInvoicePayment(tx, invoice, payment)
+	})
+}
+
+// CreateBatch stores a submission batch and marks its claims as submitted.
+// The claims are locked first and must all still be drafts. render is
+// called once the batch has its number, which the claim file uses as its
+// control number.
+func (r *claimRepository) CreateBatch(batch *models.ClaimBatch, claims []*models.Claim, render func(batch *models.ClaimBatch) (string, error)) error {
+	ids := make([]uint, len(claims))
+	for i, claim := range claims {
+		ids[i] = claim.ID
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked []uint
+		if err := tx.Model(&models.Claim{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND status = ?", ids, models.ClaimStatusDraft).
+			Pluck("id", &locked).Error; err != nil {
+			return err
+		}
+		if len(locked) != len(ids) {
+			return ErrClaimStatusChanged
+		}
+
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		batch.BatchNumber = fmt.Sprintf("BAT-%06d", batch.ID)
+
+		content, err := render(batch)
+		if err != nil {
+			return err
+		}
+		batch.Content = content
+
+		if err := tx.Model(&models.ClaimBatch{}).Where("id = ?", batch.ID).
+			Updates(map[string]interface{}{
+				"batch_number": batch.BatchNumber,
+				"content":      batch.Content,
+			}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Claim{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":          models.ClaimStatusSubmitted,
+				"batch_id":        batch.ID,
+				"submitted_at":    now,
+				"submitted_by_id": batch.CreatedByID,
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+
+		for _, claim := range claims {
+			claim.Status = models.ClaimStatusSubmitted
+			claim.BatchID = &batch.ID
+			claim.SubmittedAt = &now
+			claim.SubmittedByID = &batch.CreatedByID
+		}
+		return nil
+	})
+}
+
+func (r *claimRepository) GetBatch(id uint) (*models.ClaimBatch, error) {
+	var batch models.ClaimBatch
+	if err := r.db.Where("id = ?", id).First(&batch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("claim batch not found")
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *claimRepository) applyFilter(query *gorm.DB, filter ClaimFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("claims.status = ?", filter.Status)
+	}
+	if filter.PayerID != 0 {
+		query = query.Where("claims.policy_id IN (?)", r.db.Model(&models.InsurancePolicy{}).Select("id").Where("payer_id = ?", filter.PayerID))
+	}
+	return query
+}
+
+func (r *claimRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Policy.Payer").
+		Preload("Invoice.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		})
+}
+
+func transitionClaim(db *gorm.DB, claim *models.Claim, from []models.ClaimStatus) error {
+	diagnosisCodes, err := json.Marshal(claim.DiagnosisCodes)
+	if err != nil {
+		return err
+	}
+
+	result := db.Model(&models.Claim{}).
+		Where("id = ? AND status IN ?", claim.ID, from).
+		Updates(map[string]interface{}{
+			"status":          claim.Status,
+			"diagnosis_codes": string(diagnosisCodes),
+			"paid_cents":      claim.PaidCents,
+			"payer_claim_ref": claim.PayerClaimRef,
+			"denial_reason":   claim.DenialReason,
+			"batch_id":        claim.BatchID,
+			"submitted_at":    claim.SubmittedAt,
+			"submitted_by_id": claim.SubmittedByID,
+			"adjudicated_at":  claim.AdjudicatedAt,
+			"paid_at":         claim.PaidAt,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrClaimStatusChanged
+	}
+	return nil
+}
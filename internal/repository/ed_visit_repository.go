@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrEDVisitStatusChanged is returned when a visit has moved on since it
+	// was read, for example when two doctors pick up the same patient.
+	ErrEDVisitStatusChanged = errors.New("emergency visit status has changed")
+	ErrPatientAlreadyInED   = errors.New("patient already has an active emergency visit")
+)
+
+var activeEDVisitStatuses = []models.EDVisitStatus{models.EDVisitStatusWaiting, models.EDVisitStatusAssigned, models.EDVisitStatusInTreatment}
+
+type EDVisitRepository interface {
+	Create(visit *models.EDVisit) error
+	GetByID(id uint) (*models.EDVisit, error)
+	ListQueue(includeInTreatment bool) ([]*models.EDVisit, error)
+	ListArrivedBetween(from, to time.Time) ([]*models.EDVisit, error)
+	Transition(visit *models.EDVisit, from ...models.EDVisitStatus) error
+}
+
+type edVisitRepository struct {
+	db *gorm.DB
+}
+
+func NewEDVisitRepository(db *gorm.DB) EDVisitRepository {
+	return &edVisitRepository{db: db}
+}
+
+// Create locks the patient row before checking for an active visit, so that
+// the same walk-in cannot be checked in twice by concurrent requests.
+func (r *edVisitRepository) Create(visit *models.EDVisit) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Patient{}, visit.PatientID).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&models.EDVisit{}).
+			Where("patient_id = ? AND status IN ?", visit.PatientID, activeEDVisitStatuses).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrPatientAlreadyInED
+		}
+
+		return tx.Omit(clause.Associations).Create(visit).Error
+	})
+}
+
+func (r *edVisitRepository) GetByID(id uint) (*models.EDVisit, error) {
+	var visit models.EDVisit
+	if err := r.preload(r.db).Where("id = ?", id).First(&visit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("emergency visit not found")
+		}
+		return nil, err
+	}
+	return &visit, nil
+}
+
+// ListQueue returns the patients waiting to be seen, most acute first and
+// then longest waiting first.
+func (r *edVisitRepository) ListQueue(includeInTreatment bool) ([]*models.EDVisit, error) {
+	statuses := []models.EDVisitStatus{models.EDVisitStatusWaiting, models.EDVisitStatusAssigned}
+	if includeInTreatment {
+		statuses = activeEDVisitStatuses
+	}
+
+	var visits []*models.EDVisit
+	if err := r.preload(r.db).
+		Where("status IN ?", statuses).
+		Order("triage_level ASC, arrived_at ASC, id ASC").
+		Find(&visits).Error; err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+func (r *edVisitRepository) ListArrivedBetween(from, to time.Time) ([]*models.EDVisit, error) {
+	var visits []*models.EDVisit
+	if err := r.db.
+		Where("arrived_at >= ? AND arrived_at < ?", from, to).
+		Order("arrived_at ASC").
+		Find(&visits).Error; err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// Transition saves the visit's workflow fields only if it is still in one
+// of the given statuses.
+func (r *edVisitRepository) Transition(visit *models.EDVisit, from ...models.EDVisitStatus) error {
+	result := r.db.Model(&models.EDVisit{}).
+		Where("id = ? AND status IN ?", visit.ID, from).
+		Updates(map[string]interface{}{
+			"status":             visit.Status,
+			"triage_level":       visit.TriageLevel,
+			"assigned_doctor_id": visit.AssignedDoctorID,
+			"assigned_at":        visit.AssignedAt,
+			"seen_at":            visit.SeenAt,
+			"completed_at":       visit.CompletedAt,
+			"disposition":        visit.Disposition,
+			"notes":              visit.Notes,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEDVisitStatusChanged
+	}
+	return nil
+}
+
+func (r *edVisitRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Patient").Preload("AssignedDoctor")
+}
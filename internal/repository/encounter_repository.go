@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrEncounterLocked is returned when a signed encounter would be modified.
+var ErrEncounterLocked = errors.New("encounter is signed and can no longer be edited")
+
+type EncounterRepository interface {
+	Create(encounter *models.Encounter) error
+	GetByID(patientID, id uint) (*models.Encounter, error)
+	UpdateDraft(encounter *models.Encounter) error
+	Sign(encounter *models.Encounter, signedAt time.Time) error
+	ListByPatient(patientID uint, limit, offset int) ([]*models.Encounter, error)
+	CountByPatient(patientID uint) (int64, error)
+	CreateAddendum(addendum *models.EncounterAddendum) error
+}
+
+type encounterRepository struct {
+	db *gorm.DB
+}
+
+func NewEncounterRepository(db *gorm.DB) EncounterRepository {
+	return &encounterRepository{db: db}
+}
+
+func (r *encounterRepository) Create(encounter *models.Encounter) error {
+	return r.db.Omit("Author", "Addenda").Create(encounter).Error
+}
+
+func (r *encounterRepository) GetByID(patientID, id uint) (*models.Encounter, error) {
+	var encounter models.Encounter
+	if err := r.preload(r.db).Where("id = ? AND patient_id = ?", id, patientID).First(&encounter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("encounter not found")
+		}
+		return nil, err
+	}
+	return &encounter, nil
+}
+
+// UpdateDraft saves the SOAP note only while the encounter is still a
+// draft, so an edit racing with signing cannot change a signed note.
+func (r *encounterRepository) UpdateDraft(encounter *models.Encounter) error {
+	result := r.db.Model(&models.Encounter{}).
+		Where("id = ? AND status = ?", encounter.ID, models.EncounterStatusDraft).
+		Updates(map[string]interface{}{
+			"encounter_date": encounter.EncounterDate,
+			"subjective":     encounter.Subjective,
+			"objective":      encounter.Objective,
+			"assessment":     encounter.Assessment,
+			"plan":           encounter.Plan,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEncounterLocked
+	}
+	return nil
+}
+
+func (r *encounterRepository) Sign(encounter *models.Encounter, signedAt time.Time) error {
+	result := r.db.Model(&models.Encounter{}).
+		Where("id = ? AND status = ?", encounter.ID, models.EncounterStatusDraft).
+		Updates(map[string]interface{}{
+			"status":     models.EncounterStatusSigned,
+			"signed_at":  signedAt,
+			"updated_at": signedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEncounterLocked
+	}
+	return nil
+}
+
+// ListByPatient returns the patient's encounters as a timeline, most recent
+// first.
+func (r *encounterRepository) ListByPatient(patientID uint, limit, offset int) ([]*models.Encounter, error) {
+	var encounters []*models.Encounter
+	query := r.preload(r.db).Where("patient_id = ?", patientID).Order("encounter_date DESC, id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&encounters).Error; err != nil {
+		return nil, err
+	}
+	return encounters, nil
+}
+
+func (r *encounterRepository) CountByPatient(patientID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Encounter{}).Where("patient_id = ?", patientID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *encounterRepository) CreateAddendum(addendum *models.EncounterAddendum) error {
+	return r.db.Omit("Author").Create(addendum).Error
+}
+
+func (r *encounterRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Author").
+		Preload("Addenda", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Addenda.Author")
+}
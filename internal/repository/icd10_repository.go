@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICD10Repository interface {
+	GetByCode(code string) (*models.ICD10Code, error)
+	Search(query string, limit int) ([]*models.ICD10Code, error)
+	Upsert(codes []models.ICD10Code) error
+	Count() (int64, error)
+}
+
+type icd10Repository struct {
+	db *gorm.DB
+}
+
+func NewICD10Repository(db *gorm.DB) ICD10Repository {
+	return &icd10Repository{db: db}
+}
+
+func (r *icd10Repository) GetByCode(code string) (*models.ICD10Code, error) {
+	var icd10Code models.ICD10Code
+	if err := r.db.Where("code = ?", code).First(&icd10Code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ICD-10 code not found")
+		}
+		return nil, err
+	}
+	return &icd10Code, nil
+}
+
+// Search matches codes by prefix and descriptions by substring. Code matches
+// are listed first so that typing a code autocompletes to it.
+func (r *icd10Repository) Search(query string, limit int) ([]*models.ICD10Code, error) {
+	var codes []*models.ICD10Code
+	codePrefix := strings.ToUpper(query) + "%"
+	pattern := "%" + strings.ToLower(query) + "%"
+
+	if err := r.db.
+		Where("code LIKE ? OR LOWER(description) LIKE ?", codePrefix, pattern).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "CASE WHEN code LIKE ? THEN 0 ELSE 1 END, code ASC", Vars: []interface{}{codePrefix}}}).
+		Limit(limit).
+		Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Upsert inserts new codes and refreshes the description of existing ones,
+// so that a newer release of the code table can be imported over an older
+// one.
+func (r *icd10Repository) Upsert(codes []models.ICD10Code) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "updated_at"}),
+	}).CreateInBatches(codes, 500).Error
+}
+
+func (r *icd10Repository) Count() (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.ICD10Code{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"errors"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrActivePolicyExists is returned when a patient already has an active
+// policy at the priority being added or reactivated.
+var ErrActivePolicyExists = errors.New("patient already has an active policy at this priority")
+
+type InsuranceRepository interface {
+	CreatePayer(payer *models.InsurancePayer) error
+	GetPayer(id uint) (*models.InsurancePayer, error)
+	GetPayerByCode(code string) (*models.InsurancePayer, error)
+	ListPayers(includeInactive bool) ([]*models.InsurancePayer, error)
+	UpdatePayer(payer *models.InsurancePayer) error
+	CreatePolicy(policy *models.InsurancePolicy) error
+	GetPolicy(patientID, id uint) (*models.InsurancePolicy, error)
+	ListPolicies(patientID uint, includeInactive bool) ([]*models.InsurancePolicy, error)
+	UpdatePolicy(policy *models.InsurancePolicy) error
+}
+
+type insuranceRepository struct {
+	db *gorm.DB
+}
+
+func NewInsuranceRepository(db *gorm.DB) InsuranceRepository {
+	return &insuranceRepository{db: db}
+}
+
+func (r *insuranceRepository) CreatePayer(payer *models.InsurancePayer) error {
+	return r.db.Create(payer).Error
+}
+
+func (r *insuranceRepository) GetPayer(id uint) (*models.InsurancePayer, error) {
+	var payer models.InsurancePayer
+	if err := r.db.Where("id = ?", id).First(&payer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("insurance payer not found")
+		}
+		return nil, err
+	}
+	return &payer, nil
+}
+
+func (r *insuranceRepository) GetPayerByCode(code string) (*models.InsurancePayer, error) {
+	var payer models.InsurancePayer
+	if err := r.db.Where("LOWER(payer_code) = LOWER(?)", code).First(&payer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("insurance payer not found")
+		}
+		return nil, err
+	}
+	return &payer, nil
+}
+
+func (r *insuranceRepository) ListPayers(includeInactive bool) ([]*models.InsurancePayer, error) {
+	var payers []*models.InsurancePayer
+	query := r.db
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Order("name ASC").Find(&payers).Error; err != nil {
+		return nil, err
+	}
+	return payers, nil
+}
+
+func (r *insuranceRepository) UpdatePayer(payer *models.InsurancePayer) error {
+	return r.db.Save(payer).Error
+}
+
+// CreatePolicy locks the patient row before checking for an active policy
+// at the same priority, so that concurrent requests cannot give a patient
+// two primary policies.
+func (r *insuranceRepository) CreatePolicy(policy *models.InsurancePolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkActivePolicySlot(tx, policy); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(policy).Error
+	})
+}
+
+func (r *insuranceRepository) GetPolicy(patientID, id uint) (*models.InsurancePolicy, error) {
+	var policy models.InsurancePolicy
+	if err := r.db.Preload("Payer").Where("id = ? AND patient_id = ?", id, patientID).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("insurance policy not found")
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ListPolicies returns the patient's policies, primary first.
+func (r *insuranceRepository) ListPolicies(patientID uint, includeInactive bool) ([]*models.InsurancePolicy, error) {
+	var policies []*models.InsurancePolicy
+	query := r.db.Preload("Payer").Where("patient_id = ?", patientID)
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Order("is_active DESC, priority ASC, coverage_start DESC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// UpdatePolicy saves the policy, repeating the active-priority check when
+// the policy is active so that reactivating an old policy cannot collide
+// with its replacement.
+func (r *insuranceRepository) UpdatePolicy(policy *models.InsurancePolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if policy.IsActive {
+			if err := checkActivePolicySlot(tx, policy); err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(policy).Error
+	})
+}
+
+func checkActivePolicySlot(tx *gorm.DB, policy *models.InsurancePolicy) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Patient{}, policy.PatientID).Error; err != nil {
+		return err
+	}
+
+	var active int64
+	if err := tx.Model(&models.InsurancePolicy{}).
+		Where("patient_id = ? AND priority = ? AND is_active = ? AND id <> ?", policy.PatientID, policy.Priority, true, policy.ID).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return ErrActivePolicyExists
+	}
+	return nil
+}
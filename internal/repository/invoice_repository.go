@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvoiceStatusChanged is returned when an invoice is no longer in
+	// the status an operation expects, for example after a concurrent void.
+	ErrInvoiceStatusChanged  = errors.New("invoice status has changed")
+	ErrPaymentExceedsBalance = errors.New("payment exceeds the outstanding balance")
+	ErrRefundExceedsPaid     = errors.New("refund exceeds the amount paid")
+	ErrInvoiceHasClaims      = errors.New("invoice has claims that have been submitted or paid")
+)
+
+type InvoiceRepository interface {
+	Create(invoice *models.Invoice) error
+	GetByID(patientID, id uint) (*models.Invoice, error)
+	ListByPatient(patientID uint, status models.InvoiceStatus) ([]*models.Invoice, error)
+	ListOpen(patientID uint) ([]*models.Invoice, error)
+	UpdateDraft(invoice *models.Invoice) error
+	Transition(invoice *models.Invoice, from ...models.InvoiceStatus) error
+	Void(invoice *models.Invoice, from ...models.InvoiceStatus) error
+	RecordPayment(invoice *models.Invoice, payment *models.Payment) error
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// Create stores the invoice with its items and numbers it from its ID.
+func (r *invoiceRepository) Create(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Patient", "Payments").Create(invoice).Error; err != nil {
+			return err
+		}
+		invoice.InvoiceNumber = fmt.Sprintf("INV-%06d", invoice.ID)
+		return tx.Model(&models.Invoice{}).Where("id = ?", invoice.ID).Update("invoice_number", invoice.InvoiceNumber).Error
+	})
+}
+
+func (r *invoiceRepository) GetByID(patientID, id uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.preload(r.db).Preload("Patient").Where("id = ? AND patient_id = ?", id, patientID).First(&invoice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) ListByPatient(patientID uint, status models.InvoiceStatus) ([]*models.Invoice, error) {
+	var invoices []*models.Invoice
+	query := r.preload(r.db).Where("patient_id = ?", patientID)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// ListOpen returns the patient's issued invoices that still have a balance,
+// oldest first.
+func (r *invoiceRepository) ListOpen(patientID uint) ([]*models.Invoice, error) {
+	var invoices []*models.Invoice
+	if err := r.db.
+		Where("patient_id = ? AND status IN ?", patientID, []models.InvoiceStatus{models.InvoiceStatusIssued, models.InvoiceStatusPartiallyPaid}).
+		Order("issued_at ASC, id ASC").
+		Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// UpdateDraft replaces the items, totals and notes of an invoice that is
+// still a draft.
+func (r *invoiceRepository) UpdateDraft(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Invoice{}).
+			Where("id = ? AND status = ?", invoice.ID, models.InvoiceStatusDraft).
+			Updates(map[string]interface{}{
+				"notes":          invoice.Notes,
+				"subtotal_cents": invoice.SubtotalCents,
+				"discount_cents": invoice.DiscountCents,
+				"tax_cents":      invoice.TaxCents,
+				"total_cents":    invoice.TotalCents,
+				"updated_at":     time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvoiceStatusChanged
+		}
+
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.InvoiceItem{}).Error; err != nil {
+			return err
+		}
+		for i := range invoice.Items {
+			invoice.Items[i].ID = 0
+			invoice.Items[i].InvoiceID = invoice.ID
+		}
+		if len(invoice.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&invoice.Items).Error
+	})
+}
+
+// Transition saves the invoice's status fields only if it is still in one
+// of the given statuses.
+func (r *invoiceRepository) Transition(invoice *models.Invoice, from ...models.InvoiceStatus) error {
+	return transitionInvoice(r.db, invoice, from)
+}
+
+// Void voids the invoice and cancels its draft claims in one transaction.
+// The invoice's claims are locked first, so a batch export cannot submit
+// them meanwhile, and voiding is refused while any of them has been
+// submitted, accepted or paid. Denied claims are left as they are.
+func (r *invoiceRepository) Void(invoice *models.Invoice, from ...models.InvoiceStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var statuses []models.ClaimStatus
+		if err := tx.Model(&models.Claim{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("invoice_id = ?", invoice.ID).
+			Pluck("status", &statuses).Error; err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			switch status {
+			case models.ClaimStatusSubmitted, models.ClaimStatusAccepted, models.ClaimStatusPaid:
+				return ErrInvoiceHasClaims
+			}
+		}
+
+		if err := transitionInvoice(tx, invoice, from); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Claim{}).
+			Where("invoice_id = ? AND status = ?", invoice.ID, models.ClaimStatusDraft).
+			Updates(map[string]interface{}{
+				"status":     models.ClaimStatusCancelled,
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+func transitionInvoice(db *gorm.DB, invoice *models.Invoice, from []models.InvoiceStatus) error {
+	result := db.Model(&models.Invoice{}).
+		Where("id = ? AND status IN ?", invoice.ID, from).
+		Updates(map[string]interface{}{
+			"status":       invoice.Status,
+			"issued_at":    invoice.IssuedAt,
+			"due_date":     invoice.DueDate,
+			"voided_at":    invoice.VoidedAt,
+			"voided_by_id": invoice.VoidedByID,
+			"void_reason":  invoice.VoidReason,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvoiceStatusChanged
+	}
+	return nil
+}
+
+// RecordPayment locks the invoice and checks the payment or refund against
+// the amounts stored at that moment, so concurrent payments cannot take an
+// invoice past its total or refund more than was paid. The invoice's
+// amounts and status are updated in place.
+func (r *invoiceRepository) RecordPayment(invoice *models.Invoice, payment *models.Payment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return recordInvoicePayment(tx, invoice, payment)
+	})
+}
+
+func (r *invoiceRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("received_at ASC, id ASC")
+		})
+}
+
+// recordInvoicePayment is RecordPayment within an existing transaction, so
+// that insurance payments can be posted together with their claim.
+func recordInvoicePayment(tx *gorm.DB, invoice *models.Invoice, payment *models.Payment) error {
+	var current models.Invoice
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", invoice.ID).
+		First(&current).Error; err != nil {
+		return err
+	}
+	if !current.Status.IsOpen() {
+		return ErrInvoiceStatusChanged
+	}
+
+	switch payment.Kind {
+	case models.PaymentKindPayment:
+		if payment.AmountCents > current.BalanceCents() {
+			return ErrPaymentExceedsBalance
+		}
+		current.AmountPaidCents += payment.AmountCents
+	case models.PaymentKindRefund:
+		if payment.AmountCents > current.NetPaidCents() {
+			return ErrRefundExceedsPaid
+		}
+		current.AmountRefundedCents += payment.AmountCents
+	}
+	current.SettleStatus()
+
+	payment.InvoiceID = current.ID
+	payment.PatientID = current.PatientID
+	if err := tx.Create(payment).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.Invoice{}).Where("id = ?", current.ID).
+		Updates(map[string]interface{}{
+			"status":                current.Status,
+			"amount_paid_cents":     current.AmountPaidCents,
+			"amount_refunded_cents": current.AmountRefundedCents,
+			"updated_at":            time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+
+	invoice.Status = current.Status
+	invoice.AmountPaidCents = current.AmountPaidCents
+	invoice.AmountRefundedCents = current.AmountRefundedCents
+	return nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrLabOrderStatusChanged is returned when an order is no longer in the
+// status a transition expects, for example after a concurrent cancellation.
+var ErrLabOrderStatusChanged = errors.New("lab order status has changed")
+
+type LabOrderRepository interface {
+	Create(order *models.LabOrder) error
+	GetByID(patientID, id uint) (*models.LabOrder, error)
+	ListByPatient(patientID uint, status models.LabOrderStatus) ([]*models.LabOrder, error)
+	Transition(order *models.LabOrder, from ...models.LabOrderStatus) error
+	RecordResults(order *models.LabOrder, results []models.LabResult, from ...models.LabOrderStatus) error
+	MarkReviewed(order *models.LabOrder, reviewedAt time.Time) error
+	ListInbox(doctorID uint, includeReviewed bool, limit, offset int) ([]*models.LabOrder, error)
+	CountInbox(doctorID uint, includeReviewed bool) (int64, error)
+}
+
+type labOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewLabOrderRepository(db *gorm.DB) LabOrderRepository {
+	return &labOrderRepository{db: db}
+}
+
+func (r *labOrderRepository) Create(order *models.LabOrder) error {
+	return r.db.Omit("Patient", "OrderingDoctor", "Results").Create(order).Error
+}
+
+func (r *labOrderRepository) GetByID(patientID, id uint) (*models.LabOrder, error) {
+	var order models.LabOrder
+	if err := r.preload(r.db).Where("id = ? AND patient_id = ?", id, patientID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("lab order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *labOrderRepository) ListByPatient(patientID uint, status models.LabOrderStatus) ([]*models.LabOrder, error) {
+	var orders []*models.LabOrder
+	query := r.preload(r.db).Where("patient_id = ?", patientID)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("ordered_at DESC, id DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// Transition saves the order's workflow fields only if it is still in one
+// of the given statuses.
+func (r *labOrderRepository) Transition(order *models.LabOrder, from ...models.LabOrderStatus) error {
+	return transitionLabOrder(r.db, order, from)
+}
+
+// RecordResults stores the results and moves the order to resulted in one
+// transaction.
+func (r *labOrderRepository) RecordResults(order *models.LabOrder, results []models.LabResult, from ...models.LabOrderStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionLabOrder(tx, order, from); err != nil {
+			return err
+		}
+		for i := range results {
+			results[i].LabOrderID = order.ID
+		}
+		return tx.Create(&results).Error
+	})
+}
+
+func (r *labOrderRepository) MarkReviewed(order *models.LabOrder, reviewedAt time.Time) error {
+	result := r.db.Model(&models.LabOrder{}).
+		Where("id = ? AND status = ? AND reviewed_at IS NULL", order.ID, models.LabOrderStatusResulted).
+		Updates(map[string]interface{}{
+			"reviewed_at": reviewedAt,
+			"updated_at":  reviewedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLabOrderStatusChanged
+	}
+	return nil
+}
+
+// ListInbox returns the doctor's resulted orders, unreviewed and abnormal
+// ones first.
+func (r *labOrderRepository) ListInbox(doctorID uint, includeReviewed bool, limit, offset int) ([]*models.LabOrder, error) {
+	var orders []*models.LabOrder
+	query := r.inboxQuery(r.preload(r.db).Preload("Patient"), doctorID, includeReviewed).
+		Order("reviewed_at IS NOT NULL, has_abnormal_results DESC, resulted_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *labOrderRepository) CountInbox(doctorID uint, includeReviewed bool) (int64, error) {
+	var count int64
+	if err := r.inboxQuery(r.db.Model(&models.LabOrder{}), doctorID, includeReviewed).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *labOrderRepository) inboxQuery(query *gorm.DB, doctorID uint, includeReviewed bool) *gorm.DB {
+	query = query.Where("ordering_doctor_id = ? AND status = ?", doctorID, models.LabOrderStatusResulted)
+	if !includeReviewed {
+		query = query.Where("reviewed_at IS NULL")
+	}
+	return query
+}
+
+func (r *labOrderRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("OrderingDoctor").Preload("Results", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	})
+}
+
+func transitionLabOrder(db *gorm.DB, order *models.LabOrder, from []models.LabOrderStatus) error {
+	result := db.Model(&models.LabOrder{}).
+		Where("id = ? AND status IN ?", order.ID, from).
+		Updates(map[string]interface{}{
+			"status":               order.Status,
+			"collected_at":         order.CollectedAt,
+			"collected_by_id":      order.CollectedByID,
+			"started_at":           order.StartedAt,
+			"resulted_at":          order.ResultedAt,
+			"resulted_by_id":       order.ResultedByID,
+			"cancelled_at":         order.CancelledAt,
+			"cancelled_by_id":      order.CancelledByID,
+			"cancel_reason":        order.CancelReason,
+			"has_abnormal_results": order.HasAbnormalResults,
+			"updated_at":           time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLabOrderStatusChanged
+	}
+	return nil
+}
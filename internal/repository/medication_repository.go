@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MedicationFilter struct {
+	Search          string
+	IncludeInactive bool
+}
+
+type MedicationRepository interface {
+	Create(medication *models.Medication) error
+	GetByID(id uint) (*models.Medication, error)
+	GetByName(name string) (*models.Medication, error)
+	Update(medication *models.Medication) error
+	Search(filter MedicationFilter, limit, offset int) ([]*models.Medication, error)
+	Count(filter MedicationFilter) (int64, error)
+}
+
+type medicationRepository struct {
+	db *gorm.DB
+}
+
+func NewMedicationRepository(db *gorm.DB) MedicationRepository {
+	return &medicationRepository{db: db}
+}
+
+func (r *medicationRepository) Create(medication *models.Medication) error {
+	return r.db.Create(medication).Error
+}
+
+func (r *medicationRepository) GetByID(id uint) (*models.Medication, error) {
+	var medication models.Medication
+	if err := r.db.Where("id = ?", id).First(&medication).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("medication not found")
+		}
+		return nil, err
+	}
+	return &medication, nil
+}
+
+func (r *medicationRepository) GetByName(name string) (*models.Medication, error) {
+	var medication models.Medication
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&medication).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("medication not found")
+		}
+		return nil, err
+	}
+	return &medication, nil
+}
+
+func (r *medicationRepository) Update(medication *models.Medication) error {
+	return r.db.Save(medication).Error
+}
+
+func (r *medicationRepository) Search(filter MedicationFilter, limit, offset int) ([]*models.Medication, error) {
+	var medications []*models.Medication
+	query := r.applyFilter(r.db, filter).Order("name ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&medications).Error; err != nil {
+		return nil, err
+	}
+	return medications, nil
+}
+
+func (r *medicationRepository) Count(filter MedicationFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.Model(&models.Medication{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *medicationRepository) applyFilter(query *gorm.DB, filter MedicationFilter) *gorm.DB {
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("(LOWER(name) LIKE ? OR LOWER(generic_name) LIKE ? OR LOWER(drug_class) LIKE ?)", pattern, pattern, pattern)
+	}
+	return query
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrTOTPStepUsed is returned when a TOTP code's time step is not newer
+	// than the last one accepted for the user, i.e. the code is being
+	// replayed.
+	ErrTOTPStepUsed = errors.New("TOTP step has already been used")
+	// ErrMFAEnrollmentChanged is returned when MFA was enabled, or
+	// enrollment restarted with a new secret, while a request was working
+	// on the enrollment.
+	ErrMFAEnrollmentChanged = errors.New("multi-factor enrollment has changed")
+)
+
+type MFARepository interface {
+	ReplaceRecoveryCodes(userID uint, codes []*models.MFARecoveryCode) error
+	UseRecoveryCode(userID uint, codeHash string) error
+	DeleteRecoveryCodes(userID uint) error
+	IsRequiredForRole(role models.UserRole) (bool, error)
+	ListPolicies() ([]*models.MFAPolicy, error)
+	SavePolicy(policy *models.MFAPolicy) error
+	StartEnrollment(userID uint, secret string) error
+	EnableMFA(userID uint, secret string, step int64, enrolledAt time.Time) error
+	DisableMFA(userID uint) error
+	RecordTOTPStep(userID uint, step int64) error
+	ChallengeFailures(challengeID string) (int, error)
+	RecordChallengeFailure(challenge *models.MFAChallenge) error
+	RecordUserFailure(userID uint) (int, error)
+	ClearFailedAttempts(userID uint) error
+	LockMFA(userID uint, until time.Time) error
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uint, codes []*models.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *mfaRepository) UseRecoveryCode(userID uint, codeHash string) error {
+	result := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("recovery code not found")
+	}
+	return nil
+}
+
+func (r *mfaRepository) DeleteRecoveryCodes(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error
+}
+
+func (r *mfaRepository) IsRequiredForRole(role models.UserRole) (bool, error) {
+	var policy models.MFAPolicy
+	if err := r.db.Where("role = ?", role).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return policy.Required, nil
+}
+
+func (r *mfaRepository) ListPolicies() ([]*models.MFAPolicy, error) {
+	var policies []*models.MFAPolicy
+	if err := r.db.Order("role ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *mfaRepository) SavePolicy(policy *models.MFAPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"required", "updated_by_id", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// StartEnrollment stores a new TOTP secret for a user who has not enabled
+// MFA. Only the enrollment column is written, so failed attempts and locks
+// recorded concurrently are kept.
+func (r *mfaRepository) StartEnrollment(userID uint, secret string) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND mfa_enabled = ?", userID, false).
+		Update("mfa_secret", secret)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFAEnrollmentChanged
+	}
+	return nil
+}
+
+// EnableMFA completes enrollment with the secret the code was checked
+// against, resetting the failed attempts made while confirming.
+func (r *mfaRepository) EnableMFA(userID uint, secret string, step int64, enrolledAt time.Time) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND mfa_enabled = ? AND mfa_secret = ?", userID, false, secret).
+		Updates(map[string]interface{}{
+			"mfa_enabled":         true,
+			"mfa_last_used_step":  step,
+			"mfa_enrolled_at":     enrolledAt,
+			"mfa_failed_attempts": 0,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFAEnrollmentChanged
+	}
+	return nil
+}
+
+// DisableMFA clears the user's MFA enrollment. A lock from failed attempts
+// is left to expire.
+func (r *mfaRepository) DisableMFA(userID uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"mfa_enabled":         false,
+		"mfa_secret":          "",
+		"mfa_last_used_step":  0,
+		"mfa_enrolled_at":     nil,
+		"mfa_failed_attempts": 0,
+	}).Error
+}
+
+// RecordTOTPStep stores the step of an accepted TOTP code only if it is newer
+// than the last one, so two requests racing with the same code cannot both
+// succeed.
+func (r *mfaRepository) RecordTOTPStep(userID uint, step int64) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND mfa_last_used_step < ?", userID, step).
+		Update("mfa_last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTOTPStepUsed
+	}
+	return nil
+}
+
+func (r *mfaRepository) ChallengeFailures(challengeID string) (int, error) {
+	var failures []int
+	if err := r.db.Model(&models.MFAChallenge{}).Where("id = ?", challengeID).Pluck("failed_attempts", &failures).Error; err != nil {
+		return 0, err
+	}
+	if len(failures) == 0 {
+		return 0, nil
+	}
+	return failures[0], nil
+}
+
+// RecordChallengeFailure adds a failed attempt to the challenge and sets
+// FailedAttempts to the new total. Expired challenges are removed first.
+func (r *mfaRepository) RecordChallengeFailure(challenge *models.MFAChallenge) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.MFAChallenge{}).Error; err != nil {
+			return err
+		}
+
+		challenge.FailedAttempts = 1
+		return tx.Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"failed_attempts": gorm.Expr("mfa_challenges.failed_attempts + 1"),
+				}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "failed_attempts"}}},
+		).Create(challenge).Error
+	})
+}
+
+// RecordUserFailure adds a failed attempt to the user's count across all
+// challenges and returns the new total.
+func (r *mfaRepository) RecordUserFailure(userID uint) (int, error) {
+	user := models.User{ID: userID}
+	result := r.db.Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "mfa_failed_attempts"}}}).
+		Update("mfa_failed_attempts", gorm.Expr("mfa_failed_attempts + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return user.MFAFailedAttempts, nil
+}
+
+func (r *mfaRepository) ClearFailedAttempts(userID uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("mfa_failed_attempts", 0).Error
+}
+
+// LockMFA refuses MFA for the user until the given time and starts a fresh
+// count of failed attempts for when the lock expires.
+func (r *mfaRepository) LockMFA(userID uint, until time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"mfa_locked_until":    until,
+		"mfa_failed_attempts": 0,
+	}).Error
+}
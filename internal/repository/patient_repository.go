@@ -3,48 +3,161 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
+	"hospital-management-system/internal/matching"
 	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/patientid"
+	"hospital-management-system/internal/phi"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// PatientFilter narrows a patient list. Date ranges include From and
+// exclude To; zero times leave that end open.
+type PatientFilter struct {
+	Gender      models.Gender
+	BloodType   models.BloodType
+	CreatedByID *uint
+	IsActive    *bool
+	BornFrom    time.Time
+	BornTo      time.Time
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+}
+
+// PatientSort orders a patient list by one of PatientSortFields.
+type PatientSort struct {
+	Field      string
+	Descending bool
+}
+
+// patientSortColumns maps the fields a patient list can be sorted by to
+// their columns. Age sorts by date of birth in the opposite direction.
+var patientSortColumns = map[string]string{
+	"patient_id":    "patient_id",
+	"first_name":    "first_name",
+	"last_name":     "last_name",
+	"date_of_birth": "date_of_birth",
+	"age":           "date_of_birth",
+	"gender":        "gender",
+	"blood_type":    "blood_type",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+// PatientSortFields returns the fields a patient list can be sorted by.
+func PatientSortFields() []string {
+	fields := make([]string, 0, len(patientSortColumns))
+	for field := range patientSortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// IsPatientSortField reports whether a patient list can be sorted by field.
+func IsPatientSortField(field string) bool {
+	_, ok := patientSortColumns[field]
+	return ok
+}
+
 type PatientRepository interface {
 	Create(patient *models.Patient) error
 	GetByID(id uint) (*models.Patient, error)
 	GetByPatientID(patientID string) (*models.Patient, error)
-	Update(patient *models.Patient) error
-	Delete(id uint) error
-	List(limit, offset int) ([]*models.Patient, error)
+	Update(patient *models.Patient, updatedByID uint) error
+	Delete(id uint, deletedByID uint) error
+	List(filter PatientFilter, order []PatientSort, limit, offset int) ([]*models.Patient, error)
 	Search(query string, limit, offset int) ([]*models.Patient, error)
 	GetByCreatedBy(userID uint, limit, offset int) ([]*models.Patient, error)
-	Count() (int64, error)
-	GenerateUniquePatientID() (string, error)
+	Count(filter PatientFilter) (int64, error)
+	FindMatchCandidates(patient *models.Patient) ([]*models.Patient, error)
+	Merge(survivorID, duplicateID, mergedByID uint, merge func(survivor, duplicate *models.Patient)) (map[string]int64, error)
+}
+
+var (
+	// ErrPatientMergeChanged is returned when either patient was deactivated
+	// or merged by someone else while a merge was being prepared.
+	ErrPatientMergeChanged = errors.New("patient was changed by another user, reload and try again")
+	ErrMergeBothAdmitted   = errors.New("both patients are currently admitted, discharge one admission first")
+	ErrMergeBothInED       = errors.New("both patients have an active emergency visit, close one visit first")
+	ErrMergePolicyConflict = errors.New("both patients have an active insurance policy at the same priority, deactivate one first")
+)
+
+// patientOwnedTables hold records that belong to a patient and move to the
+// surviving record on a merge. Access logs and revisions are left with the
+// duplicate, as they are the audit trail of what happened to that record.
+var patientOwnedTables = []string{
+	"appointments",
+	"encounters",
+	"allergies",
+	"prescriptions",
+	"prescription_safety_overrides",
+	"patient_problems",
+	"vital_signs",
+	"lab_orders",
+	"admissions",
+	"ed_visits",
+	"invoices",
+	"payments",
+	"insurance_policies",
+	"claims",
 }
 
+// matchCandidateLimit caps the records loaded for duplicate scoring.
+const matchCandidateLimit = 50
+
 type patientRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	idFormat patientid.Format
 }
 
-func NewPatientRepository(db *gorm.DB) PatientRepository {
-	return &patientRepository{db: db}
+func NewPatientRepository(db *gorm.DB, idFormat patientid.Format) PatientRepository {
+	return &patientRepository{db: db, idFormat: idFormat}
 }
 
+// Create assigns the next patient ID from the counter of its scope in the
+// same transaction as the insert, so that concurrent registrations cannot
+// be given the same ID and a failed insert does not use up a number. The
+// first revision of the patient is written in that transaction too.
 func (r *patientRepository) Create(patient *models.Patient) error {
-	if patient.PatientID == "" {
-		patientID, err := r.GenerateUniquePatientID()
-		if err != nil {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if patient.PatientID == "" {
+			scope := r.idFormat.Scope(time.Now())
+			sequence, err := nextPatientIDSequence(tx, scope)
+			if err != nil {
+				return err
+			}
+			patient.PatientID = r.idFormat.Build(scope, sequence)
+		}
+		if err := tx.Create(patient).Error; err != nil {
 			return err
 		}
-		patient.PatientID = patientID
-	}
+		return recordPatientRevision(tx, patient.ID, models.RevisionActionCreate, models.PatientSnapshot{}, patient.Snapshot(), patient.CreatedByID)
+	})
+}
 
-	if err := r.db.Create(patient).Error; err != nil {
-		return err
+// nextPatientIDSequence increments the counter of a scope and returns the
+// new value. The row stays locked until the transaction ends. A scope seen
+// for the first time starts after the patients already registered in it,
+// which covers IDs issued before the counter existed.
+func nextPatientIDSequence(tx *gorm.DB, scope string) (int64, error) {
+	var sequence int64
+	err := tx.Raw(`INSERT INTO patient_id_counters (scope, value, updated_at)
+		VALUES (?, (SELECT COUNT(*) FROM patients WHERE patient_id LIKE ?) + 1, ?)
+		ON CONFLICT (scope) DO UPDATE SET value = patient_id_counters.value + 1, updated_at = excluded.updated_at
+		RETURNING value`, scope, scope+"%", time.Now()).Scan(&sequence).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate patient ID: %w", err)
 	}
-	return nil
+	return sequence, nil
 }
 
 func (r *patientRepository) GetByID(id uint) (*models.Patient, error) {
@@ -59,30 +172,101 @@ func (r *patientRepository) GetByID(id uint) (*models.Patient, error) {
 	return &patient, nil
 }
 
+// GetByPatientID rejects an ID whose check digit does not match with
+// patientid.ErrInvalidCheckDigit before looking it up, so that a mistyped
+// ID is reported as such rather than as an unknown patient.
 func (r *patientRepository) GetByPatientID(patientID string) (*models.Patient, error) {
+	if err := r.idFormat.Check(patientID); err != nil {
+		return nil, err
+	}
+
 	var patient models.Patient
 	if err := r.db.Preload("CreatedBy").Preload("LastUpdatedBy").
 		Where("patient_id = ? AND is_active = ?", patientID, true).First(&patient).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("patient not found")
+			return r.getByAlias(patientID)
 		}
 		return nil, err
 	}
 	return &patient, nil
 }
 
-func (r *patientRepository) Update(patient *models.Patient) error {
-	return r.db.Save(patient).Error
+// getByAlias finds the patient that a merged patient ID now points to.
+func (r *patientRepository) getByAlias(patientID string) (*models.Patient, error) {
+	var alias models.PatientAlias
+	if err := r.db.Where("alias = ?", patientID).First(&alias).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("patient not found")
+		}
+		return nil, err
+	}
+	return r.GetByID(alias.PatientID)
+}
+
+// Update saves the patient and records a revision of what changed since the
+// stored version, which is locked until both are written.
+func (r *patientRepository) Update(patient *models.Patient, updatedByID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockPatient(tx, patient.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(patient).Error; err != nil {
+			return err
+		}
+		return recordPatientRevision(tx, patient.ID, models.RevisionActionUpdate, current.Snapshot(), patient.Snapshot(), updatedByID)
+	})
+}
+
+// Delete deactivates the patient and records it in the patient's history.
+func (r *patientRepository) Delete(id uint, deletedByID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockPatient(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Patient{}).Where("id = ?", id).Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		before := current.Snapshot()
+		after := before
+		after.IsActive = false
+		return recordPatientRevision(tx, id, models.RevisionActionDelete, before, after, deletedByID)
+	})
 }
 
-func (r *patientRepository) Delete(id uint) error {
-	return r.db.Model(&models.Patient{}).Where("id = ?", id).Update("is_active", false).Error
+// lockPatient loads the stored version of a patient and locks its row until
+// the transaction ends.
+func lockPatient(tx *gorm.DB, id uint) (*models.Patient, error) {
+	var patient models.Patient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&patient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("patient not found")
+		}
+		return nil, err
+	}
+	return &patient, nil
 }
 
-func (r *patientRepository) List(limit, offset int) ([]*models.Patient, error) {
+// recordPatientRevision writes the revision for a change to a patient,
+// skipping updates that changed none of the tracked fields.
+func recordPatientRevision(tx *gorm.DB, patientID uint, action models.RevisionAction, before, after models.PatientSnapshot, changedByID uint) error {
+	revision, err := models.NewPatientRevision(patientID, action, before, after, changedByID)
+	if err != nil {
+		return err
+	}
+	if revision == nil {
+		return nil
+	}
+	return createPatientRevision(tx, revision)
+}
+
+// List loads the patients matching the filter in the given order, newest
+// first when none is given. Ties are broken by ID so that pages are stable.
+func (r *patientRepository) List(filter PatientFilter, order []PatientSort, limit, offset int) ([]*models.Patient, error) {
 	var patients []*models.Patient
-	query := r.db.Preload("CreatedBy").Preload("LastUpdatedBy").
-		Where("is_active = ?", true).Order("created_at DESC")
+	query := applyPatientSort(r.applyFilter(r.db.Preload("CreatedBy").Preload("LastUpdatedBy"), filter), order)
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -98,15 +282,138 @@ func (r *patientRepository) List(limit, offset int) ([]*models.Patient, error) {
 	return patients, nil
 }
 
+func (r *patientRepository) applyFilter(query *gorm.DB, filter PatientFilter) *gorm.DB {
+	if filter.Gender != "" {
+		query = query.Where("gender = ?", filter.Gender)
+	}
+	if filter.BloodType != "" {
+		query = query.Where("blood_type = ?", filter.BloodType)
+	}
+	if filter.CreatedByID != nil {
+		query = query.Where("created_by_id = ?", *filter.CreatedByID)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if !filter.BornFrom.IsZero() {
+		query = query.Where("date_of_birth >= ?", filter.BornFrom)
+	}
+	if !filter.BornTo.IsZero() {
+		query = query.Where("date_of_birth < ?", filter.BornTo)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		query = query.Where("updated_at >= ?", filter.UpdatedFrom)
+	}
+	if !filter.UpdatedTo.IsZero() {
+		query = query.Where("updated_at < ?", filter.UpdatedTo)
+	}
+	return query
+}
+
+func applyPatientSort(query *gorm.DB, order []PatientSort) *gorm.DB {
+	if len(order) == 0 {
+		order = []PatientSort{{Field: "created_at", Descending: true}}
+	}
+
+	tiebreakDesc := false
+	for _, field := range order {
+		column, ok := patientSortColumns[field.Field]
+		if !ok {
+			continue
+		}
+		desc := field.Descending
+		if field.Field == "age" {
+			desc = !desc
+		}
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+		tiebreakDesc = desc
+	}
+	return query.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: tiebreakDesc})
+}
+
+// maxSearchTokens bounds the number of words of a search query that are
+// matched, each of which adds terms to the text search query.
+const maxSearchTokens = 6
+
+// Search finds patients by name and patient ID through the search_vector
+// column, where every word of the query has to match a name or ID by prefix
+// or by its Double Metaphone code, so that "Jon Smyth" finds "John Smith".
+// Names are also matched by trigram similarity to the whole query to catch
+// typos. Phone numbers and emails only match in full, through their blind
+// indexes or, on rows still in plaintext, by value. Results are ordered by
+// relevance, which is set in SearchRank.
 func (r *patientRepository) Search(query string, limit, offset int) ([]*models.Patient, error) {
 	var patients []*models.Patient
-	searchTerm := "%" + strings.ToLower(query) + "%"
+
+	var conditions, ranks []string
+	var conditionArgs, rankArgs []interface{}
+
+	if tokens := searchTokens(query); len(tokens) > 0 {
+		terms := make([]string, len(tokens))
+		var termArgs []interface{}
+		for i, token := range tokens {
+			if isPhoneticToken(token) {
+				terms[i] = "to_tsquery('simple', concat_ws(' | ', ?, NULLIF(dmetaphone(?), '')))"
+				termArgs = append(termArgs, token+":*", token)
+			} else {
+				terms[i] = "to_tsquery('simple', ?)"
+				termArgs = append(termArgs, token+":*")
+			}
+		}
+		tsQuery := "(" + strings.Join(terms, " && ") + ")"
+		name := strings.Join(tokens, " ")
+
+		conditions = append(conditions,
+			"search_vector @@ "+tsQuery,
+			"LOWER(first_name || ' ' || last_name) % ?")
+		conditionArgs = append(append(conditionArgs, termArgs...), name)
+		ranks = append(ranks,
+			"ts_rank(search_vector, "+tsQuery+")",
+			"similarity(LOWER(first_name || ' ' || last_name), ?)")
+		rankArgs = append(append(rankArgs, termArgs...), name)
+	}
+
+	keyring := phi.CurrentKeyring()
+	var exact []string
+	var exactArgs []interface{}
+	if index := keyring.PhoneIndex(query); index != "" {
+		exact = append(exact, "phone_index = ?")
+		exactArgs = append(exactArgs, index)
+	}
+	if index := keyring.EmailIndex(query); index != "" {
+		exact = append(exact, "email_index = ?")
+		exactArgs = append(exactArgs, index)
+	}
+	// Rows written before encryption was enabled have no blind indexes.
+	if email := matching.NormalizeEmail(query); strings.Contains(email, "@") {
+		exact = append(exact, "(key_version = 0 AND LOWER(email) = ?)")
+		exactArgs = append(exactArgs, email)
+	} else if phone := matching.NormalizePhone(query); phone != "" {
+		exact = append(exact, "(key_version = 0 AND regexp_replace(phone, '[^0-9]', '', 'g') = ?)")
+		exactArgs = append(exactArgs, phone)
+	}
+	if len(exact) > 0 {
+		conditions = append(conditions, exact...)
+		conditionArgs = append(conditionArgs, exactArgs...)
+		ranks = append(ranks, "CASE WHEN "+strings.Join(exact, " OR ")+" THEN 1 ELSE 0 END")
+		rankArgs = append(rankArgs, exactArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return patients, nil
+	}
 
 	dbQuery := r.db.Preload("CreatedBy").Preload("LastUpdatedBy").
+		Select("patients.*, ("+strings.Join(ranks, " + ")+") AS search_rank", rankArgs...).
 		Where("is_active = ?", true).
-		Where("(LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ? OR LOWER(email) LIKE ? OR phone LIKE ? OR patient_id LIKE ?)",
-			searchTerm, searchTerm, searchTerm, searchTerm, searchTerm).
-		Order("created_at DESC")
+		Where("("+strings.Join(conditions, " OR ")+")", conditionArgs...).
+		Order("search_rank DESC, created_at DESC")
 
 	if limit > 0 {
 		dbQuery = dbQuery.Limit(limit)
@@ -122,6 +429,32 @@ func (r *patientRepository) Search(query string, limit, offset int) ([]*models.P
 	return patients, nil
 }
 
+// searchTokens splits a search query into lowercased words of letters and
+// digits, which are safe to use as text search terms.
+func searchTokens(query string) []string {
+	tokens := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(tokens) > maxSearchTokens {
+		tokens = tokens[:maxSearchTokens]
+	}
+	return tokens
+}
+
+// isPhoneticToken reports whether a search word is also matched by sound.
+// Double Metaphone ignores digits, so words with digits such as patient IDs
+// are not, and neither are very short words, whose codes match too much.
+func isPhoneticToken(token string) bool {
+	letters := 0
+	for _, r := range token {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+		letters++
+	}
+	return letters >= 3
+}
+
 func (r *patientRepository) GetByCreatedBy(userID uint, limit, offset int) ([]*models.Patient, error) {
 	var patients []*models.Patient
 	query := r.db.Preload("CreatedBy").Preload("LastUpdatedBy").
@@ -141,34 +474,171 @@ func (r *patientRepository) GetByCreatedBy(userID uint, limit, offset int) ([]*m
 	return patients, nil
 }
 
-func (r *patientRepository) Count() (int64, error) {
+func (r *patientRepository) Count(filter PatientFilter) (int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Patient{}).Where("is_active = ?", true).Count(&count).Error; err != nil {
+	if err := r.applyFilter(r.db.Model(&models.Patient{}), filter).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *patientRepository) GenerateUniquePatientID() (string, error) {
-	for attempts := 0; attempts < 10; attempts++ {
-		now := time.Now()
-		dateStr := now.Format("20060102")
+// FindMatchCandidates loads the active patients that could be duplicates of
+// the given one: those sharing its date of birth, or the date with day and
+// month swapped, its phone number or its email. A name alone never scores
+// high enough to be a duplicate, so names are not searched. Phone and email
+// are looked up through their blind indexes, and by value on rows that are
+// not yet encrypted.
+func (r *patientRepository) FindMatchCandidates(patient *models.Patient) ([]*models.Patient, error) {
+	dates := []time.Time{patient.DateOfBirth}
+	if swapped, ok := matching.SwappedDate(patient.DateOfBirth); ok {
+		dates = append(dates, swapped)
+	}
+
+	conditions := []string{"date_of_birth IN ?"}
+	args := []interface{}{dates}
+
+	if phone := strings.TrimSpace(patient.Phone); phone != "" {
+		conditions = append(conditions, "(key_version = 0 AND phone = ?)")
+		args = append(args, phone)
+	}
+	if email := matching.NormalizeEmail(patient.Email); email != "" {
+		conditions = append(conditions, "(key_version = 0 AND LOWER(email) = ?)")
+		args = append(args, email)
+	}
+
+	keyring := phi.CurrentKeyring()
+	if index := keyring.PhoneIndex(patient.Phone); index != "" {
+		conditions = append(conditions, "phone_index = ?")
+		args = append(args, index)
+	}
+	if index := keyring.EmailIndex(patient.Email); index != "" {
+		conditions = append(conditions, "email_index = ?")
+		args = append(args, index)
+	}
+
+	var candidates []*models.Patient
+	if err := r.db.
+		Where("is_active = ? AND id <> ?", true, patient.ID).
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Order("created_at DESC").Limit(matchCandidateLimit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// Merge folds the duplicate into the survivor in one transaction: both
+// rows are locked and re-read, merge applies the change to the locked
+// copies, the duplicate's records are moved over, both are saved, the
+// duplicate's patient ID becomes an alias of the survivor and a merge
+// revision is recorded for both. merge decides which demographics the
+// survivor keeps and marks the duplicate as merged. It returns how many
+// rows moved per table.
+func (r *patientRepository) Merge(survivorID, duplicateID, mergedByID uint, merge func(survivor, duplicate *models.Patient)) (map[string]int64, error) {
+	moved := make(map[string]int64, len(patientOwnedTables))
+	ids := []uint{survivorID, duplicateID}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked []*models.Patient
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND is_active = ?", ids, true).
+			Order("id ASC").
+			Find(&locked).Error; err != nil {
+			return err
+		}
+		if len(locked) != len(ids) {
+			return ErrPatientMergeChanged
+		}
 
-		var count int64
-		if err := r.db.Model(&models.Patient{}).Where("patient_id LIKE ?", "PAT"+dateStr+"%").Count(&count).Error; err != nil {
-			return "", err
+		survivor, duplicate := locked[0], locked[1]
+		if survivor.ID != survivorID {
+			survivor, duplicate = duplicate, survivor
+		}
+		before := map[uint]models.PatientSnapshot{
+			survivor.ID:  survivor.Snapshot(),
+			duplicate.ID: duplicate.Snapshot(),
 		}
 
-		patientID := fmt.Sprintf("PAT%s%04d", dateStr, count+1)
+		merge(survivor, duplicate)
+
+		if err := checkMergeConflicts(tx, ids); err != nil {
+			return err
+		}
 
-		var existingPatient models.Patient
-		if err := r.db.Where("patient_id = ?", patientID).First(&existingPatient).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return patientID, nil
+		for _, table := range patientOwnedTables {
+			result := tx.Table(table).Where("patient_id = ?", duplicate.ID).UpdateColumn("patient_id", survivor.ID)
+			if result.Error != nil {
+				return result.Error
 			}
-			return "", err
+			moved[table] = result.RowsAffected
 		}
+
+		// The duplicate goes first so that an email it hands over is free
+		// when the survivor is saved.
+		if err := tx.Omit(clause.Associations).Save(duplicate).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(survivor).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.PatientAlias{}).
+			Where("patient_id = ?", duplicate.ID).
+			Update("patient_id", survivor.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.PatientAlias{
+			Alias:           duplicate.PatientID,
+			PatientID:       survivor.ID,
+			MergedPatientID: duplicate.ID,
+			CreatedByID:     mergedByID,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := recordPatientRevision(tx, survivor.ID, models.RevisionActionMerge, before[survivor.ID], survivor.Snapshot(), mergedByID); err != nil {
+			return err
+		}
+		return recordPatientRevision(tx, duplicate.ID, models.RevisionActionMerge, before[duplicate.ID], duplicate.Snapshot(), mergedByID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// checkMergeConflicts refuses merges that would leave the survivor with two
+// records of a kind a patient can only have one of at a time.
+func checkMergeConflicts(tx *gorm.DB, ids []uint) error {
+	var admitted int64
+	if err := tx.Model(&models.Admission{}).
+		Where("patient_id IN ? AND status = ?", ids, models.AdmissionStatusAdmitted).
+		Distinct("patient_id").Count(&admitted).Error; err != nil {
+		return err
+	}
+	if admitted > 1 {
+		return ErrMergeBothAdmitted
+	}
+
+	var inED int64
+	if err := tx.Model(&models.EDVisit{}).
+		Where("patient_id IN ? AND status IN ?", ids, activeEDVisitStatuses).
+		Distinct("patient_id").Count(&inED).Error; err != nil {
+		return err
+	}
+	if inED > 1 {
+		return ErrMergeBothInED
 	}
 
-	return "", errors.New("failed to generate unique patient ID after multiple attempts")
+	var priorities []string
+	if err := tx.Model(&models.InsurancePolicy{}).
+		Where("patient_id IN ? AND is_active = ?", ids, true).
+		Group("priority").Having("COUNT(DISTINCT patient_id) > 1").
+		Pluck("priority", &priorities).Error; err != nil {
+		return err
+	}
+	if len(priorities) > 0 {
+		return ErrMergePolicyConflict
+	}
+	return nil
 }
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PatientRevisionRepository is append-only: revisions are never updated or
+// deleted once written.
+type PatientRevisionRepository interface {
+	Create(revision *models.PatientRevision) error
+	ListByPatient(patientID uint) ([]*models.PatientRevision, error)
+	GetByVersion(patientID uint, version int) (*models.PatientRevision, error)
+}
+
+type patientRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewPatientRevisionRepository(db *gorm.DB) PatientRevisionRepository {
+	return &patientRevisionRepository{db: db}
+}
+
+func (r *patientRevisionRepository) Create(revision *models.PatientRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createPatientRevision(tx, revision)
+	})
+}
+
+// createPatientRevision numbers a revision after the patient's latest one
+// and writes it. The patient row is locked first, so that revisions of
+// concurrent changes to the same patient are numbered one after the other
+// instead of colliding on the version index. It must run in the
+// transaction that changes the patient, so that a change is never saved
+// without its revision.
+func createPatientRevision(tx *gorm.DB, revision *models.PatientRevision) error {
+	var locked []uint
+	if err := tx.Model(&models.Patient{}).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", revision.PatientID).
+		Pluck("id", &locked).Error; err != nil {
+		return err
+	}
+	if len(locked) == 0 {
+		return errors.New("patient not found")
+	}
+
+	var latest int
+	if err := tx.Model(&models.PatientRevision{}).
+		Where("patient_id = ?", revision.PatientID).
+		Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	revision.Version = latest + 1
+	return tx.Create(revision).Error
+}
+
+func (r *patientRevisionRepository) ListByPatient(patientID uint) ([]*models.PatientRevision, error) {
+	var revisions []*models.PatientRevision
+	if err := r.db.Preload("ChangedBy").
+		Where("patient_id = ?", patientID).Order("version ASC").
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *patientRevisionRepository) GetByVersion(patientID uint, version int) (*models.PatientRevision, error) {
+	var revision models.PatientRevision
+	if err := r.db.Preload("ChangedBy").
+		Where("patient_id = ? AND version = ?", patientID, version).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("patient revision not found")
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PrescriptionRepository interface {
+	Create(prescription *models.Prescription) error
+	GetByID(patientID, id uint) (*models.Prescription, error)
+	Update(prescription *models.Prescription) error
+	ListByPatient(patientID uint, status models.PrescriptionStatus) ([]*models.Prescription, error)
+	ListCurrent(patientID uint, at time.Time) ([]*models.Prescription, error)
+	ListOverlapping(patientID uint, from time.Time, to *time.Time) ([]*models.Prescription, error)
+}
+
+type prescriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewPrescriptionRepository(db *gorm.DB) PrescriptionRepository {
+	return &prescriptionRepository{db: db}
+}
+
+// Create stores the prescription together with its safety overrides in one
+// transaction, so an override is never recorded without its prescription.
+// Overrides are also written to the patient's revision history, which is
+// the audit trail of the record.
+func (r *prescriptionRepository) Create(prescription *models.Prescription) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Medication", "Prescriber").Create(prescription).Error; err != nil {
+			return err
+		}
+		if len(prescription.SafetyOverrides) == 0 {
+			return nil
+		}
+
+		var patient models.Patient
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&patient, prescription.PatientID).Error; err != nil {
+			return err
+		}
+
+		revision, err := models.NewSafetyOverrideRevision(patient.Snapshot(), prescription, prescription.SafetyOverrides[0].OverriddenByID)
+		if err != nil {
+			return err
+		}
+		return createPatientRevision(tx, revision)
+	})
+}
+
+func (r *prescriptionRepository) GetByID(patientID, id uint) (*models.Prescription, error) {
+	var prescription models.Prescription
+	if err := r.preload(r.db).Where("id = ? AND patient_id = ?", id, patientID).First(&prescription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("prescription not found")
+		}
+		return nil, err
+	}
+	return &prescription, nil
+}
+
+func (r *prescriptionRepository) Update(prescription *models.Prescription) error {
+	return r.db.Omit(clause.Associations).Save(prescription).Error
+}
+
+func (r *prescriptionRepository) ListByPatient(patientID uint, status models.PrescriptionStatus) ([]*models.Prescription, error) {
+	var prescriptions []*models.Prescription
+	query := r.preload(r.db).Where("patient_id = ?", patientID)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("start_date DESC, id DESC").Find(&prescriptions).Error; err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+// ListCurrent returns the prescriptions that are in effect at the given time:
+// active, already started and not yet past their end date.
+func (r *prescriptionRepository) ListCurrent(patientID uint, at time.Time) ([]*models.Prescription, error) {
+	var prescriptions []*models.Prescription
+	if err := r.preload(r.db).
+		Where("patient_id = ? AND status = ?", patientID, models.PrescriptionStatusActive).
+		Where("start_date <= ?", at).
+		Where("(end_date IS NULL OR end_date > ?)", at).
+		Order("medication_name ASC").
+		Find(&prescriptions).Error; err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+// ListOverlapping returns the active prescriptions whose course overlaps the
+// range from..to, a nil to meaning open-ended. It includes prescriptions
+// that start later but are still running during the range.
+func (r *prescriptionRepository) ListOverlapping(patientID uint, from time.Time, to *time.Time) ([]*models.Prescription, error) {
+	var prescriptions []*models.Prescription
+	query := r.preload(r.db).
+		Where("patient_id = ? AND status = ?", patientID, models.PrescriptionStatusActive).
+		Where("(end_date IS NULL OR end_date > ?)", from)
+
+	if to != nil {
+		query = query.Where("start_date < ?", *to)
+	}
+
+	if err := query.Order("medication_name ASC").Find(&prescriptions).Error; err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+func (r *prescriptionRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Medication").Preload("Prescriber").Preload("SafetyOverrides")
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type PriceListFilter struct {
+	Search          string
+	Category        string
+	IncludeInactive bool
+}
+
+type PriceListRepository interface {
+	Create(item *models.PriceListItem) error
+	GetByID(id uint) (*models.PriceListItem, error)
+	GetByCode(code string) (*models.PriceListItem, error)
+	GetByIDs(ids []uint) ([]*models.PriceListItem, error)
+	Update(item *models.PriceListItem) error
+	Search(filter PriceListFilter, limit, offset int) ([]*models.PriceListItem, error)
+	Count(filter PriceListFilter) (int64, error)
+}
+
+type priceListRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) PriceListRepository {
+	return &priceListRepository{db: db}
+}
+
+func (r *priceListRepository) Create(item *models.PriceListItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *priceListRepository) GetByID(id uint) (*models.PriceListItem, error) {
+	var item models.PriceListItem
+	if err := r.db.Where("id = ?", id).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("price list item not found")
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *priceListRepository) GetByCode(code string) (*models.PriceListItem, error) {
+	var item models.PriceListItem
+	if err := r.db.Where("code = ?", code).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("price list item not found")
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *priceListRepository) GetByIDs(ids []uint) ([]*models.PriceListItem, error) {
+	var items []*models.PriceListItem
+	if len(ids) == 0 {
+		return items, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *priceListRepository) Update(item *models.PriceListItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *priceListRepository) Search(filter PriceListFilter, limit, offset int) ([]*models.PriceListItem, error) {
+	var items []*models.PriceListItem
+	query := r.applyFilter(r.db, filter).Order("category ASC, name ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *priceListRepository) Count(filter PriceListFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.Model(&models.PriceListItem{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *priceListRepository) applyFilter(query *gorm.DB, filter PriceListFilter) *gorm.DB {
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", strings.ToLower(filter.Category))
+	}
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("(LOWER(code) LIKE ? OR LOWER(name) LIKE ?)", pattern, pattern)
+	}
+	return query
+}
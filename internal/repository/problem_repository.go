@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DiagnosisFilter selects patients by a problem list entry. Code also
+// matches its subcodes, so "E11" finds "E11.9". An empty status matches both
+// active and resolved problems.
+type DiagnosisFilter struct {
+	Code   string
+	Status models.ProblemStatus
+}
+
+type ProblemRepository interface {
+	Create(problem *models.Problem) error
+	GetByID(patientID, id uint) (*models.Problem, error)
+	Update(problem *models.Problem) error
+	ListByPatient(patientID uint, status models.ProblemStatus) ([]*models.Problem, error)
+	FindActiveByCode(patientID uint, code string) (*models.Problem, error)
+	ListPatientsWithDiagnosis(filter DiagnosisFilter, limit, offset int) ([]*models.Patient, error)
+	CountPatientsWithDiagnosis(filter DiagnosisFilter) (int64, error)
+}
+
+type problemRepository struct {
+	db *gorm.DB
+}
+
+func NewProblemRepository(db *gorm.DB) ProblemRepository {
+	return &problemRepository{db: db}
+}
+
+func (r *problemRepository) Create(problem *models.Problem) error {
+	return r.db.Omit(clause.Associations).Create(problem).Error
+}
+
+func (r *problemRepository) GetByID(patientID, id uint) (*models.Problem, error) {
+	var problem models.Problem
+	if err := r.db.Preload("RecordedBy").Where("id = ? AND patient_id = ?", id, patientID).First(&problem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("problem not found")
+		}
+		return nil, err
+	}
+	return &problem, nil
+}
+
+func (r *problemRepository) Update(problem *models.Problem) error {
+	return r.db.Omit(clause.Associations).Save(problem).Error
+}
+
+// ListByPatient lists active problems before resolved ones, most recent
+// onset first.
+func (r *problemRepository) ListByPatient(patientID uint, status models.ProblemStatus) ([]*models.Problem, error) {
+	var problems []*models.Problem
+	query := r.db.Preload("RecordedBy").Where("patient_id = ?", patientID)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("status ASC, onset_date DESC NULLS LAST, id DESC").Find(&problems).Error; err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+func (r *problemRepository) FindActiveByCode(patientID uint, code string) (*models.Problem, error) {
+	var problem models.Problem
+	if err := r.db.Where("patient_id = ? AND icd10_code = ? AND status = ?", patientID, code, models.ProblemStatusActive).
+		First(&problem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("problem not found")
+		}
+		return nil, err
+	}
+	return &problem, nil
+}
+
+func (r *problemRepository) ListPatientsWithDiagnosis(filter DiagnosisFilter, limit, offset int) ([]*models.Patient, error) {
+	var patients []*models.Patient
+	query := r.db.Preload("CreatedBy").Preload("LastUpdatedBy").
+		Where("id IN (?)", r.diagnosisSubquery(filter)).
+		Order("last_name ASC, first_name ASC, id ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&patients).Error; err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
+
+func (r *problemRepository) CountPatientsWithDiagnosis(filter DiagnosisFilter) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Patient{}).Where("id IN (?)", r.diagnosisSubquery(filter)).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *problemRepository) diagnosisSubquery(filter DiagnosisFilter) *gorm.DB {
+	// Three-character categories have their subcodes after a dot; longer
+	// codes are extended in place, for example "E11.6" to "E11.65".
+	subcodes := filter.Code + "%"
+	if !strings.Contains(filter.Code, ".") {
+		subcodes = filter.Code + ".%"
+	}
+
+	query := r.db.Model(&models.Problem{}).Select("patient_id").
+		Where("(icd10_code = ? OR icd10_code LIKE ?)", filter.Code, subcodes)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrSessionRevoked is returned when a refresh token is rotated on a
+	// session that was revoked after the token was read.
+	ErrSessionRevoked = errors.New("session has been revoked")
+	// ErrSessionExpired is returned when a session has outlived its maximum
+	// lifetime and can no longer be refreshed.
+	ErrSessionExpired = errors.New("session has expired")
+)
+
+type SessionRepository interface {
+	Create(session *models.UserSession, refreshToken *models.RefreshToken) error
+	GetByID(id uint) (*models.UserSession, error)
+	GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error)
+	RotateRefreshToken(current *models.RefreshToken, next *models.RefreshToken, maxLifetime time.Duration) error
+	Revoke(id uint, reason string) error
+	RevokeAllForUser(userID uint, reason string) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.UserSession, refreshToken *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+
+		refreshToken.SessionID = session.ID
+		return tx.Create(refreshToken).Error
+	})
+}
+
+func (r *sessionRepository) GetByID(id uint) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	if err := r.db.Preload("Session").
+		Where("token_hash = ?", tokenHash).First(&refreshToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+// RotateRefreshToken marks current as used and stores next in the same
+// session. The conditional update guarantees that only one of several
+// concurrent requests presenting the same token can succeed. The session row
+// is locked first, so a concurrent revoke either fails the rotation with
+// ErrSessionRevoked or revokes the rotated session. The new expiry is capped
+// at maxLifetime after the session was created, after which rotation fails
+// with ErrSessionExpired and the user has to log in again.
+func (r *sessionRepository) RotateRefreshToken(current *models.RefreshToken, next *models.RefreshToken, maxLifetime time.Duration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var session models.UserSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", current.SessionID).First(&session).Error; err != nil {
+			return err
+		}
+		if session.IsRevoked() {
+			return ErrSessionRevoked
+		}
+
+		now := time.Now()
+		deadline := session.CreatedAt.Add(maxLifetime)
+		if !now.Before(deadline) {
+			return ErrSessionExpired
+		}
+		if next.ExpiresAt.After(deadline) {
+			next.ExpiresAt = deadline
+		}
+
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", current.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("refresh token already used")
+		}
+
+		next.SessionID = current.SessionID
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.UserSession{}).Where("id = ?", current.SessionID).
+			Updates(map[string]interface{}{"last_used_at": now, "expires_at": next.ExpiresAt}).Error
+	})
+}
+
+func (r *sessionRepository) Revoke(id uint, reason string) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+func (r *sessionRepository) RevokeAllForUser(userID uint, reason string) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
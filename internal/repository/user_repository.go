@@ -2,21 +2,41 @@ package repository
 
 import (
 	"errors"
+	"strings"
 
 	"hospital-management-system/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrLastActiveAdmin is returned when a change would leave no active
+// administrator to manage users.
+var ErrLastActiveAdmin = errors.New("cannot remove the last active administrator")
+
 type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id uint) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
+	GetByUsernameIncludingInactive(username string) (*models.User, error)
+	GetByEmailIncludingInactive(email string) (*models.User, error)
 	Update(user *models.User) error
+	UpdateRole(user *models.User) error
 	Delete(id uint) error
 	List(limit, offset int) ([]*models.User, error)
 	GetByRole(role models.UserRole) ([]*models.User, error)
+	GetByIDIncludingInactive(id uint) (*models.User, error)
+	Search(filter UserFilter, limit, offset int) ([]*models.User, error)
+	Count(filter UserFilter) (int64, error)
+}
+
+// UserFilter narrows the staff list. A nil IsActive matches both active and
+// deactivated accounts.
+type UserFilter struct {
+	Role     models.UserRole
+	IsActive *bool
+	Search   string
 }
 
 type userRepository struct {
@@ -67,12 +87,76 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByUsernameIncludingInactive also finds deactivated accounts, whose
+// usernames stay taken.
+func (r *userRepository) GetByUsernameIncludingInactive(username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByEmailIncludingInactive also finds deactivated accounts, whose email
+// addresses stay taken.
+func (r *userRepository) GetByEmailIncludingInactive(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateRole saves a user whose role changed. It fails with
+// ErrLastActiveAdmin if that demotes the last active administrator.
+func (r *userRepository) UpdateRole(user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if user.Role != models.RoleAdmin {
+			if err := ensureAnotherAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(user).Error
+	})
+}
+
+// Delete deactivates the user. It fails with ErrLastActiveAdmin if the user
+// is the last active administrator.
 func (r *userRepository) Delete(id uint) error {
-	return r.db.Model(&models.User{}).Where("id = ?", id).Update("is_active", false).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureAnotherAdmin(tx, id); err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", id).Update("is_active", false).Error
+	})
+}
+
+// ensureAnotherAdmin locks the active administrators and fails if userID is
+// the only one left. Holding the locks until the change is saved means two
+// administrators removing each other concurrently cannot both succeed.
+func ensureAnotherAdmin(tx *gorm.DB, userID uint) error {
+	var admins []uint
+	if err := tx.Model(&models.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND is_active = ?", models.RoleAdmin, true).
+		Order("id ASC").
+		Pluck("id", &admins).Error; err != nil {
+		return err
+	}
+	if len(admins) == 1 && admins[0] == userID {
+		return ErrLastActiveAdmin
+	}
+	return nil
 }
 
 func (r *userRepository) List(limit, offset int) ([]*models.User, error) {
@@ -100,3 +184,57 @@ func (r *userRepository) GetByRole(role models.UserRole) ([]*models.User, error)
 	}
 	return users, nil
 }
+
+func (r *userRepository) GetByIDIncludingInactive(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Search(filter UserFilter, limit, offset int) ([]*models.User, error) {
+	var users []*models.User
+	query := r.applyFilter(r.db, filter).Order("username ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) Count(filter UserFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.Model(&models.User{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userRepository) applyFilter(query *gorm.DB, filter UserFilter) *gorm.DB {
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.Search != "" {
+		searchPattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where(
+			"(LOWER(username) LIKE ? OR LOWER(email) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?)",
+			searchPattern, searchPattern, searchPattern, searchPattern,
+		)
+	}
+	return query
+}
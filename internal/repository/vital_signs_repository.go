@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VitalSignsRepository interface {
+	Create(vitals *models.VitalSigns) error
+	GetByID(patientID, id uint) (*models.VitalSigns, error)
+	ListByPatient(patientID uint, from, to time.Time, limit int) ([]*models.VitalSigns, error)
+	LatestHeight(patientID uint, before time.Time) (*float64, error)
+}
+
+type vitalSignsRepository struct {
+	db *gorm.DB
+}
+
+func NewVitalSignsRepository(db *gorm.DB) VitalSignsRepository {
+	return &vitalSignsRepository{db: db}
+}
+
+func (r *vitalSignsRepository) Create(vitals *models.VitalSigns) error {
+	return r.db.Omit(clause.Associations).Create(vitals).Error
+}
+
+func (r *vitalSignsRepository) GetByID(patientID, id uint) (*models.VitalSigns, error) {
+	var vitals models.VitalSigns
+	if err := r.db.Preload("RecordedBy").Where("id = ? AND patient_id = ?", id, patientID).First(&vitals).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("vital signs not found")
+		}
+		return nil, err
+	}
+	return &vitals, nil
+}
+
+// ListByPatient returns the readings taken in [from, to], oldest first, as
+// a chart expects them. When there are more than limit readings the most
+// recent ones are kept.
+func (r *vitalSignsRepository) ListByPatient(patientID uint, from, to time.Time, limit int) ([]*models.VitalSigns, error) {
+	var vitals []*models.VitalSigns
+	query := r.db.Preload("RecordedBy").
+		Where("patient_id = ? AND recorded_at >= ? AND recorded_at <= ?", patientID, from, to).
+		Order("recorded_at DESC, id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&vitals).Error; err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(vitals)-1; i < j; i, j = i+1, j-1 {
+		vitals[i], vitals[j] = vitals[j], vitals[i]
+	}
+	return vitals, nil
+}
+
+// LatestHeight returns the most recent height recorded at or before the
+// given time, or nil if none was recorded.
+func (r *vitalSignsRepository) LatestHeight(patientID uint, before time.Time) (*float64, error) {
+	var vitals models.VitalSigns
+	err := r.db.Select("height_cm").
+		Where("patient_id = ? AND height_cm IS NOT NULL AND recorded_at <= ?", patientID, before).
+		Order("recorded_at DESC").
+		First(&vitals).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vitals.HeightCm, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+
+	"hospital-management-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WardRepository interface {
+	CreateWard(ward *models.Ward) error
+	GetWard(id uint) (*models.Ward, error)
+	GetWardByName(name string) (*models.Ward, error)
+	ListWards(includeInactive bool) ([]*models.Ward, error)
+	CreateRoom(room *models.Room) error
+	GetRoom(wardID, id uint) (*models.Room, error)
+	CreateBed(bed *models.Bed) error
+	GetBed(id uint) (*models.Bed, error)
+	UpdateBed(bed *models.Bed) error
+}
+
+type wardRepository struct {
+	db *gorm.DB
+}
+
+func NewWardRepository(db *gorm.DB) WardRepository {
+	return &wardRepository{db: db}
+}
+
+func (r *wardRepository) CreateWard(ward *models.Ward) error {
+	return r.db.Omit(clause.Associations).Create(ward).Error
+}
+
+func (r *wardRepository) GetWard(id uint) (*models.Ward, error) {
+	var ward models.Ward
+	if err := r.db.Where("id = ?", id).First(&ward).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ward not found")
+		}
+		return nil, err
+	}
+	return &ward, nil
+}
+
+func (r *wardRepository) GetWardByName(name string) (*models.Ward, error) {
+	var ward models.Ward
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&ward).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ward not found")
+		}
+		return nil, err
+	}
+	return &ward, nil
+}
+
+// ListWards loads every ward with its rooms and beds, in a stable order for
+// the occupancy board.
+func (r *wardRepository) ListWards(includeInactive bool) ([]*models.Ward, error) {
+	var wards []*models.Ward
+	query := r.db.
+		Preload("Rooms", func(db *gorm.DB) *gorm.DB {
+			if !includeInactive {
+				db = db.Where("is_active = ?", true)
+			}
+			return db.Order("number ASC")
+		}).
+		Preload("Rooms.Beds", func(db *gorm.DB) *gorm.DB {
+			if !includeInactive {
+				db = db.Where("is_active = ?", true)
+			}
+			return db.Order("label ASC")
+		})
+
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Order("name ASC").Find(&wards).Error; err != nil {
+		return nil, err
+	}
+	return wards, nil
+}
+
+func (r *wardRepository) CreateRoom(room *models.Room) error {
+	return r.db.Omit(clause.Associations).Create(room).Error
+}
+
+func (r *wardRepository) GetRoom(wardID, id uint) (*models.Room, error) {
+	var room models.Room
+	if err := r.db.Where("id = ? AND ward_id = ?", id, wardID).First(&room).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("room not found")
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (r *wardRepository) CreateBed(bed *models.Bed) error {
+	return r.db.Omit(clause.Associations).Create(bed).Error
+}
+
+func (r *wardRepository) GetBed(id uint) (*models.Bed, error) {
+	var bed models.Bed
+	if err := r.db.Preload("Room.Ward").Where("id = ?", id).First(&bed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bed not found")
+		}
+		return nil, err
+	}
+	return &bed, nil
+}
+
+func (r *wardRepository) UpdateBed(bed *models.Bed) error {
+	return r.db.Omit(clause.Associations).Save(bed).Error
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+// AccessContext describes the request through which patient records were read.
+type AccessContext struct {
+	UserID   uint
+	UserRole models.UserRole
+	Endpoint string
+	Query    string
+	ClientIP string
+}
+
+type AccessLogQuery struct {
+	PatientID *uint
+	UserID    *uint
+	From      string // Format: YYYY-MM-DD
+	To        string // Format: YYYY-MM-DD, inclusive
+}
+
+type AccessLogListResponse struct {
+	Logs       []*models.PatientAccessLog `json:"logs"`
+	Pagination PaginationResponse         `json:"pagination"`
+}
+
+type AccessLogService struct {
+	accessLogRepo repository.AccessLogRepository
+}
+
+func NewAccessLogService(accessLogRepo repository.AccessLogRepository) *AccessLogService {
+	return &AccessLogService{
+		accessLogRepo: accessLogRepo,
+	}
+}
+
+func (s *AccessLogService) RecordPatientAccess(ctx AccessContext, patientIDs []uint) error {
+	if len(patientIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	seen := make(map[uint]bool, len(patientIDs))
+	logs := make([]*models.PatientAccessLog, 0, len(patientIDs))
+	for _, patientID := range patientIDs {
+		if seen[patientID] {
+			continue
+		}
+		seen[patientID] = true
+
+		logs = append(logs, &models.PatientAccessLog{
+			UserID:     ctx.UserID,
+			UserRole:   ctx.UserRole,
+			PatientID:  patientID,
+			Endpoint:   ctx.Endpoint,
+			Query:      ctx.Query,
+			ClientIP:   ctx.ClientIP,
+			AccessedAt: now,
+		})
+	}
+
+	if err := s.accessLogRepo.CreateBatch(logs); err != nil {
+		return errors.New("failed to record patient access")
+	}
+
+	return nil
+}
+
+func (s *AccessLogService) ListAccessLogs(req AccessLogQuery, page, pageSize int) (*AccessLogListResponse, error) {
+	if req.PatientID == nil && req.UserID == nil {
+		return nil, errors.New("patient_id or user_id is required")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	filter := repository.AccessLogFilter{
+		PatientID: req.PatientID,
+		UserID:    req.UserID,
+	}
+
+	if req.From != "" {
+		from, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			return nil, errors.New("invalid from date format, use YYYY-MM-DD")
+		}
+		filter.From = from
+	}
+
+	if req.To != "" {
+		to, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			return nil, errors.New("invalid to date format, use YYYY-MM-DD")
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, errors.New("from date must not be after to date")
+	}
+
+	offset := (page - 1) * pageSize
+	logs, err := s.accessLogRepo.Search(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve access logs")
+	}
+
+	total, err := s.accessLogRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("failed to count access logs")
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &AccessLogListResponse{
+		Logs: logs,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type AdmitPatientRequest struct {
+	BedID             uint   `json:"bed_id" binding:"required"`
+	AdmittingDoctorID uint   `json:"admitting_doctor_id" binding:"required"`
+	Reason            string `json:"reason" binding:"required,max=2000"`
+}
+
+type TransferPatientRequest struct {
+	BedID  uint   `json:"bed_id" binding:"required"`
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+type DischargePatientRequest struct {
+	Notes string `json:"notes" binding:"max=5000"`
+}
+
+type AdmissionService struct {
+	admissionRepo repository.AdmissionRepository
+	wardRepo      repository.WardRepository
+	patientRepo   repository.PatientRepository
+	userRepo      repository.UserRepository
+	policy        *authz.Policy
+}
+
+func NewAdmissionService(admissionRepo repository.AdmissionRepository, wardRepo repository.WardRepository, patientRepo repository.PatientRepository, userRepo repository.UserRepository, policy *authz.Policy) *AdmissionService {
+	return &AdmissionService{
+		admissionRepo: admissionRepo,
+		wardRepo:      wardRepo,
+		patientRepo:   patientRepo,
+		userRepo:      userRepo,
+		policy:        policy,
+	}
+}
+
+func (s *AdmissionService) AdmitPatient(patientID uint, req AdmitPatientRequest, userID uint, userRole models.UserRole) (*models.AdmissionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AdmissionWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	doctor, err := s.userRepo.GetByID(req.AdmittingDoctorID)
+	if err != nil || !s.policy.Can(doctor.Role, authz.AppointmentComplete) {
+		return nil, errors.New("doctor not found")
+	}
+
+	if err := s.checkBedInService(req.BedID); err != nil {
+		return nil, err
+	}
+
+	admission := &models.Admission{
+		PatientID:         patientID,
+		BedID:             req.BedID,
+		AdmittingDoctorID: req.AdmittingDoctorID,
+		Reason:            strings.TrimSpace(req.Reason),
+		Status:            models.AdmissionStatusAdmitted,
+		AdmittedAt:        time.Now(),
+		AdmittedByID:      userID,
+	}
+
+	if err := s.admissionRepo.Admit(admission); err != nil {
+		if isBedAllocationError(err) {
+			return nil, err
+		}
+		return nil, errors.New("failed to admit patient")
+	}
+
+	return s.reload(patientID, admission.ID)
+}
+
+func (s *AdmissionService) GetAdmission(patientID, id uint, userRole models.UserRole) (*models.AdmissionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AdmissionRead); err != nil {
+		return nil, err
+	}
+
+	admission, err := s.admissionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := admission.ToResponse()
+	return &response, nil
+}
+
+// CurrentAdmission is where the patient is right now.
+func (s *AdmissionService) CurrentAdmission(patientID uint, userRole models.UserRole) (*models.AdmissionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AdmissionRead); err != nil {
+		return nil, err
+	}
+
+	admission, err := s.admissionRepo.GetCurrent(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := admission.ToResponse()
+	return &response, nil
+}
+
+func (s *AdmissionService) ListAdmissions(patientID uint, userRole models.UserRole) ([]models.AdmissionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AdmissionRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	admissions, err := s.admissionRepo.ListByPatient(patientID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve admissions")
+	}
+
+	responses := make([]models.AdmissionResponse, len(admissions))
+	for i, admission := range admissions {
+		responses[i] = admission.ToResponse()
+	}
+	return responses, nil
+}
+
+func (s *AdmissionService) TransferPatient(patientID, id uint, req TransferPatientRequest, userID uint, userRole models.UserRole) (*models.AdmissionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AdmissionWrite); err != nil {
+		return nil, err
+	}
+
+	admission, err := s.admissionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if admission.Status != models.AdmissionStatusAdmitted {
+		return nil, errors.New("only current admissions can be transferred")
+	}
+	if admission.BedID == req.BedID {
+		return nil, errors.New("patient is already in this bed")
+	}
+
+	if err := s.checkBedInService(req.BedID); err != nil {
+		return nil, err
+	}
+
+	transfer := &models.BedTransfer{
+		ToBedID:         req.BedID,
+		Reason:          strings.TrimSpace(req.Reason),
+		TransferredByID: userID,
+		TransferredAt:   time.Now(),
+	}
+
+	if err := s.admissionRepo.Transfer(admission, transfer); err != nil {
+		if isBedAllocationError(err) {
+			return nil, err
+		}
+		return nil, errors.New("failed to transfer patient")
+	}
+
+	return s.reload(patientID, admission.ID)
+}
+
+// DischargePatient ends the admission and frees the bed.
+func (s *AdmissionService) DischargePatient(patientID, id uint, req DischargePatientRequest, userID uint, userRole models.UserRole) (*models.AdmissionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AdmissionWrite); err != nil {
+		return nil, err
+	}
+
+	admission, err := s.admissionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if admission.Status != models.AdmissionStatusAdmitted {
+		return nil, errors.New("patient has already been discharged")
+	}
+
+	now := time.Now()
+	admission.Status = models.AdmissionStatusDischarged
+	admission.DischargedAt = &now
+	admission.DischargedByID = &userID
+	admission.DischargeNotes = req.Notes
+
+	if err := s.admissionRepo.Discharge(admission); err != nil {
+		if errors.Is(err, repository.ErrAdmissionNotActive) {
+			return nil, err
+		}
+		return nil, errors.New("failed to discharge patient")
+	}
+
+	response := admission.ToResponse()
+	return &response, nil
+}
+
+// checkBedInService rejects beds whose room or ward has been closed. The
+// bed itself is checked again, together with its occupancy, under lock by
+// the repository.
+func (s *AdmissionService) checkBedInService(bedID uint) error {
+	bed, err := s.wardRepo.GetBed(bedID)
+	if err != nil {
+		return err
+	}
+	if !bed.IsActive || (bed.Room != nil && !bed.Room.IsActive) || (bed.Room != nil && bed.Room.Ward != nil && !bed.Room.Ward.IsActive) {
+		return repository.ErrBedUnavailable
+	}
+	return nil
+}
+
+func (s *AdmissionService) reload(patientID, id uint) (*models.AdmissionResponse, error) {
+	admission, err := s.admissionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve admission")
+	}
+
+	response := admission.ToResponse()
+	return &response, nil
+}
+
+func isBedAllocationError(err error) bool {
+	return errors.Is(err, repository.ErrBedOccupied) ||
+		errors.Is(err, repository.ErrBedUnavailable) ||
+		errors.Is(err, repository.ErrPatientAlreadyAdmitted) ||
+		errors.Is(err, repository.ErrAdmissionNotActive)
+}
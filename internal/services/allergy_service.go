@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateAllergyRequest struct {
+	Substance string                 `json:"substance" binding:"required,max=200"`
+	Category  models.AllergyCategory `json:"category" binding:"required,oneof=medication food environment other"`
+	Reaction  string                 `json:"reaction" binding:"max=1000"`
+	Severity  models.AllergySeverity `json:"severity" binding:"omitempty,oneof=mild moderate severe"`
+	OnsetDate string                 `json:"onset_date"` // Format: YYYY-MM-DD
+	Notes     string                 `json:"notes" binding:"max=2000"`
+}
+
+type UpdateAllergyRequest struct {
+	Substance *string                 `json:"substance,omitempty" binding:"omitempty,min=1,max=200"`
+	Category  *models.AllergyCategory `json:"category,omitempty" binding:"omitempty,oneof=medication food environment other"`
+	Reaction  *string                 `json:"reaction,omitempty" binding:"omitempty,max=1000"`
+	Severity  *models.AllergySeverity `json:"severity,omitempty" binding:"omitempty,oneof=mild moderate severe"`
+	OnsetDate *string                 `json:"onset_date,omitempty"`
+	Status    *models.AllergyStatus   `json:"status,omitempty" binding:"omitempty,oneof=active inactive resolved entered_in_error"`
+	Notes     *string                 `json:"notes,omitempty" binding:"omitempty,max=2000"`
+}
+
+type AllergyService struct {
+	allergyRepo repository.AllergyRepository
+	patientRepo repository.PatientRepository
+	policy      *authz.Policy
+}
+
+func NewAllergyService(allergyRepo repository.AllergyRepository, patientRepo repository.PatientRepository, policy *authz.Policy) *AllergyService {
+	return &AllergyService{
+		allergyRepo: allergyRepo,
+		patientRepo: patientRepo,
+		policy:      policy,
+	}
+}
+
+// ListAllergies returns the patient's allergies, newest first. Without a
+// status filter, entries marked as entered in error are left out.
+func (s *AllergyService) ListAllergies(patientID uint, status models.AllergyStatus, userRole models.UserRole) ([]models.AllergyResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AllergyRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	allergies, err := s.allergyRepo.ListByPatient(patientID, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve allergies")
+	}
+
+	responses := make([]models.AllergyResponse, len(allergies))
+	for i, allergy := range allergies {
+		responses[i] = allergy.ToResponse()
+	}
+	return responses, nil
+}
+
+func (s *AllergyService) GetAllergy(patientID, id uint, userRole models.UserRole) (*models.AllergyResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AllergyRead); err != nil {
+		return nil, err
+	}
+
+	allergy, err := s.allergyRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := allergy.ToResponse()
+	return &response, nil
+}
+
+func (s *AllergyService) CreateAllergy(patientID uint, req CreateAllergyRequest, recordedByID uint, userRole models.UserRole) (*models.AllergyResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AllergyWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	substance := strings.TrimSpace(req.Substance)
+	if substance == "" {
+		return nil, errors.New("substance is required")
+	}
+
+	if _, err := s.allergyRepo.FindActiveBySubstance(patientID, substance); err == nil {
+		return nil, errors.New("an active allergy to this substance is already recorded")
+	}
+
+	onsetDate, err := parseOnsetDate(req.OnsetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	allergy := &models.Allergy{
+		PatientID:    patientID,
+		Substance:    substance,
+		Category:     req.Category,
+		Reaction:     req.Reaction,
+		Severity:     req.Severity,
+		OnsetDate:    onsetDate,
+		Status:       models.AllergyStatusActive,
+		Notes:        req.Notes,
+		RecordedByID: recordedByID,
+	}
+
+	if err := s.allergyRepo.Create(allergy); err != nil {
+		return nil, errors.New("failed to create allergy")
+	}
+
+	return s.reload(patientID, allergy.ID)
+}
+
+// UpdateAllergy edits an allergy. Allergies are never deleted; a wrong entry
+// is marked with the entered_in_error status instead.
+func (s *AllergyService) UpdateAllergy(patientID, id uint, req UpdateAllergyRequest, updatedByID uint, userRole models.UserRole) (*models.AllergyResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AllergyWrite); err != nil {
+		return nil, err
+	}
+
+	allergy, err := s.allergyRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Substance != nil {
+		substance := strings.TrimSpace(*req.Substance)
+		if substance == "" {
+			return nil, errors.New("substance is required")
+		}
+		allergy.Substance = substance
+		allergy.IsLegacy = false
+	}
+	if req.Category != nil {
+		allergy.Category = *req.Category
+	}
+	if req.Reaction != nil {
+		allergy.Reaction = *req.Reaction
+	}
+	if req.Severity != nil {
+		allergy.Severity = *req.Severity
+	}
+	if req.OnsetDate != nil {
+		onsetDate, err := parseOnsetDate(*req.OnsetDate)
+		if err != nil {
+			return nil, err
+		}
+		allergy.OnsetDate = onsetDate
+	}
+	if req.Status != nil {
+		allergy.Status = *req.Status
+	}
+	if req.Notes != nil {
+		allergy.Notes = *req.Notes
+	}
+
+	if allergy.Status == models.AllergyStatusActive {
+		if existing, err := s.allergyRepo.FindActiveBySubstance(patientID, allergy.Substance); err == nil && existing.ID != allergy.ID {
+			return nil, errors.New("an active allergy to this substance is already recorded")
+		}
+	}
+
+	allergy.LastUpdatedByID = &updatedByID
+
+	if err := s.allergyRepo.Update(allergy); err != nil {
+		return nil, errors.New("failed to update allergy")
+	}
+
+	return s.reload(patientID, allergy.ID)
+}
+
+func (s *AllergyService) reload(patientID, id uint) (*models.AllergyResponse, error) {
+	allergy, err := s.allergyRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve allergy")
+	}
+
+	response := allergy.ToResponse()
+	return &response, nil
+}
+
+func parseOnsetDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	onset, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("invalid onset date format, use YYYY-MM-DD")
+	}
+	if onset.After(time.Now()) {
+		return nil, errors.New("onset date cannot be in the future")
+	}
+	return &onset, nil
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateAppointmentRequest struct {
+	PatientID       uint      `json:"patient_id" binding:"required"`
+	DoctorID        uint      `json:"doctor_id" binding:"required"`
+	StartTime       time.Time `json:"start_time" binding:"required"` // RFC 3339
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=5,max=480"`
+	Reason          string    `json:"reason" binding:"max=500"`
+}
+
+// RescheduleAppointmentRequest moves an appointment to a new start time. The
+// current duration is kept unless DurationMinutes is given.
+type RescheduleAppointmentRequest struct {
+	StartTime       time.Time `json:"start_time" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"omitempty,min=5,max=480"`
+}
+
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// AppointmentListQuery filters appointments. Date is a YYYY-MM-DD day in UTC.
+type AppointmentListQuery struct {
+	DoctorID  *uint
+	PatientID *uint
+	Date      string
+	Status    models.AppointmentStatus
+}
+
+type AppointmentListResponse struct {
+	Appointments []models.AppointmentResponse `json:"appointments"`
+	Pagination   PaginationResponse           `json:"pagination"`
+}
+
+type AppointmentService struct {
+	appointmentRepo repository.AppointmentRepository
+	patientRepo     repository.PatientRepository
+	userRepo        repository.UserRepository
+	policy          *authz.Policy
+}
+
+func NewAppointmentService(appointmentRepo repository.AppointmentRepository, patientRepo repository.PatientRepository, userRepo repository.UserRepository, policy *authz.Policy) *AppointmentService {
+	return &AppointmentService{
+		appointmentRepo: appointmentRepo,
+		patientRepo:     patientRepo,
+		userRepo:        userRepo,
+		policy:          policy,
+	}
+}
+
+func (s *AppointmentService) CreateAppointment(req CreateAppointmentRequest, createdByID uint, userRole models.UserRole) (*models.AppointmentResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AppointmentManage); err != nil {
+		return nil, err
+	}
+
+	if !req.StartTime.After(time.Now()) {
+		return nil, errors.New("start time must be in the future")
+	}
+
+	if _, err := s.patientRepo.GetByID(req.PatientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	doctor, err := s.userRepo.GetByID(req.DoctorID)
+	if err != nil || !s.policy.Can(doctor.Role, authz.AppointmentComplete) {
+		return nil, errors.New("doctor not found")
+	}
+
+	startTime := req.StartTime.UTC()
+	appointment := &models.Appointment{
+		PatientID:   req.PatientID,
+		DoctorID:    req.DoctorID,
+		StartTime:   startTime,
+		EndTime:     startTime.Add(time.Duration(req.DurationMinutes) * time.Minute),
+		Reason:      req.Reason,
+		Status:      models.AppointmentStatusScheduled,
+		CreatedByID: createdByID,
+	}
+
+	if err := s.appointmentRepo.Create(appointment); err != nil {
+		if errors.Is(err, repository.ErrAppointmentConflict) {
+			return nil, err
+		}
+		return nil, errors.New("failed to create appointment")
+	}
+
+	return s.reload(appointment.ID)
+}
+
+func (s *AppointmentService) GetAppointment(id uint, userID uint, userRole models.UserRole) (*models.AppointmentResponse, error) {
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.canView(appointment, userID, userRole) {
+		return nil, authz.ErrForbidden
+	}
+
+	response := appointment.ToResponse()
+	return &response, nil
+}
+
+// ListAppointments returns appointments ordered by start time. Users without
+// AppointmentReadAll only ever see their own schedule, whatever doctor_id
+// they ask for.
+func (s *AppointmentService) ListAppointments(req AppointmentListQuery, page, pageSize int, userID uint, userRole models.UserRole) (*AppointmentListResponse, error) {
+	filter := repository.AppointmentFilter{
+		DoctorID:  req.DoctorID,
+		PatientID: req.PatientID,
+		Status:    req.Status,
+	}
+
+	if !s.policy.Can(userRole, authz.AppointmentReadAll) {
+		filter.DoctorID = &userID
+	}
+
+	if req.Date != "" {
+		day, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		filter.From = day
+		filter.To = day.AddDate(0, 0, 1)
+	}
+
+	offset := (page - 1) * pageSize
+
+	appointments, err := s.appointmentRepo.List(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve appointments")
+	}
+
+	total, err := s.appointmentRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("failed to count appointments")
+	}
+
+	appointmentResponses := make([]models.AppointmentResponse, len(appointments))
+	for i, appointment := range appointments {
+		appointmentResponses[i] = appointment.ToResponse()
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &AppointmentListResponse{
+		Appointments: appointmentResponses,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+func (s *AppointmentService) RescheduleAppointment(id uint, req RescheduleAppointmentRequest, updatedByID uint, userRole models.UserRole) (*models.AppointmentResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AppointmentManage); err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status != models.AppointmentStatusScheduled {
+		return nil, errors.New("only scheduled appointments can be rescheduled")
+	}
+
+	if !req.StartTime.After(time.Now()) {
+		return nil, errors.New("start time must be in the future")
+	}
+
+	duration := appointment.EndTime.Sub(appointment.StartTime)
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+
+	appointment.StartTime = req.StartTime.UTC()
+	appointment.EndTime = appointment.StartTime.Add(duration)
+	appointment.LastUpdatedByID = &updatedByID
+
+	if err := s.appointmentRepo.Reschedule(appointment); err != nil {
+		if errors.Is(err, repository.ErrAppointmentConflict) || errors.Is(err, repository.ErrAppointmentStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to reschedule appointment")
+	}
+
+	return s.reload(appointment.ID)
+}
+
+func (s *AppointmentService) CancelAppointment(id uint, req CancelAppointmentRequest, updatedByID uint, userRole models.UserRole) (*models.AppointmentResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AppointmentManage); err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appointment.Status.BlocksSchedule() {
+		return nil, errors.New("appointment can no longer be cancelled")
+	}
+
+	from := appointment.Status
+	now := time.Now()
+	appointment.Status = models.AppointmentStatusCancelled
+	appointment.CancellationReason = req.Reason
+	appointment.CancelledAt = &now
+
+	return s.saveTransition(appointment, from, updatedByID)
+}
+
+func (s *AppointmentService) CheckInAppointment(id uint, updatedByID uint, userRole models.UserRole) (*models.AppointmentResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AppointmentManage); err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status != models.AppointmentStatusScheduled {
+		return nil, errors.New("only scheduled appointments can be checked in")
+	}
+
+	now := time.Now()
+	appointment.Status = models.AppointmentStatusCheckedIn
+	appointment.CheckedInAt = &now
+
+	return s.saveTransition(appointment, models.AppointmentStatusScheduled, updatedByID)
+}
+
+// CompleteAppointment can only be done by the doctor the appointment is
+// booked with, once the patient has checked in.
+func (s *AppointmentService) CompleteAppointment(id uint, updatedByID uint, userRole models.UserRole) (*models.AppointmentResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AppointmentComplete); err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.DoctorID != updatedByID {
+		return nil, authz.ErrForbidden
+	}
+
+	if appointment.Status != models.AppointmentStatusCheckedIn {
+		return nil, errors.New("only checked-in appointments can be completed")
+	}
+
+	now := time.Now()
+	appointment.Status = models.AppointmentStatusCompleted
+	appointment.CompletedAt = &now
+
+	return s.saveTransition(appointment, models.AppointmentStatusCheckedIn, updatedByID)
+}
+
+func (s *AppointmentService) canView(appointment *models.Appointment, userID uint, userRole models.UserRole) bool {
+	if s.policy.Can(userRole, authz.AppointmentReadAll) {
+		return true
+	}
+	return s.policy.Can(userRole, authz.AppointmentRead) && appointment.DoctorID == userID
+}
+
+func (s *AppointmentService) saveTransition(appointment *models.Appointment, from models.AppointmentStatus, updatedByID uint) (*models.AppointmentResponse, error) {
+	appointment.LastUpdatedByID = &updatedByID
+
+	if err := s.appointmentRepo.Transition(appointment, from); err != nil {
+		if errors.Is(err, repository.ErrAppointmentStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update appointment")
+	}
+
+	response := appointment.ToResponse()
+	return &response, nil
+}
+
+func (s *AppointmentService) reload(id uint) (*models.AppointmentResponse, error) {
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve appointment")
+	}
+
+	response := appointment.ToResponse()
+	return &response, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"log"
+	"time"
 
 	"hospital-management-system/internal/auth"
 	"hospital-management-system/internal/models"
@@ -14,29 +16,39 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// LoginResponse carries either the session tokens or, when a second factor
+// is needed, an MFA token to present to the MFA verify or enroll endpoints.
 type LoginResponse struct {
-	User  models.UserResponse `json:"user"`
-	Token string              `json:"token"`
+	User models.UserResponse `json:"user"`
+	*TokenResponse
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
 }
 
-type RegisterRequest struct {
-	Username  string          `json:"username" binding:"required,min=3,max=50"`
-	Email     string          `json:"email" binding:"required,email"`
-	Password  string          `json:"password" binding:"required,min=6"`
-	FirstName string          `json:"first_name" binding:"required,min=2,max=50"`
-	LastName  string          `json:"last_name" binding:"required,min=2,max=50"`
-	Role      models.UserRole `json:"role" binding:"required,oneof=receptionist doctor"`
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type AuthService struct {
-	userRepo   repository.UserRepository
-	jwtService *auth.JWTService
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	mfaRepo     repository.MFARepository
+	jwtService  *auth.JWTService
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtService *auth.JWTService) *AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, mfaRepo repository.MFARepository, jwtService *auth.JWTService) *AuthService {
 	return &AuthService{
-		userRepo:   userRepo,
-		jwtService: jwtService,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		mfaRepo:     mfaRepo,
+		jwtService:  jwtService,
 	}
 }
 
@@ -54,65 +66,202 @@ func (s *AuthService) Login(req LoginRequest) (*LoginResponse, error) {
 		return nil, errors.New("user account is deactivated")
 	}
 
-	token, err := s.jwtService.GenerateToken(user)
+	if user.MFAEnabled {
+		return s.mfaLoginResponse(user, auth.PurposeMFAChallenge)
+	}
+
+	mfaRequired, err := s.mfaRepo.IsRequiredForRole(user.Role)
+	if err != nil {
+		return nil, errors.New("failed to check MFA policy")
+	}
+	if mfaRequired {
+		return s.mfaLoginResponse(user, auth.PurposeMFAEnrollment)
+	}
+
+	return s.completeLogin(user)
+}
+
+func (s *AuthService) mfaLoginResponse(user *models.User, purpose string) (*LoginResponse, error) {
+	mfaToken, err := s.jwtService.GenerateMFAToken(user, purpose)
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
 
 	return &LoginResponse{
-		User:  user.ToResponse(),
-		Token: token,
+		User:                  user.ToResponse(),
+		MFARequired:           purpose == auth.PurposeMFAChallenge,
+		MFAEnrollmentRequired: purpose == auth.PurposeMFAEnrollment,
+		MFAToken:              mfaToken,
 	}, nil
 }
 
-func (s *AuthService) Register(req RegisterRequest) (*models.UserResponse, error) {
-	existingUser, err := s.userRepo.GetByUsername(req.Username)
-	if err == nil && existingUser != nil {
-		return nil, errors.New("username already exists")
+func (s *AuthService) completeLogin(user *models.User) (*LoginResponse, error) {
+	tokens, err := s.startSession(user)
+	if err != nil {
+		return nil, err
 	}
 
-	existingUser, err = s.userRepo.GetByEmail(req.Email)
-	if err == nil && existingUser != nil {
-		return nil, errors.New("email already exists")
+	return &LoginResponse{
+		User:          user.ToResponse(),
+		TokenResponse: tokens,
+	}, nil
+}
+
+func (s *AuthService) GetUserByID(id uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
 	}
 
-	hashedPassword, err := utils.HashPassword(req.Password)
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access and refresh token
+// pair. Presenting a refresh token that has already been used revokes the
+// whole session, since it means the token was copied.
+func (s *AuthService) RefreshToken(refreshToken string) (*TokenResponse, error) {
+	stored, err := s.sessionRepo.GetRefreshTokenByHash(utils.HashToken(refreshToken))
 	if err != nil {
-		return nil, errors.New("failed to process password")
+		return nil, errors.New("invalid refresh token")
 	}
 
-	user := &models.User{
-		Username:  req.Username,
-		Email:     req.Email,
-		Password:  hashedPassword,
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Role:      req.Role,
-		IsActive:  true,
+	if stored.UsedAt != nil {
+		s.revokeReusedSession(stored.SessionID)
+		return nil, errors.New("refresh token reuse detected")
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, errors.New("failed to create user")
+	if stored.Session.IsRevoked() {
+		return nil, errors.New("session has been revoked")
 	}
 
-	response := user.ToResponse()
-	return &response, nil
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token has expired")
+	}
+
+	user, err := s.userRepo.GetByID(stored.Session.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	nextToken, next, err := s.newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.RotateRefreshToken(stored, next, s.jwtService.SessionMaxLifetime()); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrSessionRevoked), errors.Is(err, repository.ErrSessionExpired):
+			return nil, err
+		case err.Error() == "refresh token already used":
+			s.revokeReusedSession(stored.SessionID)
+			return nil, errors.New("refresh token reuse detected")
+		}
+		return nil, errors.New("failed to rotate refresh token")
+	}
+
+	accessToken, err := s.jwtService.GenerateToken(user, stored.SessionID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &TokenResponse{
+		Token:        accessToken,
+		RefreshToken: nextToken,
+		ExpiresIn:    int64(s.jwtService.AccessTokenTTL().Seconds()),
+	}, nil
 }
 
-func (s *AuthService) GetUserByID(id uint) (*models.UserResponse, error) {
-	user, err := s.userRepo.GetByID(id)
+// ValidateToken verifies the access token signature and expiry and rejects
+// tokens whose session has been revoked.
+func (s *AuthService) ValidateToken(tokenString string) (*auth.Claims, error) {
+	claims, err := s.jwtService.ValidateToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	response := user.ToResponse()
-	return &response, nil
+	if claims.SessionID == 0 {
+		return nil, errors.New("token is not bound to a session")
+	}
+
+	session, err := s.sessionRepo.GetByID(claims.SessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+
+	if session.IsRevoked() || session.UserID != claims.UserID {
+		return nil, errors.New("session has been revoked")
+	}
+
+	return claims, nil
 }
 
-func (s *AuthService) RefreshToken(tokenString string) (string, error) {
-	return s.jwtService.RefreshToken(tokenString)
+// ValidateMFAEnrollmentToken accepts the token issued at login to users who
+// must enroll in MFA before they are given a session.
+func (s *AuthService) ValidateMFAEnrollmentToken(tokenString string) (*auth.Claims, error) {
+	return s.jwtService.ValidateMFAToken(tokenString, auth.PurposeMFAEnrollment)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*auth.Claims, error) {
-	return s.jwtService.ValidateToken(tokenString)
+func (s *AuthService) JWKS() auth.JWKSet {
+	return s.jwtService.JWKS()
+}
+
+func (s *AuthService) Logout(sessionID uint) error {
+	if err := s.sessionRepo.Revoke(sessionID, "logout"); err != nil {
+		return errors.New("failed to revoke session")
+	}
+	return nil
+}
+
+func (s *AuthService) LogoutAll(userID uint) error {
+	if err := s.sessionRepo.RevokeAllForUser(userID, "logout_all"); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
+
+func (s *AuthService) startSession(user *models.User) (*TokenResponse, error) {
+	refreshToken, stored, err := s.newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.UserSession{
+		UserID:     user.ID,
+		ExpiresAt:  stored.ExpiresAt,
+		LastUsedAt: time.Now(),
+	}
+
+	if err := s.sessionRepo.Create(session, stored); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	accessToken, err := s.jwtService.GenerateToken(user, session.ID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtService.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+func (s *AuthService) newRefreshToken() (string, *models.RefreshToken, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", nil, errors.New("failed to generate refresh token")
+	}
+
+	return token, &models.RefreshToken{
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(s.jwtService.RefreshTokenTTL()),
+	}, nil
+}
+
+func (s *AuthService) revokeReusedSession(sessionID uint) {
+	if err := s.sessionRepo.Revoke(sessionID, "refresh_token_reuse"); err != nil {
+		log.Printf("Failed to revoke session %d after refresh token reuse: %v", sessionID, err)
+	}
 }
@@ -0,0 +1,470 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+const (
+	maxSlotSearchDays = 31
+	defaultSlotLimit  = 50
+	maxSlotLimit      = 500
+)
+
+// WeeklyPeriodRequest is a recurring period on one weekday (0 = Sunday).
+// Times are "HH:MM" in the schedule's timezone.
+type WeeklyPeriodRequest struct {
+	Weekday   *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+type UpdateScheduleRequest struct {
+	Specialty    string                `json:"specialty" binding:"max=100"`
+	SlotMinutes  int                   `json:"slot_minutes" binding:"required,min=5,max=240"`
+	Timezone     string                `json:"timezone"`
+	WorkingHours []WeeklyPeriodRequest `json:"working_hours" binding:"dive"`
+	Breaks       []WeeklyPeriodRequest `json:"breaks" binding:"dive"`
+}
+
+type CreateScheduleExceptionRequest struct {
+	StartsAt time.Time                    `json:"starts_at" binding:"required"`
+	EndsAt   time.Time                    `json:"ends_at" binding:"required"`
+	Kind     models.ScheduleExceptionKind `json:"kind" binding:"required,oneof=leave holiday other"`
+	Reason   string                       `json:"reason" binding:"max=500"`
+}
+
+// OpenSlotQuery selects the doctors to search, either one doctor or every
+// doctor with a specialty. From and To are inclusive YYYY-MM-DD dates in each
+// doctor's timezone; they default to the next seven days.
+type OpenSlotQuery struct {
+	DoctorID  *uint
+	Specialty string
+	From      string
+	To        string
+	Limit     int
+}
+
+type OpenSlotResponse struct {
+	Slots []models.OpenSlot `json:"slots"`
+}
+
+type AvailabilityService struct {
+	availabilityRepo repository.AvailabilityRepository
+	appointmentRepo  repository.AppointmentRepository
+	userRepo         repository.UserRepository
+	policy           *authz.Policy
+}
+
+func NewAvailabilityService(availabilityRepo repository.AvailabilityRepository, appointmentRepo repository.AppointmentRepository, userRepo repository.UserRepository, policy *authz.Policy) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		appointmentRepo:  appointmentRepo,
+		userRepo:         userRepo,
+		policy:           policy,
+	}
+}
+
+func (s *AvailabilityService) GetSchedule(doctorID uint, userRole models.UserRole) (*models.DoctorScheduleResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AvailabilityRead); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.availabilityRepo.GetSchedule(doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	exceptions, err := s.availabilityRepo.ListExceptions(doctorID, now, now.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, errors.New("failed to retrieve schedule exceptions")
+	}
+
+	response := schedule.ToResponse(exceptions)
+	return &response, nil
+}
+
+func (s *AvailabilityService) UpdateSchedule(doctorID uint, req UpdateScheduleRequest, userID uint, userRole models.UserRole) (*models.DoctorScheduleResponse, error) {
+	if err := s.authorizeManage(doctorID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureBookable(doctorID); err != nil {
+		return nil, err
+	}
+
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+
+	workingHours, err := toWeeklyPeriods(req.WorkingHours, "working hours")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNoOverlap(workingHours); err != nil {
+		return nil, err
+	}
+	breaks, err := toWeeklyPeriods(req.Breaks, "break")
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &models.DoctorSchedule{
+		DoctorID:    doctorID,
+		Specialty:   strings.TrimSpace(req.Specialty),
+		SlotMinutes: req.SlotMinutes,
+		Timezone:    timezone,
+		UpdatedByID: &userID,
+	}
+	for _, period := range workingHours {
+		schedule.WorkingHours = append(schedule.WorkingHours, models.DoctorWorkingHours{
+			Weekday:   period.weekday,
+			StartTime: formatClock(period.start),
+			EndTime:   formatClock(period.end),
+		})
+	}
+	for _, period := range breaks {
+		schedule.Breaks = append(schedule.Breaks, models.DoctorBreak{
+			Weekday:   period.weekday,
+			StartTime: formatClock(period.start),
+			EndTime:   formatClock(period.end),
+		})
+	}
+
+	if err := s.availabilityRepo.SaveSchedule(schedule); err != nil {
+		return nil, errors.New("failed to save schedule")
+	}
+
+	return s.GetSchedule(doctorID, userRole)
+}
+
+func (s *AvailabilityService) AddException(doctorID uint, req CreateScheduleExceptionRequest, userID uint, userRole models.UserRole) (*models.DoctorScheduleException, error) {
+	if err := s.authorizeManage(doctorID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, errors.New("exception must end after it starts")
+	}
+
+	if err := s.ensureBookable(doctorID); err != nil {
+		return nil, err
+	}
+
+	exception := &models.DoctorScheduleException{
+		DoctorID:    doctorID,
+		StartsAt:    req.StartsAt.UTC(),
+		EndsAt:      req.EndsAt.UTC(),
+		Kind:        req.Kind,
+		Reason:      req.Reason,
+		CreatedByID: userID,
+	}
+
+	if err := s.availabilityRepo.CreateException(exception); err != nil {
+		return nil, errors.New("failed to create schedule exception")
+	}
+
+	return exception, nil
+}
+
+func (s *AvailabilityService) DeleteException(doctorID, exceptionID uint, userID uint, userRole models.UserRole) error {
+	if err := s.authorizeManage(doctorID, userID, userRole); err != nil {
+		return err
+	}
+
+	return s.availabilityRepo.DeleteException(doctorID, exceptionID)
+}
+
+// FindOpenSlots lists the free slots in the requested period, earliest
+// first. A slot is free when it lies within the doctor's working hours and
+// does not overlap a break, a schedule exception or an active appointment.
+func (s *AvailabilityService) FindOpenSlots(req OpenSlotQuery, userRole models.UserRole) (*OpenSlotResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.AvailabilityRead); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from, to, err := parseSlotRange(req.From, req.To, now)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSlotLimit
+	}
+	if limit > maxSlotLimit {
+		limit = maxSlotLimit
+	}
+
+	var schedules []*models.DoctorSchedule
+	if req.DoctorID != nil {
+		schedule, err := s.availabilityRepo.GetSchedule(*req.DoctorID)
+		if err != nil {
+			return nil, err
+		}
+		schedules = []*models.DoctorSchedule{schedule}
+	} else {
+		schedules, err = s.availabilityRepo.ListSchedules(strings.TrimSpace(req.Specialty))
+		if err != nil {
+			return nil, errors.New("failed to retrieve schedules")
+		}
+	}
+
+	slots := []models.OpenSlot{}
+	for _, schedule := range schedules {
+		doctorSlots, err := s.openSlotsFor(schedule, from, to, now)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, doctorSlots...)
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		if !slots[i].StartTime.Equal(slots[j].StartTime) {
+			return slots[i].StartTime.Before(slots[j].StartTime)
+		}
+		return slots[i].DoctorID < slots[j].DoctorID
+	})
+	if len(slots) > limit {
+		slots = slots[:limit]
+	}
+
+	return &OpenSlotResponse{Slots: slots}, nil
+}
+
+func (s *AvailabilityService) openSlotsFor(schedule *models.DoctorSchedule, from, to civilDate, now time.Time) ([]models.OpenSlot, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	rangeStart := from.in(loc, 0)
+	rangeEnd := to.in(loc, 0).AddDate(0, 0, 1)
+
+	var blocked []timeRange
+	exceptions, err := s.availabilityRepo.ListExceptions(schedule.DoctorID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, errors.New("failed to retrieve schedule exceptions")
+	}
+	for _, exception := range exceptions {
+		blocked = append(blocked, timeRange{exception.StartsAt, exception.EndsAt})
+	}
+
+	busy, err := s.appointmentRepo.ListBusy(schedule.DoctorID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, errors.New("failed to retrieve appointments")
+	}
+	for _, appointment := range busy {
+		blocked = append(blocked, timeRange{appointment.StartTime, appointment.EndTime})
+	}
+
+	length := time.Duration(schedule.SlotMinutes) * time.Minute
+	if length <= 0 {
+		return nil, nil
+	}
+	doctorName := strings.TrimSpace(schedule.Doctor.FirstName + " " + schedule.Doctor.LastName)
+
+	var slots []models.OpenSlot
+	for day := from; !day.after(to); day = day.next() {
+		weekday := day.in(loc, 0).Weekday()
+
+		dayBlocked := blocked
+		for _, b := range schedule.Breaks {
+			if b.Weekday != weekday {
+				continue
+			}
+			start, end, err := parsePeriod(b.StartTime, b.EndTime)
+			if err != nil {
+				continue
+			}
+			dayBlocked = append(dayBlocked, timeRange{day.in(loc, start), day.in(loc, end)})
+		}
+
+		for _, hours := range schedule.WorkingHours {
+			if hours.Weekday != weekday {
+				continue
+			}
+			start, end, err := parsePeriod(hours.StartTime, hours.EndTime)
+			if err != nil {
+				continue
+			}
+
+			periodEnd := day.in(loc, end)
+			for slotStart := day.in(loc, start); !slotStart.Add(length).After(periodEnd); slotStart = slotStart.Add(length) {
+				slot := timeRange{slotStart, slotStart.Add(length)}
+				if slot.start.Before(now) || slot.overlapsAny(dayBlocked) {
+					continue
+				}
+				slots = append(slots, models.OpenSlot{
+					DoctorID:   schedule.DoctorID,
+					DoctorName: doctorName,
+					Specialty:  schedule.Specialty,
+					StartTime:  slot.start,
+					EndTime:    slot.end,
+				})
+			}
+		}
+	}
+
+	return slots, nil
+}
+
+func (s *AvailabilityService) authorizeManage(doctorID uint, userID uint, userRole models.UserRole) error {
+	if s.policy.Can(userRole, authz.AvailabilityManageAll) {
+		return nil
+	}
+	if s.policy.Can(userRole, authz.AvailabilityManage) && doctorID == userID {
+		return nil
+	}
+	return authz.ErrForbidden
+}
+
+func (s *AvailabilityService) ensureBookable(doctorID uint) error {
+	doctor, err := s.userRepo.GetByID(doctorID)
+	if err != nil || !s.policy.Can(doctor.Role, authz.AppointmentComplete) {
+		return errors.New("doctor not found")
+	}
+	return nil
+}
+
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func (r timeRange) overlapsAny(others []timeRange) bool {
+	for _, other := range others {
+		if r.start.Before(other.end) && r.end.After(other.start) {
+			return true
+		}
+	}
+	return false
+}
+
+// civilDate is a calendar day independent of any timezone, so that a range
+// of dates can be expanded in each doctor's own timezone.
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) civilDate {
+	year, month, day := t.Date()
+	return civilDate{year, month, day}
+}
+
+// in returns the given minute of the day in loc. Using time.Date keeps wall
+// clock times correct across daylight saving changes.
+func (d civilDate) in(loc *time.Location, minute int) time.Time {
+	return time.Date(d.year, d.month, d.day, 0, minute, 0, 0, loc)
+}
+
+func (d civilDate) next() civilDate {
+	return dateOf(time.Date(d.year, d.month, d.day+1, 0, 0, 0, 0, time.UTC))
+}
+
+func (d civilDate) after(other civilDate) bool {
+	return d.in(time.UTC, 0).After(other.in(time.UTC, 0))
+}
+
+func parseSlotRange(fromValue, toValue string, now time.Time) (civilDate, civilDate, error) {
+	from := dateOf(now.UTC())
+	if fromValue != "" {
+		parsed, err := time.Parse("2006-01-02", fromValue)
+		if err != nil {
+			return civilDate{}, civilDate{}, errors.New("invalid from date, use YYYY-MM-DD")
+		}
+		from = dateOf(parsed)
+	}
+
+	to := dateOf(from.in(time.UTC, 0).AddDate(0, 0, 6))
+	if toValue != "" {
+		parsed, err := time.Parse("2006-01-02", toValue)
+		if err != nil {
+			return civilDate{}, civilDate{}, errors.New("invalid to date, use YYYY-MM-DD")
+		}
+		to = dateOf(parsed)
+	}
+
+	if from.after(to) {
+		return civilDate{}, civilDate{}, errors.New("from date must not be after to date")
+	}
+	if to.in(time.UTC, 0).Sub(from.in(time.UTC, 0)) >= maxSlotSearchDays*24*time.Hour {
+		return civilDate{}, civilDate{}, fmt.Errorf("date range cannot exceed %d days", maxSlotSearchDays)
+	}
+
+	return from, to, nil
+}
+
+type weeklyPeriod struct {
+	weekday time.Weekday
+	start   int
+	end     int
+}
+
+func toWeeklyPeriods(requests []WeeklyPeriodRequest, label string) ([]weeklyPeriod, error) {
+	periods := make([]weeklyPeriod, 0, len(requests))
+	for _, req := range requests {
+		start, end, err := parsePeriod(req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", label, err)
+		}
+		periods = append(periods, weeklyPeriod{weekday: time.Weekday(*req.Weekday), start: start, end: end})
+	}
+	return periods, nil
+}
+
+func checkNoOverlap(periods []weeklyPeriod) error {
+	for i := range periods {
+		for j := i + 1; j < len(periods); j++ {
+			a, b := periods[i], periods[j]
+			if a.weekday == b.weekday && a.start < b.end && b.start < a.end {
+				return fmt.Errorf("working hours overlap on %s", a.weekday)
+			}
+		}
+	}
+	return nil
+}
+
+// parsePeriod converts "HH:MM" start and end times to minutes since
+// midnight.
+func parsePeriod(startValue, endValue string) (int, int, error) {
+	start, err := parseClock(startValue)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(endValue)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, errors.New("end time must be after start time")
+	}
+	return start, end, nil
+}
+
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func formatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/billing"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type InvoiceItemInput struct {
+	PriceListItemID uint    `json:"price_list_item_id" binding:"required"`
+	Quantity        int     `json:"quantity" binding:"required,min=1,max=1000"`
+	DiscountPercent float64 `json:"discount_percent" binding:"min=0,max=100"`
+	Description     string  `json:"description" binding:"max=500"` // defaults to the price list name
+}
+
+type CreateInvoiceRequest struct {
+	EncounterID *uint              `json:"encounter_id"`
+	AdmissionID *uint              `json:"admission_id"`
+	Items       []InvoiceItemInput `json:"items" binding:"required,min=1,max=200,dive"`
+	Notes       string             `json:"notes" binding:"max=2000"`
+}
+
+type UpdateInvoiceRequest struct {
+	Items []InvoiceItemInput `json:"items" binding:"required,min=1,max=200,dive"`
+	Notes *string            `json:"notes,omitempty" binding:"omitempty,max=2000"`
+}
+
+type IssueInvoiceRequest struct {
+	DueDate string `json:"due_date"` // Format: YYYY-MM-DD, defaults to 30 days after issue
+}
+
+type VoidInvoiceRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type RecordPaymentRequest struct {
+	AmountCents int64                `json:"amount_cents" binding:"required,min=1"`
+	Method      models.PaymentMethod `json:"method" binding:"required,oneof=cash card bank_transfer insurance other"`
+	Reference   string               `json:"reference" binding:"max=100"`
+	Notes       string               `json:"notes" binding:"max=1000"`
+}
+
+type RefundPaymentRequest struct {
+	AmountCents int64                `json:"amount_cents" binding:"required,min=1"`
+	Method      models.PaymentMethod `json:"method" binding:"required,oneof=cash card bank_transfer insurance other"`
+	Reference   string               `json:"reference" binding:"max=100"`
+	Reason      string               `json:"reason" binding:"required,max=1000"`
+}
+
+// PatientBalanceResponse is what a patient owes across their open invoices.
+type PatientBalanceResponse struct {
+	PatientID        uint                     `json:"patient_id"`
+	OutstandingCents int64                    `json:"outstanding_cents"`
+	Currency         string                   `json:"currency"`
+	Invoices         []models.InvoiceResponse `json:"invoices"`
+}
+
+const defaultInvoiceTermDays = 30
+
+type BillingService struct {
+	invoiceRepo   repository.InvoiceRepository
+	priceListRepo repository.PriceListRepository
+	patientRepo   repository.PatientRepository
+	encounterRepo repository.EncounterRepository
+	admissionRepo repository.AdmissionRepository
+	printOptions  billing.PrintOptions
+	policy        *authz.Policy
+}
+
+func NewBillingService(invoiceRepo repository.InvoiceRepository, priceListRepo repository.PriceListRepository, patientRepo repository.PatientRepository, encounterRepo repository.EncounterRepository, admissionRepo repository.AdmissionRepository, printOptions billing.PrintOptions, policy *authz.Policy) *BillingService {
+	return &BillingService{
+		invoiceRepo:   invoiceRepo,
+		priceListRepo: priceListRepo,
+		patientRepo:   patientRepo,
+		encounterRepo: encounterRepo,
+		admissionRepo: admissionRepo,
+		printOptions:  printOptions,
+		policy:        policy,
+	}
+}
+
+// CreateInvoice drafts an invoice for one of the patient's encounters or
+// admissions.
+func (s *BillingService) CreateInvoice(patientID uint, req CreateInvoiceRequest, userID uint, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	if (req.EncounterID == nil) == (req.AdmissionID == nil) {
+		return nil, errors.New("invoice must reference either an encounter or an admission")
+	}
+	if req.EncounterID != nil {
+		if _, err := s.encounterRepo.GetByID(patientID, *req.EncounterID); err != nil {
+			return nil, errors.New("encounter not found for this patient")
+		}
+	}
+	if req.AdmissionID != nil {
+		if _, err := s.admissionRepo.GetByID(patientID, *req.AdmissionID); err != nil {
+			return nil, errors.New("admission not found for this patient")
+		}
+	}
+
+	items, err := s.buildItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &models.Invoice{
+		PatientID:   patientID,
+		EncounterID: req.EncounterID,
+		AdmissionID: req.AdmissionID,
+		Status:      models.InvoiceStatusDraft,
+		Notes:       strings.TrimSpace(req.Notes),
+		Items:       items,
+		CreatedByID: userID,
+	}
+	invoice.SetTotals()
+
+	if err := s.invoiceRepo.Create(invoice); err != nil {
+		return nil, errors.New("failed to create invoice")
+	}
+
+	return s.reload(patientID, invoice.ID)
+}
+
+func (s *BillingService) GetInvoice(patientID, id uint, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceRead); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := invoice.ToResponse()
+	return &response, nil
+}
+
+func (s *BillingService) ListInvoices(patientID uint, status models.InvoiceStatus, userRole models.UserRole) ([]models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	invoices, err := s.invoiceRepo.ListByPatient(patientID, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve invoices")
+	}
+
+	return toInvoiceResponses(invoices), nil
+}
+
+func (s *BillingService) PatientBalance(patientID uint, userRole models.UserRole) (*PatientBalanceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	invoices, err := s.invoiceRepo.ListOpen(patientID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve invoices")
+	}
+
+	balance := &PatientBalanceResponse{
+		PatientID: patientID,
+		Currency:  s.printOptions.Currency,
+		Invoices:  toInvoiceResponses(invoices),
+	}
+	for _, invoice := range invoices {
+		balance.OutstandingCents += invoice.BalanceCents()
+	}
+	return balance, nil
+}
+
+// UpdateDraftInvoice replaces the lines of a draft invoice.
+func (s *BillingService) UpdateDraftInvoice(patientID, id uint, req UpdateInvoiceRequest, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceWrite); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != models.InvoiceStatusDraft {
+		return nil, errors.New("only draft invoices can be edited")
+	}
+
+	items, err := s.buildItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Items = items
+	if req.Notes != nil {
+		invoice.Notes = strings.TrimSpace(*req.Notes)
+	}
+	invoice.SetTotals()
+
+	if err := s.invoiceRepo.UpdateDraft(invoice); err != nil {
+		if errors.Is(err, repository.ErrInvoiceStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update invoice")
+	}
+
+	return s.reload(patientID, invoice.ID)
+}
+
+// IssueInvoice finalizes a draft so that it can be paid.
+func (s *BillingService) IssueInvoice(patientID, id uint, req IssueInvoiceRequest, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceWrite); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != models.InvoiceStatusDraft {
+		return nil, errors.New("only draft invoices can be issued")
+	}
+
+	now := time.Now()
+	today := now.UTC().Truncate(24 * time.Hour)
+	dueDate := today.AddDate(0, 0, defaultInvoiceTermDays)
+	if req.DueDate != "" {
+		dueDate, err = time.Parse("2006-01-02", req.DueDate)
+		if err != nil {
+			return nil, errors.New("invalid due date format, use YYYY-MM-DD")
+		}
+		if dueDate.Before(today) {
+			return nil, errors.New("due date cannot be in the past")
+		}
+	}
+
+	invoice.Status = models.InvoiceStatusIssued
+	invoice.IssuedAt = &now
+	invoice.DueDate = &dueDate
+	invoice.SettleStatus()
+
+	if err := s.transition(invoice, models.InvoiceStatusDraft); err != nil {
+		return nil, err
+	}
+
+	response := invoice.ToResponse()
+	return &response, nil
+}
+
+// VoidInvoice cancels an invoice. Anything paid on it has to be refunded
+// first, and claims already sent to a payer have to be resolved. Draft
+// claims are cancelled with it.
+func (s *BillingService) VoidInvoice(patientID, id uint, req VoidInvoiceRequest, userID uint, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceWrite); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status == models.InvoiceStatusVoid {
+		return nil, errors.New("invoice is already void")
+	}
+	if invoice.NetPaidCents() != 0 {
+		return nil, errors.New("payments must be refunded before the invoice is voided")
+	}
+
+	from := invoice.Status
+	now := time.Now()
+	invoice.Status = models.InvoiceStatusVoid
+	invoice.VoidedAt = &now
+	invoice.VoidedByID = &userID
+	invoice.VoidReason = req.Reason
+
+	if err := s.invoiceRepo.Void(invoice, from); err != nil {
+		if errors.Is(err, repository.ErrInvoiceStatusChanged) || errors.Is(err, repository.ErrInvoiceHasClaims) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update invoice")
+	}
+
+	response := invoice.ToResponse()
+	return &response, nil
+}
+
+// RecordPayment takes a full or partial payment on an issued invoice.
+func (s *BillingService) RecordPayment(patientID, id uint, req RecordPaymentRequest, userID uint, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PaymentWrite); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !invoice.Status.IsOpen() {
+		return nil, errors.New("payments can only be taken on issued invoices")
+	}
+	if req.AmountCents > invoice.BalanceCents() {
+		return nil, repository.ErrPaymentExceedsBalance
+	}
+
+	payment := &models.Payment{
+		Kind:         models.PaymentKindPayment,
+		AmountCents:  req.AmountCents,
+		Method:       req.Method,
+		Reference:    strings.TrimSpace(req.Reference),
+		Notes:        req.Notes,
+		ReceivedByID: userID,
+		ReceivedAt:   time.Now(),
+	}
+
+	return s.recordPayment(invoice, payment)
+}
+
+// RefundPayment returns money paid on an invoice, for example after an
+// overcharge was corrected.
+func (s *BillingService) RefundPayment(patientID, id uint, req RefundPaymentRequest, userID uint, userRole models.UserRole) (*models.InvoiceResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PaymentWrite); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !invoice.Status.IsOpen() {
+		return nil, errors.New("refunds can only be made on issued invoices")
+	}
+	if req.AmountCents > invoice.NetPaidCents() {
+		return nil, repository.ErrRefundExceedsPaid
+	}
+
+	refund := &models.Payment{
+		Kind:         models.PaymentKindRefund,
+		AmountCents:  req.AmountCents,
+		Method:       req.Method,
+		Reference:    strings.TrimSpace(req.Reference),
+		Notes:        req.Reason,
+		ReceivedByID: userID,
+		ReceivedAt:   time.Now(),
+	}
+
+	return s.recordPayment(invoice, refund)
+}
+
+// PrintInvoice renders the invoice as a printable HTML page.
+func (s *BillingService) PrintInvoice(patientID, id uint, userRole models.UserRole) ([]byte, error) {
+	if err := s.policy.Authorize(userRole, authz.InvoiceRead); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := billing.RenderInvoice(invoice, s.printOptions)
+	if err != nil {
+		return nil, errors.New("failed to render invoice")
+	}
+	return page, nil
+}
+
+// buildItems prices invoice lines from the price list.
+func (s *BillingService) buildItems(inputs []InvoiceItemInput) ([]models.InvoiceItem, error) {
+	ids := make([]uint, len(inputs))
+	for i, input := range inputs {
+		ids[i] = input.PriceListItemID
+	}
+
+	priced, err := s.priceListRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, errors.New("failed to retrieve price list")
+	}
+	byID := make(map[uint]*models.PriceListItem, len(priced))
+	for _, item := range priced {
+		byID[item.ID] = item
+	}
+
+	items := make([]models.InvoiceItem, len(inputs))
+	for i, input := range inputs {
+		priceListItem, ok := byID[input.PriceListItemID]
+		if !ok || !priceListItem.IsActive {
+			return nil, errors.New("price list item not found")
+		}
+
+		description := strings.TrimSpace(input.Description)
+		if description == "" {
+			description = priceListItem.Name
+		}
+
+		amounts := billing.Line{
+			Quantity:        input.Quantity,
+			UnitPriceCents:  priceListItem.UnitPriceCents,
+			DiscountPercent: input.DiscountPercent,
+			TaxRatePercent:  priceListItem.TaxRatePercent,
+		}.Amounts()
+
+		priceListItemID := priceListItem.ID
+		items[i] = models.InvoiceItem{
+			PriceListItemID: &priceListItemID,
+			Code:            priceListItem.Code,
+			Description:     description,
+			Quantity:        input.Quantity,
+			UnitPriceCents:  priceListItem.UnitPriceCents,
+			DiscountPercent: input.DiscountPercent,
+			DiscountCents:   amounts.DiscountCents,
+			TaxRatePercent:  priceListItem.TaxRatePercent,
+			TaxCents:        amounts.TaxCents,
+			TotalCents:      amounts.TotalCents,
+		}
+	}
+	return items, nil
+}
+
+func (s *BillingService) recordPayment(invoice *models.Invoice, payment *models.Payment) (*models.InvoiceResponse, error) {
+	if err := s.invoiceRepo.RecordPayment(invoice, payment); err != nil {
+		if errors.Is(err, repository.ErrInvoiceStatusChanged) ||
+			errors.Is(err, repository.ErrPaymentExceedsBalance) ||
+			errors.Is(err, repository.ErrRefundExceedsPaid) {
+			return nil, err
+		}
+		return nil, errors.New("failed to record payment")
+	}
+
+	return s.reload(invoice.PatientID, invoice.ID)
+}
+
+func (s *BillingService) transition(invoice *models.Invoice, from models.InvoiceStatus) error {
+	if err := s.invoiceRepo.Transition(invoice, from); err != nil {
+		if errors.Is(err, repository.ErrInvoiceStatusChanged) {
+			return err
+		}
+		return errors.New("failed to update invoice")
+	}
+	return nil
+}
+
+func (s *BillingService) reload(patientID, id uint) (*models.InvoiceResponse, error) {
+	invoice, err := s.invoiceRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve invoice")
+	}
+
+	response := invoice.ToResponse()
+	return &response, nil
+}
+
+func toInvoiceResponses(invoices []*models.Invoice) []models.InvoiceResponse {
+	responses := make([]models.InvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		responses[i] = invoice.ToResponse()
+	}
+	return responses
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/billing"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateClaimRequest struct {
+	InvoiceID      uint     `json:"invoice_id" binding:"required"`
+	PolicyID       uint     `json:"policy_id" binding:"required"`
+	DiagnosisCodes []string `json:"diagnosis_codes" binding:"required,min=1,max=12"`
+}
+
+type AcceptClaimRequest struct {
+	PayerClaimRef string `json:"payer_claim_ref" binding:"max=50"`
+}
+
+type DenyClaimRequest struct {
+	Reason        string `json:"reason" binding:"required,max=1000"`
+	PayerClaimRef string `json:"payer_claim_ref" binding:"max=50"`
+}
+
+type PayClaimRequest struct {
+	PaidCents     int64  `json:"paid_cents" binding:"required,min=1"`
+	PayerClaimRef string `json:"payer_claim_ref" binding:"max=50"`
+	Reference     string `json:"reference" binding:"max=100"` // remittance or cheque number
+}
+
+// ReopenClaimRequest can correct the diagnoses of a denied claim before it
+// is submitted again.
+type ReopenClaimRequest struct {
+	DiagnosisCodes []string `json:"diagnosis_codes" binding:"omitempty,min=1,max=12"`
+}
+
+type CreateClaimBatchRequest struct {
+	PayerID uint `json:"payer_id"` // only submit claims to this payer; all payers if zero
+}
+
+type ClaimQuery struct {
+	Status  models.ClaimStatus
+	PayerID uint
+}
+
+type ClaimListResponse struct {
+	Claims     []models.ClaimResponse `json:"claims"`
+	Pagination PaginationResponse     `json:"pagination"`
+}
+
+type ClaimBatchResponse struct {
+	Batch  *models.ClaimBatch     `json:"batch"`
+	Claims []models.ClaimResponse `json:"claims,omitempty"`
+}
+
+// ClaimService tracks insurance claims for issued invoices, from draft
+// through batch submission to the payer's decision. Payments on a claim are
+// posted to its invoice.
+type ClaimService struct {
+	claimRepo     repository.ClaimRepository
+	invoiceRepo   repository.InvoiceRepository
+	insuranceRepo repository.InsuranceRepository
+	encounterRepo repository.EncounterRepository
+	admissionRepo repository.AdmissionRepository
+	icd10Repo     repository.ICD10Repository
+	submitter     billing.ClaimSubmitter
+	policy        *authz.Policy
+}
+
+func NewClaimService(claimRepo repository.ClaimRepository, invoiceRepo repository.InvoiceRepository, insuranceRepo repository.InsuranceRepository, encounterRepo repository.EncounterRepository, admissionRepo repository.AdmissionRepository, icd10Repo repository.ICD10Repository, submitter billing.ClaimSubmitter, policy *authz.Policy) *ClaimService {
+	return &ClaimService{
+		claimRepo:     claimRepo,
+		invoiceRepo:   invoiceRepo,
+		insuranceRepo: insuranceRepo,
+		encounterRepo: encounterRepo,
+		admissionRepo: admissionRepo,
+		icd10Repo:     icd10Repo,
+		submitter:     submitter,
+		policy:        policy,
+	}
+}
+
+// CreateClaim drafts a claim for an issued invoice. The policy has to cover
+// the date of the encounter or admission the invoice is for.
+func (s *ClaimService) CreateClaim(patientID uint, req CreateClaimRequest, userID uint, userRole models.UserRole) (*models.ClaimResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimWrite); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(patientID, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.Status.IsOpen() {
+		return nil, errors.New("claims can only be made for issued invoices")
+	}
+
+	policy, err := s.insuranceRepo.GetPolicy(patientID, req.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceDate, err := s.serviceDate(invoice)
+	if err != nil {
+		return nil, err
+	}
+	if ineligibilityReason(policy, serviceDate) != "" {
+		return nil, errors.New("insurance policy does not cover the service date")
+	}
+
+	diagnosisCodes, err := s.diagnosisCodes(req.DiagnosisCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &models.Claim{
+		PatientID:      patientID,
+		InvoiceID:      invoice.ID,
+		PolicyID:       policy.ID,
+		Status:         models.ClaimStatusDraft,
+		ServiceDate:    serviceDate,
+		DiagnosisCodes: diagnosisCodes,
+		BilledCents:    invoice.TotalCents,
+		CreatedByID:    userID,
+	}
+
+	if err := s.claimRepo.Create(claim); err != nil {
+		if errors.Is(err, repository.ErrClaimExists) || errors.Is(err, repository.ErrInvoiceStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to create claim")
+	}
+
+	return s.reload(patientID, claim.ID)
+}
+
+func (s *ClaimService) GetClaim(patientID, id uint, userRole models.UserRole) (*models.ClaimResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimRead); err != nil {
+		return nil, err
+	}
+
+	claim, err := s.claimRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := claim.ToResponse()
+	return &response, nil
+}
+
+func (s *ClaimService) ListClaims(patientID uint, status models.ClaimStatus, userRole models.UserRole) ([]models.ClaimResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimRead); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.claimRepo.ListByPatient(patientID, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve claims")
+	}
+
+	return toClaimResponses(claims), nil
+}
+
+// SearchClaims lists claims across patients for the billing office's work
+// queues, such as all drafts or all claims awaiting a decision.
+func (s *ClaimService) SearchClaims(req ClaimQuery, page, pageSize int, userRole models.UserRole) (*ClaimListResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimRead); err != nil {
+		return nil, err
+	}
+
+	filter := repository.ClaimFilter{Status: req.Status, PayerID: req.PayerID}
+	offset := (page - 1) * pageSize
+
+	claims, err := s.claimRepo.Search(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve claims")
+	}
+
+	total, err := s.claimRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("failed to count claims")
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &ClaimListResponse{
+		Claims: toClaimResponses(claims),
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+func (s *ClaimService) AcceptClaim(patientID, id uint, req AcceptClaimRequest, userRole models.UserRole) (*models.ClaimResponse, error) {
+	return s.adjudicate(patientID, id, userRole, func(claim *models.Claim) error {
+		if claim.Status != models.ClaimStatusSubmitted {
+			return errors.New("only submitted claims can be accepted")
+		}
+		claim.Status = models.ClaimStatusAccepted
+		setPayerClaimRef(claim, req.PayerClaimRef)
+		return nil
+	})
+}
+
+func (s *ClaimService) DenyClaim(patientID, id uint, req DenyClaimRequest, userRole models.UserRole) (*models.ClaimResponse, error) {
+	return s.adjudicate(patientID, id, userRole, func(claim *models.Claim) error {
+		if claim.Status != models.ClaimStatusSubmitted && claim.Status != models.ClaimStatusAccepted {
+			return errors.New("only submitted or accepted claims can be denied")
+		}
+		claim.Status = models.ClaimStatusDenied
+		claim.DenialReason = strings.TrimSpace(req.Reason)
+		setPayerClaimRef(claim, req.PayerClaimRef)
+		return nil
+	})
+}
+
+// PayClaim records the payer's payment and posts it to the invoice as an
+// insurance payment.
+func (s *ClaimService) PayClaim(patientID, id uint, req PayClaimRequest, userID uint, userRole models.UserRole) (*models.ClaimResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimWrite); err != nil {
+		return nil, err
+	}
+
+	claim, err := s.claimRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := claim.Status
+	if from != models.ClaimStatusSubmitted && from != models.ClaimStatusAccepted {
+		return nil, errors.New("only submitted or accepted claims can be paid")
+	}
+	if req.PaidCents > claim.BilledCents {
+		return nil, errors.New("paid amount cannot exceed the billed amount")
+	}
+
+	now := time.Now()
+	claim.Status = models.ClaimStatusPaid
+	claim.PaidCents = req.PaidCents
+	claim.PaidAt = &now
+	if claim.AdjudicatedAt == nil {
+		claim.AdjudicatedAt = &now
+	}
+	setPayerClaimRef(claim, req.PayerClaimRef)
+
+	reference := strings.TrimSpace(req.Reference)
+	if reference == "" {
+		reference = claim.ClaimNumber
+	}
+	payment := &models.Payment{
+		Kind:         models.PaymentKindPayment,
+		AmountCents:  req.PaidCents,
+		Method:       models.PaymentMethodInsurance,
+		Reference:    reference,
+		Notes:        "Insurance payment for claim " + claim.ClaimNumber,
+		ReceivedByID: userID,
+		ReceivedAt:   now,
+	}
+
+	if err := s.claimRepo.MarkPaid(claim, payment, from); err != nil {
+		if errors.Is(err, repository.ErrClaimStatusChanged) ||
+			errors.Is(err, repository.ErrInvoiceStatusChanged) ||
+			errors.Is(err, repository.ErrPaymentExceedsBalance) {
+			return nil, err
+		}
+		return nil, errors.New("failed to record claim payment")
+	}
+
+	return s.reload(patientID, claim.ID)
+}
+
+// ReopenClaim turns a denied claim back into a draft so that it is picked up
+// by the next batch. The payer's claim reference is kept, which makes the
+// resubmission a replacement of the original claim.
+func (s *ClaimService) ReopenClaim(patientID, id uint, req ReopenClaimRequest, userRole models.UserRole) (*models.ClaimResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimWrite); err != nil {
+		return nil, err
+	}
+
+	claim, err := s.claimRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if claim.Status != models.ClaimStatusDenied {
+		return nil, errors.New("only denied claims can be reopened")
+	}
+	if claim.Invoice != nil && claim.Invoice.Status == models.InvoiceStatusVoid {
+		return nil, errors.New("claims on void invoices cannot be reopened")
+	}
+
+	if len(req.DiagnosisCodes) > 0 {
+		claim.DiagnosisCodes, err = s.diagnosisCodes(req.DiagnosisCodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	claim.Status = models.ClaimStatusDraft
+	claim.DenialReason = ""
+	claim.BatchID = nil
+	claim.SubmittedAt = nil
+	claim.SubmittedByID = nil
+	claim.AdjudicatedAt = nil
+
+	if err := s.transition(claim, models.ClaimStatusDenied); err != nil {
+		return nil, err
+	}
+
+	return s.reload(patientID, claim.ID)
+}
+
+// CreateBatch submits every draft claim, or every draft claim to one payer,
+// as a single X12 837P file.
+func (s *ClaimService) CreateBatch(req CreateClaimBatchRequest, userID uint, userRole models.UserRole) (*ClaimBatchResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimWrite); err != nil {
+		return nil, err
+	}
+
+	if s.submitter.SubmitterID == "" || s.submitter.ReceiverID == "" || s.submitter.NPI == "" {
+		return nil, errors.New("claim submission is not configured")
+	}
+
+	var payerID *uint
+	if req.PayerID != 0 {
+		if _, err := s.insuranceRepo.GetPayer(req.PayerID); err != nil {
+			return nil, err
+		}
+		payerID = &req.PayerID
+	}
+
+	claims, err := s.claimRepo.ListDrafts(req.PayerID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve draft claims")
+	}
+	if len(claims) == 0 {
+		return nil, errors.New("no draft claims to submit")
+	}
+
+	batch := &models.ClaimBatch{
+		PayerID:     payerID,
+		Format:      billing.ClaimFormat837P,
+		ClaimCount:  len(claims),
+		CreatedByID: userID,
+	}
+	for _, claim := range claims {
+		batch.TotalBilledCents += claim.BilledCents
+	}
+
+	err = s.claimRepo.CreateBatch(batch, claims, func(batch *models.ClaimBatch) (string, error) {
+		return billing.RenderClaims837(batch, claims, s.submitter, time.Now())
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrClaimStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to create claim batch")
+	}
+
+	return &ClaimBatchResponse{Batch: batch, Claims: toClaimResponses(claims)}, nil
+}
+
+func (s *ClaimService) GetBatch(id uint, userRole models.UserRole) (*models.ClaimBatch, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimRead); err != nil {
+		return nil, err
+	}
+
+	return s.claimRepo.GetBatch(id)
+}
+
+func (s *ClaimService) adjudicate(patientID, id uint, userRole models.UserRole, apply func(claim *models.Claim) error) (*models.ClaimResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ClaimWrite); err != nil {
+		return nil, err
+	}
+
+	claim, err := s.claimRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := claim.Status
+	if err := apply(claim); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	claim.AdjudicatedAt = &now
+
+	if err := s.transition(claim, from); err != nil {
+		return nil, err
+	}
+
+	return s.reload(patientID, claim.ID)
+}
+
+// serviceDate is the date of the encounter or admission the invoice bills.
+func (s *ClaimService) serviceDate(invoice *models.Invoice) (time.Time, error) {
+	switch {
+	case invoice.EncounterID != nil:
+		encounter, err := s.encounterRepo.GetByID(invoice.PatientID, *invoice.EncounterID)
+		if err != nil {
+			return time.Time{}, errors.New("failed to retrieve encounter")
+		}
+		return encounter.EncounterDate, nil
+	case invoice.AdmissionID != nil:
+		admission, err := s.admissionRepo.GetByID(invoice.PatientID, *invoice.AdmissionID)
+		if err != nil {
+			return time.Time{}, errors.New("failed to retrieve admission")
+		}
+		return admission.AdmittedAt, nil
+	case invoice.IssuedAt != nil:
+		return *invoice.IssuedAt, nil
+	default:
+		return invoice.CreatedAt, nil
+	}
+}
+
+// diagnosisCodes normalizes the codes and checks them against the ICD-10
+// code table, dropping duplicates.
+func (s *ClaimService) diagnosisCodes(codes []string) ([]string, error) {
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		code, err := NormalizeICD10Code(code)
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		if _, err := s.icd10Repo.GetByCode(code); err != nil {
+			return nil, errors.New("ICD-10 code not found")
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return normalized, nil
+}
+
+func (s *ClaimService) transition(claim *models.Claim, from models.ClaimStatus) error {
+	if err := s.claimRepo.Transition(claim, from); err != nil {
+		if errors.Is(err, repository.ErrClaimStatusChanged) {
+			return err
+		}
+		return errors.New("failed to update claim")
+	}
+	return nil
+}
+
+func (s *ClaimService) reload(patientID, id uint) (*models.ClaimResponse, error) {
+	claim, err := s.claimRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve claim")
+	}
+
+	response := claim.ToResponse()
+	return &response, nil
+}
+
+func setPayerClaimRef(claim *models.Claim, ref string) {
+	if ref = strings.TrimSpace(ref); ref != "" {
+		claim.PayerClaimRef = ref
+	}
+}
+
+func toClaimResponses(claims []*models.Claim) []models.ClaimResponse {
+	responses := make([]models.ClaimResponse, len(claims))
+	for i, claim := range claims {
+		responses[i] = claim.ToResponse()
+	}
+	return responses
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type EDCheckInRequest struct {
+	PatientID      uint   `json:"patient_id" binding:"required"`
+	TriageLevel    int    `json:"triage_level" binding:"required,min=1,max=5"`
+	ChiefComplaint string `json:"chief_complaint" binding:"required,max=1000"`
+	ArrivedAt      string `json:"arrived_at"` // RFC3339, defaults to now
+}
+
+type EDRetriageRequest struct {
+	TriageLevel int `json:"triage_level" binding:"required,min=1,max=5"`
+}
+
+type EDAssignDoctorRequest struct {
+	DoctorID uint `json:"doctor_id" binding:"required"`
+}
+
+type EDCompleteRequest struct {
+	Disposition models.EDDisposition `json:"disposition" binding:"required,oneof=discharged admitted transferred deceased"`
+	Notes       string               `json:"notes" binding:"max=5000"`
+}
+
+type EDLeftRequest struct {
+	Notes string `json:"notes" binding:"max=5000"`
+}
+
+type EDMetricsQuery struct {
+	From string // RFC3339, defaults to 7 days before To
+	To   string // RFC3339, defaults to now
+}
+
+// EDQueueResponse is the live queue, most acute and longest waiting first.
+type EDQueueResponse struct {
+	Visits         []models.EDVisitResponse `json:"visits"`
+	WaitingByLevel map[int]int              `json:"waiting_by_level"`
+}
+
+type EDMetricsResponse struct {
+	From                      time.Time            `json:"from"`
+	To                        time.Time            `json:"to"`
+	Visits                    int                  `json:"visits"`
+	Seen                      int                  `json:"seen"`
+	LeftWithoutBeingSeen      int                  `json:"left_without_being_seen"`
+	MedianDoorToDoctorMinutes *float64             `json:"median_door_to_doctor_minutes"`
+	ByTriageLevel             []EDTriageLevelStats `json:"by_triage_level"`
+}
+
+type EDTriageLevelStats struct {
+	TriageLevel                int      `json:"triage_level"`
+	Visits                     int      `json:"visits"`
+	Seen                       int      `json:"seen"`
+	MedianDoorToDoctorMinutes  *float64 `json:"median_door_to_doctor_minutes"`
+	AverageDoorToDoctorMinutes *float64 `json:"average_door_to_doctor_minutes"`
+}
+
+const maxEDMetricsRange = 366 * 24 * time.Hour
+
+type EDService struct {
+	edVisitRepo repository.EDVisitRepository
+	patientRepo repository.PatientRepository
+	userRepo    repository.UserRepository
+	policy      *authz.Policy
+}
+
+func NewEDService(edVisitRepo repository.EDVisitRepository, patientRepo repository.PatientRepository, userRepo repository.UserRepository, policy *authz.Policy) *EDService {
+	return &EDService{
+		edVisitRepo: edVisitRepo,
+		patientRepo: patientRepo,
+		userRepo:    userRepo,
+		policy:      policy,
+	}
+}
+
+// CheckIn registers a patient's arrival in the emergency department with
+// their triage level. Walk-ins are registered with CreatePatient first.
+func (s *EDService) CheckIn(req EDCheckInRequest, userID uint, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitTriage); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(req.PatientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	now := time.Now()
+	arrivedAt := now
+	if req.ArrivedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ArrivedAt)
+		if err != nil {
+			return nil, errors.New("invalid arrived_at format, use RFC3339")
+		}
+		if parsed.After(now) {
+			return nil, errors.New("arrived_at cannot be in the future")
+		}
+		arrivedAt = parsed
+	}
+
+	visit := &models.EDVisit{
+		PatientID:      req.PatientID,
+		TriageLevel:    req.TriageLevel,
+		ChiefComplaint: strings.TrimSpace(req.ChiefComplaint),
+		Status:         models.EDVisitStatusWaiting,
+		ArrivedAt:      arrivedAt,
+		TriagedAt:      now,
+		TriagedByID:    userID,
+	}
+
+	if err := s.edVisitRepo.Create(visit); err != nil {
+		if errors.Is(err, repository.ErrPatientAlreadyInED) {
+			return nil, err
+		}
+		return nil, errors.New("failed to check in patient")
+	}
+
+	return s.reload(visit.ID)
+}
+
+func (s *EDService) GetVisit(id uint, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitRead); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := visit.ToResponse(time.Now())
+	return &response, nil
+}
+
+func (s *EDService) Queue(includeInTreatment bool, userRole models.UserRole) (*EDQueueResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitRead); err != nil {
+		return nil, err
+	}
+
+	visits, err := s.edVisitRepo.ListQueue(includeInTreatment)
+	if err != nil {
+		return nil, errors.New("failed to retrieve emergency queue")
+	}
+
+	now := time.Now()
+	queue := &EDQueueResponse{
+		Visits:         make([]models.EDVisitResponse, len(visits)),
+		WaitingByLevel: make(map[int]int),
+	}
+	for i, visit := range visits {
+		queue.Visits[i] = visit.ToResponse(now)
+		if visit.Status != models.EDVisitStatusInTreatment {
+			queue.WaitingByLevel[visit.TriageLevel]++
+		}
+	}
+	return queue, nil
+}
+
+// Retriage changes the triage level of a patient who has not been seen yet,
+// which moves them in the queue.
+func (s *EDService) Retriage(id uint, req EDRetriageRequest, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitTriage); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !awaitingDoctor(visit) {
+		return nil, errors.New("only patients waiting to be seen can be re-triaged")
+	}
+
+	visit.TriageLevel = req.TriageLevel
+	return s.transition(visit, visit.Status)
+}
+
+func (s *EDService) AssignDoctor(id uint, req EDAssignDoctorRequest, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitTriage); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !awaitingDoctor(visit) {
+		return nil, errors.New("only patients waiting to be seen can be assigned")
+	}
+
+	doctor, err := s.userRepo.GetByID(req.DoctorID)
+	if err != nil || !s.policy.Can(doctor.Role, authz.EDVisitTreat) {
+		return nil, errors.New("doctor not found")
+	}
+
+	from := visit.Status
+	now := time.Now()
+	visit.Status = models.EDVisitStatusAssigned
+	visit.AssignedDoctorID = &doctor.ID
+	visit.AssignedDoctor = doctor
+	visit.AssignedAt = &now
+
+	return s.transition(visit, from)
+}
+
+// StartTreatment records that a doctor has seen the patient, which ends the
+// door-to-doctor interval. A patient assigned to another doctor has to be
+// reassigned first; an unassigned patient is assigned to the caller.
+func (s *EDService) StartTreatment(id uint, doctorID uint, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitTreat); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !awaitingDoctor(visit) {
+		return nil, errors.New("only patients waiting to be seen can be started")
+	}
+	if visit.AssignedDoctorID != nil && *visit.AssignedDoctorID != doctorID {
+		return nil, errors.New("patient is assigned to another doctor")
+	}
+
+	from := visit.Status
+	now := time.Now()
+	if visit.AssignedDoctorID == nil {
+		visit.AssignedDoctorID = &doctorID
+		visit.AssignedAt = &now
+	}
+	visit.Status = models.EDVisitStatusInTreatment
+	visit.SeenAt = &now
+
+	if _, err := s.transition(visit, from); err != nil {
+		return nil, err
+	}
+	return s.reload(visit.ID)
+}
+
+func (s *EDService) Complete(id uint, req EDCompleteRequest, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitTreat); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if visit.Status != models.EDVisitStatusInTreatment {
+		return nil, errors.New("only patients in treatment can be completed")
+	}
+
+	now := time.Now()
+	visit.Status = models.EDVisitStatusCompleted
+	visit.CompletedAt = &now
+	visit.Disposition = req.Disposition
+	visit.Notes = req.Notes
+
+	return s.transition(visit, models.EDVisitStatusInTreatment)
+}
+
+// LeftWithoutBeingSeen closes the visit of a patient who left the queue.
+func (s *EDService) LeftWithoutBeingSeen(id uint, req EDLeftRequest, userRole models.UserRole) (*models.EDVisitResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitTriage); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !awaitingDoctor(visit) {
+		return nil, errors.New("only patients waiting to be seen can leave without being seen")
+	}
+
+	from := visit.Status
+	now := time.Now()
+	visit.Status = models.EDVisitStatusLeft
+	visit.CompletedAt = &now
+	visit.Notes = req.Notes
+
+	return s.transition(visit, from)
+}
+
+// Metrics summarizes door-to-doctor times for visits that arrived in the
+// period, overall and by triage level.
+func (s *EDService) Metrics(req EDMetricsQuery, userRole models.UserRole) (*EDMetricsResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EDVisitRead); err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return nil, errors.New("invalid to format, use RFC3339")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -7)
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return nil, errors.New("invalid from format, use RFC3339")
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	if to.Sub(from) > maxEDMetricsRange {
+		return nil, errors.New("metrics range cannot exceed one year")
+	}
+
+	visits, err := s.edVisitRepo.ListArrivedBetween(from, to)
+	if err != nil {
+		return nil, errors.New("failed to retrieve emergency visits")
+	}
+
+	metrics := &EDMetricsResponse{From: from, To: to, Visits: len(visits)}
+	var all []float64
+	byLevel := make(map[int][]float64)
+	levelVisits := make(map[int]int)
+
+	for _, visit := range visits {
+		levelVisits[visit.TriageLevel]++
+		if visit.Status == models.EDVisitStatusLeft {
+			metrics.LeftWithoutBeingSeen++
+		}
+		if doorToDoctor := visit.DoorToDoctor(); doorToDoctor != nil {
+			minutes := doorToDoctor.Minutes()
+			all = append(all, minutes)
+			byLevel[visit.TriageLevel] = append(byLevel[visit.TriageLevel], minutes)
+		}
+	}
+
+	metrics.Seen = len(all)
+	metrics.MedianDoorToDoctorMinutes = median(all)
+	for level := models.TriageLevelResuscitation; level <= models.TriageLevelNonUrgent; level++ {
+		metrics.ByTriageLevel = append(metrics.ByTriageLevel, EDTriageLevelStats{
+			TriageLevel:                level,
+			Visits:                     levelVisits[level],
+			Seen:                       len(byLevel[level]),
+			MedianDoorToDoctorMinutes:  median(byLevel[level]),
+			AverageDoorToDoctorMinutes: average(byLevel[level]),
+		})
+	}
+
+	return metrics, nil
+}
+
+func (s *EDService) transition(visit *models.EDVisit, from models.EDVisitStatus) (*models.EDVisitResponse, error) {
+	if err := s.edVisitRepo.Transition(visit, from); err != nil {
+		if errors.Is(err, repository.ErrEDVisitStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update emergency visit")
+	}
+
+	response := visit.ToResponse(time.Now())
+	return &response, nil
+}
+
+func (s *EDService) reload(id uint) (*models.EDVisitResponse, error) {
+	visit, err := s.edVisitRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve emergency visit")
+	}
+
+	response := visit.ToResponse(time.Now())
+	return &response, nil
+}
+
+func awaitingDoctor(visit *models.EDVisit) bool {
+	return visit.Status == models.EDVisitStatusWaiting || visit.Status == models.EDVisitStatusAssigned
+}
+
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	result := sorted[mid]
+	if len(sorted)%2 == 0 {
+		result = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return &result
+}
+
+func average(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	result := sum / float64(len(values))
+	return &result
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateEncounterRequest struct {
+	AppointmentID *uint      `json:"appointment_id"`
+	EncounterDate *time.Time `json:"encounter_date"`
+	Subjective    string     `json:"subjective" binding:"max=20000"`
+	Objective     string     `json:"objective" binding:"max=20000"`
+	Assessment    string     `json:"assessment" binding:"max=20000"`
+	Plan          string     `json:"plan" binding:"max=20000"`
+}
+
+type UpdateEncounterRequest struct {
+	EncounterDate *time.Time `json:"encounter_date"`
+	Subjective    *string    `json:"subjective" binding:"omitempty,max=20000"`
+	Objective     *string    `json:"objective" binding:"omitempty,max=20000"`
+	Assessment    *string    `json:"assessment" binding:"omitempty,max=20000"`
+	Plan          *string    `json:"plan" binding:"omitempty,max=20000"`
+}
+
+type AddAddendumRequest struct {
+	Content string `json:"content" binding:"required,max=20000"`
+}
+
+type EncounterListResponse struct {
+	Encounters []models.EncounterResponse `json:"encounters"`
+	Pagination PaginationResponse         `json:"pagination"`
+}
+
+type EncounterService struct {
+	encounterRepo   repository.EncounterRepository
+	patientRepo     repository.PatientRepository
+	appointmentRepo repository.AppointmentRepository
+	policy          *authz.Policy
+}
+
+func NewEncounterService(encounterRepo repository.EncounterRepository, patientRepo repository.PatientRepository, appointmentRepo repository.AppointmentRepository, policy *authz.Policy) *EncounterService {
+	return &EncounterService{
+		encounterRepo:   encounterRepo,
+		patientRepo:     patientRepo,
+		appointmentRepo: appointmentRepo,
+		policy:          policy,
+	}
+}
+
+func (s *EncounterService) CreateEncounter(patientID uint, req CreateEncounterRequest, authorID uint, userRole models.UserRole) (*models.EncounterResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EncounterWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	if req.AppointmentID != nil {
+		appointment, err := s.appointmentRepo.GetByID(*req.AppointmentID)
+		if err != nil || appointment.PatientID != patientID {
+			return nil, errors.New("appointment not found for this patient")
+		}
+	}
+
+	encounterDate := time.Now()
+	if req.EncounterDate != nil {
+		encounterDate = *req.EncounterDate
+	}
+
+	encounter := &models.Encounter{
+		PatientID:     patientID,
+		AppointmentID: req.AppointmentID,
+		AuthorID:      authorID,
+		EncounterDate: encounterDate.UTC(),
+		Status:        models.EncounterStatusDraft,
+		Subjective:    req.Subjective,
+		Objective:     req.Objective,
+		Assessment:    req.Assessment,
+		Plan:          req.Plan,
+	}
+
+	if err := s.encounterRepo.Create(encounter); err != nil {
+		return nil, errors.New("failed to create encounter")
+	}
+
+	return s.reload(patientID, encounter.ID)
+}
+
+func (s *EncounterService) GetEncounter(patientID, id uint, userRole models.UserRole) (*models.EncounterResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EncounterRead); err != nil {
+		return nil, err
+	}
+
+	encounter, err := s.encounterRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := encounter.ToResponse()
+	return &response, nil
+}
+
+// ListEncounters returns the patient's encounter timeline, most recent first.
+func (s *EncounterService) ListEncounters(patientID uint, page, pageSize int, userRole models.UserRole) (*EncounterListResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EncounterRead); err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * pageSize
+
+	encounters, err := s.encounterRepo.ListByPatient(patientID, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve encounters")
+	}
+
+	total, err := s.encounterRepo.CountByPatient(patientID)
+	if err != nil {
+		return nil, errors.New("failed to count encounters")
+	}
+
+	encounterResponses := make([]models.EncounterResponse, len(encounters))
+	for i, encounter := range encounters {
+		encounterResponses[i] = encounter.ToResponse()
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &EncounterListResponse{
+		Encounters: encounterResponses,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+// UpdateEncounter edits the SOAP note of a draft. Only the author may edit,
+// and signed encounters are locked.
+func (s *EncounterService) UpdateEncounter(patientID, id uint, req UpdateEncounterRequest, userID uint, userRole models.UserRole) (*models.EncounterResponse, error) {
+	encounter, err := s.editableEncounter(patientID, id, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.EncounterDate != nil {
+		encounter.EncounterDate = req.EncounterDate.UTC()
+	}
+	if req.Subjective != nil {
+		encounter.Subjective = *req.Subjective
+	}
+	if req.Objective != nil {
+		encounter.Objective = *req.Objective
+	}
+	if req.Assessment != nil {
+		encounter.Assessment = *req.Assessment
+	}
+	if req.Plan != nil {
+		encounter.Plan = *req.Plan
+	}
+
+	if err := s.encounterRepo.UpdateDraft(encounter); err != nil {
+		if errors.Is(err, repository.ErrEncounterLocked) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update encounter")
+	}
+
+	return s.reload(patientID, id)
+}
+
+func (s *EncounterService) SignEncounter(patientID, id uint, userID uint, userRole models.UserRole) (*models.EncounterResponse, error) {
+	encounter, err := s.editableEncounter(patientID, id, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.encounterRepo.Sign(encounter, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrEncounterLocked) {
+			return nil, err
+		}
+		return nil, errors.New("failed to sign encounter")
+	}
+
+	return s.reload(patientID, id)
+}
+
+// AddAddendum appends a note to a signed encounter. Any user who can write
+// encounters may add one, not just the original author.
+func (s *EncounterService) AddAddendum(patientID, id uint, req AddAddendumRequest, userID uint, userRole models.UserRole) (*models.EncounterResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.EncounterWrite); err != nil {
+		return nil, err
+	}
+
+	encounter, err := s.encounterRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if encounter.Status != models.EncounterStatusSigned {
+		return nil, errors.New("addenda can only be added to signed encounters")
+	}
+
+	addendum := &models.EncounterAddendum{
+		EncounterID: encounter.ID,
+		AuthorID:    userID,
+		Content:     req.Content,
+	}
+
+	if err := s.encounterRepo.CreateAddendum(addendum); err != nil {
+		return nil, errors.New("failed to add addendum")
+	}
+
+	return s.reload(patientID, id)
+}
+
+func (s *EncounterService) editableEncounter(patientID, id uint, userID uint, userRole models.UserRole) (*models.Encounter, error) {
+	if err := s.policy.Authorize(userRole, authz.EncounterWrite); err != nil {
+		return nil, err
+	}
+
+	encounter, err := s.encounterRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if encounter.AuthorID != userID {
+		return nil, authz.ErrForbidden
+	}
+
+	if encounter.Status != models.EncounterStatusDraft {
+		return nil, repository.ErrEncounterLocked
+	}
+
+	return encounter, nil
+}
+
+func (s *EncounterService) reload(patientID, id uint) (*models.EncounterResponse, error) {
+	encounter, err := s.encounterRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve encounter")
+	}
+
+	response := encounter.ToResponse()
+	return &response, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+var icd10CodePattern = regexp.MustCompile(`^[A-Z][0-9][0-9A-Z](\.[0-9A-Z]{1,4})?$`)
+
+type ICD10ImportResult struct {
+	Imported int   `json:"imported"`
+	Total    int64 `json:"total"`
+}
+
+type ICD10Service struct {
+	icd10Repo repository.ICD10Repository
+}
+
+func NewICD10Service(icd10Repo repository.ICD10Repository) *ICD10Service {
+	return &ICD10Service{
+		icd10Repo: icd10Repo,
+	}
+}
+
+// SearchCodes backs the code autocomplete. The query matches code prefixes
+// and words in the description.
+func (s *ICD10Service) SearchCodes(query string, limit int) ([]*models.ICD10Code, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query is required")
+	}
+
+	if code, err := NormalizeICD10Code(query); err == nil {
+		query = code
+	}
+
+	codes, err := s.icd10Repo.Search(query, limit)
+	if err != nil {
+		return nil, errors.New("failed to search ICD-10 codes")
+	}
+	return codes, nil
+}
+
+// ImportCodes loads a code table from CSV with the code in the first column
+// and the description in the second. A header row is skipped. Codes may be
+// given with or without the dot, as in the CMS release files. Existing codes
+// get their description updated.
+func (s *ICD10Service) ImportCodes(reader io.Reader) (*ICD10ImportResult, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	var codes []models.ICD10Code
+	seen := make(map[string]int)
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV on line %d", line)
+		}
+
+		if line == 1 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "code") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d must have a code and a description", line)
+		}
+
+		code, err := NormalizeICD10Code(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		description := strings.TrimSpace(record[1])
+		if description == "" {
+			return nil, fmt.Errorf("line %d: description is required", line)
+		}
+
+		// A later line for the same code wins, as it would in the database.
+		if i, ok := seen[code]; ok {
+			codes[i].Description = description
+			continue
+		}
+		seen[code] = len(codes)
+		codes = append(codes, models.ICD10Code{Code: code, Description: description})
+	}
+
+	if len(codes) == 0 {
+		return nil, errors.New("no ICD-10 codes found in file")
+	}
+
+	if err := s.icd10Repo.Upsert(codes); err != nil {
+		return nil, errors.New("failed to import ICD-10 codes")
+	}
+
+	total, err := s.icd10Repo.Count()
+	if err != nil {
+		return nil, errors.New("failed to count ICD-10 codes")
+	}
+
+	return &ICD10ImportResult{Imported: len(codes), Total: total}, nil
+}
+
+// NormalizeICD10Code upper-cases a code and puts the dot after the
+// three-character category, so "e119" and "E11.9" are the same code.
+func NormalizeICD10Code(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !strings.Contains(code, ".") && len(code) > 3 {
+		code = code[:3] + "." + code[3:]
+	}
+	if !icd10CodePattern.MatchString(code) {
+		return "", errors.New("invalid ICD-10 code")
+	}
+	return code, nil
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateInsurancePayerRequest struct {
+	Name      string `json:"name" binding:"required,max=200"`
+	PayerCode string `json:"payer_code" binding:"required,max=80"`
+	Phone     string `json:"phone" binding:"max=20"`
+	Address   string `json:"address" binding:"max=500"`
+}
+
+type UpdateInsurancePayerRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
+	Phone    *string `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Address  *string `json:"address,omitempty" binding:"omitempty,max=500"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+type CreateInsurancePolicyRequest struct {
+	PayerID                uint                          `json:"payer_id" binding:"required"`
+	PolicyNumber           string                        `json:"policy_number" binding:"required,max=50"`
+	GroupNumber            string                        `json:"group_number" binding:"max=50"`
+	SubscriberName         string                        `json:"subscriber_name" binding:"max=100"`
+	SubscriberRelationship models.SubscriberRelationship `json:"subscriber_relationship" binding:"omitempty,oneof=self spouse child other"`
+	Priority               models.CoveragePriority       `json:"priority" binding:"required,oneof=primary secondary"`
+	CoverageStart          string                        `json:"coverage_start" binding:"required"` // Format: YYYY-MM-DD
+	CoverageEnd            string                        `json:"coverage_end"`                      // Format: YYYY-MM-DD, open-ended if empty
+}
+
+type UpdateInsurancePolicyRequest struct {
+	PolicyNumber *string `json:"policy_number,omitempty" binding:"omitempty,min=1,max=50"`
+	GroupNumber  *string `json:"group_number,omitempty" binding:"omitempty,max=50"`
+	CoverageEnd  *string `json:"coverage_end,omitempty"` // Format: YYYY-MM-DD, empty to clear
+	IsActive     *bool   `json:"is_active,omitempty"`
+}
+
+// PolicyEligibility says whether one policy covers the checked date and, if
+// not, why.
+type PolicyEligibility struct {
+	Policy   *models.InsurancePolicy `json:"policy"`
+	Eligible bool                    `json:"eligible"`
+	Reason   string                  `json:"reason,omitempty"`
+}
+
+type EligibilityResponse struct {
+	PatientID uint                `json:"patient_id"`
+	Date      string              `json:"date"`
+	Eligible  bool                `json:"eligible"`
+	Policies  []PolicyEligibility `json:"policies"`
+}
+
+// InsuranceService manages insurance payers and the patients' policies with
+// them. Eligibility is checked against the policy's coverage dates only;
+// there is no real-time check with the payer.
+type InsuranceService struct {
+	insuranceRepo repository.InsuranceRepository
+	patientRepo   repository.PatientRepository
+	policy        *authz.Policy
+}
+
+func NewInsuranceService(insuranceRepo repository.InsuranceRepository, patientRepo repository.PatientRepository, policy *authz.Policy) *InsuranceService {
+	return &InsuranceService{
+		insuranceRepo: insuranceRepo,
+		patientRepo:   patientRepo,
+		policy:        policy,
+	}
+}
+
+func (s *InsuranceService) ListPayers(includeInactive bool) ([]*models.InsurancePayer, error) {
+	payers, err := s.insuranceRepo.ListPayers(includeInactive)
+	if err != nil {
+		return nil, errors.New("failed to retrieve insurance payers")
+	}
+	return payers, nil
+}
+
+func (s *InsuranceService) CreatePayer(req CreateInsurancePayerRequest) (*models.InsurancePayer, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.PayerCode))
+	name := strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return nil, errors.New("payer name and code are required")
+	}
+
+	if _, err := s.insuranceRepo.GetPayerByCode(code); err == nil {
+		return nil, errors.New("insurance payer already exists")
+	}
+
+	payer := &models.InsurancePayer{
+		Name:      name,
+		PayerCode: code,
+		Phone:     strings.TrimSpace(req.Phone),
+		Address:   strings.TrimSpace(req.Address),
+		IsActive:  true,
+	}
+
+	if err := s.insuranceRepo.CreatePayer(payer); err != nil {
+		return nil, errors.New("failed to create insurance payer")
+	}
+
+	return payer, nil
+}
+
+func (s *InsuranceService) UpdatePayer(id uint, req UpdateInsurancePayerRequest) (*models.InsurancePayer, error) {
+	payer, err := s.insuranceRepo.GetPayer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		payer.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Phone != nil {
+		payer.Phone = strings.TrimSpace(*req.Phone)
+	}
+	if req.Address != nil {
+		payer.Address = strings.TrimSpace(*req.Address)
+	}
+	if req.IsActive != nil {
+		payer.IsActive = *req.IsActive
+	}
+
+	if err := s.insuranceRepo.UpdatePayer(payer); err != nil {
+		return nil, errors.New("failed to update insurance payer")
+	}
+
+	return payer, nil
+}
+
+func (s *InsuranceService) ListPolicies(patientID uint, includeInactive bool, userRole models.UserRole) ([]*models.InsurancePolicy, error) {
+	if err := s.policy.Authorize(userRole, authz.InsurancePolicyRead); err != nil {
+		return nil, err
+	}
+
+	policies, err := s.insuranceRepo.ListPolicies(patientID, includeInactive)
+	if err != nil {
+		return nil, errors.New("failed to retrieve insurance policies")
+	}
+	return policies, nil
+}
+
+func (s *InsuranceService) GetPolicy(patientID, id uint, userRole models.UserRole) (*models.InsurancePolicy, error) {
+	if err := s.policy.Authorize(userRole, authz.InsurancePolicyRead); err != nil {
+		return nil, err
+	}
+
+	return s.insuranceRepo.GetPolicy(patientID, id)
+}
+
+func (s *InsuranceService) CreatePolicy(patientID uint, req CreateInsurancePolicyRequest, userID uint, userRole models.UserRole) (*models.InsurancePolicy, error) {
+	if err := s.policy.Authorize(userRole, authz.InsurancePolicyWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	payer, err := s.insuranceRepo.GetPayer(req.PayerID)
+	if err != nil || !payer.IsActive {
+		return nil, errors.New("insurance payer not found")
+	}
+
+	relationship := req.SubscriberRelationship
+	if relationship == "" {
+		relationship = models.SubscriberRelationshipSelf
+	}
+	subscriberName := strings.TrimSpace(req.SubscriberName)
+	if relationship != models.SubscriberRelationshipSelf && subscriberName == "" {
+		return nil, errors.New("subscriber name is required when the patient is not the subscriber")
+	}
+
+	coverageStart, err := time.Parse("2006-01-02", req.CoverageStart)
+	if err != nil {
+		return nil, errors.New("invalid coverage date format, use YYYY-MM-DD")
+	}
+	coverageEnd, err := parseCoverageEnd(req.CoverageEnd, coverageStart)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.InsurancePolicy{
+		PatientID:              patientID,
+		PayerID:                payer.ID,
+		PolicyNumber:           strings.TrimSpace(req.PolicyNumber),
+		GroupNumber:            strings.TrimSpace(req.GroupNumber),
+		SubscriberName:         subscriberName,
+		SubscriberRelationship: relationship,
+		Priority:               req.Priority,
+		CoverageStart:          coverageStart,
+		CoverageEnd:            coverageEnd,
+		IsActive:               true,
+		CreatedByID:            userID,
+	}
+
+	if err := s.insuranceRepo.CreatePolicy(policy); err != nil {
+		if errors.Is(err, repository.ErrActivePolicyExists) {
+			return nil, err
+		}
+		return nil, errors.New("failed to create insurance policy")
+	}
+
+	policy.Payer = payer
+	return policy, nil
+}
+
+// UpdatePolicy corrects a policy or ends it. Deactivating a policy frees its
+// priority for a replacement.
+func (s *InsuranceService) UpdatePolicy(patientID, id uint, req UpdateInsurancePolicyRequest, userRole models.UserRole) (*models.InsurancePolicy, error) {
+	if err := s.policy.Authorize(userRole, authz.InsurancePolicyWrite); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.insuranceRepo.GetPolicy(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PolicyNumber != nil {
+		policy.PolicyNumber = strings.TrimSpace(*req.PolicyNumber)
+	}
+	if req.GroupNumber != nil {
+		policy.GroupNumber = strings.TrimSpace(*req.GroupNumber)
+	}
+	if req.CoverageEnd != nil {
+		policy.CoverageEnd, err = parseCoverageEnd(*req.CoverageEnd, policy.CoverageStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	if err := s.insuranceRepo.UpdatePolicy(policy); err != nil {
+		if errors.Is(err, repository.ErrActivePolicyExists) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update insurance policy")
+	}
+
+	return policy, nil
+}
+
+// CheckEligibility reports which of the patient's active policies cover the
+// given date, today if none is given.
+func (s *InsuranceService) CheckEligibility(patientID uint, date string, userRole models.UserRole) (*EligibilityResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.InsurancePolicyRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	day := time.Now()
+	if date != "" {
+		var err error
+		day, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+	}
+
+	policies, err := s.insuranceRepo.ListPolicies(patientID, false)
+	if err != nil {
+		return nil, errors.New("failed to retrieve insurance policies")
+	}
+
+	response := &EligibilityResponse{
+		PatientID: patientID,
+		Date:      day.Format("2006-01-02"),
+		Policies:  make([]PolicyEligibility, len(policies)),
+	}
+	for i, policy := range policies {
+		reason := ineligibilityReason(policy, day)
+		response.Policies[i] = PolicyEligibility{
+			Policy:   policy,
+			Eligible: reason == "",
+			Reason:   reason,
+		}
+		if reason == "" {
+			response.Eligible = true
+		}
+	}
+	return response, nil
+}
+
+// ineligibilityReason explains why a policy does not cover a date, or
+// returns an empty string if it does.
+func ineligibilityReason(policy *models.InsurancePolicy, date time.Time) string {
+	switch {
+	case !policy.IsActive:
+		return "policy is inactive"
+	case policy.Payer != nil && !policy.Payer.IsActive:
+		return "payer is inactive"
+	case policy.CoversOn(date):
+		return ""
+	case policy.CoverageEnd != nil && date.After(*policy.CoverageEnd):
+		return "coverage ended on " + policy.CoverageEnd.Format("2006-01-02")
+	default:
+		return "coverage starts on " + policy.CoverageStart.Format("2006-01-02")
+	}
+}
+
+func parseCoverageEnd(value string, coverageStart time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	coverageEnd, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("invalid coverage date format, use YYYY-MM-DD")
+	}
+	if coverageEnd.Before(coverageStart) {
+		return nil, errors.New("coverage end cannot be before coverage start")
+	}
+	return &coverageEnd, nil
+}
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateLabOrderRequest struct {
+	TestCode      string                  `json:"test_code" binding:"required,max=50"`
+	TestName      string                  `json:"test_name" binding:"required,max=200"`
+	Priority      models.LabOrderPriority `json:"priority" binding:"omitempty,oneof=routine urgent stat"`
+	ClinicalNotes string                  `json:"clinical_notes" binding:"max=2000"`
+	EncounterID   *uint                   `json:"encounter_id"`
+}
+
+type CancelLabOrderRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// LabResultInput is one analyte of a result. When Flag is omitted it is
+// derived from a numeric value and the reference limits.
+type LabResultInput struct {
+	Analyte        string               `json:"analyte" binding:"required,max=200"`
+	Value          string               `json:"value" binding:"required,max=200"`
+	Unit           string               `json:"unit" binding:"max=50"`
+	ReferenceLow   *float64             `json:"reference_low"`
+	ReferenceHigh  *float64             `json:"reference_high"`
+	ReferenceRange string               `json:"reference_range" binding:"max=100"`
+	Flag           models.LabResultFlag `json:"flag" binding:"omitempty,oneof=normal low high abnormal critical"`
+	Comment        string               `json:"comment" binding:"max=1000"`
+}
+
+type RecordLabResultsRequest struct {
+	Results []LabResultInput `json:"results" binding:"required,min=1,max=100,dive"`
+}
+
+type LabInboxResponse struct {
+	Orders     []models.LabOrderResponse `json:"orders"`
+	Pagination PaginationResponse        `json:"pagination"`
+}
+
+type LabService struct {
+	labOrderRepo  repository.LabOrderRepository
+	patientRepo   repository.PatientRepository
+	encounterRepo repository.EncounterRepository
+	policy        *authz.Policy
+}
+
+func NewLabService(labOrderRepo repository.LabOrderRepository, patientRepo repository.PatientRepository, encounterRepo repository.EncounterRepository, policy *authz.Policy) *LabService {
+	return &LabService{
+		labOrderRepo:  labOrderRepo,
+		patientRepo:   patientRepo,
+		encounterRepo: encounterRepo,
+		policy:        policy,
+	}
+}
+
+func (s *LabService) CreateLabOrder(patientID uint, req CreateLabOrderRequest, doctorID uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabOrderWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	if req.EncounterID != nil {
+		if _, err := s.encounterRepo.GetByID(patientID, *req.EncounterID); err != nil {
+			return nil, errors.New("encounter not found for this patient")
+		}
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = models.LabOrderPriorityRoutine
+	}
+
+	order := &models.LabOrder{
+		PatientID:        patientID,
+		OrderingDoctorID: doctorID,
+		EncounterID:      req.EncounterID,
+		TestCode:         strings.ToUpper(strings.TrimSpace(req.TestCode)),
+		TestName:         strings.TrimSpace(req.TestName),
+		Priority:         priority,
+		ClinicalNotes:    req.ClinicalNotes,
+		Status:           models.LabOrderStatusOrdered,
+		OrderedAt:        time.Now(),
+	}
+
+	if err := s.labOrderRepo.Create(order); err != nil {
+		return nil, errors.New("failed to create lab order")
+	}
+
+	return s.reload(patientID, order.ID)
+}
+
+func (s *LabService) GetLabOrder(patientID, id uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabOrderRead); err != nil {
+		return nil, err
+	}
+
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// ListLabOrders is the lab section of the patient's record, newest first.
+func (s *LabService) ListLabOrders(patientID uint, status models.LabOrderStatus, userRole models.UserRole) ([]models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabOrderRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	orders, err := s.labOrderRepo.ListByPatient(patientID, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve lab orders")
+	}
+
+	responses := make([]models.LabOrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = order.ToResponse()
+	}
+	return responses, nil
+}
+
+func (s *LabService) CollectSpecimen(patientID, id uint, userID uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabResultWrite); err != nil {
+		return nil, err
+	}
+
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.LabOrderStatusOrdered {
+		return nil, errors.New("only ordered lab orders can be collected")
+	}
+
+	now := time.Now()
+	order.Status = models.LabOrderStatusCollected
+	order.CollectedAt = &now
+	order.CollectedByID = &userID
+
+	if err := s.transition(order, models.LabOrderStatusOrdered); err != nil {
+		return nil, err
+	}
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+func (s *LabService) StartProcessing(patientID, id uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabResultWrite); err != nil {
+		return nil, err
+	}
+
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.LabOrderStatusCollected {
+		return nil, errors.New("only collected lab orders can be started")
+	}
+
+	now := time.Now()
+	order.Status = models.LabOrderStatusInProgress
+	order.StartedAt = &now
+
+	if err := s.transition(order, models.LabOrderStatusCollected); err != nil {
+		return nil, err
+	}
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// RecordResults completes a collected or in-progress order. The order then
+// shows up in the ordering doctor's inbox.
+func (s *LabService) RecordResults(patientID, id uint, req RecordLabResultsRequest, userID uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabResultWrite); err != nil {
+		return nil, err
+	}
+
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.LabOrderStatusCollected && order.Status != models.LabOrderStatusInProgress {
+		return nil, errors.New("results can only be recorded for collected or in-progress lab orders")
+	}
+
+	results := make([]models.LabResult, len(req.Results))
+	hasAbnormal := false
+	for i, input := range req.Results {
+		if input.ReferenceLow != nil && input.ReferenceHigh != nil && *input.ReferenceLow > *input.ReferenceHigh {
+			return nil, errors.New("reference low cannot be above reference high")
+		}
+
+		result := models.LabResult{
+			Analyte:        strings.TrimSpace(input.Analyte),
+			Value:          strings.TrimSpace(input.Value),
+			Unit:           input.Unit,
+			ReferenceLow:   input.ReferenceLow,
+			ReferenceHigh:  input.ReferenceHigh,
+			ReferenceRange: input.ReferenceRange,
+			Flag:           input.Flag,
+			Comment:        input.Comment,
+		}
+		if value, err := strconv.ParseFloat(result.Value, 64); err == nil {
+			result.NumericValue = &value
+		}
+		if result.Flag == "" {
+			result.Flag = labResultFlag(result.NumericValue, result.ReferenceLow, result.ReferenceHigh)
+		}
+
+		hasAbnormal = hasAbnormal || result.IsAbnormal()
+		results[i] = result
+	}
+
+	from := order.Status
+	now := time.Now()
+	order.Status = models.LabOrderStatusResulted
+	order.ResultedAt = &now
+	order.ResultedByID = &userID
+	order.HasAbnormalResults = hasAbnormal
+	if order.CollectedAt == nil {
+		order.CollectedAt = &now
+	}
+
+	if err := s.labOrderRepo.RecordResults(order, results, from); err != nil {
+		if errors.Is(err, repository.ErrLabOrderStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to record lab results")
+	}
+
+	order.Results = results
+	response := order.ToResponse()
+	return &response, nil
+}
+
+func (s *LabService) CancelLabOrder(patientID, id uint, req CancelLabOrderRequest, userID uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabOrderWrite); err != nil {
+		return nil, err
+	}
+
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == models.LabOrderStatusResulted || order.Status == models.LabOrderStatusCancelled {
+		return nil, errors.New("lab order can no longer be cancelled")
+	}
+
+	from := order.Status
+	now := time.Now()
+	order.Status = models.LabOrderStatusCancelled
+	order.CancelledAt = &now
+	order.CancelledByID = &userID
+	order.CancelReason = req.Reason
+
+	if err := s.transition(order, from); err != nil {
+		return nil, err
+	}
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// ReviewResults marks a resulted order as seen by the ordering doctor,
+// removing it from their inbox.
+func (s *LabService) ReviewResults(patientID, id uint, userID uint, userRole models.UserRole) (*models.LabOrderResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabOrderRead); err != nil {
+		return nil, err
+	}
+
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.OrderingDoctorID != userID {
+		return nil, authz.ErrForbidden
+	}
+	if order.Status != models.LabOrderStatusResulted {
+		return nil, errors.New("only resulted lab orders can be reviewed")
+	}
+	if order.ReviewedAt != nil {
+		return nil, errors.New("lab results have already been reviewed")
+	}
+
+	now := time.Now()
+	if err := s.labOrderRepo.MarkReviewed(order, now); err != nil {
+		if errors.Is(err, repository.ErrLabOrderStatusChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update lab order")
+	}
+	order.ReviewedAt = &now
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// Inbox lists the caller's resulted orders awaiting review.
+func (s *LabService) Inbox(doctorID uint, includeReviewed bool, page, pageSize int, userRole models.UserRole) (*LabInboxResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.LabOrderRead); err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * pageSize
+
+	orders, err := s.labOrderRepo.ListInbox(doctorID, includeReviewed, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve lab inbox")
+	}
+
+	total, err := s.labOrderRepo.CountInbox(doctorID, includeReviewed)
+	if err != nil {
+		return nil, errors.New("failed to count lab inbox")
+	}
+
+	responses := make([]models.LabOrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = order.ToResponse()
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &LabInboxResponse{
+		Orders: responses,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+func (s *LabService) transition(order *models.LabOrder, from models.LabOrderStatus) error {
+	if err := s.labOrderRepo.Transition(order, from); err != nil {
+		if errors.Is(err, repository.ErrLabOrderStatusChanged) {
+			return err
+		}
+		return errors.New("failed to update lab order")
+	}
+	return nil
+}
+
+func (s *LabService) reload(patientID, id uint) (*models.LabOrderResponse, error) {
+	order, err := s.labOrderRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve lab order")
+	}
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// labResultFlag compares a numeric value with its reference limits. Values
+// that are not numbers, or have no limits, are left unflagged.
+func labResultFlag(value, low, high *float64) models.LabResultFlag {
+	if value == nil || (low == nil && high == nil) {
+		return ""
+	}
+	if low != nil && *value < *low {
+		return models.LabResultFlagLow
+	}
+	if high != nil && *value > *high {
+		return models.LabResultFlagHigh
+	}
+	return models.LabResultFlagNormal
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateMedicationRequest struct {
+	Name        string `json:"name" binding:"required,max=200"`
+	GenericName string `json:"generic_name" binding:"max=200"`
+	Form        string `json:"form" binding:"max=50"`
+	Strength    string `json:"strength" binding:"max=50"`
+	DrugClass   string `json:"drug_class" binding:"max=100"`
+}
+
+type UpdateMedicationRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
+	GenericName *string `json:"generic_name,omitempty" binding:"omitempty,max=200"`
+	Form        *string `json:"form,omitempty" binding:"omitempty,max=50"`
+	Strength    *string `json:"strength,omitempty" binding:"omitempty,max=50"`
+	DrugClass   *string `json:"drug_class,omitempty" binding:"omitempty,max=100"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+type MedicationListQuery struct {
+	Search          string
+	IncludeInactive bool
+}
+
+type MedicationListResponse struct {
+	Medications []*models.Medication `json:"medications"`
+	Pagination  PaginationResponse   `json:"pagination"`
+}
+
+// MedicationService manages the medication catalog that prescriptions are
+// written against. Medications are deactivated rather than deleted so that
+// existing prescriptions keep their reference.
+type MedicationService struct {
+	medicationRepo repository.MedicationRepository
+}
+
+func NewMedicationService(medicationRepo repository.MedicationRepository) *MedicationService {
+	return &MedicationService{
+		medicationRepo: medicationRepo,
+	}
+}
+
+func (s *MedicationService) ListMedications(req MedicationListQuery, page, pageSize int) (*MedicationListResponse, error) {
+	filter := repository.MedicationFilter{
+		Search:          strings.TrimSpace(req.Search),
+		IncludeInactive: req.IncludeInactive,
+	}
+
+	offset := (page - 1) * pageSize
+
+	medications, err := s.medicationRepo.Search(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve medications")
+	}
+
+	total, err := s.medicationRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("failed to count medications")
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &MedicationListResponse{
+		Medications: medications,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+func (s *MedicationService) GetMedication(id uint) (*models.Medication, error) {
+	return s.medicationRepo.GetByID(id)
+}
+
+func (s *MedicationService) CreateMedication(req CreateMedicationRequest) (*models.Medication, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("medication name is required")
+	}
+
+	if _, err := s.medicationRepo.GetByName(name); err == nil {
+		return nil, errors.New("medication already exists")
+	}
+
+	medication := &models.Medication{
+		Name:        name,
+		GenericName: strings.TrimSpace(req.GenericName),
+		Form:        strings.TrimSpace(req.Form),
+		Strength:    strings.TrimSpace(req.Strength),
+		DrugClass:   normalizeDrugClass(req.DrugClass),
+		IsActive:    true,
+	}
+
+	if err := s.medicationRepo.Create(medication); err != nil {
+		return nil, errors.New("failed to create medication")
+	}
+
+	return medication, nil
+}
+
+func (s *MedicationService) UpdateMedication(id uint, req UpdateMedicationRequest) (*models.Medication, error) {
+	medication, err := s.medicationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("medication name is required")
+		}
+		if existing, err := s.medicationRepo.GetByName(name); err == nil && existing.ID != medication.ID {
+			return nil, errors.New("medication already exists")
+		}
+		medication.Name = name
+	}
+	if req.GenericName != nil {
+		medication.GenericName = strings.TrimSpace(*req.GenericName)
+	}
+	if req.Form != nil {
+		medication.Form = strings.TrimSpace(*req.Form)
+	}
+	if req.Strength != nil {
+		medication.Strength = strings.TrimSpace(*req.Strength)
+	}
+	if req.DrugClass != nil {
+		medication.DrugClass = normalizeDrugClass(*req.DrugClass)
+	}
+	if req.IsActive != nil {
+		medication.IsActive = *req.IsActive
+	}
+
+	if err := s.medicationRepo.Update(medication); err != nil {
+		return nil, errors.New("failed to update medication")
+	}
+
+	return medication, nil
+}
+
+// normalizeDrugClass stores drug classes in lower case so that they can be
+// compared directly.
+func normalizeDrugClass(drugClass string) string {
+	return strings.ToLower(strings.TrimSpace(drugClass))
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/pkg/utils"
+)
+
+const recoveryCodeCount = 10
+
+// Failed codes are limited per challenge, so one password login allows only
+// a few guesses, and per user across challenges, after which the user's MFA
+// is locked for a while.
+const (
+	maxMFAChallengeAttempts = 5
+	maxMFAUserAttempts      = 10
+	mfaLockoutDuration      = 30 * time.Minute
+)
+
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// MFAConfirmResponse returns the recovery codes once; they cannot be
+// retrieved again. Tokens are included when confirming completes a login.
+type MFAConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	*TokenResponse
+}
+
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MFAPolicyRequest struct {
+	Role     models.UserRole `json:"role" binding:"required"`
+	Required *bool           `json:"required" binding:"required"`
+}
+
+type MFAService struct {
+	authService *AuthService
+	policy      *authz.Policy
+	issuer      string
+}
+
+func NewMFAService(authService *AuthService, policy *authz.Policy, issuer string) *MFAService {
+	return &MFAService{
+		authService: authService,
+		policy:      policy,
+		issuer:      issuer,
+	}
+}
+
+func (s *MFAService) BeginEnrollment(userID uint) (*MFAEnrollmentResponse, error) {
+	user, err := s.authService.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.MFAEnabled {
+		return nil, errors.New("multi-factor authentication is already enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate MFA secret")
+	}
+
+	if err := s.authService.mfaRepo.StartEnrollment(user.ID, secret); err != nil {
+		if errors.Is(err, repository.ErrMFAEnrollmentChanged) {
+			return nil, errors.New("multi-factor authentication is already enabled")
+		}
+		return nil, errors.New("failed to start MFA enrollment")
+	}
+
+	return &MFAEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(s.issuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user proves their authenticator
+// produces valid codes. Wrong codes count towards the same lockout as at
+// login. When completeLogin is set the caller authenticated with an
+// enrollment token, so a session is started as well.
+func (s *MFAService) ConfirmEnrollment(userID uint, code string, completeLogin bool) (*MFAConfirmResponse, error) {
+	user, err := s.authService.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.MFAEnabled {
+		return nil, errors.New("multi-factor authentication is already enabled")
+	}
+
+	if user.MFASecret == "" {
+		return nil, errors.New("multi-factor enrollment has not been started")
+	}
+
+	if mfaLocked(user) {
+		return nil, errors.New("multi-factor authentication is locked after too many failed attempts")
+	}
+
+	step, ok := auth.ValidateTOTPCode(user.MFASecret, code, time.Now())
+	if !ok {
+		return nil, s.recordFailure(user, nil, errors.New("invalid MFA code"))
+	}
+
+	recoveryCodes, stored, err := generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.mfaRepo.ReplaceRecoveryCodes(user.ID, stored); err != nil {
+		return nil, errors.New("failed to store recovery codes")
+	}
+
+	now := time.Now()
+	if err := s.authService.mfaRepo.EnableMFA(user.ID, user.MFASecret, step, now); err != nil {
+		if errors.Is(err, repository.ErrMFAEnrollmentChanged) {
+			return nil, errors.New("multi-factor enrollment has changed, please start again")
+		}
+		return nil, errors.New("failed to enable multi-factor authentication")
+	}
+	user.MFAEnabled = true
+	user.MFALastUsedStep = step
+	user.MFAEnrolledAt = &now
+	user.MFAFailedAttempts = 0
+
+	response := &MFAConfirmResponse{RecoveryCodes: recoveryCodes}
+	if completeLogin {
+		tokens, err := s.authService.startSession(user)
+		if err != nil {
+			return nil, err
+		}
+		response.TokenResponse = tokens
+	}
+
+	return response, nil
+}
+
+// VerifyLogin completes the second step of a login using either a TOTP code
+// or one of the user's unused recovery codes.
+func (s *MFAService) VerifyLogin(req MFAVerifyRequest) (*LoginResponse, error) {
+	claims, err := s.authService.jwtService.ValidateMFAToken(req.MFAToken, auth.PurposeMFAChallenge)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	user, err := s.authService.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	if !user.MFAEnabled {
+		return nil, errors.New("multi-factor authentication is not enabled")
+	}
+
+	if mfaLocked(user) {
+		return nil, errors.New("multi-factor authentication is locked after too many failed attempts")
+	}
+
+	failures, err := s.authService.mfaRepo.ChallengeFailures(claims.ID)
+	if err != nil {
+		return nil, errors.New("failed to check MFA challenge")
+	}
+	if failures >= maxMFAChallengeAttempts {
+		return nil, errors.New("too many failed MFA attempts, please log in again")
+	}
+
+	challenge := &models.MFAChallenge{
+		ID:        claims.ID,
+		UserID:    user.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+
+	switch {
+	case req.Code != "":
+		if err := s.verifyCode(user, req.Code, challenge); err != nil {
+			return nil, err
+		}
+	case req.RecoveryCode != "":
+		if err := s.authService.mfaRepo.UseRecoveryCode(user.ID, utils.HashToken(normalizeRecoveryCode(req.RecoveryCode))); err != nil {
+			return nil, s.recordFailure(user, challenge, errors.New("invalid recovery code"))
+		}
+	default:
+		return nil, errors.New("code or recovery_code is required")
+	}
+
+	if user.MFAFailedAttempts > 0 {
+		if err := s.authService.mfaRepo.ClearFailedAttempts(user.ID); err != nil {
+			return nil, errors.New("failed to record MFA code use")
+		}
+		user.MFAFailedAttempts = 0
+	}
+
+	return s.authService.completeLogin(user)
+}
+
+func (s *MFAService) Disable(userID uint, code string) error {
+	user, err := s.authService.userRepo.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.MFAEnabled {
+		return errors.New("multi-factor authentication is not enabled")
+	}
+
+	if mfaLocked(user) {
+		return errors.New("multi-factor authentication is locked after too many failed attempts")
+	}
+
+	required, err := s.authService.mfaRepo.IsRequiredForRole(user.Role)
+	if err != nil {
+		return errors.New("failed to check MFA policy")
+	}
+	if required {
+		return errors.New("multi-factor authentication is required for your role")
+	}
+
+	if err := s.verifyCode(user, code, nil); err != nil {
+		return err
+	}
+
+	if err := s.authService.mfaRepo.DeleteRecoveryCodes(user.ID); err != nil {
+		return errors.New("failed to remove recovery codes")
+	}
+
+	if err := s.authService.mfaRepo.DisableMFA(user.ID); err != nil {
+		return errors.New("failed to disable multi-factor authentication")
+	}
+
+	return nil
+}
+
+func (s *MFAService) ListPolicies() ([]*models.MFAPolicy, error) {
+	policies, err := s.authService.mfaRepo.ListPolicies()
+	if err != nil {
+		return nil, errors.New("failed to retrieve MFA policies")
+	}
+	return policies, nil
+}
+
+func (s *MFAService) SetPolicy(req MFAPolicyRequest, updatedByID uint) (*models.MFAPolicy, error) {
+	if !s.policy.HasRole(req.Role) {
+		return nil, errors.New("unknown role")
+	}
+
+	policy := &models.MFAPolicy{
+		Role:        req.Role,
+		Required:    *req.Required,
+		UpdatedByID: &updatedByID,
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.authService.mfaRepo.SavePolicy(policy); err != nil {
+		return nil, errors.New("failed to update MFA policy")
+	}
+
+	return policy, nil
+}
+
+// verifyCode checks a TOTP code and rejects replays of a code that was
+// already accepted within its validity window. Wrong codes count against the
+// challenge, when there is one, and the user.
+func (s *MFAService) verifyCode(user *models.User, code string, challenge *models.MFAChallenge) error {
+	step, ok := auth.ValidateTOTPCode(user.MFASecret, code, time.Now())
+	if !ok {
+		return s.recordFailure(user, challenge, errors.New("invalid MFA code"))
+	}
+
+	if step <= user.MFALastUsedStep {
+		return errors.New("MFA code has already been used")
+	}
+
+	if err := s.authService.mfaRepo.RecordTOTPStep(user.ID, step); err != nil {
+		if errors.Is(err, repository.ErrTOTPStepUsed) {
+			return errors.New("MFA code has already been used")
+		}
+		return errors.New("failed to record MFA code use")
+	}
+	user.MFALastUsedStep = step
+
+	return nil
+}
+
+// recordFailure counts a failed attempt and locks the user's MFA once they
+// reach the limit. It returns cause unless the attempt used up the user's
+// attempts or the challenge's.
+func (s *MFAService) recordFailure(user *models.User, challenge *models.MFAChallenge, cause error) error {
+	failures, err := s.authService.mfaRepo.RecordUserFailure(user.ID)
+	if err != nil {
+		return errors.New("failed to record MFA attempt")
+	}
+
+	if failures >= maxMFAUserAttempts {
+		if err := s.authService.mfaRepo.LockMFA(user.ID, time.Now().Add(mfaLockoutDuration)); err != nil {
+			return errors.New("failed to record MFA attempt")
+		}
+		return errors.New("multi-factor authentication is locked after too many failed attempts")
+	}
+
+	if challenge != nil {
+		if err := s.authService.mfaRepo.RecordChallengeFailure(challenge); err != nil {
+			return errors.New("failed to record MFA attempt")
+		}
+		if challenge.FailedAttempts >= maxMFAChallengeAttempts {
+			return errors.New("too many failed MFA attempts, please log in again")
+		}
+	}
+
+	return cause
+}
+
+func mfaLocked(user *models.User) bool {
+	return user.MFALockedUntil != nil && time.Now().Before(*user.MFALockedUntil)
+}
+
+func generateRecoveryCodes(userID uint) ([]string, []*models.MFARecoveryCode, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	stored := make([]*models.MFARecoveryCode, recoveryCodeCount)
+
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, errors.New("failed to generate recovery codes")
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(raw))
+		codes[i] = code[:8] + "-" + code[8:16]
+		stored[i] = &models.MFARecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(normalizeRecoveryCode(codes[i])),
+		}
+	}
+
+	return codes, stored, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
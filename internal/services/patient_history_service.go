@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type PatientHistoryService struct {
+	revisionRepo repository.PatientRevisionRepository
+	patientRepo  repository.PatientRepository
+}
+
+func NewPatientHistoryService(revisionRepo repository.PatientRevisionRepository, patientRepo repository.PatientRepository) *PatientHistoryService {
+	return &PatientHistoryService{
+		revisionRepo: revisionRepo,
+		patientRepo:  patientRepo,
+	}
+}
+
+// GetPatientHistory lists the patient's revisions, oldest first. Patients
+// registered before the revision history existed have none until their
+// next change, so an existing patient may have an empty history.
+func (s *PatientHistoryService) GetPatientHistory(patientID uint) ([]models.PatientRevisionResponse, error) {
+	revisions, err := s.revisionRepo.ListByPatient(patientID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve patient history")
+	}
+
+	if len(revisions) == 0 {
+		if _, err := s.patientRepo.GetByID(patientID); err != nil {
+			if err.Error() == "patient not found" {
+				return nil, err
+			}
+			return nil, errors.New("failed to retrieve patient history")
+		}
+	}
+
+	responses := make([]models.PatientRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		responses[i] = revision.ToResponse()
+	}
+
+	return responses, nil
+}
+
+func (s *PatientHistoryService) GetPatientVersion(patientID uint, version int) (*models.PatientRevisionResponse, error) {
+	revision, err := s.revisionRepo.GetByVersion(patientID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	response := revision.ToResponse()
+	return &response, nil
+}
+
+func (s *PatientHistoryService) DiffPatientVersions(patientID uint, fromVersion, toVersion int) (*models.PatientRevisionDiffResponse, error) {
+	from, err := s.revisionRepo.GetByVersion(patientID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.revisionRepo.GetByVersion(patientID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSnapshot, err := from.GetSnapshot()
+	if err != nil {
+		return nil, errors.New("failed to read patient revision")
+	}
+
+	toSnapshot, err := to.GetSnapshot()
+	if err != nil {
+		return nil, errors.New("failed to read patient revision")
+	}
+
+	return &models.PatientRevisionDiffResponse{
+		PatientID:   patientID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     models.DiffPatientSnapshots(fromSnapshot, toSnapshot),
+	}, nil
+}
@@ -2,40 +2,80 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/matching"
 	"hospital-management-system/internal/models"
 	"hospital-management-system/internal/repository"
 )
 
 type CreatePatientRequest struct {
-	FirstName          string           `json:"first_name" binding:"required,min=2,max=50"`
-	LastName           string           `json:"last_name" binding:"required,min=2,max=50"`
-	Email              string           `json:"email" binding:"omitempty,email"`
-	Phone              string           `json:"phone" binding:"required,min=10,max=15"`
-	DateOfBirth        string           `json:"date_of_birth" binding:"required"` // Format: YYYY-MM-DD
-	Gender             models.Gender    `json:"gender" binding:"required,oneof=male female other"`
-	BloodType          models.BloodType `json:"blood_type" binding:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-"`
-	Address            string           `json:"address"`
-	EmergencyContact   string           `json:"emergency_contact" binding:"required,min=10,max=15"`
-	MedicalHistory     string           `json:"medical_history"`
-	Allergies          string           `json:"allergies"`
-	CurrentMedications string           `json:"current_medications"`
+	FirstName        string           `json:"first_name" binding:"required,min=2,max=50"`
+	LastName         string           `json:"last_name" binding:"required,min=2,max=50"`
+	Email            string           `json:"email" binding:"omitempty,email"`
+	Phone            string           `json:"phone" binding:"required,min=10,max=15"`
+	DateOfBirth      string           `json:"date_of_birth" binding:"required"` // Format: YYYY-MM-DD
+	Gender           models.Gender    `json:"gender" binding:"required,oneof=male female other"`
+	BloodType        models.BloodType `json:"blood_type" binding:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-"`
+	Address          string           `json:"address"`
+	EmergencyContact string           `json:"emergency_contact" binding:"required,min=10,max=15"`
+	MedicalHistory   string           `json:"medical_history"`
+
+	// ConfirmNotDuplicate registers the patient even though likely
+	// duplicates were found.
+	ConfirmNotDuplicate bool `json:"confirm_not_duplicate"`
 }
 
 type UpdatePatientRequest struct {
-	FirstName          *string           `json:"first_name,omitempty" binding:"omitempty,min=2,max=50"`
-	LastName           *string           `json:"last_name,omitempty" binding:"omitempty,min=2,max=50"`
-	Email              *string           `json:"email,omitempty" binding:"omitempty,email"`
-	Phone              *string           `json:"phone,omitempty" binding:"omitempty,min=10,max=15"`
-	DateOfBirth        *string           `json:"date_of_birth,omitempty"`
-	Gender             *models.Gender    `json:"gender,omitempty" binding:"omitempty,oneof=male female other"`
-	BloodType          *models.BloodType `json:"blood_type,omitempty" binding:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-"`
-	Address            *string           `json:"address,omitempty"`
-	EmergencyContact   *string           `json:"emergency_contact,omitempty" binding:"omitempty,min=10,max=15"`
-	MedicalHistory     *string           `json:"medical_history,omitempty"`
-	Allergies          *string           `json:"allergies,omitempty"`
-	CurrentMedications *string           `json:"current_medications,omitempty"`
+	FirstName        *string           `json:"first_name,omitempty" binding:"omitempty,min=2,max=50"`
+	LastName         *string           `json:"last_name,omitempty" binding:"omitempty,min=2,max=50"`
+	Email            *string           `json:"email,omitempty" binding:"omitempty,email"`
+	Phone            *string           `json:"phone,omitempty" binding:"omitempty,min=10,max=15"`
+	DateOfBirth      *string           `json:"date_of_birth,omitempty"`
+	Gender           *models.Gender    `json:"gender,omitempty" binding:"omitempty,oneof=male female other"`
+	BloodType        *models.BloodType `json:"blood_type,omitempty" binding:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-"`
+	Address          *string           `json:"address,omitempty"`
+	EmergencyContact *string           `json:"emergency_contact,omitempty" binding:"omitempty,min=10,max=15"`
+	MedicalHistory   *string           `json:"medical_history,omitempty"`
+}
+
+func (r UpdatePatientRequest) hasMedicalChanges() bool {
+	return r.MedicalHistory != nil
+}
+
+type MergePatientRequest struct {
+	DuplicateID uint `json:"duplicate_id" binding:"required"`
+}
+
+// DuplicateCandidate is an existing patient that may be the same person as
+// the one being registered or reviewed. It carries only summary fields and
+// the match reasons, so roles that may merge but not read patients, such as
+// admins, never see another patient's demographics or contact details.
+type DuplicateCandidate struct {
+	Patient models.PatientSummary `json:"patient"`
+	Match   matching.Match        `json:"match"`
+}
+
+// DuplicatePatientError is returned when a new patient likely matches an
+// existing record. The receptionist can pick the existing record or
+// resubmit with ConfirmNotDuplicate.
+type DuplicatePatientError struct {
+	Candidates []DuplicateCandidate
+}
+
+func (e *DuplicatePatientError) Error() string {
+	return "patient likely already registered, confirm to register a new patient anyway"
+}
+
+type PatientMergeResponse struct {
+	Patient         models.PatientResponse `json:"patient"`
+	MergedPatientID string                 `json:"merged_patient_id"`
+	MovedRecords    map[string]int64       `json:"moved_records"`
 }
 
 type PatientListResponse struct {
@@ -43,6 +83,141 @@ type PatientListResponse struct {
 	Pagination PaginationResponse       `json:"pagination"`
 }
 
+// PatientListQuery filters and sorts the patient list. Dates are
+// YYYY-MM-DD and both ends of a range are inclusive. Sort is a comma
+// separated list of fields, each prefixed with - to sort it descending.
+type PatientListQuery struct {
+	Gender      models.Gender
+	BloodType   models.BloodType
+	MinAge      *int
+	MaxAge      *int
+	CreatedByID *uint
+	CreatedFrom string
+	CreatedTo   string
+	UpdatedFrom string
+	UpdatedTo   string
+	Status      string // active (default), inactive or all
+	Sort        string
+}
+
+func (q PatientListQuery) filter(now time.Time) (repository.PatientFilter, error) {
+	filter := repository.PatientFilter{
+		Gender:      q.Gender,
+		BloodType:   q.BloodType,
+		CreatedByID: q.CreatedByID,
+	}
+
+	switch q.Gender {
+	case "", models.GenderMale, models.GenderFemale, models.GenderOther:
+	default:
+		return filter, errors.New("gender must be one of male, female or other")
+	}
+
+	switch q.BloodType {
+	case "", models.BloodTypeAPos, models.BloodTypeANeg, models.BloodTypeBPos, models.BloodTypeBNeg,
+		models.BloodTypeABPos, models.BloodTypeABNeg, models.BloodTypeOPos, models.BloodTypeONeg:
+	default:
+		return filter, errors.New("blood_type must be one of A+, A-, B+, B-, AB+, AB-, O+ or O-")
+	}
+
+	switch q.Status {
+	case "", "active":
+		active := true
+		filter.IsActive = &active
+	case "inactive":
+		active := false
+		filter.IsActive = &active
+	case "all":
+	default:
+		return filter, errors.New("status must be one of active, inactive or all")
+	}
+
+	// A patient is at least MinAge if born on or before today MinAge years
+	// ago, and at most MaxAge if born after today MaxAge+1 years ago.
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if q.MinAge != nil {
+		if *q.MinAge < 0 {
+			return filter, errors.New("min_age must not be negative")
+		}
+		filter.BornTo = today.AddDate(-*q.MinAge, 0, 1)
+	}
+	if q.MaxAge != nil {
+		if *q.MaxAge < 0 {
+			return filter, errors.New("max_age must not be negative")
+		}
+		if q.MinAge != nil && *q.MinAge > *q.MaxAge {
+			return filter, errors.New("min_age must not be greater than max_age")
+		}
+		filter.BornFrom = today.AddDate(-*q.MaxAge-1, 0, 1)
+	}
+
+	var err error
+	if filter.CreatedFrom, filter.CreatedTo, err = parseDateRange("created", q.CreatedFrom, q.CreatedTo); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedFrom, filter.UpdatedTo, err = parseDateRange("updated", q.UpdatedFrom, q.UpdatedTo); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD range into the half-open
+// range the repository filters on.
+func parseDateRange(name, fromValue, toValue string) (from, to time.Time, err error) {
+	if fromValue != "" {
+		if from, err = time.Parse("2006-01-02", fromValue); err != nil {
+			return from, to, fmt.Errorf("invalid %s_from date format, use YYYY-MM-DD", name)
+		}
+	}
+	if toValue != "" {
+		if to, err = time.Parse("2006-01-02", toValue); err != nil {
+			return from, to, fmt.Errorf("invalid %s_to date format, use YYYY-MM-DD", name)
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("%s_from date must not be after %s_to date", name, name)
+	}
+	return from, to, nil
+}
+
+// parsePatientSort parses a sort parameter such as "last_name,-created_at".
+func parsePatientSort(value string) ([]repository.PatientSort, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var order []repository.PatientSort
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		descending := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		if !repository.IsPatientSortField(field) {
+			return nil, fmt.Errorf("cannot sort by %q, use one of %s", field, strings.Join(repository.PatientSortFields(), ", "))
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("sort field %q is given more than once", field)
+		}
+		seen[field] = true
+		order = append(order, repository.PatientSort{Field: field, Descending: descending})
+	}
+	return order, nil
+}
+
+// PatientSearchResult is a patient found by a search, with the relevance it
+// was ranked by. Higher is a better match.
+type PatientSearchResult struct {
+	models.PatientResponse
+	Relevance float64 `json:"relevance"`
+}
+
+type PatientSearchResponse struct {
+	Patients   []PatientSearchResult `json:"patients"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
 type PaginationResponse struct {
 	Total       int64 `json:"total"`
 	CurrentPage int   `json:"current_page"`
@@ -50,19 +225,28 @@ type PaginationResponse struct {
 	TotalPages  int   `json:"total_pages"`
 }
 
+// PatientService changes patients through the patient repository, which
+// records every change in the patient's revision history in the same
+// transaction.
 type PatientService struct {
 	patientRepo repository.PatientRepository
 	userRepo    repository.UserRepository
+	policy      *authz.Policy
 }
 
-func NewPatientService(patientRepo repository.PatientRepository, userRepo repository.UserRepository) *PatientService {
+func NewPatientService(patientRepo repository.PatientRepository, userRepo repository.UserRepository, policy *authz.Policy) *PatientService {
 	return &PatientService{
 		patientRepo: patientRepo,
 		userRepo:    userRepo,
+		policy:      policy,
 	}
 }
 
-func (s *PatientService) CreatePatient(req CreatePatientRequest, createdByID uint) (*models.PatientResponse, error) {
+func (s *PatientService) CreatePatient(req CreatePatientRequest, createdByID uint, userRole models.UserRole) (*models.PatientResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PatientCreate); err != nil {
+		return nil, err
+	}
+
 	_, err := s.userRepo.GetByID(createdByID)
 	if err != nil {
 		return nil, errors.New("invalid user")
@@ -81,20 +265,28 @@ func (s *PatientService) CreatePatient(req CreatePatientRequest, createdByID uin
 
 	// Create patient
 	patient := &models.Patient{
-		FirstName:          req.FirstName,
-		LastName:           req.LastName,
-		Email:              req.Email,
-		Phone:              req.Phone,
-		DateOfBirth:        dob,
-		Gender:             req.Gender,
-		BloodType:          req.BloodType,
-		Address:            req.Address,
-		EmergencyContact:   req.EmergencyContact,
-		MedicalHistory:     req.MedicalHistory,
-		Allergies:          req.Allergies,
-		CurrentMedications: req.CurrentMedications,
-		CreatedByID:        createdByID,
-		IsActive:           true,
+		FirstName:        req.FirstName,
+		LastName:         req.LastName,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		DateOfBirth:      dob,
+		Gender:           req.Gender,
+		BloodType:        req.BloodType,
+		Address:          req.Address,
+		EmergencyContact: req.EmergencyContact,
+		MedicalHistory:   req.MedicalHistory,
+		CreatedByID:      createdByID,
+		IsActive:         true,
+	}
+
+	if !req.ConfirmNotDuplicate {
+		candidates, err := s.findDuplicates(patient, matching.MatchLevelLikely)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			return nil, &DuplicatePatientError{Candidates: candidates}
+		}
 	}
 
 	if err := s.patientRepo.Create(patient); err != nil {
@@ -132,6 +324,18 @@ func (s *PatientService) GetPatientByPatientID(patientID string) (*models.Patien
 }
 
 func (s *PatientService) UpdatePatient(id uint, req UpdatePatientRequest, updatedByID uint, userRole models.UserRole) (*models.PatientResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PatientUpdate); err != nil {
+		return nil, err
+	}
+
+	// Medical fields are rejected outright rather than silently dropped so
+	// that callers notice they lack the permission.
+	if req.hasMedicalChanges() {
+		if err := s.policy.Authorize(userRole, authz.PatientMedicalWrite); err != nil {
+			return nil, err
+		}
+	}
+
 	patient, err := s.patientRepo.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -177,23 +381,14 @@ func (s *PatientService) UpdatePatient(id uint, req UpdatePatientRequest, update
 		patient.EmergencyContact = *req.EmergencyContact
 	}
 
-	// Only allow medical information updates for doctors
-	if userRole == models.RoleDoctor {
-		if req.MedicalHistory != nil {
-			patient.MedicalHistory = *req.MedicalHistory
-		}
-		if req.Allergies != nil {
-			patient.Allergies = *req.Allergies
-		}
-		if req.CurrentMedications != nil {
-			patient.CurrentMedications = *req.CurrentMedications
-		}
+	if req.MedicalHistory != nil {
+		patient.MedicalHistory = *req.MedicalHistory
 	}
 
 	// Set last updated by
 	patient.LastUpdatedByID = &updatedByID
 
-	if err := s.patientRepo.Update(patient); err != nil {
+	if err := s.patientRepo.Update(patient, updatedByID); err != nil {
 		return nil, errors.New("failed to update patient")
 	}
 
@@ -207,20 +402,139 @@ func (s *PatientService) UpdatePatient(id uint, req UpdatePatientRequest, update
 	return &response, nil
 }
 
-func (s *PatientService) DeletePatient(id uint, userRole models.UserRole) error {
-	if userRole != models.RoleReceptionist {
-		return errors.New("only receptionists can delete patients")
+func (s *PatientService) DeletePatient(id uint, deletedByID uint, userRole models.UserRole) error {
+	if err := s.policy.Authorize(userRole, authz.PatientDelete); err != nil {
+		return err
 	}
 
-	_, err := s.patientRepo.GetByID(id)
-	if err != nil {
+	if _, err := s.patientRepo.GetByID(id); err != nil {
 		return err
 	}
 
-	return s.patientRepo.Delete(id)
+	return s.patientRepo.Delete(id, deletedByID)
 }
 
-func (s *PatientService) ListPatients(page, pageSize int) (*PatientListResponse, error) {
+// FindDuplicates lists the active patients that are likely or possibly the
+// same person as the given patient, best match first.
+func (s *PatientService) FindDuplicates(id uint, userRole models.UserRole) ([]DuplicateCandidate, error) {
+	if err := s.policy.Authorize(userRole, authz.PatientMerge); err != nil {
+		return nil, err
+	}
+
+	patient, err := s.patientRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findDuplicates(patient, matching.MatchLevelPossible)
+}
+
+func (s *PatientService) findDuplicates(patient *models.Patient, minLevel matching.MatchLevel) ([]DuplicateCandidate, error) {
+	records, err := s.patientRepo.FindMatchCandidates(patient)
+	if err != nil {
+		return nil, errors.New("failed to check for duplicate patients")
+	}
+
+	candidates := []DuplicateCandidate{}
+	for _, record := range records {
+		match := matching.Score(patient, record)
+		if !match.IsDuplicate() {
+			continue
+		}
+		if minLevel == matching.MatchLevelLikely && match.Level != matching.MatchLevelLikely {
+			continue
+		}
+		candidates = append(candidates, DuplicateCandidate{
+			Patient: record.ToSummary(),
+			Match:   match,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Match.Score > candidates[j].Match.Score
+	})
+	return candidates, nil
+}
+
+// MergePatients folds a duplicate record into the surviving patient. The
+// survivor keeps its own details and takes over those it is missing from
+// the duplicate; differing medical histories are combined. Every record of
+// the duplicate moves to the survivor, and the duplicate's patient ID keeps
+// working as an alias.
+func (s *PatientService) MergePatients(survivorID uint, req MergePatientRequest, mergedByID uint, userRole models.UserRole) (*PatientMergeResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PatientMerge); err != nil {
+		return nil, err
+	}
+
+	if survivorID == req.DuplicateID {
+		return nil, errors.New("cannot merge a patient into itself")
+	}
+
+	survivor, err := s.patientRepo.GetByID(survivorID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.patientRepo.GetByID(req.DuplicateID)
+	if err != nil {
+		return nil, errors.New("duplicate patient not found")
+	}
+
+	moved, err := s.patientRepo.Merge(survivor.ID, duplicate.ID, mergedByID, func(survivor, duplicate *models.Patient) {
+		mergePatientDetails(survivor, duplicate, mergedByID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrPatientMergeChanged),
+			errors.Is(err, repository.ErrMergeBothAdmitted),
+			errors.Is(err, repository.ErrMergeBothInED),
+			errors.Is(err, repository.ErrMergePolicyConflict):
+			return nil, err
+		}
+		return nil, errors.New("failed to merge patients")
+	}
+
+	mergedPatient, err := s.patientRepo.GetByID(survivor.ID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve merged patient")
+	}
+
+	return &PatientMergeResponse{
+		Patient:         mergedPatient.ToResponse(),
+		MergedPatientID: duplicate.PatientID,
+		MovedRecords:    moved,
+	}, nil
+}
+
+// mergePatientDetails fills the survivor's missing details from the
+// duplicate, appends the duplicate's medical history and marks the
+// duplicate as merged into the survivor.
+func mergePatientDetails(survivor, duplicate *models.Patient, mergedByID uint) {
+	if survivor.Email == "" && duplicate.Email != "" {
+		survivor.Email = duplicate.Email
+		duplicate.Email = ""
+	}
+	if survivor.Address == "" {
+		survivor.Address = duplicate.Address
+	}
+	if survivor.BloodType == "" {
+		survivor.BloodType = duplicate.BloodType
+	}
+	switch {
+	case survivor.MedicalHistory == "":
+		survivor.MedicalHistory = duplicate.MedicalHistory
+	case duplicate.MedicalHistory != "" && duplicate.MedicalHistory != survivor.MedicalHistory:
+		survivor.MedicalHistory += "\n\nMerged from " + duplicate.PatientID + ":\n" + duplicate.MedicalHistory
+	}
+	survivor.LastUpdatedByID = &mergedByID
+
+	now := time.Now()
+	duplicate.IsActive = false
+	duplicate.MergedIntoID = &survivor.ID
+	duplicate.MergedAt = &now
+	duplicate.LastUpdatedByID = &mergedByID
+}
+
+func (s *PatientService) ListPatients(req PatientListQuery, page, pageSize int) (*PatientListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -228,14 +542,23 @@ func (s *PatientService) ListPatients(page, pageSize int) (*PatientListResponse,
 		pageSize = 10
 	}
 
+	filter, err := req.filter(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	order, err := parsePatientSort(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
 	offset := (page - 1) * pageSize
-	patients, err := s.patientRepo.List(pageSize, offset)
+	patients, err := s.patientRepo.List(filter, order, pageSize, offset)
 	if err != nil {
 		return nil, errors.New("failed to retrieve patients")
 	}
 
 	// Get total count
-	total, err := s.patientRepo.Count()
+	total, err := s.patientRepo.Count(filter)
 	if err != nil {
 		return nil, errors.New("failed to count patients")
 	}
@@ -262,7 +585,7 @@ func (s *PatientService) ListPatients(page, pageSize int) (*PatientListResponse,
 	}, nil
 }
 
-func (s *PatientService) SearchPatients(query string, page, pageSize int) (*PatientListResponse, error) {
+func (s *PatientService) SearchPatients(query string, page, pageSize int) (*PatientSearchResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -276,13 +599,16 @@ func (s *PatientService) SearchPatients(query string, page, pageSize int) (*Pati
 		return nil, errors.New("failed to search patients")
 	}
 
-	patientResponses := make([]models.PatientResponse, len(patients))
+	results := make([]PatientSearchResult, len(patients))
 	for i, patient := range patients {
-		patientResponses[i] = patient.ToResponse()
+		results[i] = PatientSearchResult{
+			PatientResponse: patient.ToResponse(),
+			Relevance:       math.Round(patient.SearchRank*1000) / 1000,
+		}
 	}
 
-	return &PatientListResponse{
-		Patients: patientResponses,
+	return &PatientSearchResponse{
+		Patients: results,
 		Pagination: PaginationResponse{
 			Total:       int64(len(patients)),
 			CurrentPage: page,
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/clinical"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreatePrescriptionRequest struct {
+	MedicationID uint                   `json:"medication_id" binding:"required"`
+	EncounterID  *uint                  `json:"encounter_id"`
+	Dose         string                 `json:"dose" binding:"required,max=100"`
+	Route        models.MedicationRoute `json:"route" binding:"required,oneof=oral intravenous intramuscular subcutaneous topical inhaled other"`
+	Frequency    string                 `json:"frequency" binding:"required,max=100"`
+	DurationDays *int                   `json:"duration_days" binding:"omitempty,min=1,max=365"`
+	Refills      int                    `json:"refills" binding:"min=0,max=12"`
+	Instructions string                 `json:"instructions" binding:"max=2000"`
+	StartDate    string                 `json:"start_date"` // Format: YYYY-MM-DD, defaults to today
+
+	// OverrideReason accepts any safety warnings raised for the prescription.
+	// It has no effect on blocking alerts.
+	OverrideReason string `json:"override_reason" binding:"max=1000"`
+}
+
+type DiscontinuePrescriptionRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// SafetyCheckError is returned when the clinical safety checks stop a
+// prescription. It carries every alert so that the prescriber can review
+// them; warnings can be accepted by resubmitting with an override reason.
+type SafetyCheckError struct {
+	Alerts []clinical.Alert
+}
+
+func (e *SafetyCheckError) Error() string {
+	if e.Overridable() {
+		return "clinical safety warnings must be overridden with a reason"
+	}
+	return "prescription blocked by clinical safety checks"
+}
+
+// Overridable reports whether every alert is a warning.
+func (e *SafetyCheckError) Overridable() bool {
+	return !clinical.HasBlocking(e.Alerts)
+}
+
+type PrescriptionService struct {
+	prescriptionRepo repository.PrescriptionRepository
+	medicationRepo   repository.MedicationRepository
+	patientRepo      repository.PatientRepository
+	encounterRepo    repository.EncounterRepository
+	allergyRepo      repository.AllergyRepository
+	safetyRules      *clinical.Rules
+	policy           *authz.Policy
+}
+
+func NewPrescriptionService(prescriptionRepo repository.PrescriptionRepository, medicationRepo repository.MedicationRepository, patientRepo repository.PatientRepository, encounterRepo repository.EncounterRepository, allergyRepo repository.AllergyRepository, safetyRules *clinical.Rules, policy *authz.Policy) *PrescriptionService {
+	return &PrescriptionService{
+		prescriptionRepo: prescriptionRepo,
+		medicationRepo:   medicationRepo,
+		patientRepo:      patientRepo,
+		encounterRepo:    encounterRepo,
+		allergyRepo:      allergyRepo,
+		safetyRules:      safetyRules,
+		policy:           policy,
+	}
+}
+
+func (s *PrescriptionService) CreatePrescription(patientID uint, req CreatePrescriptionRequest, prescriberID uint, userRole models.UserRole) (*models.PrescriptionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PrescriptionWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	medication, err := s.medicationRepo.GetByID(req.MedicationID)
+	if err != nil || !medication.IsActive {
+		return nil, errors.New("medication not found")
+	}
+
+	if req.EncounterID != nil {
+		if _, err := s.encounterRepo.GetByID(patientID, *req.EncounterID); err != nil {
+			return nil, errors.New("encounter not found for this patient")
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	startDate := today
+	if req.StartDate != "" {
+		startDate, err = time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date format, use YYYY-MM-DD")
+		}
+		if startDate.Before(today) {
+			return nil, errors.New("start date cannot be in the past")
+		}
+	}
+
+	var endDate *time.Time
+	if req.DurationDays != nil {
+		end := startDate.AddDate(0, 0, *req.DurationDays)
+		endDate = &end
+	}
+
+	overrides, err := s.checkSafety(patientID, medication, startDate, endDate, req.OverrideReason, prescriberID)
+	if err != nil {
+		return nil, err
+	}
+
+	prescription := &models.Prescription{
+		PatientID:      patientID,
+		MedicationID:   &medication.ID,
+		MedicationName: medication.Name,
+		EncounterID:    req.EncounterID,
+		Dose:           req.Dose,
+		Route:          req.Route,
+		Frequency:      req.Frequency,
+		DurationDays:   req.DurationDays,
+		Refills:        req.Refills,
+		Instructions:   req.Instructions,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		Status:         models.PrescriptionStatusActive,
+		PrescriberID:   &prescriberID,
+
+		SafetyOverrides: overrides,
+	}
+	if err := s.prescriptionRepo.Create(prescription); err != nil {
+		return nil, errors.New("failed to create prescription")
+	}
+
+	return s.reload(patientID, prescription.ID)
+}
+
+// checkSafety runs the drug-allergy and drug-drug interaction checks for a
+// new prescription against every active prescription whose course overlaps
+// it. Blocking alerts always fail; warnings fail unless an override reason
+// is given, in which case they are returned as override records to be
+// stored with the prescription.
+func (s *PrescriptionService) checkSafety(patientID uint, medication *models.Medication, startDate time.Time, endDate *time.Time, overrideReason string, prescriberID uint) ([]models.PrescriptionSafetyOverride, error) {
+	allergies, err := s.allergyRepo.ListByPatient(patientID, models.AllergyStatusActive)
+	if err != nil {
+		return nil, errors.New("failed to retrieve allergies")
+	}
+
+	current, err := s.prescriptionRepo.ListOverlapping(patientID, startDate, endDate)
+	if err != nil {
+		return nil, errors.New("failed to retrieve active medications")
+	}
+
+	alerts := s.safetyRules.Check(medication, allergies, current)
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	overrideReason = strings.TrimSpace(overrideReason)
+	if clinical.HasBlocking(alerts) || overrideReason == "" {
+		return nil, &SafetyCheckError{Alerts: alerts}
+	}
+
+	overrides := make([]models.PrescriptionSafetyOverride, len(alerts))
+	for i, alert := range alerts {
+		overrides[i] = models.PrescriptionSafetyOverride{
+			PatientID:      patientID,
+			AlertKind:      string(alert.Kind),
+			AlertLevel:     string(alert.Level),
+			Description:    alert.Description,
+			Reason:         overrideReason,
+			OverriddenByID: prescriberID,
+		}
+	}
+	return overrides, nil
+}
+
+func (s *PrescriptionService) GetPrescription(patientID, id uint, userRole models.UserRole) (*models.PrescriptionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PrescriptionRead); err != nil {
+		return nil, err
+	}
+
+	prescription, err := s.prescriptionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := prescription.ToResponse()
+	return &response, nil
+}
+
+func (s *PrescriptionService) ListPrescriptions(patientID uint, status models.PrescriptionStatus, userRole models.UserRole) ([]models.PrescriptionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PrescriptionRead); err != nil {
+		return nil, err
+	}
+
+	prescriptions, err := s.prescriptionRepo.ListByPatient(patientID, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve prescriptions")
+	}
+
+	return toPrescriptionResponses(prescriptions), nil
+}
+
+// ActiveMedications is the patient's current medication list, derived from
+// the prescriptions in effect today.
+func (s *PrescriptionService) ActiveMedications(patientID uint, userRole models.UserRole) ([]models.PrescriptionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PrescriptionRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	prescriptions, err := s.prescriptionRepo.ListCurrent(patientID, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to retrieve active medications")
+	}
+
+	return toPrescriptionResponses(prescriptions), nil
+}
+
+// StopPrescription ends a prescription as planned, for example when the
+// course is complete.
+func (s *PrescriptionService) StopPrescription(patientID, id uint, userID uint, userRole models.UserRole) (*models.PrescriptionResponse, error) {
+	return s.end(patientID, id, models.PrescriptionStatusStopped, "", userID, userRole)
+}
+
+// DiscontinuePrescription ends a prescription early. The reason is required
+// and kept on the prescription.
+func (s *PrescriptionService) DiscontinuePrescription(patientID, id uint, req DiscontinuePrescriptionRequest, userID uint, userRole models.UserRole) (*models.PrescriptionResponse, error) {
+	return s.end(patientID, id, models.PrescriptionStatusDiscontinued, req.Reason, userID, userRole)
+}
+
+func (s *PrescriptionService) end(patientID, id uint, status models.PrescriptionStatus, reason string, userID uint, userRole models.UserRole) (*models.PrescriptionResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.PrescriptionWrite); err != nil {
+		return nil, err
+	}
+
+	prescription, err := s.prescriptionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if prescription.Status != models.PrescriptionStatusActive {
+		return nil, errors.New("only active prescriptions can be stopped or discontinued")
+	}
+
+	now := time.Now()
+	prescription.Status = status
+	prescription.DiscontinuedReason = reason
+	prescription.StoppedAt = &now
+	prescription.StoppedByID = &userID
+
+	// A prescription ended before it starts ends on its start date, so the
+	// end date never falls before the start.
+	endDate := now
+	if prescription.StartDate.After(endDate) {
+		endDate = prescription.StartDate
+	}
+	if prescription.EndDate == nil || prescription.EndDate.After(endDate) {
+		prescription.EndDate = &endDate
+	}
+
+	if err := s.prescriptionRepo.Update(prescription); err != nil {
+		return nil, errors.New("failed to update prescription")
+	}
+
+	response := prescription.ToResponse()
+	return &response, nil
+}
+
+func (s *PrescriptionService) reload(patientID, id uint) (*models.PrescriptionResponse, error) {
+	prescription, err := s.prescriptionRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve prescription")
+	}
+
+	response := prescription.ToResponse()
+	return &response, nil
+}
+
+func toPrescriptionResponses(prescriptions []*models.Prescription) []models.PrescriptionResponse {
+	responses := make([]models.PrescriptionResponse, len(prescriptions))
+	for i, prescription := range prescriptions {
+		responses[i] = prescription.ToResponse()
+	}
+	return responses
+}
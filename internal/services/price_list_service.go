@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreatePriceListItemRequest struct {
+	Code           string  `json:"code" binding:"required,max=50"`
+	Name           string  `json:"name" binding:"required,max=200"`
+	Category       string  `json:"category" binding:"max=100"`
+	UnitPriceCents int64   `json:"unit_price_cents" binding:"min=0"`
+	TaxRatePercent float64 `json:"tax_rate_percent" binding:"min=0,max=100"`
+}
+
+type UpdatePriceListItemRequest struct {
+	Name           *string  `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
+	Category       *string  `json:"category,omitempty" binding:"omitempty,max=100"`
+	UnitPriceCents *int64   `json:"unit_price_cents,omitempty" binding:"omitempty,min=0"`
+	TaxRatePercent *float64 `json:"tax_rate_percent,omitempty" binding:"omitempty,min=0,max=100"`
+	IsActive       *bool    `json:"is_active,omitempty"`
+}
+
+type PriceListQuery struct {
+	Search          string
+	Category        string
+	IncludeInactive bool
+}
+
+type PriceListResponse struct {
+	Items      []*models.PriceListItem `json:"items"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// PriceListService manages the billable services that invoice lines are
+// priced from. Items are deactivated rather than deleted.
+type PriceListService struct {
+	priceListRepo repository.PriceListRepository
+}
+
+func NewPriceListService(priceListRepo repository.PriceListRepository) *PriceListService {
+	return &PriceListService{
+		priceListRepo: priceListRepo,
+	}
+}
+
+func (s *PriceListService) ListItems(req PriceListQuery, page, pageSize int) (*PriceListResponse, error) {
+	filter := repository.PriceListFilter{
+		Search:          strings.TrimSpace(req.Search),
+		Category:        strings.TrimSpace(req.Category),
+		IncludeInactive: req.IncludeInactive,
+	}
+
+	offset := (page - 1) * pageSize
+
+	items, err := s.priceListRepo.Search(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve price list")
+	}
+
+	total, err := s.priceListRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("failed to count price list items")
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &PriceListResponse{
+		Items: items,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+func (s *PriceListService) GetItem(id uint) (*models.PriceListItem, error) {
+	return s.priceListRepo.GetByID(id)
+}
+
+func (s *PriceListService) CreateItem(req CreatePriceListItemRequest) (*models.PriceListItem, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if code == "" {
+		return nil, errors.New("price list item code is required")
+	}
+
+	if _, err := s.priceListRepo.GetByCode(code); err == nil {
+		return nil, errors.New("price list item already exists")
+	}
+
+	item := &models.PriceListItem{
+		Code:           code,
+		Name:           strings.TrimSpace(req.Name),
+		Category:       strings.ToLower(strings.TrimSpace(req.Category)),
+		UnitPriceCents: req.UnitPriceCents,
+		TaxRatePercent: req.TaxRatePercent,
+		IsActive:       true,
+	}
+
+	if err := s.priceListRepo.Create(item); err != nil {
+		return nil, errors.New("failed to create price list item")
+	}
+
+	return item, nil
+}
+
+// UpdateItem changes the price for future invoice lines only; lines already
+// on invoices keep the price they were added with.
+func (s *PriceListService) UpdateItem(id uint, req UpdatePriceListItemRequest) (*models.PriceListItem, error) {
+	item, err := s.priceListRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		item.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Category != nil {
+		item.Category = strings.ToLower(strings.TrimSpace(*req.Category))
+	}
+	if req.UnitPriceCents != nil {
+		item.UnitPriceCents = *req.UnitPriceCents
+	}
+	if req.TaxRatePercent != nil {
+		item.TaxRatePercent = *req.TaxRatePercent
+	}
+	if req.IsActive != nil {
+		item.IsActive = *req.IsActive
+	}
+
+	if err := s.priceListRepo.Update(item); err != nil {
+		return nil, errors.New("failed to update price list item")
+	}
+
+	return item, nil
+}
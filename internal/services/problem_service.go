@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateProblemRequest struct {
+	ICD10Code   string `json:"icd10_code" binding:"required,max=10"`
+	Description string `json:"description" binding:"max=500"` // Defaults to the code table description
+	OnsetDate   string `json:"onset_date"`                    // Format: YYYY-MM-DD
+	Notes       string `json:"notes" binding:"max=2000"`
+}
+
+type UpdateProblemRequest struct {
+	Description  *string               `json:"description,omitempty" binding:"omitempty,min=1,max=500"`
+	OnsetDate    *string               `json:"onset_date,omitempty"`
+	ResolvedDate *string               `json:"resolved_date,omitempty"` // Defaults to today when resolving
+	Status       *models.ProblemStatus `json:"status,omitempty" binding:"omitempty,oneof=active resolved"`
+	Notes        *string               `json:"notes,omitempty" binding:"omitempty,max=2000"`
+}
+
+type DiagnosisQuery struct {
+	Code   string
+	Status models.ProblemStatus
+}
+
+type ProblemService struct {
+	problemRepo repository.ProblemRepository
+	icd10Repo   repository.ICD10Repository
+	patientRepo repository.PatientRepository
+	policy      *authz.Policy
+}
+
+func NewProblemService(problemRepo repository.ProblemRepository, icd10Repo repository.ICD10Repository, patientRepo repository.PatientRepository, policy *authz.Policy) *ProblemService {
+	return &ProblemService{
+		problemRepo: problemRepo,
+		icd10Repo:   icd10Repo,
+		patientRepo: patientRepo,
+		policy:      policy,
+	}
+}
+
+func (s *ProblemService) ListProblems(patientID uint, status models.ProblemStatus, userRole models.UserRole) ([]models.ProblemResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ProblemRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	problems, err := s.problemRepo.ListByPatient(patientID, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve problems")
+	}
+
+	responses := make([]models.ProblemResponse, len(problems))
+	for i, problem := range problems {
+		responses[i] = problem.ToResponse()
+	}
+	return responses, nil
+}
+
+func (s *ProblemService) GetProblem(patientID, id uint, userRole models.UserRole) (*models.ProblemResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ProblemRead); err != nil {
+		return nil, err
+	}
+
+	problem, err := s.problemRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := problem.ToResponse()
+	return &response, nil
+}
+
+func (s *ProblemService) CreateProblem(patientID uint, req CreateProblemRequest, recordedByID uint, userRole models.UserRole) (*models.ProblemResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ProblemWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID); err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	code, err := NormalizeICD10Code(req.ICD10Code)
+	if err != nil {
+		return nil, err
+	}
+
+	icd10Code, err := s.icd10Repo.GetByCode(code)
+	if err != nil {
+		return nil, errors.New("ICD-10 code not found")
+	}
+
+	if _, err := s.problemRepo.FindActiveByCode(patientID, code); err == nil {
+		return nil, errors.New("an active problem with this code is already recorded")
+	}
+
+	onsetDate, err := parseOnsetDate(req.OnsetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		description = icd10Code.Description
+	}
+
+	problem := &models.Problem{
+		PatientID:    patientID,
+		ICD10Code:    code,
+		Description:  description,
+		OnsetDate:    onsetDate,
+		Status:       models.ProblemStatusActive,
+		Notes:        req.Notes,
+		RecordedByID: recordedByID,
+	}
+
+	if err := s.problemRepo.Create(problem); err != nil {
+		return nil, errors.New("failed to create problem")
+	}
+
+	created, err := s.problemRepo.GetByID(patientID, problem.ID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve created problem")
+	}
+
+	response := created.ToResponse()
+	return &response, nil
+}
+
+// UpdateProblem edits a problem list entry. Resolving a problem records the
+// resolved date, today unless given; reactivating it clears the date.
+func (s *ProblemService) UpdateProblem(patientID, id uint, req UpdateProblemRequest, updatedByID uint, userRole models.UserRole) (*models.ProblemResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ProblemWrite); err != nil {
+		return nil, err
+	}
+
+	problem, err := s.problemRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		problem.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.OnsetDate != nil {
+		onsetDate, err := parseOnsetDate(*req.OnsetDate)
+		if err != nil {
+			return nil, err
+		}
+		problem.OnsetDate = onsetDate
+	}
+	if req.Notes != nil {
+		problem.Notes = *req.Notes
+	}
+
+	if req.Status != nil && *req.Status != problem.Status {
+		if *req.Status == models.ProblemStatusActive {
+			if _, err := s.problemRepo.FindActiveByCode(patientID, problem.ICD10Code); err == nil {
+				return nil, errors.New("an active problem with this code is already recorded")
+			}
+			problem.ResolvedDate = nil
+		} else if req.ResolvedDate == nil {
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			problem.ResolvedDate = &today
+		}
+		problem.Status = *req.Status
+	}
+
+	if req.ResolvedDate != nil {
+		if problem.Status != models.ProblemStatusResolved {
+			return nil, errors.New("only resolved problems have a resolved date")
+		}
+		resolvedDate, err := time.Parse("2006-01-02", *req.ResolvedDate)
+		if err != nil {
+			return nil, errors.New("invalid resolved date format, use YYYY-MM-DD")
+		}
+		if resolvedDate.After(time.Now()) {
+			return nil, errors.New("resolved date cannot be in the future")
+		}
+		problem.ResolvedDate = &resolvedDate
+	}
+
+	if problem.OnsetDate != nil && problem.ResolvedDate != nil && problem.ResolvedDate.Before(*problem.OnsetDate) {
+		return nil, errors.New("resolved date cannot be before onset date")
+	}
+
+	problem.LastUpdatedByID = &updatedByID
+
+	if err := s.problemRepo.Update(problem); err != nil {
+		return nil, errors.New("failed to update problem")
+	}
+
+	response := problem.ToResponse()
+	return &response, nil
+}
+
+// ListPatientsWithDiagnosis finds the patients whose problem list contains
+// the code or one of its subcodes.
+func (s *ProblemService) ListPatientsWithDiagnosis(req DiagnosisQuery, page, pageSize int, userRole models.UserRole) (*PatientListResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.ProblemRead); err != nil {
+		return nil, err
+	}
+
+	code, err := NormalizeICD10Code(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.DiagnosisFilter{Code: code, Status: req.Status}
+	offset := (page - 1) * pageSize
+
+	patients, err := s.problemRepo.ListPatientsWithDiagnosis(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve patients")
+	}
+
+	total, err := s.problemRepo.CountPatientsWithDiagnosis(filter)
+	if err != nil {
+		return nil, errors.New("failed to count patients")
+	}
+
+	patientResponses := make([]models.PatientResponse, len(patients))
+	for i, patient := range patients {
+		patientResponses[i] = patient.ToResponse()
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &PatientListResponse{
+		Patients: patientResponses,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/pkg/utils"
+)
+
+// MinPasswordLength is the shortest password accepted for an account. The
+// binding tags below repeat it, as tags cannot refer to a constant.
+const MinPasswordLength = 12
+
+type RegisterRequest struct {
+	Username  string          `json:"username" binding:"required,min=3,max=50"`
+	Email     string          `json:"email" binding:"required,email"`
+	Password  string          `json:"password" binding:"required,min=12"`
+	FirstName string          `json:"first_name" binding:"required,min=2,max=50"`
+	LastName  string          `json:"last_name" binding:"required,min=2,max=50"`
+	Role      models.UserRole `json:"role" binding:"required"`
+}
+
+type UpdateUserRequest struct {
+	Email     *string          `json:"email,omitempty" binding:"omitempty,email"`
+	FirstName *string          `json:"first_name,omitempty" binding:"omitempty,min=2,max=50"`
+	LastName  *string          `json:"last_name,omitempty" binding:"omitempty,min=2,max=50"`
+	Role      *models.UserRole `json:"role,omitempty"`
+}
+
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required,min=12"`
+}
+
+// UserListQuery filters the staff list. Status is one of active, inactive or
+// all and defaults to active.
+type UserListQuery struct {
+	Role   models.UserRole
+	Status string
+	Search string
+}
+
+type UserListResponse struct {
+	Users      []models.UserResponse `json:"users"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
+type UserService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	policy      *authz.Policy
+}
+
+func NewUserService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, policy *authz.Policy) *UserService {
+	return &UserService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		policy:      policy,
+	}
+}
+
+func (s *UserService) ListUsers(req UserListQuery, page, pageSize int) (*UserListResponse, error) {
+	filter := repository.UserFilter{
+		Role:   req.Role,
+		Search: strings.TrimSpace(req.Search),
+	}
+
+	switch req.Status {
+	case "", "active":
+		active := true
+		filter.IsActive = &active
+	case "inactive":
+		active := false
+		filter.IsActive = &active
+	case "all":
+	default:
+		return nil, errors.New("status must be one of active, inactive or all")
+	}
+
+	if filter.Role != "" && !s.policy.HasRole(filter.Role) {
+		return nil, s.invalidRoleError()
+	}
+
+	offset := (page - 1) * pageSize
+
+	users, err := s.userRepo.Search(filter, pageSize, offset)
+	if err != nil {
+		return nil, errors.New("failed to retrieve users")
+	}
+
+	total, err := s.userRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("failed to count users")
+	}
+
+	userResponses := make([]models.UserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = user.ToResponse()
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &UserListResponse{
+		Users: userResponses,
+		Pagination: PaginationResponse{
+			Total:       total,
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+		},
+	}, nil
+}
+
+func (s *UserService) GetUser(id uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingInactive(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+func (s *UserService) CreateUser(req RegisterRequest) (*models.UserResponse, error) {
+	if !s.policy.HasRole(req.Role) {
+		return nil, s.invalidRoleError()
+	}
+
+	// Deactivated accounts keep their username and email, so they are
+	// checked as well.
+	existingUser, err := s.userRepo.GetByUsernameIncludingInactive(req.Username)
+	if err == nil && existingUser != nil {
+		return nil, errors.New("username already exists")
+	}
+
+	existingUser, err = s.userRepo.GetByEmailIncludingInactive(req.Email)
+	if err == nil && existingUser != nil {
+		return nil, errors.New("email already exists")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, errors.New("failed to process password")
+	}
+
+	user := &models.User{
+		Username:  req.Username,
+		Email:     req.Email,
+		Password:  hashedPassword,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Role:      req.Role,
+		IsActive:  true,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, errors.New("failed to create user")
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+func (s *UserService) UpdateUser(id uint, req UpdateUserRequest, actorID uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingInactive(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if req.Email != nil && *req.Email != user.Email {
+		existingUser, err := s.userRepo.GetByEmailIncludingInactive(*req.Email)
+		if err == nil && existingUser != nil && existingUser.ID != user.ID {
+			return nil, errors.New("email already exists")
+		}
+		user.Email = *req.Email
+	}
+	if req.FirstName != nil {
+		user.FirstName = *req.FirstName
+	}
+	if req.LastName != nil {
+		user.LastName = *req.LastName
+	}
+
+	roleChanged := req.Role != nil && *req.Role != user.Role
+	if roleChanged {
+		if !s.policy.HasRole(*req.Role) {
+			return nil, s.invalidRoleError()
+		}
+		if user.ID == actorID {
+			return nil, errors.New("you cannot change your own role")
+		}
+		user.Role = *req.Role
+	}
+
+	if roleChanged {
+		err = s.userRepo.UpdateRole(user)
+	} else {
+		err = s.userRepo.Update(user)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrLastActiveAdmin) {
+			return nil, err
+		}
+		return nil, errors.New("failed to update user")
+	}
+
+	// Access tokens carry the role, so existing sessions would keep the old
+	// permissions until they expire.
+	if roleChanged {
+		if err := s.revokeSessions(user.ID, "role_changed"); err != nil {
+			return nil, err
+		}
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// DeactivateUser disables the account and ends all of its sessions. The
+// record is kept so that audit trails referencing the user stay intact.
+func (s *UserService) DeactivateUser(id uint, actorID uint) error {
+	if id == actorID {
+		return errors.New("you cannot deactivate your own account")
+	}
+
+	user, err := s.userRepo.GetByIDIncludingInactive(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.IsActive {
+		return errors.New("user is already deactivated")
+	}
+
+	if err := s.userRepo.Delete(user.ID); err != nil {
+		if errors.Is(err, repository.ErrLastActiveAdmin) {
+			return err
+		}
+		return errors.New("failed to deactivate user")
+	}
+
+	return s.revokeSessions(user.ID, "user_deactivated")
+}
+
+func (s *UserService) ReactivateUser(id uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingInactive(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.IsActive {
+		return nil, errors.New("user is already active")
+	}
+
+	user.IsActive = true
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("failed to reactivate user")
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// ResetPassword sets a new password chosen by an administrator and signs the
+// user out everywhere.
+func (s *UserService) ResetPassword(id uint, req ResetPasswordRequest) error {
+	user, err := s.userRepo.GetByIDIncludingInactive(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return errors.New("failed to process password")
+	}
+
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to reset password")
+	}
+
+	return s.revokeSessions(user.ID, "password_reset")
+}
+
+// revokeSessions runs after the user record has been saved, so a failure is
+// reported to the caller rather than silently leaving old sessions usable.
+func (s *UserService) revokeSessions(userID uint, reason string) error {
+	if err := s.sessionRepo.RevokeAllForUser(userID, reason); err != nil {
+		log.Printf("Failed to revoke sessions for user %d (%s): %v", userID, reason, err)
+		return errors.New("failed to revoke user sessions")
+	}
+	return nil
+}
+
+// invalidRoleError lists the roles defined by the policy so that callers can
+// see which values are accepted.
+func (s *UserService) invalidRoleError() error {
+	roles := s.policy.Roles()
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return errors.New("role must be one of " + strings.Join(names, ", "))
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/clinical"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+const (
+	defaultVitalSignsWindow = 90 * 24 * time.Hour
+	maxVitalSignsWindow     = 2 * 366 * 24 * time.Hour
+	maxVitalSignsReadings   = 1000
+)
+
+// RecordVitalSignsRequest takes the measurements in SI units: temperature in
+// degrees Celsius, weight in kilograms and height in centimetres. The
+// binding limits only reject values that cannot be real readings.
+type RecordVitalSignsRequest struct {
+	RecordedAt      string   `json:"recorded_at"` // RFC 3339, defaults to now
+	SystolicBP      *int     `json:"systolic_bp" binding:"omitempty,min=30,max=300"`
+	DiastolicBP     *int     `json:"diastolic_bp" binding:"omitempty,min=10,max=200"`
+	HeartRate       *int     `json:"heart_rate" binding:"omitempty,min=10,max=300"`
+	Temperature     *float64 `json:"temperature" binding:"omitempty,min=25,max=45"`
+	RespiratoryRate *int     `json:"respiratory_rate" binding:"omitempty,min=1,max=120"`
+	SpO2            *int     `json:"spo2" binding:"omitempty,min=30,max=100"`
+	WeightKg        *float64 `json:"weight_kg" binding:"omitempty,gt=0,max=700"`
+	HeightCm        *float64 `json:"height_cm" binding:"omitempty,min=20,max=280"`
+	Notes           string   `json:"notes" binding:"max=1000"`
+}
+
+type VitalSignsQuery struct {
+	From string // RFC 3339, defaults to 90 days before To
+	To   string // RFC 3339, defaults to now
+}
+
+// VitalSignsTrend is a single measure over time, ready for charting.
+// ReferenceRange is the patient's current range, if the measure has one.
+type VitalSignsTrend struct {
+	Measure        string                   `json:"measure"`
+	ReferenceRange *clinical.ReferenceRange `json:"reference_range,omitempty"`
+	Points         []VitalSignsTrendPoint   `json:"points"`
+}
+
+type VitalSignsTrendPoint struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	Value      float64   `json:"value"`
+	Abnormal   string    `json:"abnormal,omitempty"` // "low" or "high"
+}
+
+type VitalSignsService struct {
+	vitalSignsRepo repository.VitalSignsRepository
+	patientRepo    repository.PatientRepository
+	policy         *authz.Policy
+}
+
+func NewVitalSignsService(vitalSignsRepo repository.VitalSignsRepository, patientRepo repository.PatientRepository, policy *authz.Policy) *VitalSignsService {
+	return &VitalSignsService{
+		vitalSignsRepo: vitalSignsRepo,
+		patientRepo:    patientRepo,
+		policy:         policy,
+	}
+}
+
+// RecordVitalSigns stores a reading and flags it against the reference
+// ranges for the patient's age. When a weight is given without a height,
+// BMI uses the patient's most recent earlier height.
+func (s *VitalSignsService) RecordVitalSigns(patientID uint, req RecordVitalSignsRequest, recordedByID uint, userRole models.UserRole) (*models.VitalSignsResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.VitalSignsWrite); err != nil {
+		return nil, err
+	}
+
+	patient, err := s.patientRepo.GetByID(patientID)
+	if err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	recordedAt := time.Now()
+	if req.RecordedAt != "" {
+		recordedAt, err = time.Parse(time.RFC3339, req.RecordedAt)
+		if err != nil {
+			return nil, errors.New("invalid recorded_at format, use RFC 3339")
+		}
+		if recordedAt.After(time.Now().Add(5 * time.Minute)) {
+			return nil, errors.New("recorded_at cannot be in the future")
+		}
+	}
+
+	if req.SystolicBP == nil && req.DiastolicBP == nil && req.HeartRate == nil && req.Temperature == nil &&
+		req.RespiratoryRate == nil && req.SpO2 == nil && req.WeightKg == nil && req.HeightCm == nil {
+		return nil, errors.New("at least one measurement is required")
+	}
+
+	if (req.SystolicBP == nil) != (req.DiastolicBP == nil) {
+		return nil, errors.New("systolic and diastolic blood pressure must be recorded together")
+	}
+	if req.SystolicBP != nil && *req.SystolicBP <= *req.DiastolicBP {
+		return nil, errors.New("systolic blood pressure must be higher than diastolic")
+	}
+
+	vitals := &models.VitalSigns{
+		PatientID:       patientID,
+		RecordedAt:      recordedAt,
+		SystolicBP:      req.SystolicBP,
+		DiastolicBP:     req.DiastolicBP,
+		HeartRate:       req.HeartRate,
+		Temperature:     req.Temperature,
+		RespiratoryRate: req.RespiratoryRate,
+		SpO2:            req.SpO2,
+		WeightKg:        req.WeightKg,
+		HeightCm:        req.HeightCm,
+		Notes:           req.Notes,
+		RecordedByID:    recordedByID,
+	}
+
+	if vitals.WeightKg != nil {
+		height := vitals.HeightCm
+		if height == nil {
+			height, err = s.vitalSignsRepo.LatestHeight(patientID, recordedAt)
+			if err != nil {
+				return nil, errors.New("failed to retrieve previous height")
+			}
+		}
+		if height != nil {
+			bmi := calculateBMI(*vitals.WeightKg, *height)
+			vitals.BMI = &bmi
+		}
+	}
+
+	vitals.AbnormalFlags = clinical.EvaluateVitals(vitals, patient.CalculateAge())
+	vitals.IsAbnormal = len(vitals.AbnormalFlags) > 0
+
+	if err := s.vitalSignsRepo.Create(vitals); err != nil {
+		return nil, errors.New("failed to record vital signs")
+	}
+
+	created, err := s.vitalSignsRepo.GetByID(patientID, vitals.ID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve recorded vital signs")
+	}
+
+	response := created.ToResponse()
+	return &response, nil
+}
+
+func (s *VitalSignsService) GetVitalSigns(patientID, id uint, userRole models.UserRole) (*models.VitalSignsResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.VitalSignsRead); err != nil {
+		return nil, err
+	}
+
+	vitals, err := s.vitalSignsRepo.GetByID(patientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := vitals.ToResponse()
+	return &response, nil
+}
+
+// ListVitalSigns returns the readings in the requested period, oldest first.
+func (s *VitalSignsService) ListVitalSigns(patientID uint, req VitalSignsQuery, userRole models.UserRole) ([]models.VitalSignsResponse, error) {
+	if err := s.policy.Authorize(userRole, authz.VitalSignsRead); err != nil {
+		return nil, err
+	}
+
+	vitals, err := s.listInPeriod(patientID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.VitalSignsResponse, len(vitals))
+	for i, v := range vitals {
+		responses[i] = v.ToResponse()
+	}
+	return responses, nil
+}
+
+// GetTrend returns one measure as a time series for charting, with the
+// patient's current reference range to draw as a band.
+func (s *VitalSignsService) GetTrend(patientID uint, measure string, req VitalSignsQuery, userRole models.UserRole) (*VitalSignsTrend, error) {
+	if err := s.policy.Authorize(userRole, authz.VitalSignsRead); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(clinical.VitalMeasures, measure) {
+		return nil, errors.New("unknown vital sign measure")
+	}
+
+	patient, err := s.patientRepo.GetByID(patientID)
+	if err != nil {
+		return nil, errors.New("patient not found")
+	}
+
+	vitals, err := s.listInPeriod(patientID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &VitalSignsTrend{Measure: measure, Points: []VitalSignsTrendPoint{}}
+	if r, ok := clinical.VitalReferenceRanges(patient.CalculateAge())[measure]; ok {
+		trend.ReferenceRange = &r
+	}
+
+	for _, v := range vitals {
+		value, ok := v.Value(measure)
+		if !ok {
+			continue
+		}
+		point := VitalSignsTrendPoint{RecordedAt: v.RecordedAt, Value: value}
+		if flag := v.Flag(measure); flag != nil {
+			point.Abnormal = flag.Status
+		}
+		trend.Points = append(trend.Points, point)
+	}
+	return trend, nil
+}
+
+func (s *VitalSignsService) listInPeriod(patientID uint, req VitalSignsQuery) ([]*models.VitalSigns, error) {
+	to := time.Now()
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return nil, errors.New("invalid to format, use RFC 3339")
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultVitalSignsWindow)
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return nil, errors.New("invalid from format, use RFC 3339")
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	if to.Sub(from) > maxVitalSignsWindow {
+		return nil, errors.New("period cannot be longer than two years")
+	}
+
+	vitals, err := s.vitalSignsRepo.ListByPatient(patientID, from, to, maxVitalSignsReadings)
+	if err != nil {
+		return nil, errors.New("failed to retrieve vital signs")
+	}
+	return vitals, nil
+}
+
+// calculateBMI returns weight / height² rounded to one decimal place.
+func calculateBMI(weightKg, heightCm float64) float64 {
+	heightM := heightCm / 100
+	return math.Round(weightKg/(heightM*heightM)*10) / 10
+}
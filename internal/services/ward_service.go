@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+)
+
+type CreateWardRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+}
+
+type CreateRoomRequest struct {
+	Number string `json:"number" binding:"required,max=20"`
+}
+
+type CreateBedRequest struct {
+	Label string `json:"label" binding:"required,max=20"`
+}
+
+type UpdateBedRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// BedBoardResponse is the bed occupancy board across all active wards.
+type BedBoardResponse struct {
+	Wards         []WardOccupancy `json:"wards"`
+	TotalBeds     int             `json:"total_beds"`
+	OccupiedBeds  int             `json:"occupied_beds"`
+	AvailableBeds int             `json:"available_beds"`
+}
+
+type WardOccupancy struct {
+	ID            uint            `json:"id"`
+	Name          string          `json:"name"`
+	TotalBeds     int             `json:"total_beds"`
+	OccupiedBeds  int             `json:"occupied_beds"`
+	AvailableBeds int             `json:"available_beds"`
+	Rooms         []RoomOccupancy `json:"rooms"`
+}
+
+type RoomOccupancy struct {
+	ID     uint           `json:"id"`
+	Number string         `json:"number"`
+	Beds   []BedOccupancy `json:"beds"`
+}
+
+// BedOccupancy shows who is in a bed. Patient is only filled in for roles
+// that may read admissions.
+type BedOccupancy struct {
+	ID          uint                   `json:"id"`
+	Label       string                 `json:"label"`
+	Occupied    bool                   `json:"occupied"`
+	AdmissionID *uint                  `json:"admission_id,omitempty"`
+	Patient     *models.PatientSummary `json:"patient,omitempty"`
+	AdmittedAt  *time.Time             `json:"admitted_at,omitempty"`
+}
+
+// WardService manages the wards, rooms and beds that patients are admitted
+// to, and builds the occupancy board.
+type WardService struct {
+	wardRepo      repository.WardRepository
+	admissionRepo repository.AdmissionRepository
+	policy        *authz.Policy
+}
+
+func NewWardService(wardRepo repository.WardRepository, admissionRepo repository.AdmissionRepository, policy *authz.Policy) *WardService {
+	return &WardService{
+		wardRepo:      wardRepo,
+		admissionRepo: admissionRepo,
+		policy:        policy,
+	}
+}
+
+func (s *WardService) ListWards(includeInactive bool) ([]*models.Ward, error) {
+	wards, err := s.wardRepo.ListWards(includeInactive)
+	if err != nil {
+		return nil, errors.New("failed to retrieve wards")
+	}
+	return wards, nil
+}
+
+func (s *WardService) CreateWard(req CreateWardRequest) (*models.Ward, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("ward name is required")
+	}
+
+	if _, err := s.wardRepo.GetWardByName(name); err == nil {
+		return nil, errors.New("ward already exists")
+	}
+
+	ward := &models.Ward{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		IsActive:    true,
+	}
+
+	if err := s.wardRepo.CreateWard(ward); err != nil {
+		return nil, errors.New("failed to create ward")
+	}
+
+	return ward, nil
+}
+
+func (s *WardService) CreateRoom(wardID uint, req CreateRoomRequest) (*models.Room, error) {
+	if _, err := s.wardRepo.GetWard(wardID); err != nil {
+		return nil, err
+	}
+
+	number := strings.TrimSpace(req.Number)
+	if number == "" {
+		return nil, errors.New("room number is required")
+	}
+
+	room := &models.Room{
+		WardID:   wardID,
+		Number:   number,
+		IsActive: true,
+	}
+
+	if err := s.wardRepo.CreateRoom(room); err != nil {
+		return nil, errors.New("failed to create room")
+	}
+
+	return room, nil
+}
+
+func (s *WardService) CreateBed(wardID, roomID uint, req CreateBedRequest) (*models.Bed, error) {
+	if _, err := s.wardRepo.GetRoom(wardID, roomID); err != nil {
+		return nil, err
+	}
+
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return nil, errors.New("bed label is required")
+	}
+
+	bed := &models.Bed{
+		RoomID:   roomID,
+		Label:    label,
+		IsActive: true,
+	}
+
+	if err := s.wardRepo.CreateBed(bed); err != nil {
+		return nil, errors.New("failed to create bed")
+	}
+
+	return bed, nil
+}
+
+// UpdateBed takes a bed in or out of service. Occupied beds must be vacated
+// first.
+func (s *WardService) UpdateBed(id uint, req UpdateBedRequest) (*models.Bed, error) {
+	bed, err := s.wardRepo.GetBed(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if bed.IsActive && !*req.IsActive {
+		occupied, err := s.admissionRepo.IsBedOccupied(bed.ID)
+		if err != nil {
+			return nil, errors.New("failed to check bed occupancy")
+		}
+		if occupied {
+			return nil, errors.New("occupied beds cannot be taken out of service")
+		}
+	}
+
+	bed.IsActive = *req.IsActive
+	if err := s.wardRepo.UpdateBed(bed); err != nil {
+		return nil, errors.New("failed to update bed")
+	}
+
+	return bed, nil
+}
+
+// Board lists every active bed with its current occupant.
+func (s *WardService) Board(userRole models.UserRole) (*BedBoardResponse, error) {
+	wards, err := s.wardRepo.ListWards(false)
+	if err != nil {
+		return nil, errors.New("failed to retrieve wards")
+	}
+
+	admissions, err := s.admissionRepo.ListActive()
+	if err != nil {
+		return nil, errors.New("failed to retrieve admissions")
+	}
+
+	byBed := make(map[uint]*models.Admission, len(admissions))
+	for _, admission := range admissions {
+		byBed[admission.BedID] = admission
+	}
+
+	showPatients := s.policy.Can(userRole, authz.AdmissionRead)
+	board := &BedBoardResponse{Wards: make([]WardOccupancy, 0, len(wards))}
+
+	for _, ward := range wards {
+		wardOccupancy := WardOccupancy{ID: ward.ID, Name: ward.Name, Rooms: make([]RoomOccupancy, 0, len(ward.Rooms))}
+
+		for _, room := range ward.Rooms {
+			roomOccupancy := RoomOccupancy{ID: room.ID, Number: room.Number, Beds: make([]BedOccupancy, 0, len(room.Beds))}
+
+			for _, bed := range room.Beds {
+				bedOccupancy := BedOccupancy{ID: bed.ID, Label: bed.Label}
+				if admission, ok := byBed[bed.ID]; ok {
+					bedOccupancy.Occupied = true
+					if showPatients {
+						admissionID := admission.ID
+						admittedAt := admission.AdmittedAt
+						bedOccupancy.AdmissionID = &admissionID
+						bedOccupancy.AdmittedAt = &admittedAt
+						if admission.Patient != nil {
+							patient := admission.Patient.ToSummary()
+							bedOccupancy.Patient = &patient
+						}
+					}
+					wardOccupancy.OccupiedBeds++
+				}
+				wardOccupancy.TotalBeds++
+				roomOccupancy.Beds = append(roomOccupancy.Beds, bedOccupancy)
+			}
+
+			wardOccupancy.Rooms = append(wardOccupancy.Rooms, roomOccupancy)
+		}
+
+		wardOccupancy.AvailableBeds = wardOccupancy.TotalBeds - wardOccupancy.OccupiedBeds
+		board.TotalBeds += wardOccupancy.TotalBeds
+		board.OccupiedBeds += wardOccupancy.OccupiedBeds
+		board.Wards = append(board.Wards, wardOccupancy)
+	}
+
+	board.AvailableBeds = board.TotalBeds - board.OccupiedBeds
+	return board, nil
+}
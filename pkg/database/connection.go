@@ -51,16 +51,214 @@ func Migrate() error {
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.Patient{},
+		&models.PatientRevision{},
+		&models.PatientAlias{},
+		&models.PatientIDCounter{},
+		&models.PatientAccessLog{},
+		&models.UserSession{},
+		&models.RefreshToken{},
+		&models.MFARecoveryCode{},
+		&models.MFAPolicy{},
+		&models.MFAChallenge{},
+		&models.Appointment{},
+		&models.DoctorSchedule{},
+		&models.DoctorWorkingHours{},
+		&models.DoctorBreak{},
+		&models.DoctorScheduleException{},
+		&models.Encounter{},
+		&models.EncounterAddendum{},
+		&models.Allergy{},
+		&models.Medication{},
+		&models.Prescription{},
+		&models.PrescriptionSafetyOverride{},
+		&models.ICD10Code{},
+		&models.Problem{},
+		&models.VitalSigns{},
+		&models.LabOrder{},
+		&models.LabResult{},
+		&models.Ward{},
+		&models.Room{},
+		&models.Bed{},
+		&models.Admission{},
+		&models.BedTransfer{},
+		&models.EDVisit{},
+		&models.PriceListItem{},
+		&models.Invoice{},
+		&models.InvoiceItem{},
+		&models.Payment{},
+		&models.InsurancePayer{},
+		&models.InsurancePolicy{},
+		&models.Claim{},
+		&models.ClaimBatch{},
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// The unique index on patients.email dates from before PHI encryption.
+	// It now compares ciphertext, so uniqueness rests on email_index alone.
+	if DB.Migrator().HasIndex(&models.Patient{}, "idx_patients_email") {
+		if err := DB.Migrator().DropIndex(&models.Patient{}, "idx_patients_email"); err != nil {
+			return fmt.Errorf("failed to drop patient email index: %w", err)
+		}
+	}
+
+	if err := migratePatientSearch(DB); err != nil {
+		return fmt.Errorf("failed to migrate patient search: %w", err)
+	}
+
+	if err := migrateLegacyAllergies(DB); err != nil {
+		return fmt.Errorf("failed to migrate legacy allergies: %w", err)
+	}
+
+	if err := migrateLegacyMedications(DB); err != nil {
+		return fmt.Errorf("failed to migrate legacy medications: %w", err)
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
 
+// patientSearchVector is the expression of the generated
+// patients.search_vector column: names and patient ID, and the Double
+// Metaphone codes of the names at a lower weight so that exact spellings
+// rank first. The column is only added when missing, so changing the
+// expression needs the column to be dropped first.
+const patientSearchVector = `setweight(to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, '') || ' ' || coalesce(patient_id, '')), 'A') ||
+	setweight(to_tsvector('simple', dmetaphone(coalesce(first_name, '')) || ' ' || dmetaphone(coalesce(last_name, ''))), 'C')`
+
+// migratePatientSearch sets up what patient search relies on: the pg_trgm
+// and fuzzystrmatch extensions, the search_vector column and the indexes
+// for text search and name similarity.
+func migratePatientSearch(db *gorm.DB) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE EXTENSION IF NOT EXISTS fuzzystrmatch",
+	}
+	if !db.Migrator().HasColumn("patients", "search_vector") {
+		statements = append(statements,
+			"ALTER TABLE patients ADD COLUMN search_vector tsvector GENERATED ALWAYS AS ("+patientSearchVector+") STORED")
+	}
+	statements = append(statements,
+		"CREATE INDEX IF NOT EXISTS idx_patients_search_vector ON patients USING gin (search_vector)",
+		"CREATE INDEX IF NOT EXISTS idx_patients_name_trgm ON patients USING gin ((LOWER(first_name || ' ' || last_name)) gin_trgm_ops)",
+	)
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateLegacyAllergies carries the old free-text patients.allergies column
+// over to legacy allergy records and then drops the column. Once the column
+// is gone it does nothing, so it is safe to run on every start.
+func migrateLegacyAllergies(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("patients", "allergies") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID              uint
+			Allergies       string
+			CreatedByID     uint
+			LastUpdatedByID *uint
+		}
+		if err := tx.Table("patients").
+			Select("id, allergies, created_by_id, last_updated_by_id").
+			Where("allergies IS NOT NULL AND TRIM(allergies) <> ''").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		allergies := make([]models.Allergy, 0, len(rows))
+		for _, row := range rows {
+			recordedByID := row.CreatedByID
+			if row.LastUpdatedByID != nil {
+				recordedByID = *row.LastUpdatedByID
+			}
+			allergies = append(allergies, models.Allergy{
+				PatientID:    row.ID,
+				Substance:    models.LegacyAllergySubstance,
+				Category:     models.AllergyCategoryOther,
+				Status:       models.AllergyStatusActive,
+				Notes:        row.Allergies,
+				IsLegacy:     true,
+				RecordedByID: recordedByID,
+			})
+		}
+
+		if len(allergies) > 0 {
+			if err := tx.CreateInBatches(allergies, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Migrated %d legacy allergy notes", len(allergies))
+		return tx.Migrator().DropColumn("patients", "allergies")
+	})
+}
+
+// migrateLegacyMedications carries the old free-text
+// patients.current_medications column over to legacy prescriptions and then
+// drops the column, in the same way as migrateLegacyAllergies. Nobody is
+// known to have prescribed a legacy note, so they have no prescriber; notes
+// migrated when the patient's last editor was recorded instead are cleared.
+func migrateLegacyMedications(db *gorm.DB) error {
+	if err := db.Exec("ALTER TABLE prescriptions ALTER COLUMN prescriber_id DROP NOT NULL").Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.Prescription{}).
+		Where("is_legacy AND prescriber_id IS NOT NULL").
+		UpdateColumn("prescriber_id", nil).Error; err != nil {
+		return err
+	}
+
+	if !db.Migrator().HasColumn("patients", "current_medications") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID                 uint
+			CurrentMedications string
+		}
+		if err := tx.Table("patients").
+			Select("id, current_medications").
+			Where("current_medications IS NOT NULL AND TRIM(current_medications) <> ''").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		prescriptions := make([]models.Prescription, 0, len(rows))
+		for _, row := range rows {
+			prescriptions = append(prescriptions, models.Prescription{
+				PatientID:      row.ID,
+				MedicationName: models.LegacyMedicationName,
+				Route:          models.MedicationRouteOther,
+				Instructions:   row.CurrentMedications,
+				StartDate:      now,
+				Status:         models.PrescriptionStatusActive,
+				IsLegacy:       true,
+			})
+		}
+
+		if len(prescriptions) > 0 {
+			if err := tx.Omit("Medication", "Prescriber").CreateInBatches(prescriptions, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Migrated %d legacy medication notes", len(prescriptions))
+		return tx.Migrator().DropColumn("patients", "current_medications")
+	})
+}
+
 func GetDB() *gorm.DB {
 	return DB
 }
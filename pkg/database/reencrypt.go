@@ -0,0 +1,198 @@
+package database
+
+import (
+	"fmt"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/phi"
+
+	"gorm.io/gorm"
+)
+
+// ReencryptStats counts the records rewritten by ReencryptPHI.
+type ReencryptStats struct {
+	Patients      int
+	Revisions     int
+	Allergies     int
+	Prescriptions int
+}
+
+// ReencryptPHI encrypts patients, patient revisions, allergies and
+// prescriptions that are still in plaintext or whose data key is wrapped with an older master key, so that
+// the old key can be removed from the keyfile afterwards. With all set,
+// every record is rewritten, which also rebuilds the blind indexes. Records
+// are processed in batches of batchSize, each in its own transaction, so
+// the command can be interrupted and run again.
+//
+// It uses the keyring installed with phi.SetKeyring, which is also what the
+// model hooks read the records with. Only the encryption columns are
+// written; updated_at and the revision history are left alone because the
+// PHI itself does not change.
+func ReencryptPHI(batchSize int, all bool) (ReencryptStats, error) {
+	var stats ReencryptStats
+	if DB == nil {
+		return stats, fmt.Errorf("database connection not established")
+	}
+	keyring := phi.CurrentKeyring()
+	if keyring == nil {
+		return stats, fmt.Errorf("no PHI keyring configured")
+	}
+
+	pending := func(query *gorm.DB) *gorm.DB {
+		if all {
+			return query
+		}
+		return query.Where("key_version <> ?", keyring.ActiveVersion())
+	}
+
+	var lastID uint
+	for {
+		var patients []*models.Patient
+		if err := pending(DB.Unscoped().Where("id > ?", lastID)).
+			Order("id ASC").Limit(batchSize).Find(&patients).Error; err != nil {
+			return stats, fmt.Errorf("failed to load patients: %w", err)
+		}
+		if len(patients) == 0 {
+			break
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			for _, patient := range patients {
+				if err := patient.SealPHI(keyring); err != nil {
+					return fmt.Errorf("patient %d: %w", patient.ID, err)
+				}
+				if err := tx.Model(&models.Patient{}).Where("id = ?", patient.ID).
+					UpdateColumns(map[string]interface{}{
+						"email":              patient.Email,
+						"phone":              patient.Phone,
+						"address":            patient.Address,
+						"medical_history":    patient.MedicalHistory,
+						"encrypted_data_key": patient.EncryptedDataKey,
+						"key_version":        patient.KeyVersion,
+						"phone_index":        patient.PhoneIndex,
+						"email_index":        patient.EmailIndex,
+					}).Error; err != nil {
+					return fmt.Errorf("patient %d: %w", patient.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Patients += len(patients)
+		lastID = patients[len(patients)-1].ID
+	}
+
+	lastID = 0
+	for {
+		var revisions []*models.PatientRevision
+		if err := pending(DB.Where("id > ?", lastID)).
+			Order("id ASC").Limit(batchSize).Find(&revisions).Error; err != nil {
+			return stats, fmt.Errorf("failed to load patient revisions: %w", err)
+		}
+		if len(revisions) == 0 {
+			break
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			for _, revision := range revisions {
+				if err := revision.SealPHI(keyring); err != nil {
+					return fmt.Errorf("patient revision %d: %w", revision.ID, err)
+				}
+				if err := tx.Model(&models.PatientRevision{}).Where("id = ?", revision.ID).
+					UpdateColumns(map[string]interface{}{
+						"changes":            revision.Changes,
+						"snapshot":           revision.Snapshot,
+						"encrypted_data_key": revision.EncryptedDataKey,
+						"key_version":        revision.KeyVersion,
+					}).Error; err != nil {
+					return fmt.Errorf("patient revision %d: %w", revision.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Revisions += len(revisions)
+		lastID = revisions[len(revisions)-1].ID
+	}
+
+	lastID = 0
+	for {
+		var allergies []*models.Allergy
+		if err := pending(DB.Where("id > ?", lastID)).
+			Order("id ASC").Limit(batchSize).Find(&allergies).Error; err != nil {
+			return stats, fmt.Errorf("failed to load allergies: %w", err)
+		}
+		if len(allergies) == 0 {
+			break
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			for _, allergy := range allergies {
+				if err := allergy.SealPHI(keyring); err != nil {
+					return fmt.Errorf("allergy %d: %w", allergy.ID, err)
+				}
+				if err := tx.Model(&models.Allergy{}).Where("id = ?", allergy.ID).
+					UpdateColumns(map[string]interface{}{
+						"substance":          allergy.Substance,
+						"reaction":           allergy.Reaction,
+						"notes":              allergy.Notes,
+						"encrypted_data_key": allergy.EncryptedDataKey,
+						"key_version":        allergy.KeyVersion,
+					}).Error; err != nil {
+					return fmt.Errorf("allergy %d: %w", allergy.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Allergies += len(allergies)
+		lastID = allergies[len(allergies)-1].ID
+	}
+
+	lastID = 0
+	for {
+		var prescriptions []*models.Prescription
+		if err := pending(DB.Where("id > ?", lastID)).
+			Order("id ASC").Limit(batchSize).Find(&prescriptions).Error; err != nil {
+			return stats, fmt.Errorf("failed to load prescriptions: %w", err)
+		}
+		if len(prescriptions) == 0 {
+			break
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			for _, prescription := range prescriptions {
+				if err := prescription.SealPHI(keyring); err != nil {
+					return fmt.Errorf("prescription %d: %w", prescription.ID, err)
+				}
+				if err := tx.Model(&models.Prescription{}).Where("id = ?", prescription.ID).
+					UpdateColumns(map[string]interface{}{
+						"instructions":        prescription.Instructions,
+						"discontinued_reason": prescription.DiscontinuedReason,
+						"encrypted_data_key":  prescription.EncryptedDataKey,
+						"key_version":         prescription.KeyVersion,
+					}).Error; err != nil {
+					return fmt.Errorf("prescription %d: %w", prescription.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Prescriptions += len(prescriptions)
+		lastID = prescriptions[len(prescriptions)-1].ID
+	}
+
+	return stats, nil
+}
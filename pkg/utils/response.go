@@ -34,6 +34,22 @@ func ErrorResponse(c *gin.Context, statusCode int, message string, err error) {
 	c.JSON(statusCode, response)
 }
 
+// ErrorResponseWithData is ErrorResponse for failures that carry details
+// the client needs to act on.
+func ErrorResponseWithData(c *gin.Context, statusCode int, message string, err error, data interface{}) {
+	response := APIResponse{
+		Success: false,
+		Message: message,
+		Data:    data,
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	c.JSON(statusCode, response)
+}
+
 func ValidationErrorResponse(c *gin.Context, message string, err error) {
 	ErrorResponse(c, http.StatusBadRequest, message, err)
 }
@@ -0,0 +1,260 @@
+package unit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/phi"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAccessLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessLogRepository) CreateBatch(logs []*models.PatientAccessLog) error {
+	args := m.Called(logs)
+	return args.Error(0)
+}
+
+func (m *MockAccessLogRepository) Search(filter repository.AccessLogFilter, limit, offset int) ([]*models.PatientAccessLog, error) {
+	args := m.Called(filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PatientAccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) Count(filter repository.AccessLogFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestAccessLogService_RecordPatientAccess_DeduplicatesPatients(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(logs []*models.PatientAccessLog) bool {
+		return len(logs) == 2 &&
+			logs[0].PatientID == 1 && logs[1].PatientID == 2 &&
+			logs[0].UserID == 5 && logs[0].UserRole == models.RoleDoctor &&
+			logs[0].Endpoint == "GET /api/v1/patients" && logs[0].ClientIP == "10.0.0.1"
+	})).Return(nil)
+
+	err := accessLogService.RecordPatientAccess(services.AccessContext{
+		UserID:   5,
+		UserRole: models.RoleDoctor,
+		Endpoint: "GET /api/v1/patients",
+		ClientIP: "10.0.0.1",
+	}, []uint{1, 2, 1})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAccessLogService_ListAccessLogs_RequiresFilter(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	response, err := accessLogService.ListAccessLogs(services.AccessLogQuery{}, 1, 10)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "patient_id or user_id is required", err.Error())
+}
+
+func TestAccessLogService_ListAccessLogs_InvalidDateRange(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	patientID := uint(1)
+	response, err := accessLogService.ListAccessLogs(services.AccessLogQuery{
+		PatientID: &patientID,
+		From:      "2024-02-01",
+		To:        "2024-01-01",
+	}, 1, 10)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "from date must not be after to date", err.Error())
+}
+
+func TestAccessLogService_ListAccessLogs_Success(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	userID := uint(5)
+	logs := []*models.PatientAccessLog{
+		{ID: 1, UserID: 5, PatientID: 1},
+		{ID: 2, UserID: 5, PatientID: 2},
+	}
+
+	filterMatcher := mock.MatchedBy(func(filter repository.AccessLogFilter) bool {
+		return filter.UserID != nil && *filter.UserID == 5 &&
+			filter.From.Format("2006-01-02") == "2024-01-01" &&
+			filter.To.Format("2006-01-02") == "2024-02-01"
+	})
+	mockRepo.On("Search", filterMatcher, 10, 0).Return(logs, nil)
+	mockRepo.On("Count", filterMatcher).Return(int64(2), nil)
+
+	response, err := accessLogService.ListAccessLogs(services.AccessLogQuery{
+		UserID: &userID,
+		From:   "2024-01-01",
+		To:     "2024-01-31",
+	}, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Logs, 2)
+	assert.Equal(t, int64(2), response.Pagination.Total)
+	assert.Equal(t, 1, response.Pagination.TotalPages)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatientAccessLogger_RecordsAccessedPatients(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(logs []*models.PatientAccessLog) bool {
+		return len(logs) == 1 && logs[0].PatientID == 7 && logs[0].UserID == 3 &&
+			logs[0].Endpoint == "GET /patients/:id" && logs[0].Query == "include=all"
+	})).Return(nil)
+
+	router := setupRouter()
+	router.GET("/patients/:id", func(c *gin.Context) {
+		c.Set("user_id", uint(3))
+		c.Set("user_role", models.RoleReceptionist)
+		c.Next()
+	}, middleware.PatientAccessLogger(accessLogService), func(c *gin.Context) {
+		middleware.SetAccessedPatients(c, 7)
+		c.JSON(http.StatusOK, gin.H{"id": 7})
+	})
+
+	req, _ := http.NewRequest("GET", "/patients/7?include=all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":7}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatientAccessLogger_StoresBlindIndexOfSearchedContactDetails(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	phi.SetKeyring(keyring)
+	t.Cleanup(func() { phi.SetKeyring(nil) })
+
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	var queries []string
+	mockRepo.On("CreateBatch", mock.Anything).Run(func(args mock.Arguments) {
+		queries = append(queries, args.Get(0).([]*models.PatientAccessLog)[0].Query)
+	}).Return(nil)
+
+	router := setupRouter()
+	router.GET("/patients/search", func(c *gin.Context) {
+		c.Set("user_id", uint(3))
+		c.Set("user_role", models.RoleReceptionist)
+		c.Next()
+	}, middleware.PatientAccessLogger(accessLogService), func(c *gin.Context) {
+		middleware.SetAccessedPatients(c, 7)
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	for _, term := range []string{"jane.doe@example.com", "+1 555 010 2030", "Jane Doe"} {
+		req, _ := http.NewRequest("GET", "/patients/search?q="+url.QueryEscape(term)+"&page=2", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Len(t, queries, 3)
+	assert.Equal(t, url.Values{"q": {"email_index:" + keyring.EmailIndex("jane.doe@example.com")}, "page": {"2"}}.Encode(), queries[0])
+	assert.Equal(t, url.Values{"q": {"phone_index:" + keyring.PhoneIndex("15550102030")}, "page": {"2"}}.Encode(), queries[1])
+	assert.False(t, strings.Contains(queries[1], "555"))
+	assert.Equal(t, url.Values{"q": {"Jane Doe"}, "page": {"2"}}.Encode(), queries[2])
+}
+
+func TestPatientAccessLogger_SkipsFailedRequests(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	router := setupRouter()
+	router.GET("/patients/:id", func(c *gin.Context) {
+		c.Set("user_id", uint(3))
+		c.Set("user_role", models.RoleReceptionist)
+		c.Next()
+	}, middleware.PatientAccessLogger(accessLogService), func(c *gin.Context) {
+		middleware.SetAccessedPatients(c, 7)
+		c.JSON(http.StatusInternalServerError, gin.H{})
+	})
+
+	req, _ := http.NewRequest("GET", "/patients/7", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything)
+}
+
+func TestPatientAccessLogger_WithholdsResponseWhenLogWriteFails(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	mockRepo.On("CreateBatch", mock.Anything).Return(errors.New("connection refused"))
+
+	router := setupRouter()
+	router.GET("/patients/:id", func(c *gin.Context) {
+		c.Set("user_id", uint(3))
+		c.Set("user_role", models.RoleReceptionist)
+		c.Next()
+	}, middleware.PatientAccessLogger(accessLogService), func(c *gin.Context) {
+		middleware.SetAccessedPatients(c, 7)
+		c.JSON(http.StatusOK, gin.H{"phone": "+15550102030"})
+	})
+
+	req, _ := http.NewRequest("GET", "/patients/7", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "+15550102030")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatientAccessLogger_RecordsPatientsReturnedWithConflict(t *testing.T) {
+	mockRepo := new(MockAccessLogRepository)
+	accessLogService := services.NewAccessLogService(mockRepo)
+
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(logs []*models.PatientAccessLog) bool {
+		return len(logs) == 2 && logs[0].PatientID == 4 && logs[1].PatientID == 9
+	})).Return(nil)
+
+	router := setupRouter()
+	router.POST("/patients", func(c *gin.Context) {
+		c.Set("user_id", uint(3))
+		c.Set("user_role", models.RoleReceptionist)
+		c.Next()
+	}, middleware.PatientAccessLogger(accessLogService), func(c *gin.Context) {
+		middleware.SetAccessedPatients(c, 4, 9)
+		c.JSON(http.StatusConflict, gin.H{"candidates": []uint{4, 9}})
+	})
+
+	req, _ := http.NewRequest("POST", "/patients", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "candidates")
+	mockRepo.AssertExpectations(t)
+}
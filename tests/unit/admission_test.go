@@ -0,0 +1,287 @@
+package unit
+
+import (
+	"testing"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWardRepository struct {
+	mock.Mock
+}
+
+func (m *MockWardRepository) CreateWard(ward *models.Ward) error {
+	args := m.Called(ward)
+	return args.Error(0)
+}
+
+func (m *MockWardRepository) GetWard(id uint) (*models.Ward, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ward), args.Error(1)
+}
+
+func (m *MockWardRepository) GetWardByName(name string) (*models.Ward, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ward), args.Error(1)
+}
+
+func (m *MockWardRepository) ListWards(includeInactive bool) ([]*models.Ward, error) {
+	args := m.Called(includeInactive)
+	return args.Get(0).([]*models.Ward), args.Error(1)
+}
+
+func (m *MockWardRepository) CreateRoom(room *models.Room) error {
+	args := m.Called(room)
+	return args.Error(0)
+}
+
+func (m *MockWardRepository) GetRoom(wardID, id uint) (*models.Room, error) {
+	args := m.Called(wardID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Room), args.Error(1)
+}
+
+func (m *MockWardRepository) CreateBed(bed *models.Bed) error {
+	args := m.Called(bed)
+	return args.Error(0)
+}
+
+func (m *MockWardRepository) GetBed(id uint) (*models.Bed, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Bed), args.Error(1)
+}
+
+func (m *MockWardRepository) UpdateBed(bed *models.Bed) error {
+	args := m.Called(bed)
+	return args.Error(0)
+}
+
+type MockAdmissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAdmissionRepository) Admit(admission *models.Admission) error {
+	args := m.Called(admission)
+	return args.Error(0)
+}
+
+func (m *MockAdmissionRepository) GetByID(patientID, id uint) (*models.Admission, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Admission), args.Error(1)
+}
+
+func (m *MockAdmissionRepository) GetCurrent(patientID uint) (*models.Admission, error) {
+	args := m.Called(patientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Admission), args.Error(1)
+}
+
+func (m *MockAdmissionRepository) ListByPatient(patientID uint) ([]*models.Admission, error) {
+	args := m.Called(patientID)
+	return args.Get(0).([]*models.Admission), args.Error(1)
+}
+
+func (m *MockAdmissionRepository) ListActive() ([]*models.Admission, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Admission), args.Error(1)
+}
+
+func (m *MockAdmissionRepository) IsBedOccupied(bedID uint) (bool, error) {
+	args := m.Called(bedID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAdmissionRepository) Transfer(admission *models.Admission, transfer *models.BedTransfer) error {
+	args := m.Called(admission, transfer)
+	return args.Error(0)
+}
+
+func (m *MockAdmissionRepository) Discharge(admission *models.Admission) error {
+	args := m.Called(admission)
+	return args.Error(0)
+}
+
+func newAdmissionTestService() (*MockAdmissionRepository, *MockWardRepository, *MockPatientRepository, *MockUserRepository, *services.AdmissionService) {
+	admissionRepo := new(MockAdmissionRepository)
+	wardRepo := new(MockWardRepository)
+	patientRepo := new(MockPatientRepository)
+	userRepo := new(MockUserRepository)
+	return admissionRepo, wardRepo, patientRepo, userRepo, services.NewAdmissionService(admissionRepo, wardRepo, patientRepo, userRepo, authz.DefaultPolicy())
+}
+
+func activeBed(id uint) *models.Bed {
+	ward := &models.Ward{ID: 1, Name: "Medical", IsActive: true}
+	room := &models.Room{ID: 2, WardID: 1, Ward: ward, Number: "101", IsActive: true}
+	return &models.Bed{ID: id, RoomID: 2, Room: room, Label: "A", IsActive: true}
+}
+
+func TestAdmissionService_AdmitPatient(t *testing.T) {
+	admissionRepo, wardRepo, patientRepo, userRepo, admissionService := newAdmissionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor}, nil)
+	wardRepo.On("GetBed", uint(7)).Return(activeBed(7), nil)
+	admissionRepo.On("Admit", mock.MatchedBy(func(a *models.Admission) bool {
+		return a.BedID == 7 && a.Status == models.AdmissionStatusAdmitted && a.AdmittedByID == 3
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Admission).ID = 4
+	}).Return(nil)
+	admissionRepo.On("GetByID", uint(1), uint(4)).Return(&models.Admission{ID: 4, PatientID: 1, BedID: 7, Bed: activeBed(7), Status: models.AdmissionStatusAdmitted}, nil)
+
+	response, err := admissionService.AdmitPatient(1, services.AdmitPatientRequest{BedID: 7, AdmittingDoctorID: 2, Reason: "Pneumonia"}, 3, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Medical", response.Location.WardName)
+	assert.Equal(t, "101", response.Location.RoomNumber)
+	admissionRepo.AssertExpectations(t)
+}
+
+func TestAdmissionService_AdmitPatient_BedTaken(t *testing.T) {
+	admissionRepo, wardRepo, patientRepo, userRepo, admissionService := newAdmissionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor}, nil)
+	wardRepo.On("GetBed", uint(7)).Return(activeBed(7), nil)
+	admissionRepo.On("Admit", mock.Anything).Return(repository.ErrBedOccupied)
+
+	_, err := admissionService.AdmitPatient(1, services.AdmitPatientRequest{BedID: 7, AdmittingDoctorID: 2, Reason: "Pneumonia"}, 3, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, repository.ErrBedOccupied)
+}
+
+func TestAdmissionService_AdmitPatient_ClosedWard(t *testing.T) {
+	admissionRepo, wardRepo, patientRepo, userRepo, admissionService := newAdmissionTestService()
+
+	bed := activeBed(7)
+	bed.Room.Ward.IsActive = false
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor}, nil)
+	wardRepo.On("GetBed", uint(7)).Return(bed, nil)
+
+	_, err := admissionService.AdmitPatient(1, services.AdmitPatientRequest{BedID: 7, AdmittingDoctorID: 2, Reason: "Pneumonia"}, 3, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, repository.ErrBedUnavailable)
+	admissionRepo.AssertNotCalled(t, "Admit", mock.Anything)
+}
+
+func TestAdmissionService_AdmitPatient_RequiresDoctor(t *testing.T) {
+	_, _, patientRepo, userRepo, admissionService := newAdmissionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	userRepo.On("GetByID", uint(3)).Return(&models.User{ID: 3, Role: models.RoleReceptionist}, nil)
+
+	_, err := admissionService.AdmitPatient(1, services.AdmitPatientRequest{BedID: 7, AdmittingDoctorID: 3, Reason: "Pneumonia"}, 3, models.RoleReceptionist)
+
+	require.Error(t, err)
+	assert.Equal(t, "doctor not found", err.Error())
+}
+
+func TestAdmissionService_TransferPatient(t *testing.T) {
+	admissionRepo, wardRepo, _, _, admissionService := newAdmissionTestService()
+
+	admission := &models.Admission{ID: 4, PatientID: 1, BedID: 7, Status: models.AdmissionStatusAdmitted}
+	admissionRepo.On("GetByID", uint(1), uint(4)).Return(admission, nil)
+	wardRepo.On("GetBed", uint(8)).Return(activeBed(8), nil)
+	admissionRepo.On("Transfer", admission, mock.MatchedBy(func(tr *models.BedTransfer) bool {
+		return tr.ToBedID == 8 && tr.TransferredByID == 3
+	})).Return(nil)
+
+	_, err := admissionService.TransferPatient(1, 4, services.TransferPatientRequest{BedID: 8, Reason: "Step down"}, 3, models.RoleDoctor)
+	assert.NoError(t, err)
+
+	_, err = admissionService.TransferPatient(1, 4, services.TransferPatientRequest{BedID: 7}, 3, models.RoleDoctor)
+	require.Error(t, err)
+	assert.Equal(t, "patient is already in this bed", err.Error())
+}
+
+func TestAdmissionService_DischargePatient(t *testing.T) {
+	admissionRepo, _, _, _, admissionService := newAdmissionTestService()
+
+	admissionRepo.On("GetByID", uint(1), uint(4)).Return(&models.Admission{ID: 4, PatientID: 1, Status: models.AdmissionStatusAdmitted}, nil)
+	admissionRepo.On("GetByID", uint(1), uint(5)).Return(&models.Admission{ID: 5, PatientID: 1, Status: models.AdmissionStatusDischarged}, nil)
+	admissionRepo.On("Discharge", mock.MatchedBy(func(a *models.Admission) bool {
+		return a.ID == 4 && a.Status == models.AdmissionStatusDischarged && a.DischargedAt != nil
+	})).Return(nil)
+
+	response, err := admissionService.DischargePatient(1, 4, services.DischargePatientRequest{Notes: "Recovered"}, 3, models.RoleDoctor)
+	require.NoError(t, err)
+	assert.Equal(t, models.AdmissionStatusDischarged, response.Status)
+
+	_, err = admissionService.DischargePatient(1, 5, services.DischargePatientRequest{}, 3, models.RoleDoctor)
+	require.Error(t, err)
+	assert.Equal(t, "patient has already been discharged", err.Error())
+}
+
+func TestWardService_Board(t *testing.T) {
+	wardRepo := new(MockWardRepository)
+	admissionRepo := new(MockAdmissionRepository)
+	wardService := services.NewWardService(wardRepo, admissionRepo, authz.DefaultPolicy())
+
+	wardRepo.On("ListWards", false).Return([]*models.Ward{{
+		ID:   1,
+		Name: "Medical",
+		Rooms: []models.Room{{
+			ID:     2,
+			Number: "101",
+			Beds:   []models.Bed{{ID: 7, Label: "A"}, {ID: 8, Label: "B"}},
+		}},
+	}}, nil)
+	admissionRepo.On("ListActive").Return([]*models.Admission{
+		{ID: 4, BedID: 7, PatientID: 1, Patient: &models.Patient{ID: 1, FirstName: "Jane"}},
+	}, nil)
+
+	board, err := wardService.Board(models.RoleDoctor)
+	require.NoError(t, err)
+	assert.Equal(t, 2, board.TotalBeds)
+	assert.Equal(t, 1, board.OccupiedBeds)
+	assert.Equal(t, 1, board.AvailableBeds)
+	beds := board.Wards[0].Rooms[0].Beds
+	assert.True(t, beds[0].Occupied)
+	require.NotNil(t, beds[0].Patient)
+	assert.Equal(t, "Jane", beds[0].Patient.FirstName)
+	assert.False(t, beds[1].Occupied)
+
+	adminBoard, err := wardService.Board(models.RoleAdmin)
+	require.NoError(t, err)
+	assert.True(t, adminBoard.Wards[0].Rooms[0].Beds[0].Occupied)
+	assert.Nil(t, adminBoard.Wards[0].Rooms[0].Beds[0].Patient, "admins see occupancy but not patients")
+}
+
+func TestWardService_UpdateBed_RefusesOccupied(t *testing.T) {
+	wardRepo := new(MockWardRepository)
+	admissionRepo := new(MockAdmissionRepository)
+	wardService := services.NewWardService(wardRepo, admissionRepo, authz.DefaultPolicy())
+
+	wardRepo.On("GetBed", uint(7)).Return(activeBed(7), nil)
+	admissionRepo.On("IsBedOccupied", uint(7)).Return(true, nil)
+
+	inactive := false
+	_, err := wardService.UpdateBed(7, services.UpdateBedRequest{IsActive: &inactive})
+
+	require.Error(t, err)
+	assert.Equal(t, "occupied beds cannot be taken out of service", err.Error())
+	wardRepo.AssertNotCalled(t, "UpdateBed", mock.Anything)
+}
@@ -0,0 +1,154 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAllergyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAllergyRepository) Create(allergy *models.Allergy) error {
+	args := m.Called(allergy)
+	return args.Error(0)
+}
+
+func (m *MockAllergyRepository) GetByID(patientID, id uint) (*models.Allergy, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Allergy), args.Error(1)
+}
+
+func (m *MockAllergyRepository) Update(allergy *models.Allergy) error {
+	args := m.Called(allergy)
+	return args.Error(0)
+}
+
+func (m *MockAllergyRepository) ListByPatient(patientID uint, status models.AllergyStatus) ([]*models.Allergy, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]*models.Allergy), args.Error(1)
+}
+
+func (m *MockAllergyRepository) FindActiveBySubstance(patientID uint, substance string) (*models.Allergy, error) {
+	args := m.Called(patientID, substance)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Allergy), args.Error(1)
+}
+
+func newAllergyTestService() (*MockAllergyRepository, *MockPatientRepository, *services.AllergyService) {
+	allergyRepo := new(MockAllergyRepository)
+	patientRepo := new(MockPatientRepository)
+	return allergyRepo, patientRepo, services.NewAllergyService(allergyRepo, patientRepo, authz.DefaultPolicy())
+}
+
+func TestAllergyService_CreateAllergy_Success(t *testing.T) {
+	allergyRepo, patientRepo, allergyService := newAllergyTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	allergyRepo.On("FindActiveBySubstance", uint(1), "Penicillin").Return(nil, errors.New("allergy not found"))
+	allergyRepo.On("Create", mock.MatchedBy(func(a *models.Allergy) bool {
+		return a.Substance == "Penicillin" && a.Status == models.AllergyStatusActive && a.RecordedByID == 2 && a.OnsetDate != nil
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Allergy).ID = 4
+	}).Return(nil)
+	allergyRepo.On("GetByID", uint(1), uint(4)).Return(&models.Allergy{ID: 4, PatientID: 1, Substance: "Penicillin", Severity: models.AllergySeveritySevere}, nil)
+
+	response, err := allergyService.CreateAllergy(1, services.CreateAllergyRequest{
+		Substance: " Penicillin ",
+		Category:  models.AllergyCategoryMedication,
+		Reaction:  "Anaphylaxis",
+		Severity:  models.AllergySeveritySevere,
+		OnsetDate: "2010-06-01",
+	}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Penicillin", response.Substance)
+	allergyRepo.AssertExpectations(t)
+}
+
+func TestAllergyService_CreateAllergy_Duplicate(t *testing.T) {
+	allergyRepo, patientRepo, allergyService := newAllergyTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	allergyRepo.On("FindActiveBySubstance", uint(1), "Peanuts").Return(&models.Allergy{ID: 3}, nil)
+
+	response, err := allergyService.CreateAllergy(1, services.CreateAllergyRequest{
+		Substance: "Peanuts",
+		Category:  models.AllergyCategoryFood,
+	}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "an active allergy to this substance is already recorded", err.Error())
+	assert.Nil(t, response)
+	allergyRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAllergyService_CreateAllergy_ReceptionistForbidden(t *testing.T) {
+	_, _, allergyService := newAllergyTestService()
+
+	response, err := allergyService.CreateAllergy(1, services.CreateAllergyRequest{Substance: "Latex"}, 5, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestAllergyService_ListAllergies_ReceptionistCanRead(t *testing.T) {
+	allergyRepo, patientRepo, allergyService := newAllergyTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	allergyRepo.On("ListByPatient", uint(1), models.AllergyStatus("")).Return([]*models.Allergy{
+		{ID: 1, Substance: models.LegacyAllergySubstance, Notes: "Penicillin, shellfish", IsLegacy: true},
+	}, nil)
+
+	allergies, err := allergyService.ListAllergies(1, "", models.RoleReceptionist)
+
+	assert.NoError(t, err)
+	assert.Len(t, allergies, 1)
+	assert.True(t, allergies[0].IsLegacy)
+	assert.Equal(t, "Penicillin, shellfish", allergies[0].Notes)
+}
+
+func TestAllergyService_UpdateAllergy_ReplacesLegacyNote(t *testing.T) {
+	allergyRepo, _, allergyService := newAllergyTestService()
+
+	allergy := &models.Allergy{ID: 1, PatientID: 1, Substance: models.LegacyAllergySubstance, Category: models.AllergyCategoryOther, Status: models.AllergyStatusActive, IsLegacy: true}
+	allergyRepo.On("GetByID", uint(1), uint(1)).Return(allergy, nil)
+	allergyRepo.On("FindActiveBySubstance", uint(1), "Shellfish").Return(nil, errors.New("allergy not found"))
+	allergyRepo.On("Update", allergy).Return(nil)
+
+	substance := "Shellfish"
+	category := models.AllergyCategoryFood
+	response, err := allergyService.UpdateAllergy(1, 1, services.UpdateAllergyRequest{Substance: &substance, Category: &category}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Shellfish", response.Substance)
+	assert.False(t, response.IsLegacy)
+	assert.Equal(t, uint(2), *allergy.LastUpdatedByID)
+}
+
+func TestAllergyService_UpdateAllergy_EnteredInError(t *testing.T) {
+	allergyRepo, _, allergyService := newAllergyTestService()
+
+	allergy := &models.Allergy{ID: 1, PatientID: 1, Substance: "Latex", Status: models.AllergyStatusActive}
+	allergyRepo.On("GetByID", uint(1), uint(1)).Return(allergy, nil)
+	allergyRepo.On("Update", allergy).Return(nil)
+
+	status := models.AllergyStatusEnteredInError
+	response, err := allergyService.UpdateAllergy(1, 1, services.UpdateAllergyRequest{Status: &status}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AllergyStatusEnteredInError, response.Status)
+	allergyRepo.AssertNotCalled(t, "FindActiveBySubstance", mock.Anything, mock.Anything)
+}
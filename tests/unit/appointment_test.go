@@ -0,0 +1,314 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAppointmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAppointmentRepository) Create(appointment *models.Appointment) error {
+	args := m.Called(appointment)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) GetByID(id uint) (*models.Appointment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Transition(appointment *models.Appointment, from ...models.AppointmentStatus) error {
+	args := m.Called(appointment, from)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) Reschedule(appointment *models.Appointment) error {
+	args := m.Called(appointment)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) List(filter repository.AppointmentFilter, limit, offset int) ([]*models.Appointment, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]*models.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Count(filter repository.AppointmentFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) ListBusy(doctorID uint, from, to time.Time) ([]*models.Appointment, error) {
+	args := m.Called(doctorID, from, to)
+	return args.Get(0).([]*models.Appointment), args.Error(1)
+}
+
+func newAppointmentTestService() (*MockAppointmentRepository, *MockPatientRepository, *MockUserRepository, *services.AppointmentService) {
+	appointmentRepo := new(MockAppointmentRepository)
+	patientRepo := new(MockPatientRepository)
+	userRepo := new(MockUserRepository)
+	return appointmentRepo, patientRepo, userRepo, services.NewAppointmentService(appointmentRepo, patientRepo, userRepo, authz.DefaultPolicy())
+}
+
+func scheduledAppointment() *models.Appointment {
+	start := time.Now().Add(24 * time.Hour).UTC()
+	return &models.Appointment{
+		ID:        1,
+		PatientID: 3,
+		DoctorID:  2,
+		StartTime: start,
+		EndTime:   start.Add(30 * time.Minute),
+		Status:    models.AppointmentStatusScheduled,
+	}
+}
+
+func TestAppointmentService_CreateAppointment_Success(t *testing.T) {
+	appointmentRepo, patientRepo, userRepo, appointmentService := newAppointmentTestService()
+
+	start := time.Now().Add(48 * time.Hour)
+	patientRepo.On("GetByID", uint(3)).Return(&models.Patient{ID: 3}, nil)
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor, IsActive: true}, nil)
+	appointmentRepo.On("Create", mock.MatchedBy(func(a *models.Appointment) bool {
+		return a.EndTime.Sub(a.StartTime) == 45*time.Minute && a.Status == models.AppointmentStatusScheduled && a.CreatedByID == 5
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Appointment).ID = 1
+	}).Return(nil)
+	appointmentRepo.On("GetByID", uint(1)).Return(&models.Appointment{ID: 1, StartTime: start, EndTime: start.Add(45 * time.Minute), Status: models.AppointmentStatusScheduled}, nil)
+
+	response, err := appointmentService.CreateAppointment(services.CreateAppointmentRequest{
+		PatientID:       3,
+		DoctorID:        2,
+		StartTime:       start,
+		DurationMinutes: 45,
+	}, 5, models.RoleReceptionist)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 45, response.DurationMinutes)
+	appointmentRepo.AssertExpectations(t)
+}
+
+func TestAppointmentService_CreateAppointment_Conflict(t *testing.T) {
+	appointmentRepo, patientRepo, userRepo, appointmentService := newAppointmentTestService()
+
+	patientRepo.On("GetByID", uint(3)).Return(&models.Patient{ID: 3}, nil)
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor, IsActive: true}, nil)
+	appointmentRepo.On("Create", mock.AnythingOfType("*models.Appointment")).Return(repository.ErrAppointmentConflict)
+
+	response, err := appointmentService.CreateAppointment(services.CreateAppointmentRequest{
+		PatientID:       3,
+		DoctorID:        2,
+		StartTime:       time.Now().Add(time.Hour),
+		DurationMinutes: 30,
+	}, 5, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, repository.ErrAppointmentConflict)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_CreateAppointment_NotBookableUser(t *testing.T) {
+	appointmentRepo, patientRepo, userRepo, appointmentService := newAppointmentTestService()
+
+	patientRepo.On("GetByID", uint(3)).Return(&models.Patient{ID: 3}, nil)
+	userRepo.On("GetByID", uint(4)).Return(&models.User{ID: 4, Role: models.RoleReceptionist, IsActive: true}, nil)
+
+	response, err := appointmentService.CreateAppointment(services.CreateAppointmentRequest{
+		PatientID:       3,
+		DoctorID:        4,
+		StartTime:       time.Now().Add(time.Hour),
+		DurationMinutes: 30,
+	}, 5, models.RoleReceptionist)
+
+	assert.Error(t, err)
+	assert.Equal(t, "doctor not found", err.Error())
+	assert.Nil(t, response)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAppointmentService_CreateAppointment_InPast(t *testing.T) {
+	_, _, _, appointmentService := newAppointmentTestService()
+
+	response, err := appointmentService.CreateAppointment(services.CreateAppointmentRequest{
+		PatientID:       3,
+		DoctorID:        2,
+		StartTime:       time.Now().Add(-time.Hour),
+		DurationMinutes: 30,
+	}, 5, models.RoleReceptionist)
+
+	assert.Error(t, err)
+	assert.Equal(t, "start time must be in the future", err.Error())
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_CreateAppointment_DoctorForbidden(t *testing.T) {
+	_, _, _, appointmentService := newAppointmentTestService()
+
+	response, err := appointmentService.CreateAppointment(services.CreateAppointmentRequest{}, 2, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_ListAppointments_DoctorSeesOwnSchedule(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	doctorID := uint(2)
+	otherDoctorID := uint(7)
+	filter := repository.AppointmentFilter{
+		DoctorID: &doctorID,
+		From:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+	}
+	appointmentRepo.On("List", filter, 10, 0).Return([]*models.Appointment{scheduledAppointment()}, nil)
+	appointmentRepo.On("Count", filter).Return(int64(1), nil)
+
+	response, err := appointmentService.ListAppointments(services.AppointmentListQuery{DoctorID: &otherDoctorID, Date: "2026-03-02"}, 1, 10, doctorID, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Appointments, 1)
+	appointmentRepo.AssertExpectations(t)
+}
+
+func TestAppointmentService_ListAppointments_InvalidDate(t *testing.T) {
+	_, _, _, appointmentService := newAppointmentTestService()
+
+	response, err := appointmentService.ListAppointments(services.AppointmentListQuery{Date: "02/03/2026"}, 1, 10, 5, models.RoleReceptionist)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_GetAppointment_OtherDoctorForbidden(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointmentRepo.On("GetByID", uint(1)).Return(scheduledAppointment(), nil)
+
+	response, err := appointmentService.GetAppointment(1, 7, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_RescheduleAppointment_KeepsDuration(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	newStart := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Minute)
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+	appointmentRepo.On("Reschedule", appointment).Return(nil)
+
+	response, err := appointmentService.RescheduleAppointment(1, services.RescheduleAppointmentRequest{StartTime: newStart}, 5, models.RoleReceptionist)
+
+	assert.NoError(t, err)
+	assert.Equal(t, newStart, response.StartTime)
+	assert.Equal(t, 30, response.DurationMinutes)
+}
+
+func TestAppointmentService_CancelAppointment(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+	appointmentRepo.On("Transition", appointment, []models.AppointmentStatus{models.AppointmentStatusScheduled}).Return(nil)
+
+	response, err := appointmentService.CancelAppointment(1, services.CancelAppointmentRequest{Reason: "Patient request"}, 5, models.RoleReceptionist)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusCancelled, response.Status)
+	assert.Equal(t, "Patient request", response.CancellationReason)
+	assert.NotNil(t, response.CancelledAt)
+}
+
+func TestAppointmentService_CheckInAppointment_StatusChangedConcurrently(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+	appointmentRepo.On("Transition", appointment, []models.AppointmentStatus{models.AppointmentStatusScheduled}).Return(repository.ErrAppointmentStatusChanged)
+
+	response, err := appointmentService.CheckInAppointment(1, 5, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, repository.ErrAppointmentStatusChanged)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_RescheduleAppointment_CancelledConcurrently(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+	appointmentRepo.On("Reschedule", appointment).Return(repository.ErrAppointmentStatusChanged)
+
+	response, err := appointmentService.RescheduleAppointment(1, services.RescheduleAppointmentRequest{StartTime: time.Now().Add(48 * time.Hour)}, 5, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, repository.ErrAppointmentStatusChanged)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_CancelAppointment_AlreadyCompleted(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	appointment.Status = models.AppointmentStatusCompleted
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+
+	response, err := appointmentService.CancelAppointment(1, services.CancelAppointmentRequest{Reason: "Too late"}, 5, models.RoleReceptionist)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	appointmentRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything)
+}
+
+func TestAppointmentService_CompleteAppointment(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+	appointmentRepo.On("Transition", appointment, []models.AppointmentStatus{models.AppointmentStatusCheckedIn}).Return(nil)
+
+	_, err := appointmentService.CompleteAppointment(1, 2, models.RoleDoctor)
+	assert.Error(t, err, "a scheduled appointment must be checked in first")
+
+	appointment.Status = models.AppointmentStatusCheckedIn
+	response, err := appointmentService.CompleteAppointment(1, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusCompleted, response.Status)
+}
+
+func TestAppointmentService_CompleteAppointment_OtherDoctor(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointment := scheduledAppointment()
+	appointment.Status = models.AppointmentStatusCheckedIn
+	appointmentRepo.On("GetByID", uint(1)).Return(appointment, nil)
+
+	response, err := appointmentService.CompleteAppointment(1, 7, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestAppointmentService_GetAppointment_NotFound(t *testing.T) {
+	appointmentRepo, _, _, appointmentService := newAppointmentTestService()
+
+	appointmentRepo.On("GetByID", uint(99)).Return(nil, errors.New("appointment not found"))
+
+	response, err := appointmentService.GetAppointment(99, 5, models.RoleReceptionist)
+
+	assert.Error(t, err)
+	assert.Equal(t, "appointment not found", err.Error())
+	assert.Nil(t, response)
+}
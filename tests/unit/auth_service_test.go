@@ -6,6 +6,7 @@ import (
 
 	"hospital-management-system/internal/auth"
 	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
 	"hospital-management-system/internal/services"
 	"hospital-management-system/pkg/utils"
 
@@ -46,11 +47,32 @@ func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByUsernameIncludingInactive(username string) (*models.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmailIncludingInactive(email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(user *models.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateRole(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -72,10 +94,33 @@ func (m *MockUserRepository) GetByRole(role models.UserRole) ([]*models.User, er
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDIncludingInactive(id uint) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Search(filter repository.UserFilter, limit, offset int) ([]*models.User, error) {
+	args := m.Called(filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Count(filter repository.UserFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestAuthService_Login_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
 
 	hashedPassword, _ := utils.HashPassword("password123")
 	user := &models.User{
@@ -90,6 +135,11 @@ func TestAuthService_Login_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetByUsername", "testuser").Return(user, nil)
+	mockMFARepo.On("IsRequiredForRole", models.RoleReceptionist).Return(false, nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession"), mock.AnythingOfType("*models.RefreshToken")).Return(nil).Run(func(args mock.Arguments) {
+		session := args.Get(0).(*models.UserSession)
+		session.ID = 10
+	})
 
 	req := services.LoginRequest{
 		Username: "testuser",
@@ -102,13 +152,21 @@ func TestAuthService_Login_Success(t *testing.T) {
 	assert.NotNil(t, response)
 	assert.Equal(t, "testuser", response.User.Username)
 	assert.NotEmpty(t, response.Token)
+	assert.NotEmpty(t, response.RefreshToken)
+
+	claims, err := jwtService.ValidateToken(response.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), claims.SessionID)
 	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
 }
 
 func TestAuthService_Login_InvalidUsername(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
 
 	mockRepo.On("GetByUsername", "nonexistent").Return(nil, errors.New("user not found"))
 
@@ -128,7 +186,9 @@ func TestAuthService_Login_InvalidUsername(t *testing.T) {
 func TestAuthService_Login_InvalidPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
 
 	hashedPassword, _ := utils.HashPassword("correct_password")
 	user := &models.User{
@@ -156,7 +216,9 @@ func TestAuthService_Login_InvalidPassword(t *testing.T) {
 func TestAuthService_Login_InactiveUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
 
 	hashedPassword, _ := utils.HashPassword("password123")
 	user := &models.User{
@@ -181,95 +243,12 @@ func TestAuthService_Login_InactiveUser(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestAuthService_Register_Success(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
-
-	mockRepo.On("GetByUsername", "newuser").Return(nil, errors.New("user not found"))
-	mockRepo.On("GetByEmail", "new@example.com").Return(nil, errors.New("user not found"))
-	mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
-
-	req := services.RegisterRequest{
-		Username:  "newuser",
-		Email:     "new@example.com",
-		Password:  "password123",
-		FirstName: "New",
-		LastName:  "User",
-		Role:      models.RoleDoctor,
-	}
-
-	response, err := authService.Register(req)
-
-	assert.NoError(t, err)
-	assert.NotNil(t, response)
-	assert.Equal(t, "newuser", response.Username)
-	assert.Equal(t, "new@example.com", response.Email)
-	assert.Equal(t, models.RoleDoctor, response.Role)
-	mockRepo.AssertExpectations(t)
-}
-
-func TestAuthService_Register_UsernameExists(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
-
-	existingUser := &models.User{
-		Username: "existinguser",
-	}
-
-	mockRepo.On("GetByUsername", "existinguser").Return(existingUser, nil)
-
-	req := services.RegisterRequest{
-		Username:  "existinguser",
-		Email:     "new@example.com",
-		Password:  "password123",
-		FirstName: "New",
-		LastName:  "User",
-		Role:      models.RoleDoctor,
-	}
-
-	response, err := authService.Register(req)
-
-	assert.Error(t, err)
-	assert.Nil(t, response)
-	assert.Equal(t, "username already exists", err.Error())
-	mockRepo.AssertExpectations(t)
-}
-
-func TestAuthService_Register_EmailExists(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
-
-	existingUser := &models.User{
-		Email: "existing@example.com",
-	}
-
-	mockRepo.On("GetByUsername", "newuser").Return(nil, errors.New("user not found"))
-	mockRepo.On("GetByEmail", "existing@example.com").Return(existingUser, nil)
-
-	req := services.RegisterRequest{
-		Username:  "newuser",
-		Email:     "existing@example.com",
-		Password:  "password123",
-		FirstName: "New",
-		LastName:  "User",
-		Role:      models.RoleDoctor,
-	}
-
-	response, err := authService.Register(req)
-
-	assert.Error(t, err)
-	assert.Nil(t, response)
-	assert.Equal(t, "email already exists", err.Error())
-	mockRepo.AssertExpectations(t)
-}
-
 func TestAuthService_GetUserByID_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
 
 	user := &models.User{
 		ID:        1,
@@ -295,7 +274,9 @@ func TestAuthService_GetUserByID_Success(t *testing.T) {
 func TestAuthService_GetUserByID_NotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	jwtService := auth.NewJWTService("test_secret")
-	authService := services.NewAuthService(mockRepo, jwtService)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
 
 	mockRepo.On("GetByID", uint(999)).Return(nil, errors.New("user not found"))
 
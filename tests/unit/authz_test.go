@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/middleware"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_DefaultMatchesPreviousRoleRules(t *testing.T) {
+	policy := authz.DefaultPolicy()
+
+	assert.True(t, policy.Can(models.RoleReceptionist, authz.PatientCreate))
+	assert.True(t, policy.Can(models.RoleReceptionist, authz.PatientDelete))
+	assert.False(t, policy.Can(models.RoleReceptionist, authz.PatientMedicalWrite))
+	assert.False(t, policy.Can(models.RoleDoctor, authz.PatientCreate))
+	assert.True(t, policy.Can(models.RoleDoctor, authz.PatientMedicalWrite))
+	assert.True(t, policy.Can(models.RoleDoctor, authz.PatientHistoryDiff))
+	assert.True(t, policy.Can(models.RoleAdmin, authz.UserManage))
+	assert.False(t, policy.Can(models.RoleAdmin, authz.PatientRead))
+	assert.False(t, policy.Can("nurse", authz.PatientRead))
+}
+
+func TestPolicy_Wildcards(t *testing.T) {
+	policy := authz.NewPolicy(map[models.UserRole][]authz.Permission{
+		"superuser": {"*"},
+		"clinician": {"patient.*"},
+	})
+
+	assert.True(t, policy.Can("superuser", authz.UserManage))
+	assert.True(t, policy.Can("clinician", authz.PatientMedicalWrite))
+	assert.True(t, policy.Can("clinician", authz.PatientHistoryRead))
+	assert.False(t, policy.Can("clinician", authz.AccessLogRead))
+}
+
+func TestPolicy_LoadFromFile_AddsNewRole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"roles": {
+		"admin": ["user.manage"],
+		"nurse": ["patient.read", "patient.history.read"]
+	}}`), 0600))
+
+	policy, err := authz.LoadPolicy(path)
+	require.NoError(t, err)
+
+	assert.True(t, policy.HasRole("nurse"))
+	assert.True(t, policy.Can("nurse", authz.PatientRead))
+	assert.False(t, policy.Can("nurse", authz.PatientUpdate))
+	assert.False(t, policy.HasRole(models.RoleDoctor), "the file replaces the default policy")
+	assert.Equal(t, []models.UserRole{"admin", "nurse"}, policy.Roles())
+}
+
+func TestPolicy_LoadFromFile_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"roles": {}}`), 0600))
+
+	policy, err := authz.LoadPolicy(path)
+
+	assert.Error(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     models.UserRole
+		expected int
+	}{
+		{name: "granted", role: models.RoleAdmin, expected: http.StatusOK},
+		{name: "denied", role: models.RoleDoctor, expected: http.StatusForbidden},
+		{name: "unknown role", role: "nurse", expected: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupRouter()
+			router.GET("/users", func(c *gin.Context) {
+				c.Set("user_role", tt.role)
+				c.Next()
+			}, middleware.RequirePermission(authz.DefaultPolicy(), authz.UserManage), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/users", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestPatientService_UpdatePatient_MedicalFieldsForbiddenForReceptionist(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	patientService := services.NewPatientService(mockPatientRepo, new(MockUserRepository), authz.DefaultPolicy())
+
+	history := "Type 2 diabetes"
+	response, err := patientService.UpdatePatient(1, services.UpdatePatientRequest{MedicalHistory: &history}, 2, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+	mockPatientRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPatientService_UpdatePatient_CustomRoleFromPolicy(t *testing.T) {
+	policy := authz.NewPolicy(map[models.UserRole][]authz.Permission{
+		"nurse": {authz.PatientRead},
+	})
+	patientService := services.NewPatientService(new(MockPatientRepository), new(MockUserRepository), policy)
+
+	name := "Jane"
+	response, err := patientService.UpdatePatient(1, services.UpdatePatientRequest{FirstName: &name}, 2, "nurse")
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
@@ -0,0 +1,226 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAvailabilityRepository struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityRepository) GetSchedule(doctorID uint) (*models.DoctorSchedule, error) {
+	args := m.Called(doctorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DoctorSchedule), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) SaveSchedule(schedule *models.DoctorSchedule) error {
+	args := m.Called(schedule)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) ListSchedules(specialty string) ([]*models.DoctorSchedule, error) {
+	args := m.Called(specialty)
+	return args.Get(0).([]*models.DoctorSchedule), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) CreateException(exception *models.DoctorScheduleException) error {
+	args := m.Called(exception)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) DeleteException(doctorID, exceptionID uint) error {
+	args := m.Called(doctorID, exceptionID)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) ListExceptions(doctorID uint, from, to time.Time) ([]models.DoctorScheduleException, error) {
+	args := m.Called(doctorID, from, to)
+	return args.Get(0).([]models.DoctorScheduleException), args.Error(1)
+}
+
+// nextMonday returns a Monday at least two weeks ahead so that none of its
+// slots are in the past.
+func nextMonday() time.Time {
+	day := time.Now().UTC().AddDate(0, 0, 14)
+	for day.Weekday() != time.Monday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func mondayMorningSchedule(doctorID uint) *models.DoctorSchedule {
+	return &models.DoctorSchedule{
+		DoctorID:    doctorID,
+		Doctor:      models.User{ID: doctorID, FirstName: "John", LastName: "Smith", Role: models.RoleDoctor},
+		Specialty:   "Cardiology",
+		SlotMinutes: 30,
+		Timezone:    "UTC",
+		WorkingHours: []models.DoctorWorkingHours{
+			{Weekday: time.Monday, StartTime: "09:00", EndTime: "12:00"},
+		},
+		Breaks: []models.DoctorBreak{
+			{Weekday: time.Monday, StartTime: "10:30", EndTime: "11:00"},
+		},
+	}
+}
+
+func newAvailabilityTestService() (*MockAvailabilityRepository, *MockAppointmentRepository, *MockUserRepository, *services.AvailabilityService) {
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	return availabilityRepo, appointmentRepo, userRepo, services.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo, authz.DefaultPolicy())
+}
+
+func TestAvailabilityService_FindOpenSlots_SkipsBreaksAndBookings(t *testing.T) {
+	availabilityRepo, appointmentRepo, _, availabilityService := newAvailabilityTestService()
+
+	monday := nextMonday()
+	dayEnd := monday.AddDate(0, 0, 1)
+	availabilityRepo.On("GetSchedule", uint(2)).Return(mondayMorningSchedule(2), nil)
+	availabilityRepo.On("ListExceptions", uint(2), monday, dayEnd).Return([]models.DoctorScheduleException{}, nil)
+	appointmentRepo.On("ListBusy", uint(2), monday, dayEnd).Return([]*models.Appointment{
+		{StartTime: monday.Add(9*time.Hour + 30*time.Minute), EndTime: monday.Add(10 * time.Hour)},
+	}, nil)
+
+	doctorID := uint(2)
+	date := monday.Format("2006-01-02")
+	response, err := availabilityService.FindOpenSlots(services.OpenSlotQuery{DoctorID: &doctorID, From: date, To: date}, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	var starts []string
+	for _, slot := range response.Slots {
+		starts = append(starts, slot.StartTime.Format("15:04"))
+		assert.Equal(t, "John Smith", slot.DoctorName)
+	}
+	assert.Equal(t, []string{"09:00", "10:00", "11:00", "11:30"}, starts)
+}
+
+func TestAvailabilityService_FindOpenSlots_ExceptionBlocksDay(t *testing.T) {
+	availabilityRepo, appointmentRepo, _, availabilityService := newAvailabilityTestService()
+
+	monday := nextMonday()
+	dayEnd := monday.AddDate(0, 0, 1)
+	availabilityRepo.On("GetSchedule", uint(2)).Return(mondayMorningSchedule(2), nil)
+	availabilityRepo.On("ListExceptions", uint(2), monday, dayEnd).Return([]models.DoctorScheduleException{
+		{DoctorID: 2, StartsAt: monday, EndsAt: dayEnd, Kind: models.ScheduleExceptionLeave},
+	}, nil)
+	appointmentRepo.On("ListBusy", uint(2), monday, dayEnd).Return([]*models.Appointment{}, nil)
+
+	doctorID := uint(2)
+	date := monday.Format("2006-01-02")
+	response, err := availabilityService.FindOpenSlots(services.OpenSlotQuery{DoctorID: &doctorID, From: date, To: date}, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Empty(t, response.Slots)
+}
+
+func TestAvailabilityService_FindOpenSlots_BySpecialtyOrdersAcrossDoctors(t *testing.T) {
+	availabilityRepo, appointmentRepo, _, availabilityService := newAvailabilityTestService()
+
+	monday := nextMonday()
+	dayEnd := monday.AddDate(0, 0, 1)
+	late := mondayMorningSchedule(3)
+	late.WorkingHours = []models.DoctorWorkingHours{{Weekday: time.Monday, StartTime: "08:30", EndTime: "09:30"}}
+	late.Breaks = nil
+
+	availabilityRepo.On("ListSchedules", "Cardiology").Return([]*models.DoctorSchedule{mondayMorningSchedule(2), late}, nil)
+	availabilityRepo.On("ListExceptions", mock.Anything, monday, dayEnd).Return([]models.DoctorScheduleException{}, nil)
+	appointmentRepo.On("ListBusy", mock.Anything, monday, dayEnd).Return([]*models.Appointment{}, nil)
+
+	date := monday.Format("2006-01-02")
+	response, err := availabilityService.FindOpenSlots(services.OpenSlotQuery{Specialty: " Cardiology ", From: date, To: date, Limit: 3}, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	require.Len(t, response.Slots, 3)
+	assert.Equal(t, uint(3), response.Slots[0].DoctorID)
+	assert.Equal(t, "08:30", response.Slots[0].StartTime.Format("15:04"))
+	assert.Equal(t, uint(2), response.Slots[1].DoctorID)
+	assert.Equal(t, uint(3), response.Slots[2].DoctorID)
+}
+
+func TestAvailabilityService_FindOpenSlots_RangeTooLong(t *testing.T) {
+	_, _, _, availabilityService := newAvailabilityTestService()
+
+	response, err := availabilityService.FindOpenSlots(services.OpenSlotQuery{From: "2026-01-01", To: "2026-03-01"}, models.RoleReceptionist)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+}
+
+func TestAvailabilityService_UpdateSchedule_OverlappingHours(t *testing.T) {
+	availabilityRepo, _, userRepo, availabilityService := newAvailabilityTestService()
+
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor, IsActive: true}, nil)
+
+	monday := int(time.Monday)
+	response, err := availabilityService.UpdateSchedule(2, services.UpdateScheduleRequest{
+		SlotMinutes: 15,
+		WorkingHours: []services.WeeklyPeriodRequest{
+			{Weekday: &monday, StartTime: "09:00", EndTime: "13:00"},
+			{Weekday: &monday, StartTime: "12:00", EndTime: "17:00"},
+		},
+	}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "working hours overlap on Monday", err.Error())
+	assert.Nil(t, response)
+	availabilityRepo.AssertNotCalled(t, "SaveSchedule", mock.Anything)
+}
+
+func TestAvailabilityService_UpdateSchedule_OtherDoctorForbidden(t *testing.T) {
+	availabilityRepo, _, _, availabilityService := newAvailabilityTestService()
+
+	response, err := availabilityService.UpdateSchedule(3, services.UpdateScheduleRequest{SlotMinutes: 15}, 2, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+	availabilityRepo.AssertNotCalled(t, "SaveSchedule", mock.Anything)
+}
+
+func TestAvailabilityService_UpdateSchedule_ReceptionistSavesForDoctor(t *testing.T) {
+	availabilityRepo, _, userRepo, availabilityService := newAvailabilityTestService()
+
+	userRepo.On("GetByID", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor, IsActive: true}, nil)
+	availabilityRepo.On("SaveSchedule", mock.MatchedBy(func(s *models.DoctorSchedule) bool {
+		return s.DoctorID == 2 && s.Timezone == "UTC" && len(s.WorkingHours) == 1 && s.WorkingHours[0].StartTime == "08:00"
+	})).Return(nil)
+	availabilityRepo.On("GetSchedule", uint(2)).Return(mondayMorningSchedule(2), nil)
+	availabilityRepo.On("ListExceptions", uint(2), mock.Anything, mock.Anything).Return([]models.DoctorScheduleException{}, nil)
+
+	tuesday := int(time.Tuesday)
+	response, err := availabilityService.UpdateSchedule(2, services.UpdateScheduleRequest{
+		SlotMinutes:  20,
+		WorkingHours: []services.WeeklyPeriodRequest{{Weekday: &tuesday, StartTime: "8:00", EndTime: "16:00"}},
+	}, 5, models.RoleReceptionist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	availabilityRepo.AssertExpectations(t)
+}
+
+func TestAvailabilityService_AddException_EndBeforeStart(t *testing.T) {
+	availabilityRepo, _, _, availabilityService := newAvailabilityTestService()
+
+	start := nextMonday()
+	exception, err := availabilityService.AddException(2, services.CreateScheduleExceptionRequest{
+		StartsAt: start,
+		EndsAt:   start.Add(-time.Hour),
+		Kind:     models.ScheduleExceptionHoliday,
+	}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Nil(t, exception)
+	availabilityRepo.AssertNotCalled(t, "CreateException", mock.Anything)
+}
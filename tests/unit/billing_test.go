@@ -0,0 +1,283 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/billing"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPriceListRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceListRepository) Create(item *models.PriceListItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockPriceListRepository) GetByID(id uint) (*models.PriceListItem, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceListItem), args.Error(1)
+}
+
+func (m *MockPriceListRepository) GetByCode(code string) (*models.PriceListItem, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceListItem), args.Error(1)
+}
+
+func (m *MockPriceListRepository) GetByIDs(ids []uint) ([]*models.PriceListItem, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]*models.PriceListItem), args.Error(1)
+}
+
+func (m *MockPriceListRepository) Update(item *models.PriceListItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockPriceListRepository) Search(filter repository.PriceListFilter, limit, offset int) ([]*models.PriceListItem, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]*models.PriceListItem), args.Error(1)
+}
+
+func (m *MockPriceListRepository) Count(filter repository.PriceListFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockInvoiceRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceRepository) Create(invoice *models.Invoice) error {
+	args := m.Called(invoice)
+	return args.Error(0)
+}
+
+func (m *MockInvoiceRepository) GetByID(patientID, id uint) (*models.Invoice, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) ListByPatient(patientID uint, status models.InvoiceStatus) ([]*models.Invoice, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]*models.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) ListOpen(patientID uint) ([]*models.Invoice, error) {
+	args := m.Called(patientID)
+	return args.Get(0).([]*models.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) UpdateDraft(invoice *models.Invoice) error {
+	args := m.Called(invoice)
+	return args.Error(0)
+}
+
+func (m *MockInvoiceRepository) Transition(invoice *models.Invoice, from ...models.InvoiceStatus) error {
+	args := m.Called(invoice, from)
+	return args.Error(0)
+}
+
+func (m *MockInvoiceRepository) Void(invoice *models.Invoice, from ...models.InvoiceStatus) error {
+	args := m.Called(invoice, from)
+	return args.Error(0)
+}
+
+func (m *MockInvoiceRepository) RecordPayment(invoice *models.Invoice, payment *models.Payment) error {
+	args := m.Called(invoice, payment)
+	return args.Error(0)
+}
+
+func newBillingTestService() (*MockInvoiceRepository, *MockPriceListRepository, *MockPatientRepository, *MockEncounterRepository, *services.BillingService) {
+	invoiceRepo := new(MockInvoiceRepository)
+	priceListRepo := new(MockPriceListRepository)
+	patientRepo := new(MockPatientRepository)
+	encounterRepo := new(MockEncounterRepository)
+	admissionRepo := new(MockAdmissionRepository)
+	billingService := services.NewBillingService(invoiceRepo, priceListRepo, patientRepo, encounterRepo, admissionRepo, billing.PrintOptions{
+		HospitalName: "General Hospital",
+		Currency:     "USD",
+	}, authz.DefaultPolicy())
+	return invoiceRepo, priceListRepo, patientRepo, encounterRepo, billingService
+}
+
+func issuedInvoice(totalCents, paidCents int64) *models.Invoice {
+	invoice := &models.Invoice{
+		ID:              1,
+		InvoiceNumber:   "INV-000001",
+		PatientID:       1,
+		Status:          models.InvoiceStatusIssued,
+		TotalCents:      totalCents,
+		AmountPaidCents: paidCents,
+	}
+	invoice.SettleStatus()
+	return invoice
+}
+
+func TestBillingLine_Amounts(t *testing.T) {
+	amounts := billing.Line{
+		Quantity:        3,
+		UnitPriceCents:  1999,
+		DiscountPercent: 10,
+		TaxRatePercent:  7.5,
+	}.Amounts()
+
+	assert.Equal(t, int64(5997), amounts.GrossCents)
+	assert.Equal(t, int64(600), amounts.DiscountCents)
+	assert.Equal(t, int64(405), amounts.TaxCents) // 7.5% of 5397, rounded
+	assert.Equal(t, int64(5802), amounts.TotalCents)
+
+	assert.Equal(t, "1,234,567.89", billing.FormatCents(123456789))
+	assert.Equal(t, "-0.05", billing.FormatCents(-5))
+}
+
+func TestBillingService_CreateInvoice(t *testing.T) {
+	invoiceRepo, priceListRepo, patientRepo, encounterRepo, billingService := newBillingTestService()
+
+	encounterID := uint(4)
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	encounterRepo.On("GetByID", uint(1), encounterID).Return(&models.Encounter{ID: encounterID, PatientID: 1}, nil)
+	priceListRepo.On("GetByIDs", []uint{10, 11}).Return([]*models.PriceListItem{
+		{ID: 10, Code: "CONSULT", Name: "Consultation", UnitPriceCents: 5000, IsActive: true},
+		{ID: 11, Code: "XRAY", Name: "Chest X-ray", UnitPriceCents: 8000, TaxRatePercent: 10, IsActive: true},
+	}, nil)
+
+	created := &models.Invoice{}
+	invoiceRepo.On("Create", mock.AnythingOfType("*models.Invoice")).Run(func(args mock.Arguments) {
+		invoice := args.Get(0).(*models.Invoice)
+		invoice.ID = 1
+		*created = *invoice
+	}).Return(nil)
+	invoiceRepo.On("GetByID", uint(1), uint(1)).Return(created, nil)
+
+	response, err := billingService.CreateInvoice(1, services.CreateInvoiceRequest{
+		EncounterID: &encounterID,
+		Items: []services.InvoiceItemInput{
+			{PriceListItemID: 10, Quantity: 1},
+			{PriceListItemID: 11, Quantity: 2, DiscountPercent: 25},
+		},
+	}, 7, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceStatusDraft, response.Status)
+	assert.Equal(t, int64(21000), response.SubtotalCents)
+	assert.Equal(t, int64(4000), response.DiscountCents)
+	assert.Equal(t, int64(1200), response.TaxCents)
+	assert.Equal(t, int64(18200), response.TotalCents)
+	assert.Equal(t, "Chest X-ray", created.Items[1].Description)
+}
+
+func TestBillingService_CreateInvoice_RequiresOneSource(t *testing.T) {
+	_, _, patientRepo, _, billingService := newBillingTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+
+	_, err := billingService.CreateInvoice(1, services.CreateInvoiceRequest{
+		Items: []services.InvoiceItemInput{{PriceListItemID: 10, Quantity: 1}},
+	}, 7, models.RoleReceptionist)
+
+	require.Error(t, err)
+	assert.Equal(t, "invoice must reference either an encounter or an admission", err.Error())
+}
+
+func TestBillingService_RecordPayment_PartialThenOverpayment(t *testing.T) {
+	invoiceRepo, _, _, _, billingService := newBillingTestService()
+
+	invoice := issuedInvoice(10000, 4000)
+	assert.Equal(t, models.InvoiceStatusPartiallyPaid, invoice.Status)
+	invoiceRepo.On("GetByID", uint(1), uint(1)).Return(invoice, nil)
+
+	_, err := billingService.RecordPayment(1, 1, services.RecordPaymentRequest{
+		AmountCents: 6001,
+		Method:      models.PaymentMethodCash,
+	}, 7, models.RoleReceptionist)
+
+	assert.True(t, errors.Is(err, repository.ErrPaymentExceedsBalance))
+	invoiceRepo.AssertNotCalled(t, "RecordPayment", mock.Anything, mock.Anything)
+}
+
+func TestBillingService_RefundPayment_ExceedsPaid(t *testing.T) {
+	invoiceRepo, _, _, _, billingService := newBillingTestService()
+
+	invoiceRepo.On("GetByID", uint(1), uint(1)).Return(issuedInvoice(10000, 2500), nil)
+
+	_, err := billingService.RefundPayment(1, 1, services.RefundPaymentRequest{
+		AmountCents: 3000,
+		Method:      models.PaymentMethodCard,
+		Reason:      "Overcharged",
+	}, 7, models.RoleReceptionist)
+
+	assert.True(t, errors.Is(err, repository.ErrRefundExceedsPaid))
+}
+
+func TestBillingService_VoidInvoice_RequiresRefund(t *testing.T) {
+	invoiceRepo, _, _, _, billingService := newBillingTestService()
+
+	invoiceRepo.On("GetByID", uint(1), uint(1)).Return(issuedInvoice(10000, 10000), nil)
+
+	_, err := billingService.VoidInvoice(1, 1, services.VoidInvoiceRequest{Reason: "Billed in error"}, 7, models.RoleReceptionist)
+
+	require.Error(t, err)
+	assert.Equal(t, "payments must be refunded before the invoice is voided", err.Error())
+	invoiceRepo.AssertNotCalled(t, "Void", mock.Anything, mock.Anything)
+}
+
+func TestBillingService_VoidInvoice_SubmittedClaim(t *testing.T) {
+	invoiceRepo, _, _, _, billingService := newBillingTestService()
+
+	invoice := issuedInvoice(10000, 0)
+	invoiceRepo.On("GetByID", uint(1), uint(1)).Return(invoice, nil)
+	invoiceRepo.On("Void", invoice, []models.InvoiceStatus{models.InvoiceStatusIssued}).Return(repository.ErrInvoiceHasClaims)
+
+	response, err := billingService.VoidInvoice(1, 1, services.VoidInvoiceRequest{Reason: "Billed in error"}, 7, models.RoleReceptionist)
+
+	assert.Nil(t, response)
+	assert.True(t, errors.Is(err, repository.ErrInvoiceHasClaims))
+	invoiceRepo.AssertExpectations(t)
+}
+
+func TestBillingService_DoctorCannotSeeInvoices(t *testing.T) {
+	invoiceRepo, _, _, _, billingService := newBillingTestService()
+
+	_, err := billingService.ListInvoices(1, "", models.RoleDoctor)
+	assert.True(t, errors.Is(err, authz.ErrForbidden))
+
+	_, err = billingService.PatientBalance(1, models.RoleDoctor)
+	assert.True(t, errors.Is(err, authz.ErrForbidden))
+
+	invoiceRepo.AssertNotCalled(t, "ListByPatient", mock.Anything, mock.Anything)
+}
+
+func TestRenderInvoice(t *testing.T) {
+	invoice := issuedInvoice(12345, 0)
+	invoice.Patient = &models.Patient{ID: 1, FirstName: "Jane", LastName: "Doe"}
+	invoice.Items = []models.InvoiceItem{
+		{Code: "CONSULT", Description: "Consultation <follow-up>", Quantity: 1, UnitPriceCents: 12345, TotalCents: 12345},
+	}
+
+	page, err := billing.RenderInvoice(invoice, billing.PrintOptions{HospitalName: "General Hospital", Currency: "USD"})
+
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "INV-000001")
+	assert.Contains(t, string(page), "123.45")
+	assert.Contains(t, string(page), "Consultation &lt;follow-up&gt;")
+}
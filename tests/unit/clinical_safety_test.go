@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hospital-management-system/internal/clinical"
+	"hospital-management-system/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafetyRules_DirectAllergyBlocks(t *testing.T) {
+	rules := clinical.DefaultRules()
+
+	alerts := rules.Check(
+		&models.Medication{Name: "Lisinopril 10mg", GenericName: "lisinopril"},
+		[]*models.Allergy{{ID: 1, Substance: "Lisinopril", Status: models.AllergyStatusActive}},
+		nil,
+	)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, clinical.AlertLevelBlocking, alerts[0].Level)
+}
+
+func TestSafetyRules_CrossReactionWarns(t *testing.T) {
+	rules := clinical.DefaultRules()
+
+	alerts := rules.Check(
+		&models.Medication{Name: "Cefalexin 250mg", GenericName: "cefalexin", DrugClass: "cephalosporin"},
+		[]*models.Allergy{{ID: 1, Substance: "Penicillin", Status: models.AllergyStatusActive}},
+		nil,
+	)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, clinical.AlertLevelWarning, alerts[0].Level)
+	assert.False(t, clinical.HasBlocking(alerts))
+}
+
+func TestSafetyRules_IgnoresInactiveAllergiesAndUnrelatedDrugs(t *testing.T) {
+	rules := clinical.DefaultRules()
+
+	alerts := rules.Check(
+		&models.Medication{Name: "Amoxicillin 500mg", GenericName: "amoxicillin"},
+		[]*models.Allergy{
+			{ID: 1, Substance: "Penicillin", Status: models.AllergyStatusResolved},
+			{ID: 2, Substance: "Peanuts", Status: models.AllergyStatusActive},
+		},
+		[]*models.Prescription{{ID: 3, MedicationName: "Metformin 500mg"}},
+	)
+
+	assert.Empty(t, alerts)
+}
+
+func TestSafetyRules_InteractionWithLegacyMedicationNote(t *testing.T) {
+	rules := clinical.DefaultRules()
+
+	alerts := rules.Check(
+		&models.Medication{Name: "Ibuprofen 400mg", GenericName: "ibuprofen", DrugClass: "nsaid"},
+		nil,
+		[]*models.Prescription{{ID: 4, MedicationName: models.LegacyMedicationName, Instructions: "Warfarin 5mg daily", IsLegacy: true}},
+	)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, clinical.AlertKindDrugInteraction, alerts[0].Kind)
+	assert.Equal(t, uint(4), *alerts[0].PrescriptionID)
+}
+
+func TestSafetyRules_InteractionUsesMostSevereMatchingRule(t *testing.T) {
+	rules, err := clinical.NewRules(nil, []clinical.Interaction{
+		{Drugs: [2]string{"tramadol", "ssri"}, Level: clinical.AlertLevelWarning, Description: "lowered seizure threshold"},
+		{Drugs: [2]string{"tramadol", "sertraline"}, Level: clinical.AlertLevelBlocking, Description: "risk of serotonin syndrome"},
+	})
+	require.NoError(t, err)
+
+	alerts := rules.Check(
+		&models.Medication{Name: "Tramadol 50mg", GenericName: "tramadol"},
+		nil,
+		[]*models.Prescription{{ID: 5, MedicationName: "Sertraline 50mg", Medication: &models.Medication{GenericName: "sertraline", DrugClass: "ssri"}}},
+	)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, clinical.AlertLevelBlocking, alerts[0].Level)
+	assert.Contains(t, alerts[0].Description, "serotonin syndrome")
+}
+
+func TestSafetyRules_LoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "safety_rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"allergy_classes": [{"name": "Macrolide", "members": ["erythromycin", "clarithromycin"], "level": "warning"}],
+		"interactions": [{"drugs": ["methotrexate", "trimethoprim"], "level": "blocking", "description": "bone marrow suppression"}]
+	}`), 0600))
+
+	rules, err := clinical.LoadRules(path)
+	require.NoError(t, err)
+
+	alerts := rules.Check(
+		&models.Medication{Name: "Clarithromycin 500mg"},
+		[]*models.Allergy{{ID: 1, Substance: "Erythromycin", Status: models.AllergyStatusActive}},
+		[]*models.Prescription{{ID: 2, MedicationName: "Amoxicillin 500mg"}},
+	)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, clinical.AlertLevelWarning, alerts[0].Level)
+
+	alerts = rules.Check(&models.Medication{Name: "Trimethoprim 200mg"}, nil, []*models.Prescription{{ID: 3, MedicationName: "Methotrexate 10mg"}})
+	require.Len(t, alerts, 1)
+	assert.True(t, clinical.HasBlocking(alerts))
+}
+
+func TestSafetyRules_LoadFromFile_UnknownCrossReactiveClass(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "safety_rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"allergy_classes": [{"name": "penicillin", "level": "blocking", "cross_reactive": [{"class": "carbapenem", "level": "warning"}]}]
+	}`), 0600))
+
+	rules, err := clinical.LoadRules(path)
+
+	assert.Error(t, err)
+	assert.Nil(t, rules)
+}
@@ -0,0 +1,193 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEDVisitRepository struct {
+	mock.Mock
+}
+
+func (m *MockEDVisitRepository) Create(visit *models.EDVisit) error {
+	args := m.Called(visit)
+	return args.Error(0)
+}
+
+func (m *MockEDVisitRepository) GetByID(id uint) (*models.EDVisit, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EDVisit), args.Error(1)
+}
+
+func (m *MockEDVisitRepository) ListQueue(includeInTreatment bool) ([]*models.EDVisit, error) {
+	args := m.Called(includeInTreatment)
+	return args.Get(0).([]*models.EDVisit), args.Error(1)
+}
+
+func (m *MockEDVisitRepository) ListArrivedBetween(from, to time.Time) ([]*models.EDVisit, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]*models.EDVisit), args.Error(1)
+}
+
+func (m *MockEDVisitRepository) Transition(visit *models.EDVisit, from ...models.EDVisitStatus) error {
+	args := m.Called(visit, from)
+	return args.Error(0)
+}
+
+func newEDTestService() (*MockEDVisitRepository, *MockPatientRepository, *MockUserRepository, *services.EDService) {
+	edVisitRepo := new(MockEDVisitRepository)
+	patientRepo := new(MockPatientRepository)
+	userRepo := new(MockUserRepository)
+	return edVisitRepo, patientRepo, userRepo, services.NewEDService(edVisitRepo, patientRepo, userRepo, authz.DefaultPolicy())
+}
+
+func TestEDService_CheckIn(t *testing.T) {
+	edVisitRepo, patientRepo, _, edService := newEDTestService()
+
+	arrivedAt := time.Now().Add(-20 * time.Minute).Truncate(time.Second)
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	edVisitRepo.On("Create", mock.MatchedBy(func(v *models.EDVisit) bool {
+		return v.TriageLevel == 2 && v.Status == models.EDVisitStatusWaiting && v.ArrivedAt.Equal(arrivedAt) && v.TriagedByID == 3
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.EDVisit).ID = 9
+	}).Return(nil)
+	edVisitRepo.On("GetByID", uint(9)).Return(&models.EDVisit{ID: 9, PatientID: 1, TriageLevel: 2, ArrivedAt: arrivedAt, Status: models.EDVisitStatusWaiting}, nil)
+
+	visit, err := edService.CheckIn(services.EDCheckInRequest{
+		PatientID:      1,
+		TriageLevel:    2,
+		ChiefComplaint: "Chest pain",
+		ArrivedAt:      arrivedAt.Format(time.RFC3339),
+	}, 3, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, visit.WaitMinutes, 20)
+	assert.Nil(t, visit.DoorToDoctorMinutes)
+}
+
+func TestEDService_CheckIn_AlreadyInDepartment(t *testing.T) {
+	edVisitRepo, patientRepo, _, edService := newEDTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	edVisitRepo.On("Create", mock.Anything).Return(repository.ErrPatientAlreadyInED)
+
+	_, err := edService.CheckIn(services.EDCheckInRequest{PatientID: 1, TriageLevel: 3, ChiefComplaint: "Fever"}, 3, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, repository.ErrPatientAlreadyInED)
+}
+
+func TestEDService_Queue(t *testing.T) {
+	edVisitRepo, _, _, edService := newEDTestService()
+
+	now := time.Now()
+	edVisitRepo.On("ListQueue", false).Return([]*models.EDVisit{
+		{ID: 1, PatientID: 1, TriageLevel: 1, ArrivedAt: now.Add(-5 * time.Minute), Status: models.EDVisitStatusAssigned},
+		{ID: 2, PatientID: 2, TriageLevel: 3, ArrivedAt: now.Add(-90 * time.Minute), Status: models.EDVisitStatusWaiting},
+		{ID: 3, PatientID: 3, TriageLevel: 3, ArrivedAt: now.Add(-30 * time.Minute), Status: models.EDVisitStatusWaiting},
+	}, nil)
+
+	queue, err := edService.Queue(false, models.RoleDoctor)
+
+	require.NoError(t, err)
+	require.Len(t, queue.Visits, 3)
+	assert.Equal(t, uint(1), queue.Visits[0].ID)
+	assert.Equal(t, 90, queue.Visits[1].WaitMinutes)
+	assert.Equal(t, map[int]int{1: 1, 3: 2}, queue.WaitingByLevel)
+}
+
+func TestEDService_AssignDoctor_RequiresDoctor(t *testing.T) {
+	edVisitRepo, _, userRepo, edService := newEDTestService()
+
+	edVisitRepo.On("GetByID", uint(1)).Return(&models.EDVisit{ID: 1, Status: models.EDVisitStatusWaiting}, nil)
+	userRepo.On("GetByID", uint(3)).Return(&models.User{ID: 3, Role: models.RoleReceptionist}, nil)
+
+	_, err := edService.AssignDoctor(1, services.EDAssignDoctorRequest{DoctorID: 3}, models.RoleReceptionist)
+
+	require.Error(t, err)
+	assert.Equal(t, "doctor not found", err.Error())
+	edVisitRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything)
+}
+
+func TestEDService_StartTreatment(t *testing.T) {
+	edVisitRepo, _, _, edService := newEDTestService()
+
+	assigned := uint(2)
+	visit := &models.EDVisit{ID: 1, Status: models.EDVisitStatusAssigned, AssignedDoctorID: &assigned, ArrivedAt: time.Now().Add(-45 * time.Minute)}
+	edVisitRepo.On("GetByID", uint(1)).Return(visit, nil)
+
+	_, err := edService.StartTreatment(1, 7, models.RoleDoctor)
+	require.Error(t, err)
+	assert.Equal(t, "patient is assigned to another doctor", err.Error())
+
+	edVisitRepo.On("Transition", visit, []models.EDVisitStatus{models.EDVisitStatusAssigned}).Return(nil)
+
+	response, err := edService.StartTreatment(1, 2, models.RoleDoctor)
+	require.NoError(t, err)
+	assert.Equal(t, models.EDVisitStatusInTreatment, response.Status)
+	require.NotNil(t, response.DoorToDoctorMinutes)
+	assert.Equal(t, 45, *response.DoorToDoctorMinutes)
+}
+
+func TestEDService_StartTreatment_Concurrent(t *testing.T) {
+	edVisitRepo, _, _, edService := newEDTestService()
+
+	visit := &models.EDVisit{ID: 1, Status: models.EDVisitStatusWaiting, ArrivedAt: time.Now()}
+	edVisitRepo.On("GetByID", uint(1)).Return(visit, nil)
+	edVisitRepo.On("Transition", visit, []models.EDVisitStatus{models.EDVisitStatusWaiting}).Return(repository.ErrEDVisitStatusChanged)
+
+	_, err := edService.StartTreatment(1, 2, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, repository.ErrEDVisitStatusChanged)
+}
+
+func TestEDService_Metrics(t *testing.T) {
+	edVisitRepo, _, _, edService := newEDTestService()
+
+	arrived := time.Now().Add(-6 * time.Hour)
+	seenAfter := func(minutes int) *time.Time {
+		seen := arrived.Add(time.Duration(minutes) * time.Minute)
+		return &seen
+	}
+	edVisitRepo.On("ListArrivedBetween", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]*models.EDVisit{
+		{TriageLevel: 2, ArrivedAt: arrived, SeenAt: seenAfter(10), Status: models.EDVisitStatusCompleted},
+		{TriageLevel: 2, ArrivedAt: arrived, SeenAt: seenAfter(20), Status: models.EDVisitStatusInTreatment},
+		{TriageLevel: 4, ArrivedAt: arrived, SeenAt: seenAfter(90), Status: models.EDVisitStatusCompleted},
+		{TriageLevel: 4, ArrivedAt: arrived, Status: models.EDVisitStatusLeft},
+	}, nil)
+
+	metrics, err := edService.Metrics(services.EDMetricsQuery{}, models.RoleDoctor)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, metrics.Visits)
+	assert.Equal(t, 3, metrics.Seen)
+	assert.Equal(t, 1, metrics.LeftWithoutBeingSeen)
+	require.NotNil(t, metrics.MedianDoorToDoctorMinutes)
+	assert.Equal(t, 20.0, *metrics.MedianDoorToDoctorMinutes)
+
+	require.Len(t, metrics.ByTriageLevel, 5)
+	level2 := metrics.ByTriageLevel[1]
+	assert.Equal(t, 2, level2.Visits)
+	assert.Equal(t, 15.0, *level2.MedianDoorToDoctorMinutes)
+	assert.Nil(t, metrics.ByTriageLevel[0].MedianDoorToDoctorMinutes)
+}
+
+func TestEDService_Metrics_InvalidRange(t *testing.T) {
+	_, _, _, edService := newEDTestService()
+
+	_, err := edService.Metrics(services.EDMetricsQuery{From: "2026-01-10T00:00:00Z", To: "2026-01-01T00:00:00Z"}, models.RoleDoctor)
+
+	require.Error(t, err)
+	assert.Equal(t, "from must be before to", err.Error())
+}
@@ -0,0 +1,215 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEncounterRepository struct {
+	mock.Mock
+}
+
+func (m *MockEncounterRepository) Create(encounter *models.Encounter) error {
+	args := m.Called(encounter)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) GetByID(patientID, id uint) (*models.Encounter, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) UpdateDraft(encounter *models.Encounter) error {
+	args := m.Called(encounter)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) Sign(encounter *models.Encounter, signedAt time.Time) error {
+	args := m.Called(encounter, signedAt)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) ListByPatient(patientID uint, limit, offset int) ([]*models.Encounter, error) {
+	args := m.Called(patientID, limit, offset)
+	return args.Get(0).([]*models.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) CountByPatient(patientID uint) (int64, error) {
+	args := m.Called(patientID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEncounterRepository) CreateAddendum(addendum *models.EncounterAddendum) error {
+	args := m.Called(addendum)
+	return args.Error(0)
+}
+
+func newEncounterTestService() (*MockEncounterRepository, *MockPatientRepository, *MockAppointmentRepository, *services.EncounterService) {
+	encounterRepo := new(MockEncounterRepository)
+	patientRepo := new(MockPatientRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	return encounterRepo, patientRepo, appointmentRepo, services.NewEncounterService(encounterRepo, patientRepo, appointmentRepo, authz.DefaultPolicy())
+}
+
+func draftEncounter() *models.Encounter {
+	return &models.Encounter{
+		ID:         10,
+		PatientID:  1,
+		AuthorID:   2,
+		Status:     models.EncounterStatusDraft,
+		Subjective: "Headache for three days",
+	}
+}
+
+func TestEncounterService_CreateEncounter_Success(t *testing.T) {
+	encounterRepo, patientRepo, _, encounterService := newEncounterTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	encounterRepo.On("Create", mock.MatchedBy(func(e *models.Encounter) bool {
+		return e.AuthorID == 2 && e.Status == models.EncounterStatusDraft && e.Plan == "Ibuprofen"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Encounter).ID = 10
+	}).Return(nil)
+	encounterRepo.On("GetByID", uint(1), uint(10)).Return(draftEncounter(), nil)
+
+	response, err := encounterService.CreateEncounter(1, services.CreateEncounterRequest{Plan: "Ibuprofen"}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.EncounterStatusDraft, response.Status)
+	encounterRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_CreateEncounter_AppointmentOfOtherPatient(t *testing.T) {
+	encounterRepo, patientRepo, appointmentRepo, encounterService := newEncounterTestService()
+
+	appointmentID := uint(5)
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	appointmentRepo.On("GetByID", appointmentID).Return(&models.Appointment{ID: 5, PatientID: 9}, nil)
+
+	response, err := encounterService.CreateEncounter(1, services.CreateEncounterRequest{AppointmentID: &appointmentID}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "appointment not found for this patient", err.Error())
+	assert.Nil(t, response)
+	encounterRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestEncounterService_CreateEncounter_ReceptionistForbidden(t *testing.T) {
+	_, _, _, encounterService := newEncounterTestService()
+
+	response, err := encounterService.CreateEncounter(1, services.CreateEncounterRequest{}, 5, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestEncounterService_ListEncounters_ReceptionistForbidden(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	response, err := encounterService.ListEncounters(1, 1, 10, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+	encounterRepo.AssertNotCalled(t, "ListByPatient", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEncounterService_UpdateEncounter_Draft(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	encounter := draftEncounter()
+	encounterRepo.On("GetByID", uint(1), uint(10)).Return(encounter, nil)
+	encounterRepo.On("UpdateDraft", encounter).Return(nil)
+
+	assessment := "Tension headache"
+	response, err := encounterService.UpdateEncounter(1, 10, services.UpdateEncounterRequest{Assessment: &assessment}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tension headache", response.Assessment)
+	assert.Equal(t, "Headache for three days", response.Subjective)
+}
+
+func TestEncounterService_UpdateEncounter_SignedIsLocked(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	encounter := draftEncounter()
+	encounter.Status = models.EncounterStatusSigned
+	encounterRepo.On("GetByID", uint(1), uint(10)).Return(encounter, nil)
+
+	plan := "Changed"
+	response, err := encounterService.UpdateEncounter(1, 10, services.UpdateEncounterRequest{Plan: &plan}, 2, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, repository.ErrEncounterLocked)
+	assert.Nil(t, response)
+	encounterRepo.AssertNotCalled(t, "UpdateDraft", mock.Anything)
+}
+
+func TestEncounterService_UpdateEncounter_NotAuthor(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	encounterRepo.On("GetByID", uint(1), uint(10)).Return(draftEncounter(), nil)
+
+	plan := "Changed"
+	response, err := encounterService.UpdateEncounter(1, 10, services.UpdateEncounterRequest{Plan: &plan}, 7, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestEncounterService_SignEncounter_RaceWithSigning(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	encounter := draftEncounter()
+	encounterRepo.On("GetByID", uint(1), uint(10)).Return(encounter, nil)
+	encounterRepo.On("Sign", encounter, mock.AnythingOfType("time.Time")).Return(repository.ErrEncounterLocked)
+
+	response, err := encounterService.SignEncounter(1, 10, 2, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, repository.ErrEncounterLocked)
+	assert.Nil(t, response)
+}
+
+func TestEncounterService_AddAddendum(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	encounter := draftEncounter()
+	encounterRepo.On("GetByID", uint(1), uint(10)).Return(encounter, nil)
+
+	_, err := encounterService.AddAddendum(1, 10, services.AddAddendumRequest{Content: "Too early"}, 7, models.RoleDoctor)
+	assert.Error(t, err)
+	assert.Equal(t, "addenda can only be added to signed encounters", err.Error())
+
+	encounter.Status = models.EncounterStatusSigned
+	encounterRepo.On("CreateAddendum", mock.MatchedBy(func(a *models.EncounterAddendum) bool {
+		return a.EncounterID == 10 && a.AuthorID == 7 && a.Content == "Follow-up: symptoms resolved"
+	})).Return(nil)
+
+	response, err := encounterService.AddAddendum(1, 10, services.AddAddendumRequest{Content: "Follow-up: symptoms resolved"}, 7, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	encounterRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_GetEncounter_NotFound(t *testing.T) {
+	encounterRepo, _, _, encounterService := newEncounterTestService()
+
+	encounterRepo.On("GetByID", uint(1), uint(99)).Return(nil, errors.New("encounter not found"))
+
+	response, err := encounterService.GetEncounter(1, 99, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "encounter not found", err.Error())
+	assert.Nil(t, response)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/authz"
 	"hospital-management-system/internal/handlers"
 	"hospital-management-system/internal/models"
 	"hospital-management-system/internal/services"
@@ -19,20 +20,19 @@ import (
 
 type AuthServiceInterface interface {
 	Login(req services.LoginRequest) (*services.LoginResponse, error)
-	Register(req services.RegisterRequest) (*models.UserResponse, error)
 	GetUserByID(id uint) (*models.UserResponse, error)
-	RefreshToken(tokenString string) (string, error)
+	RefreshToken(refreshToken string) (*services.TokenResponse, error)
 	ValidateToken(tokenString string) (*auth.Claims, error)
 }
 
 type PatientServiceInterface interface {
-	CreatePatient(req services.CreatePatientRequest, createdByID uint) (*models.PatientResponse, error)
+	CreatePatient(req services.CreatePatientRequest, createdByID uint, userRole models.UserRole) (*models.PatientResponse, error)
 	GetPatientByID(id uint) (*models.PatientResponse, error)
 	GetPatientByPatientID(patientID string) (*models.PatientResponse, error)
 	UpdatePatient(id uint, req services.UpdatePatientRequest, updatedByID uint, userRole models.UserRole) (*models.PatientResponse, error)
-	DeletePatient(id uint, userRole models.UserRole) error
-	ListPatients(page, pageSize int) (*services.PatientListResponse, error)
-	SearchPatients(query string, page, pageSize int) (*services.PatientListResponse, error)
+	DeletePatient(id uint, deletedByID uint, userRole models.UserRole) error
+	ListPatients(req services.PatientListQuery, page, pageSize int) (*services.PatientListResponse, error)
+	SearchPatients(query string, page, pageSize int) (*services.PatientSearchResponse, error)
 }
 
 type MockAuthService struct {
@@ -47,25 +47,20 @@ func (m *MockAuthService) Login(req services.LoginRequest) (*services.LoginRespo
 	return args.Get(0).(*services.LoginResponse), args.Error(1)
 }
 
-func (m *MockAuthService) Register(req services.RegisterRequest) (*models.UserResponse, error) {
-	args := m.Called(req)
+func (m *MockAuthService) GetUserByID(id uint) (*models.UserResponse, error) {
+	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.UserResponse), args.Error(1)
 }
 
-func (m *MockAuthService) GetUserByID(id uint) (*models.UserResponse, error) {
-	args := m.Called(id)
+func (m *MockAuthService) RefreshToken(refreshToken string) (*services.TokenResponse, error) {
+	args := m.Called(refreshToken)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.UserResponse), args.Error(1)
-}
-
-func (m *MockAuthService) RefreshToken(tokenString string) (string, error) {
-	args := m.Called(tokenString)
-	return args.String(0), args.Error(1)
+	return args.Get(0).(*services.TokenResponse), args.Error(1)
 }
 
 func (m *MockAuthService) ValidateToken(tokenString string) (*auth.Claims, error) {
@@ -80,8 +75,8 @@ type MockPatientService struct {
 	mock.Mock
 }
 
-func (m *MockPatientService) CreatePatient(req services.CreatePatientRequest, createdByID uint) (*models.PatientResponse, error) {
-	args := m.Called(req, createdByID)
+func (m *MockPatientService) CreatePatient(req services.CreatePatientRequest, createdByID uint, userRole models.UserRole) (*models.PatientResponse, error) {
+	args := m.Called(req, createdByID, userRole)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -112,25 +107,25 @@ func (m *MockPatientService) UpdatePatient(id uint, req services.UpdatePatientRe
 	return args.Get(0).(*models.PatientResponse), args.Error(1)
 }
 
-func (m *MockPatientService) DeletePatient(id uint, userRole models.UserRole) error {
-	args := m.Called(id, userRole)
+func (m *MockPatientService) DeletePatient(id uint, deletedByID uint, userRole models.UserRole) error {
+	args := m.Called(id, deletedByID, userRole)
 	return args.Error(0)
 }
 
-func (m *MockPatientService) ListPatients(page, pageSize int) (*services.PatientListResponse, error) {
-	args := m.Called(page, pageSize)
+func (m *MockPatientService) ListPatients(req services.PatientListQuery, page, pageSize int) (*services.PatientListResponse, error) {
+	args := m.Called(req, page, pageSize)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*services.PatientListResponse), args.Error(1)
 }
 
-func (m *MockPatientService) SearchPatients(query string, page, pageSize int) (*services.PatientListResponse, error) {
+func (m *MockPatientService) SearchPatients(query string, page, pageSize int) (*services.PatientSearchResponse, error) {
 	args := m.Called(query, page, pageSize)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*services.PatientListResponse), args.Error(1)
+	return args.Get(0).(*services.PatientSearchResponse), args.Error(1)
 }
 
 func setupRouter() *gin.Engine {
@@ -178,12 +173,12 @@ func TestAuthHandler_Login_MissingFields(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestAuthHandler_Register_InvalidJSON(t *testing.T) {
-	authService := &services.AuthService{}
-	authHandler := handlers.NewAuthHandler(authService)
+func TestUserHandler_CreateUser_InvalidJSON(t *testing.T) {
+	userService := &services.UserService{}
+	userHandler := handlers.NewUserHandler(userService)
 
 	router := setupRouter()
-	router.POST("/register", authHandler.Register)
+	router.POST("/register", userHandler.CreateUser)
 
 	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
@@ -194,12 +189,12 @@ func TestAuthHandler_Register_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestAuthHandler_Register_MissingFields(t *testing.T) {
-	authService := &services.AuthService{}
-	authHandler := handlers.NewAuthHandler(authService)
+func TestUserHandler_CreateUser_MissingFields(t *testing.T) {
+	userService := &services.UserService{}
+	userHandler := handlers.NewUserHandler(userService)
 
 	router := setupRouter()
-	router.POST("/register", authHandler.Register)
+	router.POST("/register", userHandler.CreateUser)
 
 	reqData := map[string]string{"username": "testuser"}
 	jsonData, _ := json.Marshal(reqData)
@@ -233,7 +228,7 @@ func TestAuthHandler_GetProfile_MissingUserContext(t *testing.T) {
 	assert.False(t, response["success"].(bool))
 }
 
-func TestAuthHandler_RefreshToken_MissingHeader(t *testing.T) {
+func TestAuthHandler_RefreshToken_MissingBody(t *testing.T) {
 	authService := &services.AuthService{}
 	authHandler := handlers.NewAuthHandler(authService)
 
@@ -245,23 +240,38 @@ func TestAuthHandler_RefreshToken_MissingHeader(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestAuthHandler_RefreshToken_InvalidHeaderFormat(t *testing.T) {
+func TestAuthHandler_RefreshToken_MissingRefreshToken(t *testing.T) {
 	authService := &services.AuthService{}
 	authHandler := handlers.NewAuthHandler(authService)
 
 	router := setupRouter()
 	router.POST("/refresh", authHandler.RefreshToken)
 
-	req, _ := http.NewRequest("POST", "/refresh", nil)
-	req.Header.Set("Authorization", "InvalidFormat token")
+	req, _ := http.NewRequest("POST", "/refresh", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuthHandler_Logout_MissingSessionContext(t *testing.T) {
+	authService := &services.AuthService{}
+	authHandler := handlers.NewAuthHandler(authService)
+
+	router := setupRouter()
+	router.POST("/logout", authHandler.Logout)
+
+	req, _ := http.NewRequest("POST", "/logout", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
 func TestPatientHandler_CreatePatient_MissingUserContext(t *testing.T) {
@@ -296,7 +306,7 @@ func TestPatientHandler_CreatePatient_MissingUserContext(t *testing.T) {
 
 func TestPatientHandler_CreatePatient_ForbiddenForDoctor(t *testing.T) {
 	// Setup
-	patientService := &services.PatientService{} // nil dependencies for this test
+	patientService := services.NewPatientService(nil, nil, authz.DefaultPolicy())
 	patientHandler := handlers.NewPatientHandler(patientService)
 
 	router := setupRouter()
@@ -332,7 +342,7 @@ func TestPatientHandler_CreatePatient_ForbiddenForDoctor(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.False(t, response["success"].(bool))
-	assert.Contains(t, response["message"], "Only receptionists can create patients")
+	assert.Contains(t, response["message"], "Insufficient permissions to create patients")
 }
 
 func TestPatientHandler_CreatePatient_InvalidJSON(t *testing.T) {
@@ -487,7 +497,7 @@ func TestPatientHandler_DeletePatient_InvalidID(t *testing.T) {
 
 func TestPatientHandler_DeletePatient_ForbiddenForDoctor(t *testing.T) {
 	// Setup
-	patientService := &services.PatientService{} // nil dependencies for this test
+	patientService := services.NewPatientService(nil, nil, authz.DefaultPolicy())
 	patientHandler := handlers.NewPatientHandler(patientService)
 
 	router := setupRouter()
@@ -512,7 +522,7 @@ func TestPatientHandler_DeletePatient_ForbiddenForDoctor(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.False(t, response["success"].(bool))
-	assert.Contains(t, response["message"], "Only receptionists can delete patients")
+	assert.Contains(t, response["message"], "Insufficient permissions to delete patients")
 }
 
 func TestPatientHandler_DeletePatient_MissingUserContext(t *testing.T) {
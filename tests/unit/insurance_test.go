@@ -0,0 +1,388 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/billing"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockInsuranceRepository struct {
+	mock.Mock
+}
+
+func (m *MockInsuranceRepository) CreatePayer(payer *models.InsurancePayer) error {
+	args := m.Called(payer)
+	return args.Error(0)
+}
+
+func (m *MockInsuranceRepository) GetPayer(id uint) (*models.InsurancePayer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InsurancePayer), args.Error(1)
+}
+
+func (m *MockInsuranceRepository) GetPayerByCode(code string) (*models.InsurancePayer, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InsurancePayer), args.Error(1)
+}
+
+func (m *MockInsuranceRepository) ListPayers(includeInactive bool) ([]*models.InsurancePayer, error) {
+	args := m.Called(includeInactive)
+	return args.Get(0).([]*models.InsurancePayer), args.Error(1)
+}
+
+func (m *MockInsuranceRepository) UpdatePayer(payer *models.InsurancePayer) error {
+	args := m.Called(payer)
+	return args.Error(0)
+}
+
+func (m *MockInsuranceRepository) CreatePolicy(policy *models.InsurancePolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockInsuranceRepository) GetPolicy(patientID, id uint) (*models.InsurancePolicy, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InsurancePolicy), args.Error(1)
+}
+
+func (m *MockInsuranceRepository) ListPolicies(patientID uint, includeInactive bool) ([]*models.InsurancePolicy, error) {
+	args := m.Called(patientID, includeInactive)
+	return args.Get(0).([]*models.InsurancePolicy), args.Error(1)
+}
+
+func (m *MockInsuranceRepository) UpdatePolicy(policy *models.InsurancePolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+type MockClaimRepository struct {
+	mock.Mock
+}
+
+func (m *MockClaimRepository) Create(claim *models.Claim) error {
+	args := m.Called(claim)
+	return args.Error(0)
+}
+
+func (m *MockClaimRepository) GetByID(patientID, id uint) (*models.Claim, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Claim), args.Error(1)
+}
+
+func (m *MockClaimRepository) ListByPatient(patientID uint, status models.ClaimStatus) ([]*models.Claim, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]*models.Claim), args.Error(1)
+}
+
+func (m *MockClaimRepository) Search(filter repository.ClaimFilter, limit, offset int) ([]*models.Claim, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]*models.Claim), args.Error(1)
+}
+
+func (m *MockClaimRepository) Count(filter repository.ClaimFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClaimRepository) ListDrafts(payerID uint) ([]*models.Claim, error) {
+	args := m.Called(payerID)
+	return args.Get(0).([]*models.Claim), args.Error(1)
+}
+
+func (m *MockClaimRepository) Transition(claim *models.Claim, from ...models.ClaimStatus) error {
+	args := m.Called(claim, from)
+	return args.Error(0)
+}
+
+func (m *MockClaimRepository) MarkPaid(claim *models.Claim, payment *models.Payment, from ...models.ClaimStatus) error {
+	args := m.Called(claim, payment, from)
+	return args.Error(0)
+}
+
+func (m *MockClaimRepository) CreateBatch(batch *models.ClaimBatch, claims []*models.Claim, render func(batch *models.ClaimBatch) (string, error)) error {
+	args := m.Called(batch, claims, render)
+	return args.Error(0)
+}
+
+func (m *MockClaimRepository) GetBatch(id uint) (*models.ClaimBatch, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ClaimBatch), args.Error(1)
+}
+
+var testClaimSubmitter = billing.ClaimSubmitter{
+	Name:         "General Hospital",
+	SubmitterID:  "GH001",
+	ContactPhone: "(555) 010-2000",
+	ReceiverName: "Clearinghouse",
+	ReceiverID:   "CH999",
+	NPI:          "1234567893",
+	TaxID:        "12-3456789",
+	Address:      "1 Main St",
+	City:         "Springfield",
+	State:        "IL",
+	PostalCode:   "62701",
+}
+
+type claimTestRepos struct {
+	claims    *MockClaimRepository
+	invoices  *MockInvoiceRepository
+	insurance *MockInsuranceRepository
+	encounter *MockEncounterRepository
+	icd10     *MockICD10Repository
+}
+
+func newClaimTestService() (claimTestRepos, *services.ClaimService) {
+	repos := claimTestRepos{
+		claims:    new(MockClaimRepository),
+		invoices:  new(MockInvoiceRepository),
+		insurance: new(MockInsuranceRepository),
+		encounter: new(MockEncounterRepository),
+		icd10:     new(MockICD10Repository),
+	}
+	claimService := services.NewClaimService(repos.claims, repos.invoices, repos.insurance, repos.encounter, new(MockAdmissionRepository), repos.icd10, testClaimSubmitter, authz.DefaultPolicy())
+	return repos, claimService
+}
+
+func testPolicy(start string, end string) *models.InsurancePolicy {
+	coverageStart, _ := time.Parse("2006-01-02", start)
+	policy := &models.InsurancePolicy{
+		ID:                     3,
+		PatientID:              1,
+		PayerID:                2,
+		Payer:                  &models.InsurancePayer{ID: 2, Name: "Acme Health", PayerCode: "ACME1", IsActive: true},
+		PolicyNumber:           "POL123",
+		GroupNumber:            "GRP9",
+		SubscriberRelationship: models.SubscriberRelationshipSelf,
+		Priority:               models.CoveragePriorityPrimary,
+		CoverageStart:          coverageStart,
+		IsActive:               true,
+	}
+	if end != "" {
+		coverageEnd, _ := time.Parse("2006-01-02", end)
+		policy.CoverageEnd = &coverageEnd
+	}
+	return policy
+}
+
+func TestInsuranceService_CheckEligibility(t *testing.T) {
+	insuranceRepo := new(MockInsuranceRepository)
+	patientRepo := new(MockPatientRepository)
+	insuranceService := services.NewInsuranceService(insuranceRepo, patientRepo, authz.DefaultPolicy())
+
+	current := testPolicy("2026-01-01", "2026-12-31")
+	expired := testPolicy("2024-01-01", "2025-12-31")
+	expired.Priority = models.CoveragePrioritySecondary
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	insuranceRepo.On("ListPolicies", uint(1), false).Return([]*models.InsurancePolicy{current, expired}, nil)
+
+	response, err := insuranceService.CheckEligibility(1, "2026-12-31", models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.True(t, response.Eligible)
+	require.Len(t, response.Policies, 2)
+	assert.True(t, response.Policies[0].Eligible)
+	assert.False(t, response.Policies[1].Eligible)
+	assert.Equal(t, "coverage ended on 2025-12-31", response.Policies[1].Reason)
+
+	response, err = insuranceService.CheckEligibility(1, "2027-01-01", models.RoleReceptionist)
+	require.NoError(t, err)
+	assert.False(t, response.Eligible)
+}
+
+func TestInsuranceService_CreatePolicy_DependentNeedsSubscriber(t *testing.T) {
+	insuranceRepo := new(MockInsuranceRepository)
+	patientRepo := new(MockPatientRepository)
+	insuranceService := services.NewInsuranceService(insuranceRepo, patientRepo, authz.DefaultPolicy())
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	insuranceRepo.On("GetPayer", uint(2)).Return(&models.InsurancePayer{ID: 2, IsActive: true}, nil)
+
+	_, err := insuranceService.CreatePolicy(1, services.CreateInsurancePolicyRequest{
+		PayerID:                2,
+		PolicyNumber:           "POL123",
+		SubscriberRelationship: models.SubscriberRelationshipChild,
+		Priority:               models.CoveragePriorityPrimary,
+		CoverageStart:          "2026-01-01",
+	}, 7, models.RoleReceptionist)
+
+	require.Error(t, err)
+	assert.Equal(t, "subscriber name is required when the patient is not the subscriber", err.Error())
+	insuranceRepo.AssertNotCalled(t, "CreatePolicy", mock.Anything)
+}
+
+func TestClaimService_CreateClaim(t *testing.T) {
+	repos, claimService := newClaimTestService()
+
+	encounterID := uint(4)
+	encounterDate := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	invoice := issuedInvoice(15000, 0)
+	invoice.EncounterID = &encounterID
+	repos.invoices.On("GetByID", uint(1), uint(1)).Return(invoice, nil)
+	repos.insurance.On("GetPolicy", uint(1), uint(3)).Return(testPolicy("2026-01-01", ""), nil)
+	repos.encounter.On("GetByID", uint(1), encounterID).Return(&models.Encounter{ID: encounterID, PatientID: 1, EncounterDate: encounterDate}, nil)
+	repos.icd10.On("GetByCode", "E11.9").Return(&models.ICD10Code{Code: "E11.9"}, nil)
+	repos.icd10.On("GetByCode", "I10").Return(&models.ICD10Code{Code: "I10"}, nil)
+
+	created := &models.Claim{}
+	repos.claims.On("Create", mock.AnythingOfType("*models.Claim")).Run(func(args mock.Arguments) {
+		claim := args.Get(0).(*models.Claim)
+		claim.ID = 5
+		*created = *claim
+	}).Return(nil)
+	repos.claims.On("GetByID", uint(1), uint(5)).Return(created, nil)
+
+	response, err := claimService.CreateClaim(1, services.CreateClaimRequest{
+		InvoiceID:      1,
+		PolicyID:       3,
+		DiagnosisCodes: []string{"e119", "I10", "E11.9"},
+	}, 7, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ClaimStatusDraft, response.Status)
+	assert.Equal(t, []string{"E11.9", "I10"}, response.DiagnosisCodes)
+	assert.Equal(t, int64(15000), response.BilledCents)
+	assert.Equal(t, encounterDate, response.ServiceDate)
+}
+
+func TestClaimService_CreateClaim_NotCovered(t *testing.T) {
+	repos, claimService := newClaimTestService()
+
+	encounterID := uint(4)
+	invoice := issuedInvoice(15000, 0)
+	invoice.EncounterID = &encounterID
+	repos.invoices.On("GetByID", uint(1), uint(1)).Return(invoice, nil)
+	repos.insurance.On("GetPolicy", uint(1), uint(3)).Return(testPolicy("2026-01-01", "2026-02-28"), nil)
+	repos.encounter.On("GetByID", uint(1), encounterID).Return(&models.Encounter{ID: encounterID, EncounterDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}, nil)
+
+	_, err := claimService.CreateClaim(1, services.CreateClaimRequest{
+		InvoiceID:      1,
+		PolicyID:       3,
+		DiagnosisCodes: []string{"I10"},
+	}, 7, models.RoleReceptionist)
+
+	require.Error(t, err)
+	assert.Equal(t, "insurance policy does not cover the service date", err.Error())
+	repos.claims.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestClaimService_PayClaim_PostsInsurancePayment(t *testing.T) {
+	repos, claimService := newClaimTestService()
+
+	claim := &models.Claim{ID: 5, ClaimNumber: "CLM-000005", PatientID: 1, InvoiceID: 1, Status: models.ClaimStatusAccepted, BilledCents: 15000}
+	repos.claims.On("GetByID", uint(1), uint(5)).Return(claim, nil)
+
+	_, err := claimService.PayClaim(1, 5, services.PayClaimRequest{PaidCents: 15001}, 7, models.RoleReceptionist)
+	require.Error(t, err)
+	assert.Equal(t, "paid amount cannot exceed the billed amount", err.Error())
+
+	var payment *models.Payment
+	repos.claims.On("MarkPaid", claim, mock.AnythingOfType("*models.Payment"), []models.ClaimStatus{models.ClaimStatusAccepted}).
+		Run(func(args mock.Arguments) {
+			payment = args.Get(1).(*models.Payment)
+		}).Return(nil)
+
+	response, err := claimService.PayClaim(1, 5, services.PayClaimRequest{PaidCents: 12000, Reference: "EFT-778"}, 7, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ClaimStatusPaid, response.Status)
+	assert.Equal(t, int64(12000), payment.AmountCents)
+	assert.Equal(t, models.PaymentMethodInsurance, payment.Method)
+	assert.Equal(t, "EFT-778", payment.Reference)
+}
+
+func TestClaimService_CreateBatch_Renders837(t *testing.T) {
+	repos, claimService := newClaimTestService()
+
+	claim := &models.Claim{
+		ID:             5,
+		ClaimNumber:    "CLM-000005",
+		PatientID:      1,
+		Patient:        &models.Patient{ID: 1, FirstName: "Jane", LastName: "Doe", Gender: models.GenderFemale, DateOfBirth: time.Date(1980, 5, 17, 0, 0, 0, 0, time.UTC)},
+		Invoice:        &models.Invoice{ID: 1, Items: []models.InvoiceItem{{Code: "99213", Quantity: 1, TotalCents: 15000}}},
+		Policy:         testPolicy("2026-01-01", ""),
+		Status:         models.ClaimStatusDraft,
+		ServiceDate:    time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		DiagnosisCodes: []string{"E11.9", "I10"},
+		BilledCents:    15000,
+	}
+	repos.claims.On("ListDrafts", uint(0)).Return([]*models.Claim{claim}, nil)
+
+	var content string
+	repos.claims.On("CreateBatch", mock.AnythingOfType("*models.ClaimBatch"), []*models.Claim{claim}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			batch := args.Get(0).(*models.ClaimBatch)
+			batch.ID = 12
+			batch.BatchNumber = "BAT-000012"
+			render := args.Get(2).(func(batch *models.ClaimBatch) (string, error))
+			var err error
+			content, err = render(batch)
+			require.NoError(t, err)
+		}).Return(nil)
+
+	response, err := claimService.CreateBatch(services.CreateClaimBatchRequest{}, 7, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Batch.ClaimCount)
+	assert.Equal(t, int64(15000), response.Batch.TotalBilledCents)
+	assert.Equal(t, billing.ClaimFormat837P, response.Batch.Format)
+
+	segments := strings.Split(strings.TrimSpace(content), "~\n")
+	assert.True(t, strings.HasPrefix(segments[0], "ISA*00*"))
+	assert.Contains(t, segments[0], "*000000012*0*P*:")
+	assert.Contains(t, content, "ST*837*0001*005010X222A1~")
+	assert.Contains(t, content, "NM1*IL*1*DOE*JANE****MI*POL123~")
+	assert.Contains(t, content, "DMG*D8*19800517*F~")
+	assert.Contains(t, content, "NM1*PR*2*ACME HEALTH*****PI*ACME1~")
+	assert.Contains(t, content, "CLM*CLM-000005*150.00***11:B:1*Y*A*Y*Y~")
+	assert.Contains(t, content, "HI*ABK:E119*ABF:I10~")
+	assert.Contains(t, content, "SV1*HC:99213*150.00*UN*1***1:2~")
+	assert.Contains(t, content, "DTP*472*D8*20260314~")
+	assert.Contains(t, content, "IEA*1*000000012")
+
+	// SE counts the segments from ST to SE inclusive.
+	var st, se int
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "ST*") {
+			st = i
+		}
+		if strings.HasPrefix(segment, "SE*") {
+			se = i
+		}
+	}
+	assert.Equal(t, fmt.Sprintf("SE*%d*0001", se-st+1), segments[se])
+}
+
+func TestClaimService_DoctorCannotSeeClaims(t *testing.T) {
+	repos, claimService := newClaimTestService()
+
+	_, err := claimService.ListClaims(1, "", models.RoleDoctor)
+	assert.True(t, errors.Is(err, authz.ErrForbidden))
+	repos.claims.AssertNotCalled(t, "ListByPatient", mock.Anything, mock.Anything)
+}
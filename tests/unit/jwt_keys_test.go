@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519SigningKey(t *testing.T, kid string, notBefore time.Time) *auth.SigningKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &auth.SigningKey{
+		ID:         kid,
+		Method:     jwt.SigningMethodEdDSA,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		NotBefore:  notBefore,
+	}
+}
+
+func writePrivateKeyPEM(t *testing.T, dir, name string, privateKey interface{}) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+}
+
+func testUser() *models.User {
+	return &models.User{ID: 1, Username: "testuser", Role: models.RoleDoctor}
+}
+
+func TestJWTService_AsymmetricKey_SignsWithKid(t *testing.T) {
+	key := newEd25519SigningKey(t, "ed-2024", time.Now().Add(-time.Hour))
+	jwtService := auth.NewJWTServiceWithKeys(auth.NewKeySet(key), auth.DefaultAccessTokenTTL, auth.DefaultRefreshTokenTTL, auth.DefaultSessionMaxLifetime)
+
+	tokenString, err := jwtService.GenerateToken(testUser(), 1)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, &auth.Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "ed-2024", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Header["alg"])
+
+	claims, err := jwtService.ValidateToken(tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), claims.UserID)
+}
+
+func TestJWTService_Rotation_NewestActiveKeySigns(t *testing.T) {
+	oldKey := newEd25519SigningKey(t, "old", time.Now().Add(-48*time.Hour))
+	currentKey := newEd25519SigningKey(t, "current", time.Now().Add(-time.Hour))
+	scheduledKey := newEd25519SigningKey(t, "scheduled", time.Now().Add(24*time.Hour))
+
+	oldService := auth.NewJWTServiceWithKeys(auth.NewKeySet(oldKey), auth.DefaultAccessTokenTTL, auth.DefaultRefreshTokenTTL, auth.DefaultSessionMaxLifetime)
+	oldToken, err := oldService.GenerateToken(testUser(), 1)
+	require.NoError(t, err)
+
+	jwtService := auth.NewJWTServiceWithKeys(auth.NewKeySet(oldKey, scheduledKey, currentKey), auth.DefaultAccessTokenTTL, auth.DefaultRefreshTokenTTL, auth.DefaultSessionMaxLifetime)
+
+	newToken, err := jwtService.GenerateToken(testUser(), 1)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &auth.Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "current", parsed.Header["kid"])
+
+	_, err = jwtService.ValidateToken(oldToken)
+	assert.NoError(t, err, "tokens signed by a non-retired key must still validate")
+}
+
+func TestJWTService_RetiredKeyRejected(t *testing.T) {
+	key := newEd25519SigningKey(t, "retiring", time.Now().Add(-time.Hour))
+	jwtService := auth.NewJWTServiceWithKeys(auth.NewKeySet(key), auth.DefaultAccessTokenTTL, auth.DefaultRefreshTokenTTL, auth.DefaultSessionMaxLifetime)
+
+	tokenString, err := jwtService.GenerateToken(testUser(), 1)
+	require.NoError(t, err)
+
+	retired := *key
+	retired.RetireAt = time.Now().Add(-time.Minute)
+	replacement := newEd25519SigningKey(t, "replacement", time.Now().Add(-time.Minute))
+	rotatedService := auth.NewJWTServiceWithKeys(auth.NewKeySet(&retired, replacement), auth.DefaultAccessTokenTTL, auth.DefaultRefreshTokenTTL, auth.DefaultSessionMaxLifetime)
+
+	claims, err := rotatedService.ValidateToken(tokenString)
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestJWTService_RejectsAlgorithmMismatch(t *testing.T) {
+	key := newEd25519SigningKey(t, "ed-key", time.Now().Add(-time.Hour))
+	jwtService := auth.NewJWTServiceWithKeys(auth.NewKeySet(key), auth.DefaultAccessTokenTTL, auth.DefaultRefreshTokenTTL, auth.DefaultSessionMaxLifetime)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{UserID: 1})
+	token.Header["kid"] = "ed-key"
+	tokenString, err := token.SignedString([]byte(key.PublicKey.(ed25519.PublicKey)))
+	require.NoError(t, err)
+
+	claims, err := jwtService.ValidateToken(tokenString)
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestKeySet_LoadFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	writePrivateKeyPEM(t, dir, "rsa.pem", rsaKey)
+	writePrivateKeyPEM(t, dir, "ed.pem", edKey)
+
+	notBefore := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	manifest := `{"keys": [
+		{"kid": "rsa-1", "private_key_file": "rsa.pem", "not_before": "2020-01-01T00:00:00Z"},
+		{"kid": "ed-1", "private_key_file": "ed.pem", "not_before": "` + notBefore + `"}
+	]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, auth.KeyManifestFile), []byte(manifest), 0600))
+
+	keySet, err := auth.LoadKeySet(dir)
+	require.NoError(t, err)
+
+	signingKey, err := keySet.SigningKey(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "ed-1", signingKey.ID)
+
+	jwks := keySet.JWKS(time.Now())
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+	assert.NotEmpty(t, jwks.Keys[1].N)
+}
+
+func TestKeySet_LoadFromDirectory_InvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, auth.KeyManifestFile), []byte(`{"keys": [{"kid": "missing"}]}`), 0600))
+
+	keySet, err := auth.LoadKeySet(dir)
+
+	assert.Error(t, err)
+	assert.Nil(t, keySet)
+	assert.True(t, strings.Contains(err.Error(), "missing"))
+}
+
+func TestKeySet_AutoReload_NonPositiveIntervalDisablesReload(t *testing.T) {
+	keySet := auth.NewHMACKeySet("test-secret")
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			keySet.AutoReload(interval, nil)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("AutoReload(%v) did not return", interval)
+		}
+	}
+}
+
+func TestKeySet_JWKS_ExcludesSymmetricAndRetiredKeys(t *testing.T) {
+	retired := newEd25519SigningKey(t, "retired", time.Now().Add(-48*time.Hour))
+	retired.RetireAt = time.Now().Add(-time.Hour)
+	active := newEd25519SigningKey(t, "active", time.Now().Add(-time.Hour))
+
+	jwks := auth.NewKeySet(retired, active).JWKS(time.Now())
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "active", jwks.Keys[0].KeyID)
+
+	assert.Empty(t, auth.NewHMACKeySet("secret").JWKS(time.Now()).Keys)
+}
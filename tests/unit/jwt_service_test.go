@@ -19,7 +19,7 @@ func TestJWTService_GenerateToken_Success(t *testing.T) {
 		Role:     models.RoleDoctor,
 	}
 
-	token, err := jwtService.GenerateToken(user)
+	token, err := jwtService.GenerateToken(user, 1)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -41,7 +41,7 @@ func TestJWTService_ValidateToken_Success(t *testing.T) {
 		Role:     models.RoleReceptionist,
 	}
 
-	token, err := jwtService.GenerateToken(user)
+	token, err := jwtService.GenerateToken(user, 1)
 	assert.NoError(t, err)
 
 	claims, err := jwtService.ValidateToken(token)
@@ -83,7 +83,7 @@ func TestJWTService_ValidateToken_WrongSecret(t *testing.T) {
 		Role:     models.RoleDoctor,
 	}
 
-	token, err := jwtService1.GenerateToken(user)
+	token, err := jwtService1.GenerateToken(user, 1)
 	assert.NoError(t, err)
 
 	claims, err := jwtService2.ValidateToken(token)
@@ -120,68 +120,20 @@ func TestJWTService_ValidateToken_ExpiredToken(t *testing.T) {
 	assert.Contains(t, err.Error(), "token is expired")
 }
 
-func TestJWTService_RefreshToken_Success(t *testing.T) {
+func TestJWTService_GenerateToken_IncludesSessionID(t *testing.T) {
 	jwtService := auth.NewJWTService("test_secret_key")
-
 	user := &models.User{
 		ID:       1,
 		Username: "testuser",
 		Role:     models.RoleReceptionist,
 	}
 
-	originalToken, err := jwtService.GenerateToken(user)
-	assert.NoError(t, err)
-	assert.NotEmpty(t, originalToken)
-
-	time.Sleep(time.Second * 1)
-
-	newToken, err := jwtService.RefreshToken(originalToken)
-	assert.NoError(t, err)
-	assert.NotEmpty(t, newToken)
-
-	assert.NotEqual(t, originalToken, newToken, "New token should be different from original token")
-
-	originalClaims, err := jwtService.ValidateToken(originalToken)
-	assert.NoError(t, err)
-	assert.Equal(t, user.ID, originalClaims.UserID)
-
-	newClaims, err := jwtService.ValidateToken(newToken)
+	token, err := jwtService.GenerateToken(user, 42)
 	assert.NoError(t, err)
-	assert.Equal(t, user.ID, newClaims.UserID)
-}
-
-func TestJWTService_RefreshToken_InvalidToken(t *testing.T) {
-	jwtService := auth.NewJWTService("test_secret_key")
-
-	newToken, err := jwtService.RefreshToken("invalid_token")
-
-	assert.Error(t, err)
-	assert.Empty(t, newToken)
-}
-
-func TestJWTService_RefreshToken_ExpiredToken(t *testing.T) {
-	jwtService := auth.NewJWTService("test_secret_key")
 
-	claims := &auth.Claims{
-		UserID:   1,
-		Username: "testuser",
-		Role:     models.RoleDoctor,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)), // Expired 1 hour ago
-			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
-			Issuer:    "hospital-management-system",
-			Subject:   "testuser",
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte("test_secret_key"))
+	claims, err := jwtService.ValidateToken(token)
 	assert.NoError(t, err)
-
-	newToken, err := jwtService.RefreshToken(tokenString)
-
-	assert.Error(t, err)
-	assert.Empty(t, newToken)
+	assert.Equal(t, uint(42), claims.SessionID)
 }
 
 func TestJWTService_TokenExpirationTime(t *testing.T) {
@@ -194,7 +146,7 @@ func TestJWTService_TokenExpirationTime(t *testing.T) {
 
 	beforeGeneration := time.Now()
 
-	token, err := jwtService.GenerateToken(user)
+	token, err := jwtService.GenerateToken(user, 1)
 	assert.NoError(t, err)
 
 	afterGeneration := time.Now()
@@ -202,8 +154,8 @@ func TestJWTService_TokenExpirationTime(t *testing.T) {
 	claims, err := jwtService.ValidateToken(token)
 	assert.NoError(t, err)
 
-	expectedMinExpiration := beforeGeneration.Add(24 * time.Hour).Add(-time.Minute)
-	expectedMaxExpiration := afterGeneration.Add(24 * time.Hour).Add(time.Minute)
+	expectedMinExpiration := beforeGeneration.Add(auth.DefaultAccessTokenTTL).Add(-time.Minute)
+	expectedMaxExpiration := afterGeneration.Add(auth.DefaultAccessTokenTTL).Add(time.Minute)
 
 	assert.True(t, claims.ExpiresAt.After(expectedMinExpiration))
 	assert.True(t, claims.ExpiresAt.Before(expectedMaxExpiration))
@@ -224,8 +176,8 @@ func TestJWTService_DifferentUsers(t *testing.T) {
 		Role:     models.RoleReceptionist,
 	}
 
-	token1, err1 := jwtService.GenerateToken(user1)
-	token2, err2 := jwtService.GenerateToken(user2)
+	token1, err1 := jwtService.GenerateToken(user1, 1)
+	token2, err2 := jwtService.GenerateToken(user2, 1)
 
 	assert.NoError(t, err1)
 	assert.NoError(t, err2)
@@ -0,0 +1,182 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLabOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockLabOrderRepository) Create(order *models.LabOrder) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+func (m *MockLabOrderRepository) GetByID(patientID, id uint) (*models.LabOrder, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LabOrder), args.Error(1)
+}
+
+func (m *MockLabOrderRepository) ListByPatient(patientID uint, status models.LabOrderStatus) ([]*models.LabOrder, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]*models.LabOrder), args.Error(1)
+}
+
+func (m *MockLabOrderRepository) Transition(order *models.LabOrder, from ...models.LabOrderStatus) error {
+	args := m.Called(order, from)
+	return args.Error(0)
+}
+
+func (m *MockLabOrderRepository) RecordResults(order *models.LabOrder, results []models.LabResult, from ...models.LabOrderStatus) error {
+	args := m.Called(order, results, from)
+	return args.Error(0)
+}
+
+func (m *MockLabOrderRepository) MarkReviewed(order *models.LabOrder, reviewedAt time.Time) error {
+	args := m.Called(order, reviewedAt)
+	return args.Error(0)
+}
+
+func (m *MockLabOrderRepository) ListInbox(doctorID uint, includeReviewed bool, limit, offset int) ([]*models.LabOrder, error) {
+	args := m.Called(doctorID, includeReviewed, limit, offset)
+	return args.Get(0).([]*models.LabOrder), args.Error(1)
+}
+
+func (m *MockLabOrderRepository) CountInbox(doctorID uint, includeReviewed bool) (int64, error) {
+	args := m.Called(doctorID, includeReviewed)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newLabTestService() (*MockLabOrderRepository, *MockPatientRepository, *services.LabService) {
+	labOrderRepo := new(MockLabOrderRepository)
+	patientRepo := new(MockPatientRepository)
+	return labOrderRepo, patientRepo, services.NewLabService(labOrderRepo, patientRepo, new(MockEncounterRepository), authz.DefaultPolicy())
+}
+
+func TestLabService_CreateLabOrder(t *testing.T) {
+	labOrderRepo, patientRepo, labService := newLabTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	labOrderRepo.On("Create", mock.MatchedBy(func(o *models.LabOrder) bool {
+		return o.TestCode == "CBC" && o.Priority == models.LabOrderPriorityRoutine &&
+			o.Status == models.LabOrderStatusOrdered && o.OrderingDoctorID == 2
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.LabOrder).ID = 5
+	}).Return(nil)
+	labOrderRepo.On("GetByID", uint(1), uint(5)).Return(&models.LabOrder{ID: 5, PatientID: 1, TestCode: "CBC", Status: models.LabOrderStatusOrdered}, nil)
+
+	response, err := labService.CreateLabOrder(1, services.CreateLabOrderRequest{TestCode: " cbc ", TestName: "Complete blood count"}, 2, models.RoleDoctor)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), response.ID)
+	labOrderRepo.AssertExpectations(t)
+}
+
+func TestLabService_CreateLabOrder_Forbidden(t *testing.T) {
+	_, _, labService := newLabTestService()
+
+	_, err := labService.CreateLabOrder(1, services.CreateLabOrderRequest{TestCode: "CBC", TestName: "Complete blood count"}, 2, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+}
+
+func TestLabService_RecordResults_DerivesFlags(t *testing.T) {
+	labOrderRepo, _, labService := newLabTestService()
+
+	order := &models.LabOrder{ID: 5, PatientID: 1, Status: models.LabOrderStatusInProgress}
+	labOrderRepo.On("GetByID", uint(1), uint(5)).Return(order, nil)
+	labOrderRepo.On("RecordResults", order, mock.Anything, []models.LabOrderStatus{models.LabOrderStatusInProgress}).Return(nil)
+
+	response, err := labService.RecordResults(1, 5, services.RecordLabResultsRequest{Results: []services.LabResultInput{
+		{Analyte: "Hemoglobin", Value: "10.2", Unit: "g/dL", ReferenceLow: floatPtr(12), ReferenceHigh: floatPtr(16)},
+		{Analyte: "Platelets", Value: "250", ReferenceLow: floatPtr(150), ReferenceHigh: floatPtr(400)},
+		{Analyte: "Morphology", Value: "see comment"},
+	}}, 3, models.RoleDoctor)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.LabOrderStatusResulted, response.Status)
+	assert.True(t, response.HasAbnormalResults)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, models.LabResultFlagLow, response.Results[0].Flag)
+	assert.Equal(t, models.LabResultFlagNormal, response.Results[1].Flag)
+	assert.Equal(t, models.LabResultFlag(""), response.Results[2].Flag)
+}
+
+func TestLabService_InvalidTransitions(t *testing.T) {
+	labOrderRepo, _, labService := newLabTestService()
+
+	labOrderRepo.On("GetByID", uint(1), uint(5)).Return(&models.LabOrder{ID: 5, PatientID: 1, Status: models.LabOrderStatusOrdered}, nil)
+	labOrderRepo.On("GetByID", uint(1), uint(6)).Return(&models.LabOrder{ID: 6, PatientID: 1, Status: models.LabOrderStatusResulted}, nil)
+
+	_, err := labService.StartProcessing(1, 5, models.RoleDoctor)
+	require.Error(t, err)
+	assert.Equal(t, "only collected lab orders can be started", err.Error())
+
+	_, err = labService.RecordResults(1, 5, services.RecordLabResultsRequest{Results: []services.LabResultInput{{Analyte: "Hb", Value: "13"}}}, 3, models.RoleDoctor)
+	require.Error(t, err)
+	assert.Equal(t, "results can only be recorded for collected or in-progress lab orders", err.Error())
+
+	_, err = labService.CancelLabOrder(1, 6, services.CancelLabOrderRequest{Reason: "Duplicate"}, 2, models.RoleDoctor)
+	require.Error(t, err)
+	assert.Equal(t, "lab order can no longer be cancelled", err.Error())
+
+	labOrderRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything)
+}
+
+func TestLabService_CollectSpecimen_ConcurrentChange(t *testing.T) {
+	labOrderRepo, _, labService := newLabTestService()
+
+	order := &models.LabOrder{ID: 5, PatientID: 1, Status: models.LabOrderStatusOrdered}
+	labOrderRepo.On("GetByID", uint(1), uint(5)).Return(order, nil)
+	labOrderRepo.On("Transition", order, []models.LabOrderStatus{models.LabOrderStatusOrdered}).Return(repository.ErrLabOrderStatusChanged)
+
+	_, err := labService.CollectSpecimen(1, 5, 3, models.RoleDoctor)
+
+	assert.ErrorIs(t, err, repository.ErrLabOrderStatusChanged)
+}
+
+func TestLabService_ReviewResults_OnlyOrderingDoctor(t *testing.T) {
+	labOrderRepo, _, labService := newLabTestService()
+
+	order := &models.LabOrder{ID: 5, PatientID: 1, OrderingDoctorID: 2, Status: models.LabOrderStatusResulted}
+	labOrderRepo.On("GetByID", uint(1), uint(5)).Return(order, nil)
+	labOrderRepo.On("MarkReviewed", order, mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := labService.ReviewResults(1, 5, 9, models.RoleDoctor)
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	response, err := labService.ReviewResults(1, 5, 2, models.RoleDoctor)
+	require.NoError(t, err)
+	assert.NotNil(t, response.ReviewedAt)
+	labOrderRepo.AssertNumberOfCalls(t, "MarkReviewed", 1)
+}
+
+func TestLabService_Inbox_Pagination(t *testing.T) {
+	labOrderRepo, _, labService := newLabTestService()
+
+	labOrderRepo.On("ListInbox", uint(2), false, 10, 10).Return([]*models.LabOrder{
+		{ID: 11, PatientID: 1, Status: models.LabOrderStatusResulted},
+	}, nil)
+	labOrderRepo.On("CountInbox", uint(2), false).Return(int64(11), nil)
+
+	inbox, err := labService.Inbox(2, false, 2, 10, models.RoleDoctor)
+
+	require.NoError(t, err)
+	assert.Len(t, inbox.Orders, 1)
+	assert.Equal(t, int64(11), inbox.Pagination.Total)
+	assert.Equal(t, 2, inbox.Pagination.TotalPages)
+}
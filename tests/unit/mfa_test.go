@@ -0,0 +1,490 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID uint, codes []*models.MFARecoveryCode) error {
+	args := m.Called(userID, codes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID uint, codeHash string) error {
+	args := m.Called(userID, codeHash)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) DeleteRecoveryCodes(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) IsRequiredForRole(role models.UserRole) (bool, error) {
+	args := m.Called(role)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) ListPolicies() ([]*models.MFAPolicy, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MFAPolicy), args.Error(1)
+}
+
+func (m *MockMFARepository) SavePolicy(policy *models.MFAPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) StartEnrollment(userID uint, secret string) error {
+	args := m.Called(userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) EnableMFA(userID uint, secret string, step int64, enrolledAt time.Time) error {
+	args := m.Called(userID, secret, step, enrolledAt)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) DisableMFA(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) RecordTOTPStep(userID uint, step int64) error {
+	args := m.Called(userID, step)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) ChallengeFailures(challengeID string) (int, error) {
+	args := m.Called(challengeID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMFARepository) RecordChallengeFailure(challenge *models.MFAChallenge) error {
+	args := m.Called(challenge)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) RecordUserFailure(userID uint) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMFARepository) ClearFailedAttempts(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) LockMFA(userID uint, until time.Time) error {
+	args := m.Called(userID, until)
+	return args.Error(0)
+}
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func newMFATestServices() (*MockUserRepository, *MockSessionRepository, *MockMFARepository, *auth.JWTService, *services.MFAService) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	jwtService := auth.NewJWTService("test_secret")
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
+	return mockRepo, mockSessionRepo, mockMFARepo, jwtService, services.NewMFAService(authService, authz.DefaultPolicy(), "HMS")
+}
+
+func newMFAUser(t *testing.T) *models.User {
+	hashedPassword, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+
+	return &models.User{
+		ID:         1,
+		Username:   "testuser",
+		Password:   hashedPassword,
+		Role:       models.RoleDoctor,
+		IsActive:   true,
+		MFAEnabled: true,
+		MFASecret:  testTOTPSecret,
+	}
+}
+
+func TestTOTP_GenerateAndValidate(t *testing.T) {
+	now := time.Now()
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, now)
+	require.NoError(t, err)
+	assert.Len(t, code, auth.TOTPDigits)
+
+	step, ok := auth.ValidateTOTPCode(testTOTPSecret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, auth.TOTPStep(now), step)
+
+	_, ok = auth.ValidateTOTPCode(testTOTPSecret, code, now.Add(time.Duration(auth.TOTPPeriod)*time.Second))
+	assert.True(t, ok, "a code from the previous step should be accepted")
+
+	_, ok = auth.ValidateTOTPCode(testTOTPSecret, code, now.Add(5*time.Minute))
+	assert.False(t, ok)
+}
+
+func TestTOTP_RFC6238Vector(t *testing.T) {
+	// RFC 6238 appendix B, SHA1, T = 59s, secret "12345678901234567890".
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	code, err := auth.GenerateTOTPCode(secret, time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestTOTP_ProvisioningURI(t *testing.T) {
+	uri := auth.TOTPProvisioningURI("HMS", "testuser", testTOTPSecret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/HMS:testuser?"))
+	assert.Contains(t, uri, "secret="+testTOTPSecret)
+	assert.Contains(t, uri, "issuer=HMS")
+}
+
+func TestAuthService_Login_MFAEnabledReturnsChallenge(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	jwtService := auth.NewJWTService("test_secret")
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
+
+	mockRepo.On("GetByUsername", "testuser").Return(newMFAUser(t), nil)
+
+	response, err := authService.Login(services.LoginRequest{Username: "testuser", Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.True(t, response.MFARequired)
+	assert.NotEmpty(t, response.MFAToken)
+	assert.Nil(t, response.TokenResponse)
+	mockSessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	_, err = jwtService.ValidateToken(response.MFAToken)
+	assert.Error(t, err, "an MFA token must not be accepted as an access token")
+}
+
+func TestAuthService_Login_PolicyRequiresEnrollment(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	user := newMFAUser(t)
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	mockRepo.On("GetByUsername", "testuser").Return(user, nil)
+	mockMFARepo.On("IsRequiredForRole", models.RoleDoctor).Return(true, nil)
+
+	response, err := authService.Login(services.LoginRequest{Username: "testuser", Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.True(t, response.MFAEnrollmentRequired)
+	assert.False(t, response.MFARequired)
+	assert.Nil(t, response.TokenResponse)
+
+	claims, err := authService.ValidateMFAEnrollmentToken(response.MFAToken)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), claims.UserID)
+}
+
+func TestMFAService_VerifyLogin_WithCode(t *testing.T) {
+	mockRepo, mockSessionRepo, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(0, nil)
+	mockMFARepo.On("RecordTOTPStep", uint(1), mock.AnythingOfType("int64")).Return(nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession"), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: code})
+
+	assert.NoError(t, err)
+	require.NotNil(t, response.TokenResponse)
+	assert.NotEmpty(t, response.Token)
+	assert.InDelta(t, auth.TOTPStep(time.Now()), user.MFALastUsedStep, 1)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestMFAService_VerifyLogin_RejectsReplayedCode(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	user.MFALastUsedStep = auth.TOTPStep(time.Now())
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(0, nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: code})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "MFA code has already been used", err.Error())
+	mockMFARepo.AssertNotCalled(t, "RecordTOTPStep", mock.Anything, mock.Anything)
+}
+
+func TestMFAService_VerifyLogin_ConcurrentReplayRejected(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(0, nil)
+	mockMFARepo.On("RecordTOTPStep", uint(1), mock.AnythingOfType("int64")).Return(repository.ErrTOTPStepUsed)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: code})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "MFA code has already been used", err.Error())
+}
+
+func TestMFAService_VerifyLogin_CountsFailedCode(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+	claims, err := jwtService.ValidateMFAToken(mfaToken, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", claims.ID).Return(0, nil)
+	mockMFARepo.On("RecordUserFailure", uint(1)).Return(1, nil)
+	mockMFARepo.On("RecordChallengeFailure", mock.MatchedBy(func(challenge *models.MFAChallenge) bool {
+		return challenge.ID == claims.ID && challenge.UserID == 1
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.MFAChallenge).FailedAttempts = 1
+	}).Return(nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: "000000"})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "invalid MFA code", err.Error())
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestMFAService_VerifyLogin_ChallengeExhausted(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(5, nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: code})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "too many failed MFA attempts, please log in again", err.Error())
+	mockMFARepo.AssertNotCalled(t, "RecordTOTPStep", mock.Anything, mock.Anything)
+}
+
+func TestMFAService_VerifyLogin_LocksAfterUserLimit(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(0, nil)
+	mockMFARepo.On("RecordUserFailure", uint(1)).Return(10, nil)
+	mockMFARepo.On("LockMFA", uint(1), mock.MatchedBy(func(until time.Time) bool {
+		return until.After(time.Now())
+	})).Return(nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: "000000"})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "multi-factor authentication is locked after too many failed attempts", err.Error())
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestMFAService_VerifyLogin_LockedUser(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	user.MFALockedUntil = &lockedUntil
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: code})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "multi-factor authentication is locked after too many failed attempts", err.Error())
+	mockMFARepo.AssertNotCalled(t, "RecordTOTPStep", mock.Anything, mock.Anything)
+}
+
+func TestMFAService_VerifyLogin_WithRecoveryCode(t *testing.T) {
+	mockRepo, mockSessionRepo, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(0, nil)
+	mockMFARepo.On("UseRecoveryCode", uint(1), utils.HashToken("abcdefghijklmnop")).Return(nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession"), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, RecoveryCode: " ABCDEFGH-ijklmnop "})
+
+	assert.NoError(t, err)
+	require.NotNil(t, response.TokenResponse)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestMFAService_VerifyLogin_UsedRecoveryCode(t *testing.T) {
+	mockRepo, _, mockMFARepo, jwtService, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	mfaToken, err := jwtService.GenerateMFAToken(user, auth.PurposeMFAChallenge)
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("ChallengeFailures", mock.Anything).Return(0, nil)
+	mockMFARepo.On("UseRecoveryCode", uint(1), mock.Anything).Return(errors.New("recovery code not found"))
+	mockMFARepo.On("RecordUserFailure", uint(1)).Return(1, nil)
+	mockMFARepo.On("RecordChallengeFailure", mock.AnythingOfType("*models.MFAChallenge")).Return(nil)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, RecoveryCode: "abcdefgh-ijklmnop"})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "invalid recovery code", err.Error())
+}
+
+func TestMFAService_VerifyLogin_RejectsEnrollmentToken(t *testing.T) {
+	_, _, _, jwtService, mfaService := newMFATestServices()
+
+	mfaToken, err := jwtService.GenerateMFAToken(newMFAUser(t), auth.PurposeMFAEnrollment)
+	require.NoError(t, err)
+
+	response, err := mfaService.VerifyLogin(services.MFAVerifyRequest{MFAToken: mfaToken, Code: "123456"})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "invalid or expired MFA token", err.Error())
+}
+
+func TestMFAService_ConfirmEnrollment(t *testing.T) {
+	mockRepo, mockSessionRepo, mockMFARepo, _, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	user.MFAEnabled = false
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("EnableMFA", uint(1), testTOTPSecret, mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time")).Return(nil)
+	mockMFARepo.On("ReplaceRecoveryCodes", uint(1), mock.MatchedBy(func(codes []*models.MFARecoveryCode) bool {
+		return len(codes) == 10
+	})).Return(nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession"), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	response, err := mfaService.ConfirmEnrollment(1, code, true)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.RecoveryCodes, 10)
+	require.NotNil(t, response.TokenResponse)
+	assert.True(t, user.MFAEnabled)
+	assert.NotNil(t, user.MFAEnrolledAt)
+	mockMFARepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestMFAService_ConfirmEnrollment_InvalidCode(t *testing.T) {
+	mockRepo, _, mockMFARepo, _, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	user.MFAEnabled = false
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("RecordUserFailure", uint(1)).Return(1, nil)
+
+	response, err := mfaService.ConfirmEnrollment(1, "000000", false)
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid MFA code", err.Error())
+	assert.Nil(t, response)
+	assert.False(t, user.MFAEnabled)
+	mockMFARepo.AssertNotCalled(t, "ReplaceRecoveryCodes", mock.Anything, mock.Anything)
+	mockMFARepo.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMFAService_ConfirmEnrollment_LocksAfterTooManyFailures(t *testing.T) {
+	mockRepo, _, mockMFARepo, _, mfaService := newMFATestServices()
+
+	user := newMFAUser(t)
+	user.MFAEnabled = false
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockMFARepo.On("RecordUserFailure", uint(1)).Return(10, nil)
+	mockMFARepo.On("LockMFA", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := mfaService.ConfirmEnrollment(1, "000000", false)
+
+	assert.EqualError(t, err, "multi-factor authentication is locked after too many failed attempts")
+	mockMFARepo.AssertExpectations(t)
+
+	lockedUntil := time.Now().Add(time.Minute)
+	user.MFALockedUntil = &lockedUntil
+	code, err := auth.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	_, err = mfaService.ConfirmEnrollment(1, code, false)
+
+	assert.EqualError(t, err, "multi-factor authentication is locked after too many failed attempts")
+	mockMFARepo.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMFAService_Disable_RequiredByPolicy(t *testing.T) {
+	mockRepo, _, mockMFARepo, _, mfaService := newMFATestServices()
+
+	mockRepo.On("GetByID", uint(1)).Return(newMFAUser(t), nil)
+	mockMFARepo.On("IsRequiredForRole", models.RoleDoctor).Return(true, nil)
+
+	err := mfaService.Disable(1, "123456")
+
+	assert.Error(t, err)
+	assert.Equal(t, "multi-factor authentication is required for your role", err.Error())
+}
@@ -85,27 +85,25 @@ func TestPatient_ToResponse(t *testing.T) {
 	}
 
 	patient := models.Patient{
-		ID:                 1,
-		PatientID:          "PAT20240101001",
-		FirstName:          "Alice",
-		LastName:           "Johnson",
-		Email:              "alice@example.com",
-		Phone:              "1234567890",
-		DateOfBirth:        time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
-		Gender:             models.GenderFemale,
-		BloodType:          models.BloodTypeAPos,
-		Address:            "123 Main St",
-		EmergencyContact:   "0987654321",
-		MedicalHistory:     "No major illnesses",
-		Allergies:          "Peanuts",
-		CurrentMedications: "None",
-		CreatedByID:        1,
-		CreatedBy:          createdBy,
-		LastUpdatedByID:    &[]uint{2}[0],
-		LastUpdatedBy:      &updatedBy,
-		IsActive:           true,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
+		ID:               1,
+		PatientID:        "PAT20240101001",
+		FirstName:        "Alice",
+		LastName:         "Johnson",
+		Email:            "alice@example.com",
+		Phone:            "1234567890",
+		DateOfBirth:      time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+		Gender:           models.GenderFemale,
+		BloodType:        models.BloodTypeAPos,
+		Address:          "123 Main St",
+		EmergencyContact: "0987654321",
+		MedicalHistory:   "No major illnesses",
+		CreatedByID:      1,
+		CreatedBy:        createdBy,
+		LastUpdatedByID:  &[]uint{2}[0],
+		LastUpdatedBy:    &updatedBy,
+		IsActive:         true,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
 	}
 
 	response := patient.ToResponse()
@@ -122,8 +120,6 @@ func TestPatient_ToResponse(t *testing.T) {
 	assert.Equal(t, patient.Address, response.Address)
 	assert.Equal(t, patient.EmergencyContact, response.EmergencyContact)
 	assert.Equal(t, patient.MedicalHistory, response.MedicalHistory)
-	assert.Equal(t, patient.Allergies, response.Allergies)
-	assert.Equal(t, patient.CurrentMedications, response.CurrentMedications)
 	assert.Equal(t, patient.IsActive, response.IsActive)
 	assert.Equal(t, patient.CreatedAt, response.CreatedAt)
 	assert.Equal(t, patient.UpdatedAt, response.UpdatedAt)
@@ -0,0 +1,241 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPatientRevisionRepository) Create(revision *models.PatientRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockPatientRevisionRepository) ListByPatient(patientID uint) ([]*models.PatientRevision, error) {
+	args := m.Called(patientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PatientRevision), args.Error(1)
+}
+
+func (m *MockPatientRevisionRepository) GetByVersion(patientID uint, version int) (*models.PatientRevision, error) {
+	args := m.Called(patientID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PatientRevision), args.Error(1)
+}
+
+func newTestRevision(t *testing.T, version int, action models.RevisionAction, before, after models.PatientSnapshot) *models.PatientRevision {
+	changes, err := json.Marshal(models.DiffPatientSnapshots(before, after))
+	assert.NoError(t, err)
+	snapshot, err := json.Marshal(after)
+	assert.NoError(t, err)
+
+	return &models.PatientRevision{
+		ID:          uint(version),
+		PatientID:   1,
+		Version:     version,
+		Action:      action,
+		Changes:     string(changes),
+		Snapshot:    string(snapshot),
+		ChangedByID: 2,
+		ChangedBy:   models.User{ID: 2, Username: "doctor1", Role: models.RoleDoctor},
+	}
+}
+
+func TestDiffPatientSnapshots_ReportsChangedFieldsOnly(t *testing.T) {
+	before := models.PatientSnapshot{
+		FirstName:      "John",
+		LastName:       "Doe",
+		MedicalHistory: "None",
+		IsActive:       true,
+	}
+	after := before
+	after.MedicalHistory = "Type 2 diabetes"
+
+	changes := models.DiffPatientSnapshots(before, after)
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "medical_history", changes[0].Field)
+	assert.Equal(t, "None", changes[0].Before)
+	assert.Equal(t, "Type 2 diabetes", changes[0].After)
+}
+
+func TestDiffPatientSnapshots_NoChanges(t *testing.T) {
+	snapshot := models.PatientSnapshot{
+		FirstName:   "John",
+		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	changes := models.DiffPatientSnapshots(snapshot, snapshot)
+
+	assert.Empty(t, changes)
+}
+
+func TestNewPatientRevision(t *testing.T) {
+	before := models.PatientSnapshot{PatientID: "PAT202401010001", FirstName: "John", IsActive: true}
+	after := before
+	after.FirstName = "Jane"
+
+	revision, err := models.NewPatientRevision(1, models.RevisionActionUpdate, before, after, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), revision.PatientID)
+	assert.Equal(t, models.RevisionActionUpdate, revision.Action)
+	assert.Equal(t, uint(2), revision.ChangedByID)
+	changes, err := revision.GetChanges()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{{Field: "first_name", Before: "John", After: "Jane"}}, changes)
+	snapshot, err := revision.GetSnapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", snapshot.FirstName)
+}
+
+func TestNewPatientRevision_NoChangesSkipsUpdate(t *testing.T) {
+	snapshot := models.PatientSnapshot{FirstName: "John", IsActive: true}
+
+	revision, err := models.NewPatientRevision(1, models.RevisionActionUpdate, snapshot, snapshot, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, revision)
+
+	// A merge is recorded even when the survivor kept all of its details.
+	revision, err = models.NewPatientRevision(1, models.RevisionActionMerge, snapshot, snapshot, 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, revision)
+}
+
+func TestNewSafetyOverrideRevision(t *testing.T) {
+	snapshot := models.PatientSnapshot{FirstName: "John", IsActive: true}
+	prescription := &models.Prescription{
+		ID:             10,
+		PatientID:      1,
+		MedicationName: "Aspirin 75mg",
+		SafetyOverrides: []models.PrescriptionSafetyOverride{
+			{AlertKind: "drug_interaction", AlertLevel: "warning", Description: "Aspirin 75mg interacts with Warfarin 5mg", Reason: "Cardiology advised dual therapy", OverriddenByID: 2},
+		},
+	}
+
+	revision, err := models.NewSafetyOverrideRevision(snapshot, prescription, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), revision.PatientID)
+	assert.Equal(t, models.RevisionActionSafetyOverride, revision.Action)
+	changes, err := revision.GetChanges()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "safety_override", changes[0].Field)
+	assert.Nil(t, changes[0].Before)
+	after := changes[0].After.(map[string]interface{})
+	assert.Equal(t, "Aspirin 75mg", after["medication_name"])
+	assert.Equal(t, "Cardiology advised dual therapy", after["reason"])
+	restored, err := revision.GetSnapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, restored)
+}
+
+func TestPatientHistoryService_GetPatientHistory_Success(t *testing.T) {
+	mockRevisionRepo := new(MockPatientRevisionRepository)
+	historyService := services.NewPatientHistoryService(mockRevisionRepo, new(MockPatientRepository))
+
+	created := models.PatientSnapshot{FirstName: "John", IsActive: true}
+	updated := created
+	updated.FirstName = "Jane"
+
+	revisions := []*models.PatientRevision{
+		newTestRevision(t, 1, models.RevisionActionCreate, models.PatientSnapshot{}, created),
+		newTestRevision(t, 2, models.RevisionActionUpdate, created, updated),
+	}
+
+	mockRevisionRepo.On("ListByPatient", uint(1)).Return(revisions, nil)
+
+	history, err := historyService.GetPatientHistory(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, models.RevisionActionUpdate, history[1].Action)
+	assert.Len(t, history[1].Changes, 1)
+	assert.Equal(t, "first_name", history[1].Changes[0].Field)
+	assert.Equal(t, "Jane", history[1].Snapshot.FirstName)
+	assert.Equal(t, "doctor1", history[1].ChangedBy.Username)
+	mockRevisionRepo.AssertExpectations(t)
+}
+
+func TestPatientHistoryService_GetPatientHistory_NoRevisionsYet(t *testing.T) {
+	mockRevisionRepo := new(MockPatientRevisionRepository)
+	mockPatientRepo := new(MockPatientRepository)
+	historyService := services.NewPatientHistoryService(mockRevisionRepo, mockPatientRepo)
+
+	mockRevisionRepo.On("ListByPatient", uint(3)).Return([]*models.PatientRevision{}, nil)
+	mockPatientRepo.On("GetByID", uint(3)).Return(&models.Patient{ID: 3, IsActive: true}, nil)
+
+	history, err := historyService.GetPatientHistory(3)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, history)
+	assert.Empty(t, history)
+}
+
+func TestPatientHistoryService_GetPatientHistory_NotFound(t *testing.T) {
+	mockRevisionRepo := new(MockPatientRevisionRepository)
+	mockPatientRepo := new(MockPatientRepository)
+	historyService := services.NewPatientHistoryService(mockRevisionRepo, mockPatientRepo)
+
+	mockRevisionRepo.On("ListByPatient", uint(99)).Return([]*models.PatientRevision{}, nil)
+	mockPatientRepo.On("GetByID", uint(99)).Return(nil, errors.New("patient not found"))
+
+	history, err := historyService.GetPatientHistory(99)
+
+	assert.Error(t, err)
+	assert.Nil(t, history)
+	assert.Equal(t, "patient not found", err.Error())
+}
+
+func TestPatientHistoryService_DiffPatientVersions_Success(t *testing.T) {
+	mockRevisionRepo := new(MockPatientRevisionRepository)
+	historyService := services.NewPatientHistoryService(mockRevisionRepo, new(MockPatientRepository))
+
+	v1 := models.PatientSnapshot{FirstName: "John", MedicalHistory: "None", IsActive: true}
+	v2 := v1
+	v2.FirstName = "Jane"
+	v3 := v2
+	v3.MedicalHistory = "Type 2 diabetes"
+
+	mockRevisionRepo.On("GetByVersion", uint(1), 1).Return(newTestRevision(t, 1, models.RevisionActionCreate, models.PatientSnapshot{}, v1), nil)
+	mockRevisionRepo.On("GetByVersion", uint(1), 3).Return(newTestRevision(t, 3, models.RevisionActionUpdate, v2, v3), nil)
+
+	diff, err := historyService.DiffPatientVersions(1, 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 3, diff.ToVersion)
+	assert.Len(t, diff.Changes, 2)
+	assert.Equal(t, "first_name", diff.Changes[0].Field)
+	assert.Equal(t, "medical_history", diff.Changes[1].Field)
+	mockRevisionRepo.AssertExpectations(t)
+}
+
+func TestPatientHistoryService_DiffPatientVersions_VersionNotFound(t *testing.T) {
+	mockRevisionRepo := new(MockPatientRevisionRepository)
+	historyService := services.NewPatientHistoryService(mockRevisionRepo, new(MockPatientRepository))
+
+	mockRevisionRepo.On("GetByVersion", uint(1), 5).Return(nil, errors.New("patient revision not found"))
+
+	diff, err := historyService.DiffPatientVersions(1, 5, 6)
+
+	assert.Error(t, err)
+	assert.Nil(t, diff)
+	assert.Equal(t, "patient revision not found", err.Error())
+}
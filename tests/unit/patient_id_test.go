@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/patientid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPatientIDFormat() patientid.Format {
+	return patientid.Format{Prefix: "PAT", DateFormat: "YYYYMMDD", Width: 4, CheckDigit: patientid.CheckDigitLuhn}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	assert.Equal(t, 3, patientid.LuhnCheckDigit("7992739871"))
+	assert.Equal(t, 0, patientid.LuhnCheckDigit("0"))
+	assert.Equal(t, 8, patientid.LuhnCheckDigit("1"))
+}
+
+func TestPatientIDFormat_Build(t *testing.T) {
+	format := testPatientIDFormat()
+	scope := format.Scope(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	assert.Equal(t, "PAT20240101", scope)
+
+	id := format.Build(scope, 42)
+	assert.Equal(t, "PAT202401010042", id[:len(id)-1])
+	assert.NoError(t, format.Check(id))
+
+	monthly := patientid.Format{Prefix: "MRN-", DateFormat: "YYMM", Width: 6, CheckDigit: patientid.CheckDigitNone}
+	assert.Equal(t, "MRN-2401000042", monthly.Build(monthly.Scope(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), 42))
+
+	undated := patientid.Format{Prefix: "P", Width: 5, CheckDigit: patientid.CheckDigitLuhn}
+	assert.Equal(t, "P", undated.Scope(time.Now()))
+	assert.Len(t, undated.Build("P", 7), 7)
+}
+
+func TestPatientIDFormat_CheckDetectsTypos(t *testing.T) {
+	format := testPatientIDFormat()
+	id := format.Build("PAT20240101", 42)
+
+	for i := 3; i < len(id); i++ {
+		typo := []byte(id)
+		typo[i] = '0' + (typo[i]-'0'+1)%10
+		assert.ErrorIs(t, format.Check(string(typo)), patientid.ErrInvalidCheckDigit, "digit %d changed", i)
+	}
+
+	swapped := []byte(id)
+	swapped[len(id)-3], swapped[len(id)-2] = swapped[len(id)-2], swapped[len(id)-3]
+	assert.ErrorIs(t, format.Check(string(swapped)), patientid.ErrInvalidCheckDigit)
+}
+
+func TestPatientIDFormat_CheckSkipsOtherIDs(t *testing.T) {
+	format := testPatientIDFormat()
+
+	// IDs issued before check digits were introduced are looked up as they are.
+	assert.NoError(t, format.Check("PAT202401010007"))
+	assert.NoError(t, format.Check("LEGACY-123"))
+	assert.NoError(t, format.Check("PATIENT"))
+
+	none := format
+	none.CheckDigit = patientid.CheckDigitNone
+	assert.NoError(t, none.Check("PAT2024010100429"))
+}
+
+func TestPatientIDFormat_Validate(t *testing.T) {
+	require.NoError(t, testPatientIDFormat().Validate())
+
+	invalid := []patientid.Format{
+		{Prefix: "PAT", DateFormat: "YYYYMMDD", Width: 0, CheckDigit: patientid.CheckDigitLuhn},
+		{Prefix: "P1", Width: 4, CheckDigit: patientid.CheckDigitLuhn},
+		{Prefix: "PAT", DateFormat: "YYYY-MM", Width: 4, CheckDigit: patientid.CheckDigitLuhn},
+		{Prefix: "PAT", Width: 4, CheckDigit: "verhoeff"},
+	}
+	for _, format := range invalid {
+		assert.Error(t, format.Validate(), "%+v", format)
+	}
+}
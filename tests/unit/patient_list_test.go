@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatientService_ListPatients_Filters(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	patientService := services.NewPatientService(mockPatientRepo, new(MockUserRepository), authz.DefaultPolicy())
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	minAge, maxAge := 18, 65
+	createdBy := uint(3)
+
+	filterMatcher := mock.MatchedBy(func(filter repository.PatientFilter) bool {
+		return filter.Gender == models.GenderFemale &&
+			filter.BloodType == models.BloodTypeONeg &&
+			filter.CreatedByID != nil && *filter.CreatedByID == 3 &&
+			filter.IsActive == nil &&
+			filter.BornTo.Equal(today.AddDate(-18, 0, 1)) &&
+			filter.BornFrom.Equal(today.AddDate(-66, 0, 1)) &&
+			filter.CreatedFrom.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			filter.CreatedTo.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) &&
+			filter.UpdatedFrom.IsZero() && filter.UpdatedTo.IsZero()
+	})
+	order := []repository.PatientSort{
+		{Field: "last_name"},
+		{Field: "age", Descending: true},
+	}
+	mockPatientRepo.On("List", filterMatcher, order, 20, 20).Return([]*models.Patient{{ID: 4}}, nil)
+	mockPatientRepo.On("Count", filterMatcher).Return(int64(21), nil)
+
+	response, err := patientService.ListPatients(services.PatientListQuery{
+		Gender:      models.GenderFemale,
+		BloodType:   models.BloodTypeONeg,
+		MinAge:      &minAge,
+		MaxAge:      &maxAge,
+		CreatedByID: &createdBy,
+		CreatedFrom: "2024-01-01",
+		CreatedTo:   "2024-01-31",
+		Status:      "all",
+		Sort:        "last_name, -age",
+	}, 2, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(21), response.Pagination.Total)
+	assert.Equal(t, 2, response.Pagination.TotalPages)
+	mockPatientRepo.AssertExpectations(t)
+}
+
+func TestPatientService_ListPatients_InvalidQuery(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	patientService := services.NewPatientService(mockPatientRepo, new(MockUserRepository), authz.DefaultPolicy())
+
+	negative, young, old := -1, 30, 20
+	tests := []struct {
+		name  string
+		query services.PatientListQuery
+		err   string
+	}{
+		{"unknown gender", services.PatientListQuery{Gender: "unknown"}, "gender must be one of male, female or other"},
+		{"unknown blood type", services.PatientListQuery{BloodType: "C+"}, "blood_type must be one of A+, A-, B+, B-, AB+, AB-, O+ or O-"},
+		{"unknown status", services.PatientListQuery{Status: "deleted"}, "status must be one of active, inactive or all"},
+		{"negative age", services.PatientListQuery{MinAge: &negative}, "min_age must not be negative"},
+		{"age range reversed", services.PatientListQuery{MinAge: &young, MaxAge: &old}, "min_age must not be greater than max_age"},
+		{"bad date", services.PatientListQuery{UpdatedFrom: "01/02/2024"}, "invalid updated_from date format, use YYYY-MM-DD"},
+		{"date range reversed", services.PatientListQuery{CreatedFrom: "2024-02-01", CreatedTo: "2024-01-01"}, "created_from date must not be after created_to date"},
+		{"repeated sort field", services.PatientListQuery{Sort: "last_name,-last_name"}, `sort field "last_name" is given more than once`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := patientService.ListPatients(tt.query, 1, 10)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	_, err := patientService.ListPatients(services.PatientListQuery{Sort: "email"}, 1, 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot sort by "email"`)
+	assert.Contains(t, err.Error(), "last_name")
+
+	mockPatientRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
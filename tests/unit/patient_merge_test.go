@@ -0,0 +1,227 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/matching"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func matchPatient(first, last, dob, phone, email string) *models.Patient {
+	dateOfBirth, _ := time.Parse("2006-01-02", dob)
+	return &models.Patient{
+		FirstName:   first,
+		LastName:    last,
+		DateOfBirth: dateOfBirth,
+		Phone:       phone,
+		Email:       email,
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.InDelta(t, 0.961, matching.JaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, matching.JaroWinkler("dwayne", "duane"), 0.001)
+	assert.Equal(t, 1.0, matching.JaroWinkler("jane", "jane"))
+	assert.Equal(t, 0.0, matching.JaroWinkler("abc", "xyz"))
+}
+
+func TestMatchingScore(t *testing.T) {
+	existing := matchPatient("Jonathan", "O'Neil", "1985-03-07", "+1 555 010 2030", "jon@example.com")
+
+	tests := []struct {
+		name    string
+		patient *models.Patient
+		level   matching.MatchLevel
+	}{
+		{"same name and birth date", matchPatient("Jonathan", "Oneil", "1985-03-07", "5550000000", ""), matching.MatchLevelLikely},
+		{"typo with same phone", matchPatient("Jonathon", "O'Neil", "1985-03-07", "15550102030", ""), matching.MatchLevelLikely},
+		{"swapped names", matchPatient("Oneil", "Jonathan", "1985-03-07", "", ""), matching.MatchLevelLikely},
+		{"day and month swapped with email", matchPatient("Jonathan", "O'Neil", "1985-07-03", "", "JON@example.com"), matching.MatchLevelLikely},
+		{"phone without country code", matchPatient("Jonathan", "O'Neil", "1990-01-01", "555-010-2030 ", ""), ""},
+		{"name and phone with formatting", matchPatient("Jonathan", "O'Neil", "1990-01-01", "+1 (555) 010-2030", ""), matching.MatchLevelPossible},
+		{"different person same birthday", matchPatient("Maria", "Garcia", "1985-03-07", "5559990000", ""), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := matching.Score(tt.patient, existing)
+			assert.Equal(t, tt.level, match.Level, "score %d, reasons %v", match.Score, match.Reasons)
+		})
+	}
+}
+
+func TestPatientService_CreatePatient_LikelyDuplicate(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockUserRepo := new(MockUserRepository)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
+
+	existing := matchPatient("John", "Doe", "1990-01-01", "1234567890", "john@example.com")
+	existing.ID = 7
+	existing.PatientID = "PAT202401010007"
+
+	mockUserRepo.On("GetByID", uint(1)).Return(&models.User{ID: 1, Role: models.RoleReceptionist, IsActive: true}, nil)
+	mockPatientRepo.On("FindMatchCandidates", mock.AnythingOfType("*models.Patient")).Return([]*models.Patient{existing}, nil)
+
+	req := services.CreatePatientRequest{
+		FirstName:        "Jon",
+		LastName:         "Doe",
+		Phone:            "123-456-7890",
+		DateOfBirth:      "1990-01-01",
+		Gender:           models.GenderMale,
+		EmergencyContact: "0987654321",
+	}
+
+	response, err := patientService.CreatePatient(req, 1, models.RoleReceptionist)
+
+	assert.Nil(t, response)
+	var duplicateErr *services.DuplicatePatientError
+	require.True(t, errors.As(err, &duplicateErr))
+	require.Len(t, duplicateErr.Candidates, 1)
+	candidate := duplicateErr.Candidates[0]
+	assert.Equal(t, "PAT202401010007", candidate.Patient.PatientID)
+	assert.Equal(t, matching.MatchLevelLikely, candidate.Match.Level)
+	assert.Contains(t, candidate.Match.Reasons, "same phone number")
+	mockPatientRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPatientService_CreatePatient_ConfirmNotDuplicate(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockUserRepo := new(MockUserRepository)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
+
+	created := matchPatient("John", "Doe", "1990-01-01", "1234567890", "")
+	created.ID = 8
+
+	mockUserRepo.On("GetByID", uint(1)).Return(&models.User{ID: 1, Role: models.RoleReceptionist, IsActive: true}, nil)
+	mockPatientRepo.On("Create", mock.AnythingOfType("*models.Patient")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Patient).ID = 8
+	})
+	mockPatientRepo.On("GetByID", uint(8)).Return(created, nil)
+
+	req := services.CreatePatientRequest{
+		FirstName:           "John",
+		LastName:            "Doe",
+		Phone:               "1234567890",
+		DateOfBirth:         "1990-01-01",
+		Gender:              models.GenderMale,
+		EmergencyContact:    "0987654321",
+		ConfirmNotDuplicate: true,
+	}
+
+	response, err := patientService.CreatePatient(req, 1, models.RoleReceptionist)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(8), response.ID)
+	mockPatientRepo.AssertNotCalled(t, "FindMatchCandidates", mock.Anything)
+}
+
+func TestPatientService_FindDuplicates_AdminSeesSummaryOnly(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	patientService := services.NewPatientService(mockPatientRepo, new(MockUserRepository), authz.DefaultPolicy())
+
+	patient := matchPatient("John", "Doe", "1990-01-01", "123-456-7890", "john@example.com")
+	patient.ID = 1
+	existing := matchPatient("Jon", "Doe", "1990-01-01", "123-456-7890", "jon@example.com")
+	existing.ID = 2
+	existing.PatientID = "PAT202401010002"
+	mockPatientRepo.On("GetByID", uint(1)).Return(patient, nil)
+	mockPatientRepo.On("FindMatchCandidates", patient).Return([]*models.Patient{existing}, nil)
+
+	candidates, err := patientService.FindDuplicates(1, models.RoleAdmin)
+
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, models.PatientSummary{ID: 2, PatientID: "PAT202401010002", FirstName: "Jon", LastName: "Doe"}, candidates[0].Patient)
+	assert.Contains(t, candidates[0].Match.Reasons, "same phone number")
+}
+
+func TestPatientService_MergePatients(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockUserRepo := new(MockUserRepository)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
+
+	newSurvivor := func() *models.Patient {
+		survivor := matchPatient("John", "Doe", "1990-01-01", "1234567890", "")
+		survivor.ID = 1
+		survivor.PatientID = "PAT202401010001"
+		survivor.MedicalHistory = "Asthma"
+		survivor.IsActive = true
+		return survivor
+	}
+	newDuplicate := func() *models.Patient {
+		duplicate := matchPatient("Jon", "Doe", "1990-01-01", "1234567890", "john@example.com")
+		duplicate.ID = 2
+		duplicate.PatientID = "PAT202402020001"
+		duplicate.Address = "1 Main Street"
+		duplicate.MedicalHistory = "Penicillin reaction in 2019"
+		duplicate.IsActive = true
+		return duplicate
+	}
+
+	// The merge is applied to the rows as locked by the repository, which
+	// here carry a blood type recorded after the service read the survivor.
+	survivor, duplicate := newSurvivor(), newDuplicate()
+	survivor.BloodType = models.BloodTypeAPos
+
+	mockPatientRepo.On("GetByID", uint(1)).Return(newSurvivor(), nil)
+	mockPatientRepo.On("GetByID", uint(2)).Return(newDuplicate(), nil).Once()
+	mockPatientRepo.On("Merge", uint(1), uint(2), uint(5), mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(3).(func(survivor, duplicate *models.Patient))(survivor, duplicate)
+	}).Return(map[string]int64{"encounters": 3, "invoices": 1}, nil)
+
+	response, err := patientService.MergePatients(1, services.MergePatientRequest{DuplicateID: 2}, 5, models.RoleDoctor)
+
+	require.NoError(t, err)
+	assert.Equal(t, "PAT202402020001", response.MergedPatientID)
+	assert.Equal(t, int64(3), response.MovedRecords["encounters"])
+
+	assert.Equal(t, "john@example.com", survivor.Email)
+	assert.Equal(t, "1 Main Street", survivor.Address)
+	assert.Equal(t, models.BloodTypeAPos, survivor.BloodType)
+	assert.Contains(t, survivor.MedicalHistory, "Asthma")
+	assert.Contains(t, survivor.MedicalHistory, "Merged from PAT202402020001:\nPenicillin reaction in 2019")
+
+	assert.False(t, duplicate.IsActive)
+	assert.Empty(t, duplicate.Email)
+	require.NotNil(t, duplicate.MergedIntoID)
+	assert.Equal(t, uint(1), *duplicate.MergedIntoID)
+	assert.NotNil(t, duplicate.MergedAt)
+	mockPatientRepo.AssertExpectations(t)
+}
+
+func TestPatientService_MergePatients_Conflict(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockUserRepo := new(MockUserRepository)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
+
+	survivor := &models.Patient{ID: 1, IsActive: true}
+	duplicate := &models.Patient{ID: 2, IsActive: true}
+	mockPatientRepo.On("GetByID", uint(1)).Return(survivor, nil)
+	mockPatientRepo.On("GetByID", uint(2)).Return(duplicate, nil)
+	mockPatientRepo.On("Merge", uint(1), uint(2), uint(5), mock.Anything).Return(nil, repository.ErrMergeBothAdmitted)
+
+	_, err := patientService.MergePatients(1, services.MergePatientRequest{DuplicateID: 2}, 5, models.RoleAdmin)
+
+	assert.ErrorIs(t, err, repository.ErrMergeBothAdmitted)
+}
+
+func TestPatientService_MergePatients_Rejected(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	patientService := services.NewPatientService(mockPatientRepo, new(MockUserRepository), authz.DefaultPolicy())
+
+	_, err := patientService.MergePatients(1, services.MergePatientRequest{DuplicateID: 2}, 5, models.RoleReceptionist)
+	assert.Equal(t, authz.ErrForbidden, err)
+
+	_, err = patientService.MergePatients(1, services.MergePatientRequest{DuplicateID: 1}, 5, models.RoleDoctor)
+	assert.EqualError(t, err, "cannot merge a patient into itself")
+	mockPatientRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,15 +1,19 @@
 package unit
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
+	"hospital-management-system/internal/authz"
 	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
 	"hospital-management-system/internal/services"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockPatientRepository struct {
@@ -37,18 +41,18 @@ func (m *MockPatientRepository) GetByPatientID(patientID string) (*models.Patien
 	return args.Get(0).(*models.Patient), args.Error(1)
 }
 
-func (m *MockPatientRepository) Update(patient *models.Patient) error {
-	args := m.Called(patient)
+func (m *MockPatientRepository) Update(patient *models.Patient, updatedByID uint) error {
+	args := m.Called(patient, updatedByID)
 	return args.Error(0)
 }
 
-func (m *MockPatientRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockPatientRepository) Delete(id uint, deletedByID uint) error {
+	args := m.Called(id, deletedByID)
 	return args.Error(0)
 }
 
-func (m *MockPatientRepository) List(limit, offset int) ([]*models.Patient, error) {
-	args := m.Called(limit, offset)
+func (m *MockPatientRepository) List(filter repository.PatientFilter, order []repository.PatientSort, limit, offset int) ([]*models.Patient, error) {
+	args := m.Called(filter, order, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -71,20 +75,31 @@ func (m *MockPatientRepository) GetByCreatedBy(userID uint, limit, offset int) (
 	return args.Get(0).([]*models.Patient), args.Error(1)
 }
 
-func (m *MockPatientRepository) Count() (int64, error) {
-	args := m.Called()
+func (m *MockPatientRepository) Count(filter repository.PatientFilter) (int64, error) {
+	args := m.Called(filter)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockPatientRepository) GenerateUniquePatientID() (string, error) {
-	args := m.Called()
-	return args.String(0), args.Error(1)
+func (m *MockPatientRepository) FindMatchCandidates(patient *models.Patient) ([]*models.Patient, error) {
+	args := m.Called(patient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Patient), args.Error(1)
+}
+
+func (m *MockPatientRepository) Merge(survivorID, duplicateID, mergedByID uint, merge func(survivor, duplicate *models.Patient)) (map[string]int64, error) {
+	args := m.Called(survivorID, duplicateID, mergedByID, merge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
 }
 
 func TestPatientService_CreatePatient_Success(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	createdBy := &models.User{
 		ID:       1,
@@ -95,6 +110,7 @@ func TestPatientService_CreatePatient_Success(t *testing.T) {
 
 	mockUserRepo.On("GetByID", uint(1)).Return(createdBy, nil)
 
+	mockPatientRepo.On("FindMatchCandidates", mock.AnythingOfType("*models.Patient")).Return([]*models.Patient{}, nil)
 	mockPatientRepo.On("Create", mock.AnythingOfType("*models.Patient")).Return(nil).Run(func(args mock.Arguments) {
 		patient := args.Get(0).(*models.Patient)
 		patient.ID = 1
@@ -125,7 +141,7 @@ func TestPatientService_CreatePatient_Success(t *testing.T) {
 		EmergencyContact: "0987654321",
 	}
 
-	response, err := patientService.CreatePatient(req, 1)
+	response, err := patientService.CreatePatient(req, 1, models.RoleReceptionist)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -139,7 +155,7 @@ func TestPatientService_CreatePatient_Success(t *testing.T) {
 func TestPatientService_CreatePatient_InvalidUser(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	mockUserRepo.On("GetByID", uint(999)).Return(nil, errors.New("user not found"))
 
@@ -152,7 +168,7 @@ func TestPatientService_CreatePatient_InvalidUser(t *testing.T) {
 		EmergencyContact: "0987654321",
 	}
 
-	response, err := patientService.CreatePatient(req, 999)
+	response, err := patientService.CreatePatient(req, 999, models.RoleReceptionist)
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -163,7 +179,7 @@ func TestPatientService_CreatePatient_InvalidUser(t *testing.T) {
 func TestPatientService_CreatePatient_InvalidDateFormat(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	createdBy := &models.User{
 		ID:       1,
@@ -183,7 +199,7 @@ func TestPatientService_CreatePatient_InvalidDateFormat(t *testing.T) {
 		EmergencyContact: "0987654321",
 	}
 
-	response, err := patientService.CreatePatient(req, 1)
+	response, err := patientService.CreatePatient(req, 1, models.RoleReceptionist)
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -194,7 +210,7 @@ func TestPatientService_CreatePatient_InvalidDateFormat(t *testing.T) {
 func TestPatientService_CreatePatient_FutureDateOfBirth(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	createdBy := &models.User{
 		ID:       1,
@@ -215,7 +231,7 @@ func TestPatientService_CreatePatient_FutureDateOfBirth(t *testing.T) {
 		EmergencyContact: "0987654321",
 	}
 
-	response, err := patientService.CreatePatient(req, 1)
+	response, err := patientService.CreatePatient(req, 1, models.RoleReceptionist)
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -226,7 +242,7 @@ func TestPatientService_CreatePatient_FutureDateOfBirth(t *testing.T) {
 func TestPatientService_GetPatientByID_Success(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	patient := &models.Patient{
 		ID:        1,
@@ -249,7 +265,7 @@ func TestPatientService_GetPatientByID_Success(t *testing.T) {
 func TestPatientService_GetPatientByID_NotFound(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	mockPatientRepo.On("GetByID", uint(999)).Return(nil, errors.New("patient not found"))
 
@@ -264,7 +280,7 @@ func TestPatientService_GetPatientByID_NotFound(t *testing.T) {
 func TestPatientService_GetPatientByPatientID_Success(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	patient := &models.Patient{
 		ID:        1,
@@ -287,7 +303,7 @@ func TestPatientService_GetPatientByPatientID_Success(t *testing.T) {
 func TestPatientService_UpdatePatient_Success_Receptionist(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	existingPatient := &models.Patient{
 		ID:        1,
@@ -305,7 +321,7 @@ func TestPatientService_UpdatePatient_Success_Receptionist(t *testing.T) {
 
 	mockPatientRepo.On("GetByID", uint(1)).Return(existingPatient, nil)
 	mockUserRepo.On("GetByID", uint(2)).Return(updatedBy, nil)
-	mockPatientRepo.On("Update", mock.AnythingOfType("*models.Patient")).Return(nil)
+	mockPatientRepo.On("Update", mock.AnythingOfType("*models.Patient"), uint(2)).Return(nil)
 	mockPatientRepo.On("GetByID", uint(1)).Return(existingPatient, nil) // For returning updated patient
 
 	newFirstName := "Jane"
@@ -324,7 +340,7 @@ func TestPatientService_UpdatePatient_Success_Receptionist(t *testing.T) {
 func TestPatientService_UpdatePatient_Success_Doctor_MedicalFields(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	existingPatient := &models.Patient{
 		ID:             1,
@@ -342,8 +358,8 @@ func TestPatientService_UpdatePatient_Success_Doctor_MedicalFields(t *testing.T)
 
 	mockPatientRepo.On("GetByID", uint(1)).Return(existingPatient, nil)
 	mockUserRepo.On("GetByID", uint(2)).Return(updatedBy, nil)
-	mockPatientRepo.On("Update", mock.AnythingOfType("*models.Patient")).Return(nil)
-	mockPatientRepo.On("GetByID", uint(1)).Return(existingPatient, nil)	
+	mockPatientRepo.On("Update", mock.AnythingOfType("*models.Patient"), uint(2)).Return(nil)
+	mockPatientRepo.On("GetByID", uint(1)).Return(existingPatient, nil)
 
 	newMedicalHistory := "Diabetes"
 	req := services.UpdatePatientRequest{
@@ -361,7 +377,7 @@ func TestPatientService_UpdatePatient_Success_Doctor_MedicalFields(t *testing.T)
 func TestPatientService_DeletePatient_Success_Receptionist(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	patient := &models.Patient{
 		ID:        1,
@@ -370,9 +386,9 @@ func TestPatientService_DeletePatient_Success_Receptionist(t *testing.T) {
 	}
 
 	mockPatientRepo.On("GetByID", uint(1)).Return(patient, nil)
-	mockPatientRepo.On("Delete", uint(1)).Return(nil)
+	mockPatientRepo.On("Delete", uint(1), uint(2)).Return(nil)
 
-	err := patientService.DeletePatient(1, models.RoleReceptionist)
+	err := patientService.DeletePatient(1, 2, models.RoleReceptionist)
 
 	assert.NoError(t, err)
 	mockPatientRepo.AssertExpectations(t)
@@ -381,28 +397,30 @@ func TestPatientService_DeletePatient_Success_Receptionist(t *testing.T) {
 func TestPatientService_DeletePatient_Forbidden_Doctor(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
-	err := patientService.DeletePatient(1, models.RoleDoctor)
+	err := patientService.DeletePatient(1, 2, models.RoleDoctor)
 
 	assert.Error(t, err)
-	assert.Equal(t, "only receptionists can delete patients", err.Error())
+	assert.Equal(t, authz.ErrForbidden, err)
 }
 
 func TestPatientService_ListPatients_Success(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	patients := []*models.Patient{
 		{ID: 1, FirstName: "John", LastName: "Doe"},
 		{ID: 2, FirstName: "Jane", LastName: "Smith"},
 	}
 
-	mockPatientRepo.On("List", 10, 0).Return(patients, nil)
-	mockPatientRepo.On("Count").Return(int64(2), nil)
+	active := true
+	filter := repository.PatientFilter{IsActive: &active}
+	mockPatientRepo.On("List", filter, []repository.PatientSort(nil), 10, 0).Return(patients, nil)
+	mockPatientRepo.On("Count", filter).Return(int64(2), nil)
 
-	response, err := patientService.ListPatients(1, 10)
+	response, err := patientService.ListPatients(services.PatientListQuery{}, 1, 10)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -416,7 +434,7 @@ func TestPatientService_ListPatients_Success(t *testing.T) {
 func TestPatientService_SearchPatients_Success(t *testing.T) {
 	mockPatientRepo := new(MockPatientRepository)
 	mockUserRepo := new(MockUserRepository)
-	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo)
+	patientService := services.NewPatientService(mockPatientRepo, mockUserRepo, authz.DefaultPolicy())
 
 	patients := []*models.Patient{
 		{ID: 1, FirstName: "John", LastName: "Doe"},
@@ -432,3 +450,27 @@ func TestPatientService_SearchPatients_Success(t *testing.T) {
 	assert.Equal(t, "John", response.Patients[0].FirstName)
 	mockPatientRepo.AssertExpectations(t)
 }
+
+func TestPatientService_SearchPatients_Relevance(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	patientService := services.NewPatientService(mockPatientRepo, new(MockUserRepository), authz.DefaultPolicy())
+
+	patients := []*models.Patient{
+		{ID: 2, FirstName: "John", LastName: "Smith", SearchRank: 0.8607},
+		{ID: 1, FirstName: "Jon", LastName: "Smyth", SearchRank: 0.12345},
+	}
+	mockPatientRepo.On("Search", "Jon Smyth", 10, 0).Return(patients, nil)
+
+	response, err := patientService.SearchPatients("Jon Smyth", 1, 10)
+
+	require.NoError(t, err)
+	require.Len(t, response.Patients, 2)
+	assert.Equal(t, uint(2), response.Patients[0].ID)
+	assert.Equal(t, 0.861, response.Patients[0].Relevance)
+	assert.Equal(t, 0.123, response.Patients[1].Relevance)
+
+	body, err := json.Marshal(response.Patients[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"relevance":0.123`)
+	assert.Contains(t, string(body), `"first_name":"Jon"`)
+}
@@ -0,0 +1,217 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/phi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func newTestKeyring(t *testing.T, active int, versions ...int) *phi.Keyring {
+	masters := make(map[int][]byte, len(versions))
+	for _, version := range versions {
+		masters[version] = testKey(byte(version))
+	}
+	keyring, err := phi.NewKeyring(active, masters, testKey(0xAA))
+	require.NoError(t, err)
+	return keyring
+}
+
+func encryptedPatient() *models.Patient {
+	return &models.Patient{
+		ID:             1,
+		FirstName:      "Jane",
+		LastName:       "Doe",
+		Email:          "Jane.Doe@example.com",
+		Phone:          "+1 (555) 010-2030",
+		Address:        "1 Main Street",
+		MedicalHistory: "Type 2 diabetes",
+	}
+}
+
+func TestPatientPHI_SealAndOpen(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	patient := encryptedPatient()
+
+	require.NoError(t, patient.SealPHI(keyring))
+	assert.Equal(t, 1, patient.KeyVersion)
+	assert.NotEmpty(t, patient.EncryptedDataKey)
+	for _, value := range []string{patient.Email, patient.Phone, patient.Address, patient.MedicalHistory} {
+		assert.True(t, strings.HasPrefix(value, "enc:"))
+	}
+	assert.NotContains(t, patient.MedicalHistory, "diabetes")
+	assert.Equal(t, "Jane", patient.FirstName)
+
+	require.NoError(t, patient.OpenPHI(keyring))
+	assert.Equal(t, encryptedPatient().Email, patient.Email)
+	assert.Equal(t, encryptedPatient().Phone, patient.Phone)
+	assert.Equal(t, encryptedPatient().Address, patient.Address)
+	assert.Equal(t, encryptedPatient().MedicalHistory, patient.MedicalHistory)
+}
+
+func TestPatientPHI_PlaintextRowsStillRead(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	patient := encryptedPatient()
+
+	require.NoError(t, patient.OpenPHI(keyring))
+	assert.Equal(t, "Type 2 diabetes", patient.MedicalHistory)
+
+	// Without a keyring plaintext is written as it is.
+	require.NoError(t, patient.SealPHI(nil))
+	assert.Equal(t, "Type 2 diabetes", patient.MedicalHistory)
+	assert.Empty(t, patient.EncryptedDataKey)
+	assert.Empty(t, patient.PhoneIndex)
+}
+
+func TestPatientPHI_EncryptedRowNeedsKeyring(t *testing.T) {
+	patient := encryptedPatient()
+	require.NoError(t, patient.SealPHI(newTestKeyring(t, 1, 1)))
+
+	err := patient.OpenPHI(nil)
+	assert.ErrorIs(t, err, phi.ErrNoKeyring)
+}
+
+func TestPatientPHI_CiphertextIsBoundToRecordAndColumn(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	first := encryptedPatient()
+	second := encryptedPatient()
+	require.NoError(t, first.SealPHI(keyring))
+	require.NoError(t, second.SealPHI(keyring))
+
+	// Each record has its own data key.
+	assert.NotEqual(t, first.EncryptedDataKey, second.EncryptedDataKey)
+	copied := *second
+	copied.MedicalHistory = first.MedicalHistory
+	assert.Error(t, copied.OpenPHI(keyring))
+
+	// A value moved to another column does not decrypt either.
+	swapped := *first
+	swapped.Address = first.MedicalHistory
+	assert.Error(t, swapped.OpenPHI(keyring))
+}
+
+func TestPatientPHI_RotationRewrapsDataKey(t *testing.T) {
+	patient := encryptedPatient()
+	require.NoError(t, patient.SealPHI(newTestKeyring(t, 1, 1)))
+	oldDataKey := patient.EncryptedDataKey
+
+	// After rotation the old version stays readable.
+	rotated := newTestKeyring(t, 2, 1, 2)
+	readable := *patient
+	require.NoError(t, readable.OpenPHI(rotated))
+	assert.Equal(t, "Type 2 diabetes", readable.MedicalHistory)
+
+	// Sealing again moves the record to the active version.
+	require.NoError(t, patient.SealPHI(rotated))
+	assert.Equal(t, 2, patient.KeyVersion)
+	assert.NotEqual(t, oldDataKey, patient.EncryptedDataKey)
+
+	// Once it is re-encrypted, version 1 can be dropped from the keyfile.
+	current := newTestKeyring(t, 2, 2)
+	require.NoError(t, patient.OpenPHI(current))
+	assert.Equal(t, "Type 2 diabetes", patient.MedicalHistory)
+}
+
+func TestPatientPHI_BlindIndexes(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	patient := encryptedPatient()
+	require.NoError(t, patient.SealPHI(keyring))
+
+	assert.Len(t, patient.PhoneIndex, 32)
+	assert.Equal(t, patient.PhoneIndex, keyring.PhoneIndex("15550102030"))
+	assert.Equal(t, patient.EmailIndex, keyring.EmailIndex(" jane.doe@EXAMPLE.com"))
+	assert.NotEqual(t, keyring.PhoneIndex("15550102030"), keyring.PhoneIndex("15550102031"))
+	assert.Empty(t, keyring.PhoneIndex("no digits"))
+
+	other, err := phi.NewKeyring(1, map[int][]byte{1: testKey(1)}, testKey(0xBB))
+	require.NoError(t, err)
+	assert.NotEqual(t, patient.PhoneIndex, other.PhoneIndex("15550102030"))
+}
+
+func TestPatientRevisionPHI_StaysValidJSON(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	snapshot, err := json.Marshal(encryptedPatient().Snapshot())
+	require.NoError(t, err)
+	revision := &models.PatientRevision{
+		Changes:  `[{"field":"phone","before":"","after":"555-0102"}]`,
+		Snapshot: string(snapshot),
+	}
+
+	require.NoError(t, revision.SealPHI(keyring))
+	assert.True(t, json.Valid([]byte(revision.Changes)))
+	assert.True(t, json.Valid([]byte(revision.Snapshot)))
+	assert.NotContains(t, revision.Snapshot, "diabetes")
+
+	require.NoError(t, revision.OpenPHI(keyring))
+	restored, err := revision.GetSnapshot()
+	require.NoError(t, err)
+	assert.Equal(t, "Type 2 diabetes", restored.MedicalHistory)
+	changes, err := revision.GetChanges()
+	require.NoError(t, err)
+	assert.Equal(t, "555-0102", changes[0].After)
+}
+
+func TestAllergyAndPrescriptionPHI_SealAndOpen(t *testing.T) {
+	keyring := newTestKeyring(t, 1, 1)
+	allergy := &models.Allergy{ID: 1, Substance: "Penicillin", Reaction: "Hives", Notes: "Since childhood"}
+	prescription := &models.Prescription{ID: 2, MedicationName: "Metformin 500mg", Instructions: "Take with food"}
+
+	require.NoError(t, allergy.SealPHI(keyring))
+	require.NoError(t, prescription.SealPHI(keyring))
+	for _, value := range []string{allergy.Substance, allergy.Reaction, allergy.Notes, prescription.Instructions} {
+		assert.True(t, strings.HasPrefix(value, "enc:"))
+	}
+	assert.Empty(t, prescription.DiscontinuedReason)
+	assert.Equal(t, "Metformin 500mg", prescription.MedicationName)
+
+	require.NoError(t, allergy.OpenPHI(keyring))
+	require.NoError(t, prescription.OpenPHI(keyring))
+	assert.Equal(t, "Penicillin", allergy.Substance)
+	assert.Equal(t, "Hives", allergy.Reaction)
+	assert.Equal(t, "Since childhood", allergy.Notes)
+	assert.Equal(t, "Take with food", prescription.Instructions)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "phi_keys.json")
+	encode := base64.StdEncoding.EncodeToString
+
+	file := `{"active_version": 2, "master_keys": [` +
+		`{"version": 1, "key": "` + encode(testKey(1)) + `"},` +
+		`{"version": 2, "key": "` + encode(testKey(2)) + `"}],` +
+		`"blind_index_key": "` + encode(testKey(0xAA)) + `"}`
+	require.NoError(t, os.WriteFile(path, []byte(file), 0600))
+
+	keyring, err := phi.LoadKeyring(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, keyring.ActiveVersion())
+
+	// Matches a keyring built from the same keys.
+	assert.Equal(t, newTestKeyring(t, 2, 1, 2).PhoneIndex("5550102030"), keyring.PhoneIndex("5550102030"))
+
+	missing := `{"active_version": 3, "master_keys": [{"version": 1, "key": "` + encode(testKey(1)) + `"}],` +
+		`"blind_index_key": "` + encode(testKey(0xAA)) + `"}`
+	require.NoError(t, os.WriteFile(path, []byte(missing), 0600))
+	_, err = phi.LoadKeyring(path)
+	assert.Error(t, err)
+
+	short := `{"active_version": 1, "master_keys": [{"version": 1, "key": "` + encode([]byte("short")) + `"}],` +
+		`"blind_index_key": "` + encode(testKey(0xAA)) + `"}`
+	require.NoError(t, os.WriteFile(path, []byte(short), 0600))
+	_, err = phi.LoadKeyring(path)
+	assert.Error(t, err)
+}
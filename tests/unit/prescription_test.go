@@ -0,0 +1,341 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/clinical"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMedicationRepository struct {
+	mock.Mock
+}
+
+func (m *MockMedicationRepository) Create(medication *models.Medication) error {
+	args := m.Called(medication)
+	return args.Error(0)
+}
+
+func (m *MockMedicationRepository) GetByID(id uint) (*models.Medication, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Medication), args.Error(1)
+}
+
+func (m *MockMedicationRepository) GetByName(name string) (*models.Medication, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Medication), args.Error(1)
+}
+
+func (m *MockMedicationRepository) Update(medication *models.Medication) error {
+	args := m.Called(medication)
+	return args.Error(0)
+}
+
+func (m *MockMedicationRepository) Search(filter repository.MedicationFilter, limit, offset int) ([]*models.Medication, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]*models.Medication), args.Error(1)
+}
+
+func (m *MockMedicationRepository) Count(filter repository.MedicationFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockPrescriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPrescriptionRepository) Create(prescription *models.Prescription) error {
+	args := m.Called(prescription)
+	return args.Error(0)
+}
+
+func (m *MockPrescriptionRepository) GetByID(patientID, id uint) (*models.Prescription, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Prescription), args.Error(1)
+}
+
+func (m *MockPrescriptionRepository) Update(prescription *models.Prescription) error {
+	args := m.Called(prescription)
+	return args.Error(0)
+}
+
+func (m *MockPrescriptionRepository) ListByPatient(patientID uint, status models.PrescriptionStatus) ([]*models.Prescription, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]*models.Prescription), args.Error(1)
+}
+
+func (m *MockPrescriptionRepository) ListCurrent(patientID uint, at time.Time) ([]*models.Prescription, error) {
+	args := m.Called(patientID, at)
+	return args.Get(0).([]*models.Prescription), args.Error(1)
+}
+
+func (m *MockPrescriptionRepository) ListOverlapping(patientID uint, from time.Time, to *time.Time) ([]*models.Prescription, error) {
+	args := m.Called(patientID, from, to)
+	return args.Get(0).([]*models.Prescription), args.Error(1)
+}
+
+func newPrescriptionTestService() (*MockPrescriptionRepository, *MockMedicationRepository, *MockPatientRepository, *MockEncounterRepository, *MockAllergyRepository, *services.PrescriptionService) {
+	prescriptionRepo := new(MockPrescriptionRepository)
+	medicationRepo := new(MockMedicationRepository)
+	patientRepo := new(MockPatientRepository)
+	encounterRepo := new(MockEncounterRepository)
+	allergyRepo := new(MockAllergyRepository)
+	return prescriptionRepo, medicationRepo, patientRepo, encounterRepo, allergyRepo, services.NewPrescriptionService(prescriptionRepo, medicationRepo, patientRepo, encounterRepo, allergyRepo, clinical.DefaultRules(), authz.DefaultPolicy())
+}
+
+func TestPrescriptionService_CreatePrescription_Success(t *testing.T) {
+	prescriptionRepo, medicationRepo, patientRepo, _, allergyRepo, prescriptionService := newPrescriptionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	medicationRepo.On("GetByID", uint(7)).Return(&models.Medication{ID: 7, Name: "Amoxicillin 500mg", DrugClass: "penicillin", IsActive: true}, nil)
+	allergyRepo.On("ListByPatient", uint(1), models.AllergyStatusActive).Return([]*models.Allergy{}, nil)
+	prescriptionRepo.On("ListOverlapping", uint(1), mock.AnythingOfType("time.Time"), mock.MatchedBy(func(to *time.Time) bool {
+		return to != nil
+	})).Return([]*models.Prescription{}, nil)
+	prescriptionRepo.On("Create", mock.MatchedBy(func(p *models.Prescription) bool {
+		return p.MedicationName == "Amoxicillin 500mg" && p.Status == models.PrescriptionStatusActive &&
+			p.PrescriberID != nil && *p.PrescriberID == 2 && p.EndDate != nil && p.EndDate.Sub(p.StartDate) == 7*24*time.Hour
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Prescription).ID = 9
+	}).Return(nil)
+	prescriptionRepo.On("GetByID", uint(1), uint(9)).Return(&models.Prescription{ID: 9, PatientID: 1, MedicationName: "Amoxicillin 500mg", Status: models.PrescriptionStatusActive, StartDate: time.Now().Add(-time.Minute)}, nil)
+
+	duration := 7
+	response, err := prescriptionService.CreatePrescription(1, services.CreatePrescriptionRequest{
+		MedicationID: 7,
+		Dose:         "500 mg",
+		Route:        models.MedicationRouteOral,
+		Frequency:    "three times daily",
+		DurationDays: &duration,
+	}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Amoxicillin 500mg", response.MedicationName)
+	assert.True(t, response.IsCurrent)
+	prescriptionRepo.AssertExpectations(t)
+}
+
+func TestPrescriptionService_CreatePrescription_InactiveMedication(t *testing.T) {
+	prescriptionRepo, medicationRepo, patientRepo, _, _, prescriptionService := newPrescriptionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	medicationRepo.On("GetByID", uint(7)).Return(&models.Medication{ID: 7, Name: "Ranitidine", IsActive: false}, nil)
+
+	response, err := prescriptionService.CreatePrescription(1, services.CreatePrescriptionRequest{
+		MedicationID: 7,
+		Dose:         "150 mg",
+		Route:        models.MedicationRouteOral,
+		Frequency:    "twice daily",
+	}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "medication not found", err.Error())
+	assert.Nil(t, response)
+	prescriptionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPrescriptionService_CreatePrescription_EncounterFromOtherPatient(t *testing.T) {
+	_, medicationRepo, patientRepo, encounterRepo, _, prescriptionService := newPrescriptionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	medicationRepo.On("GetByID", uint(7)).Return(&models.Medication{ID: 7, Name: "Amoxicillin 500mg", IsActive: true}, nil)
+	encounterRepo.On("GetByID", uint(1), uint(4)).Return(nil, errors.New("encounter not found"))
+
+	encounterID := uint(4)
+	_, err := prescriptionService.CreatePrescription(1, services.CreatePrescriptionRequest{
+		MedicationID: 7,
+		EncounterID:  &encounterID,
+		Dose:         "500 mg",
+		Route:        models.MedicationRouteOral,
+		Frequency:    "three times daily",
+	}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "encounter not found for this patient", err.Error())
+}
+
+func TestPrescriptionService_CreatePrescription_ReceptionistForbidden(t *testing.T) {
+	_, _, _, _, _, prescriptionService := newPrescriptionTestService()
+
+	response, err := prescriptionService.CreatePrescription(1, services.CreatePrescriptionRequest{MedicationID: 7}, 5, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestPrescriptionService_CreatePrescription_AllergyClassBlocks(t *testing.T) {
+	prescriptionRepo, medicationRepo, patientRepo, _, allergyRepo, prescriptionService := newPrescriptionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	medicationRepo.On("GetByID", uint(7)).Return(&models.Medication{ID: 7, Name: "Amoxicillin 500mg", GenericName: "amoxicillin", IsActive: true}, nil)
+	allergyRepo.On("ListByPatient", uint(1), models.AllergyStatusActive).Return([]*models.Allergy{
+		{ID: 3, PatientID: 1, Substance: models.LegacyAllergySubstance, Notes: "Penicillin, shellfish", Status: models.AllergyStatusActive, IsLegacy: true},
+	}, nil)
+	prescriptionRepo.On("ListOverlapping", uint(1), mock.AnythingOfType("time.Time"), mock.Anything).Return([]*models.Prescription{}, nil)
+
+	response, err := prescriptionService.CreatePrescription(1, services.CreatePrescriptionRequest{
+		MedicationID:   7,
+		Dose:           "500 mg",
+		Route:          models.MedicationRouteOral,
+		Frequency:      "three times daily",
+		OverrideReason: "Tolerated before",
+	}, 2, models.RoleDoctor)
+
+	var safetyErr *services.SafetyCheckError
+	assert.ErrorAs(t, err, &safetyErr)
+	assert.False(t, safetyErr.Overridable())
+	assert.Len(t, safetyErr.Alerts, 1)
+	assert.Equal(t, clinical.AlertKindDrugAllergy, safetyErr.Alerts[0].Kind)
+	assert.Equal(t, uint(3), *safetyErr.Alerts[0].AllergyID)
+	assert.Nil(t, response)
+	prescriptionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPrescriptionService_CreatePrescription_InteractionNeedsOverride(t *testing.T) {
+	prescriptionRepo, medicationRepo, patientRepo, _, allergyRepo, prescriptionService := newPrescriptionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	medicationRepo.On("GetByID", uint(8)).Return(&models.Medication{ID: 8, Name: "Aspirin 75mg", GenericName: "aspirin", DrugClass: "nsaid", IsActive: true}, nil)
+	allergyRepo.On("ListByPatient", uint(1), models.AllergyStatusActive).Return([]*models.Allergy{}, nil)
+	prescriptionRepo.On("ListOverlapping", uint(1), mock.AnythingOfType("time.Time"), mock.Anything).Return([]*models.Prescription{
+		{ID: 5, MedicationName: "Warfarin 5mg", Medication: &models.Medication{Name: "Warfarin 5mg", GenericName: "warfarin", DrugClass: "anticoagulant"}, Status: models.PrescriptionStatusActive, StartDate: time.Now().AddDate(0, 0, 7)},
+	}, nil)
+
+	req := services.CreatePrescriptionRequest{
+		MedicationID: 8,
+		Dose:         "75 mg",
+		Route:        models.MedicationRouteOral,
+		Frequency:    "once daily",
+	}
+
+	_, err := prescriptionService.CreatePrescription(1, req, 2, models.RoleDoctor)
+
+	var safetyErr *services.SafetyCheckError
+	assert.ErrorAs(t, err, &safetyErr)
+	assert.True(t, safetyErr.Overridable())
+	assert.Equal(t, uint(5), *safetyErr.Alerts[0].PrescriptionID)
+
+	prescriptionRepo.On("Create", mock.MatchedBy(func(p *models.Prescription) bool {
+		return len(p.SafetyOverrides) == 1 && p.SafetyOverrides[0].Reason == "Cardiology advised dual therapy" &&
+			p.SafetyOverrides[0].OverriddenByID == 2 && p.SafetyOverrides[0].AlertKind == string(clinical.AlertKindDrugInteraction)
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Prescription).ID = 10
+	}).Return(nil)
+	prescriptionRepo.On("GetByID", uint(1), uint(10)).Return(&models.Prescription{ID: 10, PatientID: 1, MedicationName: "Aspirin 75mg"}, nil)
+
+	req.OverrideReason = "Cardiology advised dual therapy"
+	response, err := prescriptionService.CreatePrescription(1, req, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), response.ID)
+	prescriptionRepo.AssertExpectations(t)
+}
+
+func TestPrescriptionService_DiscontinuePrescription(t *testing.T) {
+	prescriptionRepo, _, _, _, _, prescriptionService := newPrescriptionTestService()
+
+	prescription := &models.Prescription{ID: 9, PatientID: 1, Status: models.PrescriptionStatusActive, StartDate: time.Now().AddDate(0, 0, -3)}
+	prescriptionRepo.On("GetByID", uint(1), uint(9)).Return(prescription, nil)
+	prescriptionRepo.On("Update", prescription).Return(nil)
+
+	response, err := prescriptionService.DiscontinuePrescription(1, 9, services.DiscontinuePrescriptionRequest{Reason: "Rash"}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PrescriptionStatusDiscontinued, response.Status)
+	assert.Equal(t, "Rash", response.DiscontinuedReason)
+	assert.False(t, response.IsCurrent)
+	assert.Equal(t, uint(2), *prescription.StoppedByID)
+}
+
+func TestPrescriptionService_StopPrescription_FutureStartEndsOnStartDate(t *testing.T) {
+	prescriptionRepo, _, _, _, _, prescriptionService := newPrescriptionTestService()
+
+	startDate := time.Now().AddDate(0, 0, 5)
+	endDate := startDate.AddDate(0, 0, 7)
+	prescription := &models.Prescription{ID: 9, PatientID: 1, Status: models.PrescriptionStatusActive, StartDate: startDate, EndDate: &endDate}
+	prescriptionRepo.On("GetByID", uint(1), uint(9)).Return(prescription, nil)
+	prescriptionRepo.On("Update", prescription).Return(nil)
+
+	response, err := prescriptionService.StopPrescription(1, 9, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	require.NotNil(t, response.EndDate)
+	assert.True(t, response.EndDate.Equal(startDate))
+	assert.False(t, response.IsCurrent)
+}
+
+func TestPrescriptionService_StopPrescription_AlreadyStopped(t *testing.T) {
+	prescriptionRepo, _, _, _, _, prescriptionService := newPrescriptionTestService()
+
+	prescriptionRepo.On("GetByID", uint(1), uint(9)).Return(&models.Prescription{ID: 9, PatientID: 1, Status: models.PrescriptionStatusStopped}, nil)
+
+	response, err := prescriptionService.StopPrescription(1, 9, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "only active prescriptions can be stopped or discontinued", err.Error())
+	assert.Nil(t, response)
+	prescriptionRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestPrescriptionService_ActiveMedications(t *testing.T) {
+	prescriptionRepo, _, patientRepo, _, _, prescriptionService := newPrescriptionTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	prescriptionRepo.On("ListCurrent", uint(1), mock.AnythingOfType("time.Time")).Return([]*models.Prescription{
+		{ID: 1, MedicationName: models.LegacyMedicationName, Instructions: "Metformin 500mg", Status: models.PrescriptionStatusActive, IsLegacy: true, StartDate: time.Now().AddDate(-1, 0, 0)},
+	}, nil)
+
+	medications, err := prescriptionService.ActiveMedications(1, models.RoleReceptionist)
+
+	assert.NoError(t, err)
+	assert.Len(t, medications, 1)
+	assert.True(t, medications[0].IsLegacy)
+	assert.True(t, medications[0].IsCurrent)
+}
+
+func TestMedicationService_CreateMedication_Duplicate(t *testing.T) {
+	medicationRepo := new(MockMedicationRepository)
+	medicationService := services.NewMedicationService(medicationRepo)
+
+	medicationRepo.On("GetByName", "Amoxicillin 500mg").Return(&models.Medication{ID: 7}, nil)
+
+	medication, err := medicationService.CreateMedication(services.CreateMedicationRequest{Name: "Amoxicillin 500mg"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "medication already exists", err.Error())
+	assert.Nil(t, medication)
+}
+
+func TestMedicationService_CreateMedication_NormalizesDrugClass(t *testing.T) {
+	medicationRepo := new(MockMedicationRepository)
+	medicationService := services.NewMedicationService(medicationRepo)
+
+	medicationRepo.On("GetByName", "Amoxicillin 500mg").Return(nil, errors.New("medication not found"))
+	medicationRepo.On("Create", mock.AnythingOfType("*models.Medication")).Return(nil)
+
+	medication, err := medicationService.CreateMedication(services.CreateMedicationRequest{Name: " Amoxicillin 500mg ", DrugClass: " Penicillin "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Amoxicillin 500mg", medication.Name)
+	assert.Equal(t, "penicillin", medication.DrugClass)
+	assert.True(t, medication.IsActive)
+}
@@ -0,0 +1,237 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProblemRepository struct {
+	mock.Mock
+}
+
+func (m *MockProblemRepository) Create(problem *models.Problem) error {
+	args := m.Called(problem)
+	return args.Error(0)
+}
+
+func (m *MockProblemRepository) GetByID(patientID, id uint) (*models.Problem, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Problem), args.Error(1)
+}
+
+func (m *MockProblemRepository) Update(problem *models.Problem) error {
+	args := m.Called(problem)
+	return args.Error(0)
+}
+
+func (m *MockProblemRepository) ListByPatient(patientID uint, status models.ProblemStatus) ([]*models.Problem, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]*models.Problem), args.Error(1)
+}
+
+func (m *MockProblemRepository) FindActiveByCode(patientID uint, code string) (*models.Problem, error) {
+	args := m.Called(patientID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Problem), args.Error(1)
+}
+
+func (m *MockProblemRepository) ListPatientsWithDiagnosis(filter repository.DiagnosisFilter, limit, offset int) ([]*models.Patient, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]*models.Patient), args.Error(1)
+}
+
+func (m *MockProblemRepository) CountPatientsWithDiagnosis(filter repository.DiagnosisFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockICD10Repository struct {
+	mock.Mock
+}
+
+func (m *MockICD10Repository) GetByCode(code string) (*models.ICD10Code, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ICD10Code), args.Error(1)
+}
+
+func (m *MockICD10Repository) Search(query string, limit int) ([]*models.ICD10Code, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]*models.ICD10Code), args.Error(1)
+}
+
+func (m *MockICD10Repository) Upsert(codes []models.ICD10Code) error {
+	args := m.Called(codes)
+	return args.Error(0)
+}
+
+func (m *MockICD10Repository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newProblemTestService() (*MockProblemRepository, *MockICD10Repository, *MockPatientRepository, *services.ProblemService) {
+	problemRepo := new(MockProblemRepository)
+	icd10Repo := new(MockICD10Repository)
+	patientRepo := new(MockPatientRepository)
+	return problemRepo, icd10Repo, patientRepo, services.NewProblemService(problemRepo, icd10Repo, patientRepo, authz.DefaultPolicy())
+}
+
+func TestNormalizeICD10Code(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"E11.9", "E11.9", true},
+		{"e119", "E11.9", true},
+		{" I10 ", "I10", true},
+		{"S72.001A", "S72.001A", true},
+		{"11.9", "", false},
+		{"E11.12345", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		code, err := services.NormalizeICD10Code(tt.input)
+		if tt.valid {
+			assert.NoError(t, err, tt.input)
+			assert.Equal(t, tt.expected, code)
+		} else {
+			assert.Error(t, err, tt.input)
+		}
+	}
+}
+
+func TestProblemService_CreateProblem_UsesCodeDescription(t *testing.T) {
+	problemRepo, icd10Repo, patientRepo, problemService := newProblemTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	icd10Repo.On("GetByCode", "E11.9").Return(&models.ICD10Code{Code: "E11.9", Description: "Type 2 diabetes mellitus without complications"}, nil)
+	problemRepo.On("FindActiveByCode", uint(1), "E11.9").Return(nil, errors.New("problem not found"))
+	problemRepo.On("Create", mock.MatchedBy(func(p *models.Problem) bool {
+		return p.ICD10Code == "E11.9" && p.Description == "Type 2 diabetes mellitus without complications" &&
+			p.Status == models.ProblemStatusActive && p.RecordedByID == 2
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Problem).ID = 6
+	}).Return(nil)
+	problemRepo.On("GetByID", uint(1), uint(6)).Return(&models.Problem{ID: 6, PatientID: 1, ICD10Code: "E11.9", Status: models.ProblemStatusActive}, nil)
+
+	response, err := problemService.CreateProblem(1, services.CreateProblemRequest{ICD10Code: "e119", OnsetDate: "2019-03-01"}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "E11.9", response.ICD10Code)
+	problemRepo.AssertExpectations(t)
+}
+
+func TestProblemService_CreateProblem_UnknownCode(t *testing.T) {
+	problemRepo, icd10Repo, patientRepo, problemService := newProblemTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	icd10Repo.On("GetByCode", "Z99.99").Return(nil, errors.New("ICD-10 code not found"))
+
+	response, err := problemService.CreateProblem(1, services.CreateProblemRequest{ICD10Code: "Z99.99"}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "ICD-10 code not found", err.Error())
+	assert.Nil(t, response)
+	problemRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestProblemService_UpdateProblem_ResolveSetsResolvedDate(t *testing.T) {
+	problemRepo, _, _, problemService := newProblemTestService()
+
+	problem := &models.Problem{ID: 6, PatientID: 1, ICD10Code: "J18.9", Status: models.ProblemStatusActive}
+	problemRepo.On("GetByID", uint(1), uint(6)).Return(problem, nil)
+	problemRepo.On("Update", problem).Return(nil)
+
+	status := models.ProblemStatusResolved
+	response, err := problemService.UpdateProblem(1, 6, services.UpdateProblemRequest{Status: &status}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ProblemStatusResolved, response.Status)
+	assert.NotNil(t, response.ResolvedDate)
+	assert.Equal(t, uint(2), *problem.LastUpdatedByID)
+}
+
+func TestProblemService_UpdateProblem_ResolvedDateOnActiveProblem(t *testing.T) {
+	problemRepo, _, _, problemService := newProblemTestService()
+
+	problemRepo.On("GetByID", uint(1), uint(6)).Return(&models.Problem{ID: 6, PatientID: 1, Status: models.ProblemStatusActive}, nil)
+
+	resolvedDate := "2024-01-10"
+	_, err := problemService.UpdateProblem(1, 6, services.UpdateProblemRequest{ResolvedDate: &resolvedDate}, 2, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "only resolved problems have a resolved date", err.Error())
+	problemRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestProblemService_ListPatientsWithDiagnosis(t *testing.T) {
+	problemRepo, _, _, problemService := newProblemTestService()
+
+	filter := repository.DiagnosisFilter{Code: "E11", Status: models.ProblemStatusActive}
+	problemRepo.On("ListPatientsWithDiagnosis", filter, 10, 10).Return([]*models.Patient{{ID: 4}, {ID: 9}}, nil)
+	problemRepo.On("CountPatientsWithDiagnosis", filter).Return(int64(12), nil)
+
+	response, err := problemService.ListPatientsWithDiagnosis(services.DiagnosisQuery{Code: "e11", Status: models.ProblemStatusActive}, 2, 10, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Patients, 2)
+	assert.Equal(t, 2, response.Pagination.TotalPages)
+}
+
+func TestProblemService_ListPatientsWithDiagnosis_ReceptionistForbidden(t *testing.T) {
+	_, _, _, problemService := newProblemTestService()
+
+	response, err := problemService.ListPatientsWithDiagnosis(services.DiagnosisQuery{Code: "E11"}, 1, 10, models.RoleReceptionist)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestICD10Service_ImportCodes(t *testing.T) {
+	icd10Repo := new(MockICD10Repository)
+	icd10Service := services.NewICD10Service(icd10Repo)
+
+	icd10Repo.On("Upsert", []models.ICD10Code{
+		{Code: "E11.9", Description: "Type 2 diabetes mellitus without complications"},
+		{Code: "I10", Description: "Essential (primary) hypertension"},
+	}).Return(nil)
+	icd10Repo.On("Count").Return(int64(2), nil)
+
+	result, err := icd10Service.ImportCodes(strings.NewReader("code,description\nE119,Type 2 diabetes mellitus without complications\nI10,\"Essential (primary) hypertension\"\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	icd10Repo.AssertExpectations(t)
+}
+
+func TestICD10Service_ImportCodes_InvalidCode(t *testing.T) {
+	icd10Repo := new(MockICD10Repository)
+	icd10Service := services.NewICD10Service(icd10Repo)
+
+	result, err := icd10Service.ImportCodes(strings.NewReader("E11.9,Type 2 diabetes\n123,Not a code\n"))
+
+	assert.Error(t, err)
+	assert.Equal(t, "line 2: invalid ICD-10 code", err.Error())
+	assert.Nil(t, result)
+	icd10Repo.AssertNotCalled(t, "Upsert", mock.Anything)
+}
@@ -161,7 +161,6 @@ type patientRepositoryInterface interface {
 	Search(query string, limit, offset int) ([]*models.Patient, error)
 	GetByCreatedBy(userID uint, limit, offset int) ([]*models.Patient, error)
 	Count() (int64, error)
-	GenerateUniquePatientID() (string, error)
 }
 
 // Mock implementations for interface testing
@@ -190,8 +189,7 @@ func (m *mockPatientRepo) Search(query string, limit, offset int) ([]*models.Pat
 func (m *mockPatientRepo) GetByCreatedBy(userID uint, limit, offset int) ([]*models.Patient, error) {
 	return nil, nil
 }
-func (m *mockPatientRepo) Count() (int64, error)                    { return 0, nil }
-func (m *mockPatientRepo) GenerateUniquePatientID() (string, error) { return "", nil }
+func (m *mockPatientRepo) Count() (int64, error) { return 0, nil }
 
 // Test pagination calculations
 func TestPaginationCalculations(t *testing.T) {
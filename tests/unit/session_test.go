@@ -0,0 +1,279 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/auth"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+	"hospital-management-system/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(session *models.UserSession, refreshToken *models.RefreshToken) error {
+	args := m.Called(session, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetByID(id uint) (*models.UserSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockSessionRepository) RotateRefreshToken(current *models.RefreshToken, next *models.RefreshToken, maxLifetime time.Duration) error {
+	args := m.Called(current, next, maxLifetime)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Revoke(id uint, reason string) error {
+	args := m.Called(id, reason)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllForUser(userID uint, reason string) error {
+	args := m.Called(userID, reason)
+	return args.Error(0)
+}
+
+func newStoredRefreshToken(token string, usedAt *time.Time) *models.RefreshToken {
+	return &models.RefreshToken{
+		ID:        1,
+		SessionID: 10,
+		Session:   models.UserSession{ID: 10, UserID: 1},
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    usedAt,
+	}
+}
+
+func TestAuthService_RefreshToken_RotatesToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	jwtService := auth.NewJWTService("test_secret")
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
+
+	stored := newStoredRefreshToken("old_refresh_token", nil)
+	user := &models.User{ID: 1, Username: "testuser", Role: models.RoleDoctor, IsActive: true}
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("old_refresh_token")).Return(stored, nil)
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	mockSessionRepo.On("RotateRefreshToken", stored, mock.MatchedBy(func(next *models.RefreshToken) bool {
+		return next.TokenHash != stored.TokenHash && next.ExpiresAt.After(time.Now())
+	}), auth.DefaultSessionMaxLifetime).Return(nil)
+
+	tokens, err := authService.RefreshToken("old_refresh_token")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.Token)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.NotEqual(t, "old_refresh_token", tokens.RefreshToken)
+	assert.Equal(t, int64(auth.DefaultAccessTokenTTL.Seconds()), tokens.ExpiresIn)
+
+	claims, err := jwtService.ValidateToken(tokens.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), claims.SessionID)
+	mockSessionRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RefreshToken_ReuseRevokesSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	usedAt := time.Now().Add(-time.Minute)
+	stored := newStoredRefreshToken("used_refresh_token", &usedAt)
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("used_refresh_token")).Return(stored, nil)
+	mockSessionRepo.On("Revoke", uint(10), "refresh_token_reuse").Return(nil)
+
+	tokens, err := authService.RefreshToken("used_refresh_token")
+
+	assert.Error(t, err)
+	assert.Nil(t, tokens)
+	assert.Equal(t, "refresh token reuse detected", err.Error())
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RefreshToken_ConcurrentReuseRevokesSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	stored := newStoredRefreshToken("raced_refresh_token", nil)
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("raced_refresh_token")).Return(stored, nil)
+	mockRepo.On("GetByID", uint(1)).Return(&models.User{ID: 1, IsActive: true}, nil)
+	mockSessionRepo.On("RotateRefreshToken", stored, mock.AnythingOfType("*models.RefreshToken"), auth.DefaultSessionMaxLifetime).Return(errors.New("refresh token already used"))
+	mockSessionRepo.On("Revoke", uint(10), "refresh_token_reuse").Return(nil)
+
+	tokens, err := authService.RefreshToken("raced_refresh_token")
+
+	assert.Error(t, err)
+	assert.Nil(t, tokens)
+	assert.Equal(t, "refresh token reuse detected", err.Error())
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RefreshToken_RevokedSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	revokedAt := time.Now()
+	stored := newStoredRefreshToken("refresh_token", nil)
+	stored.Session.RevokedAt = &revokedAt
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("refresh_token")).Return(stored, nil)
+
+	tokens, err := authService.RefreshToken("refresh_token")
+
+	assert.Error(t, err)
+	assert.Nil(t, tokens)
+	assert.Equal(t, "session has been revoked", err.Error())
+}
+
+func TestAuthService_RefreshToken_SessionRevokedDuringRotation(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	stored := newStoredRefreshToken("refresh_token", nil)
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("refresh_token")).Return(stored, nil)
+	mockRepo.On("GetByID", uint(1)).Return(&models.User{ID: 1, IsActive: true}, nil)
+	mockSessionRepo.On("RotateRefreshToken", stored, mock.AnythingOfType("*models.RefreshToken"), auth.DefaultSessionMaxLifetime).Return(repository.ErrSessionRevoked)
+
+	tokens, err := authService.RefreshToken("refresh_token")
+
+	assert.Nil(t, tokens)
+	assert.ErrorIs(t, err, repository.ErrSessionRevoked)
+	mockSessionRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_RefreshToken_SessionPastMaxLifetime(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	stored := newStoredRefreshToken("refresh_token", nil)
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("refresh_token")).Return(stored, nil)
+	mockRepo.On("GetByID", uint(1)).Return(&models.User{ID: 1, IsActive: true}, nil)
+	mockSessionRepo.On("RotateRefreshToken", stored, mock.AnythingOfType("*models.RefreshToken"), auth.DefaultSessionMaxLifetime).Return(repository.ErrSessionExpired)
+
+	tokens, err := authService.RefreshToken("refresh_token")
+
+	assert.Nil(t, tokens)
+	assert.EqualError(t, err, "session has expired")
+}
+
+func TestAuthService_RefreshToken_UnknownToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	mockSessionRepo.On("GetRefreshTokenByHash", utils.HashToken("unknown")).Return(nil, errors.New("refresh token not found"))
+
+	tokens, err := authService.RefreshToken("unknown")
+
+	assert.Error(t, err)
+	assert.Nil(t, tokens)
+	assert.Equal(t, "invalid refresh token", err.Error())
+}
+
+func TestAuthService_ValidateToken_RevokedSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	jwtService := auth.NewJWTService("test_secret")
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
+
+	token, err := jwtService.GenerateToken(&models.User{ID: 1, Username: "testuser", Role: models.RoleDoctor}, 10)
+	assert.NoError(t, err)
+
+	revokedAt := time.Now()
+	mockSessionRepo.On("GetByID", uint(10)).Return(&models.UserSession{ID: 10, UserID: 1, RevokedAt: &revokedAt}, nil)
+
+	claims, err := authService.ValidateToken(token)
+
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+	assert.Equal(t, "session has been revoked", err.Error())
+}
+
+func TestAuthService_ValidateToken_ActiveSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	jwtService := auth.NewJWTService("test_secret")
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
+
+	token, err := jwtService.GenerateToken(&models.User{ID: 1, Username: "testuser", Role: models.RoleDoctor}, 10)
+	assert.NoError(t, err)
+
+	mockSessionRepo.On("GetByID", uint(10)).Return(&models.UserSession{ID: 10, UserID: 1}, nil)
+
+	claims, err := authService.ValidateToken(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), claims.UserID)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_ValidateToken_WithoutSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	jwtService := auth.NewJWTService("test_secret")
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, jwtService)
+
+	token, err := jwtService.GenerateToken(&models.User{ID: 1, Username: "testuser", Role: models.RoleDoctor}, 0)
+	assert.NoError(t, err)
+
+	claims, err := authService.ValidateToken(token)
+
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestAuthService_LogoutAll_RevokesUserSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+	authService := services.NewAuthService(mockRepo, mockSessionRepo, mockMFARepo, auth.NewJWTService("test_secret"))
+
+	mockSessionRepo.On("RevokeAllForUser", uint(1), "logout_all").Return(nil)
+
+	err := authService.LogoutAll(1)
+
+	assert.NoError(t, err)
+	mockSessionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,280 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/repository"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserService_CreateUser_Success(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	mockRepo.On("GetByUsernameIncludingInactive", "newuser").Return(nil, errors.New("user not found"))
+	mockRepo.On("GetByEmailIncludingInactive", "new@example.com").Return(nil, errors.New("user not found"))
+	mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
+
+	req := services.RegisterRequest{
+		Username:  "newuser",
+		Email:     "new@example.com",
+		Password:  "password123",
+		FirstName: "New",
+		LastName:  "User",
+		Role:      models.RoleDoctor,
+	}
+
+	response, err := userService.CreateUser(req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "newuser", response.Username)
+	assert.Equal(t, "new@example.com", response.Email)
+	assert.Equal(t, models.RoleDoctor, response.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_CreateUser_UsernameExists(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	// A deactivated account keeps its username.
+	existingUser := &models.User{
+		Username: "existinguser",
+		IsActive: false,
+	}
+
+	mockRepo.On("GetByUsernameIncludingInactive", "existinguser").Return(existingUser, nil)
+
+	req := services.RegisterRequest{
+		Username:  "existinguser",
+		Email:     "new@example.com",
+		Password:  "password123",
+		FirstName: "New",
+		LastName:  "User",
+		Role:      models.RoleDoctor,
+	}
+
+	response, err := userService.CreateUser(req)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "username already exists", err.Error())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_CreateUser_EmailExists(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	existingUser := &models.User{
+		Email: "existing@example.com",
+	}
+
+	mockRepo.On("GetByUsernameIncludingInactive", "newuser").Return(nil, errors.New("user not found"))
+	mockRepo.On("GetByEmailIncludingInactive", "existing@example.com").Return(existingUser, nil)
+
+	req := services.RegisterRequest{
+		Username:  "newuser",
+		Email:     "existing@example.com",
+		Password:  "password123",
+		FirstName: "New",
+		LastName:  "User",
+		Role:      models.RoleDoctor,
+	}
+
+	response, err := userService.CreateUser(req)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "email already exists", err.Error())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_CreateUser_UnknownRole(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	response, err := userService.CreateUser(services.RegisterRequest{
+		Username:  "newnurse",
+		Email:     "nurse@example.com",
+		Password:  "password123",
+		FirstName: "New",
+		LastName:  "Nurse",
+		Role:      "nurse",
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "role must be one of admin, doctor, receptionist", err.Error())
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestUserService_ListUsers_FiltersByRoleAndStatus(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	inactive := false
+	filter := repository.UserFilter{Role: models.RoleDoctor, IsActive: &inactive, Search: "smith"}
+	users := []*models.User{{ID: 2, Username: "dr_smith", Role: models.RoleDoctor}}
+
+	mockRepo.On("Search", filter, 10, 10).Return(users, nil)
+	mockRepo.On("Count", filter).Return(int64(11), nil)
+
+	response, err := userService.ListUsers(services.UserListQuery{Role: models.RoleDoctor, Status: "inactive", Search: " smith "}, 2, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Users, 1)
+	assert.Equal(t, int64(11), response.Pagination.Total)
+	assert.Equal(t, 2, response.Pagination.TotalPages)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_ListUsers_InvalidStatus(t *testing.T) {
+	userService := services.NewUserService(new(MockUserRepository), new(MockSessionRepository), authz.DefaultPolicy())
+
+	response, err := userService.ListUsers(services.UserListQuery{Status: "deleted"}, 1, 10)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+}
+
+func TestUserService_DeactivateUser_RevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	userService := services.NewUserService(mockRepo, mockSessionRepo, authz.DefaultPolicy())
+
+	mockRepo.On("GetByIDIncludingInactive", uint(2)).Return(&models.User{ID: 2, Role: models.RoleDoctor, IsActive: true}, nil)
+	mockRepo.On("Delete", uint(2)).Return(nil)
+	mockSessionRepo.On("RevokeAllForUser", uint(2), "user_deactivated").Return(nil)
+
+	err := userService.DeactivateUser(2, 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestUserService_DeactivateUser_Self(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	err := userService.DeactivateUser(1, 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "you cannot deactivate your own account", err.Error())
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestUserService_DeactivateUser_LastAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	userService := services.NewUserService(mockRepo, mockSessionRepo, authz.DefaultPolicy())
+
+	mockRepo.On("GetByIDIncludingInactive", uint(2)).Return(&models.User{ID: 2, Role: models.RoleAdmin, IsActive: true}, nil)
+	mockRepo.On("Delete", uint(2)).Return(repository.ErrLastActiveAdmin)
+
+	err := userService.DeactivateUser(2, 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "cannot remove the last active administrator", err.Error())
+	mockSessionRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func TestUserService_UpdateUser_DemoteLastAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	userService := services.NewUserService(mockRepo, mockSessionRepo, authz.DefaultPolicy())
+
+	user := &models.User{ID: 2, Role: models.RoleAdmin, IsActive: true}
+	role := models.RoleDoctor
+	mockRepo.On("GetByIDIncludingInactive", uint(2)).Return(user, nil)
+	mockRepo.On("UpdateRole", user).Return(repository.ErrLastActiveAdmin)
+
+	response, err := userService.UpdateUser(2, services.UpdateUserRequest{Role: &role}, 1)
+
+	assert.ErrorIs(t, err, repository.ErrLastActiveAdmin)
+	assert.Nil(t, response)
+	mockSessionRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func TestUserService_ReactivateUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	user := &models.User{ID: 2, Role: models.RoleReceptionist, IsActive: false}
+	mockRepo.On("GetByIDIncludingInactive", uint(2)).Return(user, nil)
+	mockRepo.On("Update", user).Return(nil)
+
+	response, err := userService.ReactivateUser(2)
+
+	assert.NoError(t, err)
+	assert.True(t, response.IsActive)
+}
+
+func TestUserService_UpdateUser_RoleChangeRevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	userService := services.NewUserService(mockRepo, mockSessionRepo, authz.DefaultPolicy())
+
+	user := &models.User{ID: 2, Role: models.RoleReceptionist, IsActive: true}
+	role := models.RoleDoctor
+	mockRepo.On("GetByIDIncludingInactive", uint(2)).Return(user, nil)
+	mockRepo.On("UpdateRole", user).Return(nil)
+	mockSessionRepo.On("RevokeAllForUser", uint(2), "role_changed").Return(nil)
+
+	response, err := userService.UpdateUser(2, services.UpdateUserRequest{Role: &role}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleDoctor, response.Role)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateUser_OwnRole(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	role := models.RoleDoctor
+	mockRepo.On("GetByIDIncludingInactive", uint(1)).Return(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true}, nil)
+
+	response, err := userService.UpdateUser(1, services.UpdateUserRequest{Role: &role}, 1)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, "you cannot change your own role", err.Error())
+}
+
+func TestUserService_ResetPassword_RevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	userService := services.NewUserService(mockRepo, mockSessionRepo, authz.DefaultPolicy())
+
+	user := &models.User{ID: 2, Password: "old_hash", IsActive: true}
+	mockRepo.On("GetByIDIncludingInactive", uint(2)).Return(user, nil)
+	mockRepo.On("Update", user).Return(nil)
+	mockSessionRepo.On("RevokeAllForUser", uint(2), "password_reset").Return(nil)
+
+	err := userService.ResetPassword(2, services.ResetPasswordRequest{NewPassword: "new_password"})
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old_hash", user.Password)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestUserService_ResetPassword_UserNotFound(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := services.NewUserService(mockRepo, new(MockSessionRepository), authz.DefaultPolicy())
+
+	mockRepo.On("GetByIDIncludingInactive", uint(99)).Return(nil, errors.New("user not found"))
+
+	err := userService.ResetPassword(99, services.ResetPasswordRequest{NewPassword: "new_password"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
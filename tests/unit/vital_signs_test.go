@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"hospital-management-system/internal/authz"
+	"hospital-management-system/internal/clinical"
+	"hospital-management-system/internal/models"
+	"hospital-management-system/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockVitalSignsRepository struct {
+	mock.Mock
+}
+
+func (m *MockVitalSignsRepository) Create(vitals *models.VitalSigns) error {
+	args := m.Called(vitals)
+	return args.Error(0)
+}
+
+func (m *MockVitalSignsRepository) GetByID(patientID, id uint) (*models.VitalSigns, error) {
+	args := m.Called(patientID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VitalSigns), args.Error(1)
+}
+
+func (m *MockVitalSignsRepository) ListByPatient(patientID uint, from, to time.Time, limit int) ([]*models.VitalSigns, error) {
+	args := m.Called(patientID, from, to, limit)
+	return args.Get(0).([]*models.VitalSigns), args.Error(1)
+}
+
+func (m *MockVitalSignsRepository) LatestHeight(patientID uint, before time.Time) (*float64, error) {
+	args := m.Called(patientID, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*float64), args.Error(1)
+}
+
+func newVitalSignsTestService() (*MockVitalSignsRepository, *MockPatientRepository, *services.VitalSignsService) {
+	vitalSignsRepo := new(MockVitalSignsRepository)
+	patientRepo := new(MockPatientRepository)
+	return vitalSignsRepo, patientRepo, services.NewVitalSignsService(vitalSignsRepo, patientRepo, authz.DefaultPolicy())
+}
+
+func patientAged(id uint, years int) *models.Patient {
+	return &models.Patient{ID: id, DateOfBirth: time.Now().AddDate(-years, 0, -1)}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestEvaluateVitals_AgeAwareHeartRate(t *testing.T) {
+	vitals := &models.VitalSigns{HeartRate: intPtr(130)}
+
+	assert.Empty(t, clinical.EvaluateVitals(vitals, 2), "130 bpm is normal for a toddler")
+
+	flags := clinical.EvaluateVitals(vitals, 40)
+	require.Len(t, flags, 1)
+	assert.Equal(t, "heart_rate", flags[0].Measure)
+	assert.Equal(t, "high", flags[0].Status)
+}
+
+func TestEvaluateVitals_BMIOnlyFlaggedForAdults(t *testing.T) {
+	vitals := &models.VitalSigns{BMI: floatPtr(16.0), SpO2: intPtr(91)}
+
+	childFlags := clinical.EvaluateVitals(vitals, 8)
+	require.Len(t, childFlags, 1)
+	assert.Equal(t, "spo2", childFlags[0].Measure)
+
+	adultFlags := clinical.EvaluateVitals(vitals, 30)
+	assert.Len(t, adultFlags, 2)
+}
+
+func TestVitalSignsService_RecordVitalSigns_FlagsAbnormal(t *testing.T) {
+	vitalSignsRepo, patientRepo, vitalSignsService := newVitalSignsTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(patientAged(1, 45), nil)
+	vitalSignsRepo.On("Create", mock.MatchedBy(func(v *models.VitalSigns) bool {
+		return v.IsAbnormal && len(v.AbnormalFlags) == 2 && v.BMI != nil && *v.BMI == 24.7 && v.RecordedByID == 2
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.VitalSigns).ID = 3
+	}).Return(nil)
+	vitalSignsRepo.On("GetByID", uint(1), uint(3)).Return(&models.VitalSigns{ID: 3, PatientID: 1, IsAbnormal: true}, nil)
+
+	response, err := vitalSignsService.RecordVitalSigns(1, services.RecordVitalSignsRequest{
+		SystolicBP:  intPtr(150),
+		DiastolicBP: intPtr(95),
+		HeartRate:   intPtr(72),
+		WeightKg:    floatPtr(80),
+		HeightCm:    floatPtr(180),
+	}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	assert.True(t, response.IsAbnormal)
+	vitalSignsRepo.AssertExpectations(t)
+	vitalSignsRepo.AssertNotCalled(t, "LatestHeight", mock.Anything, mock.Anything)
+}
+
+func TestVitalSignsService_RecordVitalSigns_BMIUsesPreviousHeight(t *testing.T) {
+	vitalSignsRepo, patientRepo, vitalSignsService := newVitalSignsTestService()
+
+	patientRepo.On("GetByID", uint(1)).Return(patientAged(1, 45), nil)
+	vitalSignsRepo.On("LatestHeight", uint(1), mock.AnythingOfType("time.Time")).Return(floatPtr(170), nil)
+	vitalSignsRepo.On("Create", mock.MatchedBy(func(v *models.VitalSigns) bool {
+		return v.HeightCm == nil && v.BMI != nil && *v.BMI == 24.2
+	})).Return(nil)
+	vitalSignsRepo.On("GetByID", uint(1), uint(0)).Return(&models.VitalSigns{PatientID: 1}, nil)
+
+	_, err := vitalSignsService.RecordVitalSigns(1, services.RecordVitalSignsRequest{WeightKg: floatPtr(70)}, 2, models.RoleDoctor)
+
+	assert.NoError(t, err)
+	vitalSignsRepo.AssertExpectations(t)
+}
+
+func TestVitalSignsService_RecordVitalSigns_Validation(t *testing.T) {
+	_, patientRepo, vitalSignsService := newVitalSignsTestService()
+	patientRepo.On("GetByID", uint(1)).Return(patientAged(1, 45), nil)
+
+	tests := []struct {
+		name     string
+		req      services.RecordVitalSignsRequest
+		expected string
+	}{
+		{"no measurements", services.RecordVitalSignsRequest{Notes: "Patient refused"}, "at least one measurement is required"},
+		{"systolic only", services.RecordVitalSignsRequest{SystolicBP: intPtr(120)}, "systolic and diastolic blood pressure must be recorded together"},
+		{"inverted pressure", services.RecordVitalSignsRequest{SystolicBP: intPtr(70), DiastolicBP: intPtr(90)}, "systolic blood pressure must be higher than diastolic"},
+		{"future reading", services.RecordVitalSignsRequest{HeartRate: intPtr(70), RecordedAt: time.Now().Add(time.Hour).Format(time.RFC3339)}, "recorded_at cannot be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vitalSignsService.RecordVitalSigns(1, tt.req, 2, models.RoleDoctor)
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestVitalSignsService_GetTrend(t *testing.T) {
+	vitalSignsRepo, patientRepo, vitalSignsService := newVitalSignsTestService()
+
+	start := time.Now().AddDate(0, 0, -10)
+	patientRepo.On("GetByID", uint(1)).Return(patientAged(1, 45), nil)
+	vitalSignsRepo.On("ListByPatient", uint(1), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), 1000).Return([]*models.VitalSigns{
+		{RecordedAt: start, HeartRate: intPtr(72)},
+		{RecordedAt: start.Add(24 * time.Hour), Temperature: floatPtr(37.0)},
+		{RecordedAt: start.Add(48 * time.Hour), HeartRate: intPtr(112), AbnormalFlags: []models.VitalSignFlag{{Measure: "heart_rate", Value: 112, Status: "high"}}},
+	}, nil)
+
+	trend, err := vitalSignsService.GetTrend(1, "heart_rate", services.VitalSignsQuery{}, models.RoleDoctor)
+
+	require.NoError(t, err)
+	require.Len(t, trend.Points, 2)
+	assert.Equal(t, "", trend.Points[0].Abnormal)
+	assert.Equal(t, "high", trend.Points[1].Abnormal)
+	assert.Equal(t, &clinical.ReferenceRange{Min: 60, Max: 100}, trend.ReferenceRange)
+}
+
+func TestVitalSignsService_GetTrend_UnknownMeasure(t *testing.T) {
+	_, _, vitalSignsService := newVitalSignsTestService()
+
+	trend, err := vitalSignsService.GetTrend(1, "blood_sugar", services.VitalSignsQuery{}, models.RoleDoctor)
+
+	assert.Error(t, err)
+	assert.Equal(t, "unknown vital sign measure", err.Error())
+	assert.Nil(t, trend)
+}